@@ -0,0 +1,192 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package admin implements the Admin gRPC service defined in adminpb/admin.proto, which lets operators inspect the
+// logical and target state of mapr, e.g., to find which target entities were produced by a logical object.
+//
+// The Go code in adminpb is generated with protoc, protoc-gen-go and protoc-gen-go-grpc, with P4RUNTIME_PROTO set to
+// the proto directory of the P4Runtime repository.
+package admin
+
+//go:generate protoc -I adminpb -I ${P4RUNTIME_PROTO} --go_out=adminpb --go_opt=paths=source_relative --go-grpc_out=adminpb --go-grpc_opt=paths=source_relative adminpb/admin.proto
+
+import (
+	"context"
+	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"mapr/admin/adminpb"
+	"mapr/translate"
+)
+
+// Serves the Admin service from snapshots of a translator context, such that responses are consistent even while
+// controllers write.
+type Server struct {
+	adminpb.UnimplementedAdminServer
+	ctx translate.Context
+}
+
+// Creates a server inspecting the given translator context, nil if the processor has none (i.e., the dummy one), in
+// which case all RPCs fail.
+func NewServer(ctx translate.Context) *Server {
+	return &Server{ctx: ctx}
+}
+
+// Registers the Admin service on the given server.
+func (s *Server) Register(g *grpc.Server) {
+	adminpb.RegisterAdminServer(g, s)
+}
+
+func (s *Server) snapshot() (translate.Context, error) {
+	if s.ctx == nil {
+		return nil, status.Error(codes.FailedPrecondition, "mapr: the processor does not keep a logical state")
+	}
+	return s.ctx.Snapshot(), nil
+}
+
+func (s *Server) GetLogicalState(_ context.Context, request *adminpb.GetLogicalStateRequest) (
+	*adminpb.GetLogicalStateResponse, error) {
+	ctx, err := s.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	kinds := make(map[string]bool)
+	for _, k := range request.Kinds {
+		if !knownKinds[k] {
+			return nil, status.Errorf(codes.InvalidArgument, "mapr: unknown object kind %q", k)
+		}
+		kinds[k] = true
+	}
+	response := &adminpb.GetLogicalStateResponse{}
+	for _, o := range logicalObjects(ctx.Logical()) {
+		if len(kinds) == 0 || kinds[o.Id.Kind] {
+			response.Objects = append(response.Objects, o)
+		}
+	}
+	return response, nil
+}
+
+func (s *Server) GetTargetState(context.Context, *adminpb.GetTargetStateRequest) (*adminpb.GetTargetStateResponse,
+	error) {
+	ctx, err := s.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	response := &adminpb.GetTargetStateResponse{}
+	for _, u := range translate.StoreUpdates(ctx.Target()) {
+		response.Entities = append(response.Entities, u.Entity)
+	}
+	return response, nil
+}
+
+func (s *Server) GetProvenance(_ context.Context, request *adminpb.GetProvenanceRequest) (
+	*adminpb.GetProvenanceResponse, error) {
+	ctx, err := s.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	objects := make(map[translate.ObjectId]*adminpb.LogicalObject)
+	for _, o := range logicalObjects(ctx.Logical()) {
+		objects[fromObjectId(o.Id)] = o
+	}
+	response := &adminpb.GetProvenanceResponse{}
+	switch x := request.Of.(type) {
+	case *adminpb.GetProvenanceRequest_Logical:
+		id := fromObjectId(x.Logical)
+		o, ok := objects[id]
+		if !ok {
+			return nil, status.Errorf(codes.NotFound, "mapr: no logical object %s", id)
+		}
+		response.Objects = []*adminpb.LogicalObject{o}
+		for _, k := range ctx.Logical().Produced.Get(id) {
+			if e := translate.LookupEntity(ctx.Target(), k); e != nil {
+				response.Entities = append(response.Entities, e)
+			}
+		}
+	case *adminpb.GetProvenanceRequest_Target:
+		k, ok := translate.EntityKeyOf(x.Target)
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "mapr: provenance of %T is not tracked", x.Target.GetEntity())
+		}
+		e := translate.LookupEntity(ctx.Target(), k)
+		if e == nil {
+			return nil, status.Errorf(codes.NotFound, "mapr: no target entity %v", x.Target)
+		}
+		response.Entities = []*p4v1.Entity{e}
+		for _, id := range ctx.Logical().Owners.Get(k) {
+			if o, ok := objects[id]; ok {
+				response.Objects = append(response.Objects, o)
+			}
+		}
+	default:
+		return nil, status.Error(codes.InvalidArgument, "mapr: either a logical object or a target entity is required")
+	}
+	return response, nil
+}
+
+func (s *Server) ListIncompleteAttachments(context.Context, *adminpb.ListIncompleteAttachmentsRequest) (
+	*adminpb.ListIncompleteAttachmentsResponse, error) {
+	ctx, err := s.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	response := &adminpb.ListIncompleteAttachmentsResponse{}
+	for _, o := range logicalObjects(ctx.Logical()) {
+		if a := o.GetAttachment(); a != nil && !a.Complete {
+			response.Attachments = append(response.Attachments, a)
+		}
+	}
+	return response, nil
+}
+
+func (s *Server) LookupAttachments(_ context.Context, request *adminpb.LookupAttachmentsRequest) (
+	*adminpb.LookupAttachmentsResponse, error) {
+	ctx, err := s.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	var key translate.AttachmentIndexKey
+	switch x := request.By.(type) {
+	case *adminpb.LookupAttachmentsRequest_Ipv4Addr:
+		key = translate.AttachmentIpv4Key(x.Ipv4Addr)
+	case *adminpb.LookupAttachmentsRequest_MacAddr:
+		key = translate.AttachmentMacKey(x.MacAddr)
+	case *adminpb.LookupAttachmentsRequest_Vlans:
+		key = translate.AttachmentVlansKey(x.Vlans.Port, x.Vlans.STag, x.Vlans.CTag)
+	case *adminpb.LookupAttachmentsRequest_PppoeSession:
+		key = translate.AttachmentPppoeKey(x.PppoeSession.Port, x.PppoeSession.PppoeSessId)
+	default:
+		return nil, status.Error(codes.InvalidArgument, "mapr: an IPv4 address, MAC address, VLANs or PPPoE session "+
+			"is required")
+	}
+	response := &adminpb.LookupAttachmentsResponse{}
+	for _, a := range ctx.Logical().AttachmentsByIndex(key) {
+		response.Attachments = append(response.Attachments, toAttachment(a))
+	}
+	return response, nil
+}
+
+func (s *Server) ListPendingObjects(context.Context, *adminpb.ListPendingObjectsRequest) (
+	*adminpb.ListPendingObjectsResponse, error) {
+	ctx, err := s.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	response := &adminpb.ListPendingObjectsResponse{}
+	for _, o := range logicalObjects(ctx.Logical()) {
+		p := ctx.Logical().Pending.Get(fromObjectId(o.Id))
+		if p == nil {
+			continue
+		}
+		pending := &adminpb.PendingObject{Object: o}
+		for _, id := range p.Waiting {
+			pending.WaitingFor = append(pending.WaitingFor, toObjectId(id))
+		}
+		response.Objects = append(response.Objects, pending)
+	}
+	return response, nil
+}
@@ -0,0 +1,232 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package admin
+
+import (
+	"context"
+	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"mapr/admin/adminpb"
+	"mapr/fabric"
+	"mapr/translate"
+	"net"
+	"testing"
+)
+
+var (
+	myStation = &translate.IngressPipeMyStationsEntry{
+		Port:   []byte{0, 1},
+		EthDst: []byte{0xaa, 0, 0, 0, 0, 1},
+		Action: &translate.IngressPipeSetMyStationAction{},
+	}
+	upstreamLine = &translate.IngressPipeUpstreamLinesEntry{
+		Port:   []byte{0, 1},
+		STag:   []byte{0, 10},
+		CTag:   []byte{0, 20},
+		Action: &translate.IngressPipeUpstreamSetLineAction{LineId: []byte{0, 0, 0, 42}},
+	}
+	upstreamAttachment = &translate.IngressPipeUpstreamAttachmentsV4Entry{
+		LineId:      []byte{0, 0, 0, 42},
+		EthSrc:      []byte{0xbb, 0, 0, 0, 0, 1},
+		Ipv4Src:     []byte{10, 0, 0, 1},
+		PppoeSessId: []byte{0, 5},
+		Action:      &translate.NopAction{},
+	}
+	upstreamId = &adminpb.ObjectId{Kind: translate.ObjectAttachment, Key: "up/42"}
+)
+
+// Returns a server on the context of a fabric translator, and a function writing logical table entries to it.
+func newTestServer(t *testing.T) (*Server, func(*p4v1.TableEntry)) {
+	ctx := translate.NewContext(nil)
+	trn := translate.NewTranslator(fabric.NewFabricProcessor(ctx, nil), ctx, nil)
+	return NewServer(ctx), func(e *p4v1.TableEntry) {
+		u := &p4v1.Update{Type: p4v1.Update_INSERT, Entity: &p4v1.Entity{Entity: &p4v1.Entity_TableEntry{TableEntry: e}}}
+		target, err := trn.Translate(u)
+		require.NoError(t, err)
+		require.NoError(t, trn.ApplyUpdate(u, target))
+	}
+}
+
+func Test_Server(t *testing.T) {
+	s, write := newTestServer(t)
+	write(myStation.ToTableEntry())
+	write(upstreamLine.ToTableEntry())
+
+	incomplete, err := s.ListIncompleteAttachments(context.Background(), &adminpb.ListIncompleteAttachmentsRequest{})
+	require.NoError(t, err)
+	require.Len(t, incomplete.Attachments, 1)
+	assert.Equal(t, adminpb.Direction_UPSTREAM, incomplete.Attachments[0].Direction)
+	assert.Equal(t, []string{"mac_addr", "ipv4_addr", "pppoe_sess_id"}, incomplete.Attachments[0].MissingFields)
+	provenance, err := s.GetProvenance(context.Background(), &adminpb.GetProvenanceRequest{
+		Of: &adminpb.GetProvenanceRequest_Logical{Logical: upstreamId}})
+	require.NoError(t, err)
+	assert.Empty(t, provenance.Entities)
+
+	write(upstreamAttachment.ToTableEntry())
+	incomplete, err = s.ListIncompleteAttachments(context.Background(), &adminpb.ListIncompleteAttachmentsRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, incomplete.Attachments)
+
+	logical, err := s.GetLogicalState(context.Background(), &adminpb.GetLogicalStateRequest{})
+	require.NoError(t, err)
+	require.Len(t, logical.Objects, 2)
+	assert.Equal(t, upstreamId.Key, logical.Objects[0].Id.Key)
+	assert.True(t, logical.Objects[0].GetAttachment().Complete)
+	assert.Equal(t, translate.ObjectMyStation, logical.Objects[1].Id.Kind)
+	logical, err = s.GetLogicalState(context.Background(), &adminpb.GetLogicalStateRequest{
+		Kinds: []string{translate.ObjectMyStation}})
+	require.NoError(t, err)
+	require.Len(t, logical.Objects, 1)
+	assert.Equal(t, []byte{0xaa, 0, 0, 0, 0, 1}, logical.Objects[0].GetMyStation().EthDst)
+
+	targetState, err := s.GetTargetState(context.Background(), &adminpb.GetTargetStateRequest{})
+	require.NoError(t, err)
+	// A forwarding classifier entry for the MyStation, and 3 entries for the attachment.
+	assert.Len(t, targetState.Entities, 4)
+
+	// Logical to target.
+	provenance, err = s.GetProvenance(context.Background(), &adminpb.GetProvenanceRequest{
+		Of: &adminpb.GetProvenanceRequest_Logical{Logical: upstreamId}})
+	require.NoError(t, err)
+	require.Len(t, provenance.Objects, 1)
+	require.Len(t, provenance.Entities, 3)
+	// Target to logical, using a copy of the entity with the key fields only.
+	entry := provenance.Entities[0].GetTableEntry()
+	provenance, err = s.GetProvenance(context.Background(), &adminpb.GetProvenanceRequest{
+		Of: &adminpb.GetProvenanceRequest_Target{Target: &p4v1.Entity{Entity: &p4v1.Entity_TableEntry{
+			TableEntry: &p4v1.TableEntry{TableId: entry.TableId, Match: entry.Match, Priority: entry.Priority}}}}})
+	require.NoError(t, err)
+	require.Len(t, provenance.Objects, 1)
+	assert.Equal(t, upstreamId.Key, provenance.Objects[0].Id.Key)
+	assert.Equal(t, entry, provenance.Entities[0].GetTableEntry())
+}
+
+func Test_Server_LookupAttachments(t *testing.T) {
+	s, write := newTestServer(t)
+	write(upstreamLine.ToTableEntry())
+	write(upstreamAttachment.ToTableEntry())
+	tests := []struct {
+		name    string
+		request *adminpb.LookupAttachmentsRequest
+		want    int
+	}{
+		{"ipv4", &adminpb.LookupAttachmentsRequest{By: &adminpb.LookupAttachmentsRequest_Ipv4Addr{
+			Ipv4Addr: []byte{10, 0, 0, 1}}}, 1},
+		{"mac", &adminpb.LookupAttachmentsRequest{By: &adminpb.LookupAttachmentsRequest_MacAddr{
+			MacAddr: []byte{0xbb, 0, 0, 0, 0, 1}}}, 1},
+		{"vlans", &adminpb.LookupAttachmentsRequest{By: &adminpb.LookupAttachmentsRequest_Vlans{
+			Vlans: &adminpb.Vlans{Port: []byte{1}, STag: []byte{10}, CTag: []byte{20}}}}, 1},
+		{"pppoe session", &adminpb.LookupAttachmentsRequest{By: &adminpb.LookupAttachmentsRequest_PppoeSession{
+			PppoeSession: &adminpb.PppoeSession{Port: []byte{0, 1}, PppoeSessId: []byte{0, 5}}}}, 1},
+		{"other port", &adminpb.LookupAttachmentsRequest{By: &adminpb.LookupAttachmentsRequest_PppoeSession{
+			PppoeSession: &adminpb.PppoeSession{Port: []byte{0, 2}, PppoeSessId: []byte{0, 5}}}}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := s.LookupAttachments(context.Background(), tt.request)
+			require.NoError(t, err)
+			require.Len(t, response.Attachments, tt.want)
+			if tt.want > 0 {
+				assert.Equal(t, []byte{0, 0, 0, 42}, response.Attachments[0].LineId)
+			}
+		})
+	}
+}
+
+func Test_Server_ListPendingObjects(t *testing.T) {
+	ctx := translate.NewContext(nil)
+	trn := translate.NewTranslator(fabric.NewFabricProcessor(ctx, nil), ctx, nil)
+	s := NewServer(ctx)
+	write := func(u *p4v1.Update) {
+		target, err := trn.Translate(u)
+		require.NoError(t, err)
+		require.NoError(t, trn.ApplyUpdate(u, target))
+	}
+	nextHop := &translate.IngressPipeUpstreamEcmpMember{
+		MemberId: 1,
+		Action:   &translate.IngressPipeUpstreamRouteV4Action{Port: []byte{0, 1}, Dmac: []byte{0xcc, 0, 0, 0, 0, 1}},
+	}
+	write(&p4v1.Update{Type: p4v1.Update_INSERT, Entity: &p4v1.Entity{Entity: &p4v1.Entity_ActionProfileMember{
+		ActionProfileMember: nextHop.ToActionProfileMember()}}})
+
+	pending, err := s.ListPendingObjects(context.Background(), &adminpb.ListPendingObjectsRequest{})
+	require.NoError(t, err)
+	require.Len(t, pending.Objects, 1)
+	assert.Equal(t, uint32(1), pending.Objects[0].Object.GetNextHop().Id)
+	assert.Equal(t, []*adminpb.ObjectId{{Kind: translate.ObjectMyStation, Key: "1"}}, pending.Objects[0].WaitingFor)
+
+	write(&p4v1.Update{Type: p4v1.Update_INSERT, Entity: &p4v1.Entity{Entity: &p4v1.Entity_TableEntry{
+		TableEntry: myStation.ToTableEntry()}}})
+	ready := trn.Ready()
+	require.Len(t, ready, 1)
+	write(ready[0])
+	pending, err = s.ListPendingObjects(context.Background(), &adminpb.ListPendingObjectsRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, pending.Objects)
+}
+
+func Test_Server_Errors(t *testing.T) {
+	s, _ := newTestServer(t)
+	tests := []struct {
+		name    string
+		request *adminpb.GetProvenanceRequest
+		want    codes.Code
+	}{
+		{"empty", &adminpb.GetProvenanceRequest{}, codes.InvalidArgument},
+		{"unknown object", &adminpb.GetProvenanceRequest{
+			Of: &adminpb.GetProvenanceRequest_Logical{Logical: upstreamId}}, codes.NotFound},
+		{"unknown entity", &adminpb.GetProvenanceRequest{
+			Of: &adminpb.GetProvenanceRequest_Target{Target: &p4v1.Entity{Entity: &p4v1.Entity_TableEntry{
+				TableEntry: &p4v1.TableEntry{TableId: 1}}}}}, codes.NotFound},
+		{"untracked entity", &adminpb.GetProvenanceRequest{
+			Of: &adminpb.GetProvenanceRequest_Target{Target: &p4v1.Entity{Entity: &p4v1.Entity_CounterEntry{
+				CounterEntry: &p4v1.CounterEntry{CounterId: 1}}}}}, codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.GetProvenance(context.Background(), tt.request)
+			assert.Equal(t, tt.want, status.Code(err))
+		})
+	}
+
+	_, err := s.GetLogicalState(context.Background(), &adminpb.GetLogicalStateRequest{Kinds: []string{"foo"}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = s.LookupAttachments(context.Background(), &adminpb.LookupAttachmentsRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = NewServer(nil).GetTargetState(context.Background(), &adminpb.GetTargetStateRequest{})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func Test_Server_Grpc(t *testing.T) {
+	s, write := newTestServer(t)
+	write(myStation.ToTableEntry())
+	lis := bufconn.Listen(1 << 20)
+	g := grpc.NewServer()
+	s.Register(g)
+	go func() {
+		_ = g.Serve(lis)
+	}()
+	defer g.Stop()
+	conn, err := grpc.Dial("bufnet", grpc.WithInsecure(), grpc.WithContextDialer(
+		func(context.Context, string) (net.Conn, error) {
+			return lis.Dial()
+		}))
+	require.NoError(t, err)
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	response, err := adminpb.NewAdminClient(conn).GetTargetState(context.Background(), &adminpb.GetTargetStateRequest{})
+	require.NoError(t, err)
+	require.Len(t, response.Entities, 1)
+	assert.Equal(t, fabric.Table_FabricIngressFilteringFwdClassifier, response.Entities[0].GetTableEntry().TableId)
+}
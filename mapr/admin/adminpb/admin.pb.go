@@ -0,0 +1,2339 @@
+// Copyright 2020-present Open Networking Foundation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1
+// 	protoc        (unknown)
+// source: admin.proto
+
+package adminpb
+
+import (
+	v1 "github.com/p4lang/p4runtime/go/p4/v1"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Direction int32
+
+const (
+	Direction_DIRECTION_UNSPECIFIED Direction = 0
+	Direction_UPSTREAM              Direction = 1
+	Direction_DOWNSTREAM            Direction = 2
+)
+
+// Enum value maps for Direction.
+var (
+	Direction_name = map[int32]string{
+		0: "DIRECTION_UNSPECIFIED",
+		1: "UPSTREAM",
+		2: "DOWNSTREAM",
+	}
+	Direction_value = map[string]int32{
+		"DIRECTION_UNSPECIFIED": 0,
+		"UPSTREAM":              1,
+		"DOWNSTREAM":            2,
+	}
+)
+
+func (x Direction) Enum() *Direction {
+	p := new(Direction)
+	*p = x
+	return p
+}
+
+func (x Direction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Direction) Descriptor() protoreflect.EnumDescriptor {
+	return file_admin_proto_enumTypes[0].Descriptor()
+}
+
+func (Direction) Type() protoreflect.EnumType {
+	return &file_admin_proto_enumTypes[0]
+}
+
+func (x Direction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Direction.Descriptor instead.
+func (Direction) EnumDescriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{0}
+}
+
+// Identifies a logical object.
+type ObjectId struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// E.g., "attachment" or "route_v4".
+	Kind string `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	// Unique among the objects of the same kind, e.g., "up/42" for the upstream attachment of line 42.
+	Key string `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+}
+
+func (x *ObjectId) Reset() {
+	*x = ObjectId{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_admin_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ObjectId) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ObjectId) ProtoMessage() {}
+
+func (x *ObjectId) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ObjectId.ProtoReflect.Descriptor instead.
+func (*ObjectId) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ObjectId) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *ObjectId) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+type IfType struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Port   []byte `protobuf:"bytes,1,opt,name=port,proto3" json:"port,omitempty"`
+	IfType []byte `protobuf:"bytes,2,opt,name=if_type,json=ifType,proto3" json:"if_type,omitempty"`
+}
+
+func (x *IfType) Reset() {
+	*x = IfType{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_admin_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IfType) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IfType) ProtoMessage() {}
+
+func (x *IfType) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IfType.ProtoReflect.Descriptor instead.
+func (*IfType) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *IfType) GetPort() []byte {
+	if x != nil {
+		return x.Port
+	}
+	return nil
+}
+
+func (x *IfType) GetIfType() []byte {
+	if x != nil {
+		return x.IfType
+	}
+	return nil
+}
+
+type MyStation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Port   []byte `protobuf:"bytes,1,opt,name=port,proto3" json:"port,omitempty"`
+	EthDst []byte `protobuf:"bytes,2,opt,name=eth_dst,json=ethDst,proto3" json:"eth_dst,omitempty"`
+}
+
+func (x *MyStation) Reset() {
+	*x = MyStation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_admin_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MyStation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MyStation) ProtoMessage() {}
+
+func (x *MyStation) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MyStation.ProtoReflect.Descriptor instead.
+func (*MyStation) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *MyStation) GetPort() []byte {
+	if x != nil {
+		return x.Port
+	}
+	return nil
+}
+
+func (x *MyStation) GetEthDst() []byte {
+	if x != nil {
+		return x.EthDst
+	}
+	return nil
+}
+
+type Attachment struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Direction   Direction `protobuf:"varint,1,opt,name=direction,proto3,enum=mapr.admin.v1.Direction" json:"direction,omitempty"`
+	Port        []byte    `protobuf:"bytes,2,opt,name=port,proto3" json:"port,omitempty"`
+	LineId      []byte    `protobuf:"bytes,3,opt,name=line_id,json=lineId,proto3" json:"line_id,omitempty"`
+	STag        []byte    `protobuf:"bytes,4,opt,name=s_tag,json=sTag,proto3" json:"s_tag,omitempty"`
+	CTag        []byte    `protobuf:"bytes,5,opt,name=c_tag,json=cTag,proto3" json:"c_tag,omitempty"`
+	MacAddr     []byte    `protobuf:"bytes,6,opt,name=mac_addr,json=macAddr,proto3" json:"mac_addr,omitempty"`
+	Ipv4Addr    []byte    `protobuf:"bytes,7,opt,name=ipv4_addr,json=ipv4Addr,proto3" json:"ipv4_addr,omitempty"`
+	PppoeSessId []byte    `protobuf:"bytes,8,opt,name=pppoe_sess_id,json=pppoeSessId,proto3" json:"pppoe_sess_id,omitempty"`
+	// Whether all fields are known, i.e., whether the attachment is programmed on the target.
+	Complete bool `protobuf:"varint,9,opt,name=complete,proto3" json:"complete,omitempty"`
+	// The names of the unknown fields, e.g., "mac_addr".
+	MissingFields []string `protobuf:"bytes,10,rep,name=missing_fields,json=missingFields,proto3" json:"missing_fields,omitempty"`
+}
+
+func (x *Attachment) Reset() {
+	*x = Attachment{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_admin_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Attachment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Attachment) ProtoMessage() {}
+
+func (x *Attachment) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Attachment.ProtoReflect.Descriptor instead.
+func (*Attachment) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Attachment) GetDirection() Direction {
+	if x != nil {
+		return x.Direction
+	}
+	return Direction_DIRECTION_UNSPECIFIED
+}
+
+func (x *Attachment) GetPort() []byte {
+	if x != nil {
+		return x.Port
+	}
+	return nil
+}
+
+func (x *Attachment) GetLineId() []byte {
+	if x != nil {
+		return x.LineId
+	}
+	return nil
+}
+
+func (x *Attachment) GetSTag() []byte {
+	if x != nil {
+		return x.STag
+	}
+	return nil
+}
+
+func (x *Attachment) GetCTag() []byte {
+	if x != nil {
+		return x.CTag
+	}
+	return nil
+}
+
+func (x *Attachment) GetMacAddr() []byte {
+	if x != nil {
+		return x.MacAddr
+	}
+	return nil
+}
+
+func (x *Attachment) GetIpv4Addr() []byte {
+	if x != nil {
+		return x.Ipv4Addr
+	}
+	return nil
+}
+
+func (x *Attachment) GetPppoeSessId() []byte {
+	if x != nil {
+		return x.PppoeSessId
+	}
+	return nil
+}
+
+func (x *Attachment) GetComplete() bool {
+	if x != nil {
+		return x.Complete
+	}
+	return false
+}
+
+func (x *Attachment) GetMissingFields() []string {
+	if x != nil {
+		return x.MissingFields
+	}
+	return nil
+}
+
+type RouteV4 struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Direction      Direction `protobuf:"varint,1,opt,name=direction,proto3,enum=mapr.admin.v1.Direction" json:"direction,omitempty"`
+	Ipv4Addr       []byte    `protobuf:"bytes,2,opt,name=ipv4_addr,json=ipv4Addr,proto3" json:"ipv4_addr,omitempty"`
+	PrefixLen      int32     `protobuf:"varint,3,opt,name=prefix_len,json=prefixLen,proto3" json:"prefix_len,omitempty"`
+	NextHopGroupId uint32    `protobuf:"varint,4,opt,name=next_hop_group_id,json=nextHopGroupId,proto3" json:"next_hop_group_id,omitempty"`
+}
+
+func (x *RouteV4) Reset() {
+	*x = RouteV4{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_admin_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RouteV4) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RouteV4) ProtoMessage() {}
+
+func (x *RouteV4) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RouteV4.ProtoReflect.Descriptor instead.
+func (*RouteV4) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RouteV4) GetDirection() Direction {
+	if x != nil {
+		return x.Direction
+	}
+	return Direction_DIRECTION_UNSPECIFIED
+}
+
+func (x *RouteV4) GetIpv4Addr() []byte {
+	if x != nil {
+		return x.Ipv4Addr
+	}
+	return nil
+}
+
+func (x *RouteV4) GetPrefixLen() int32 {
+	if x != nil {
+		return x.PrefixLen
+	}
+	return 0
+}
+
+func (x *RouteV4) GetNextHopGroupId() uint32 {
+	if x != nil {
+		return x.NextHopGroupId
+	}
+	return 0
+}
+
+type NextHopGroup struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id      uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Members []*NextHopGroup_Member `protobuf:"bytes,2,rep,name=members,proto3" json:"members,omitempty"`
+	MaxSize int32                  `protobuf:"varint,3,opt,name=max_size,json=maxSize,proto3" json:"max_size,omitempty"`
+}
+
+func (x *NextHopGroup) Reset() {
+	*x = NextHopGroup{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_admin_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NextHopGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NextHopGroup) ProtoMessage() {}
+
+func (x *NextHopGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NextHopGroup.ProtoReflect.Descriptor instead.
+func (*NextHopGroup) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *NextHopGroup) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *NextHopGroup) GetMembers() []*NextHopGroup_Member {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+func (x *NextHopGroup) GetMaxSize() int32 {
+	if x != nil {
+		return x.MaxSize
+	}
+	return 0
+}
+
+type NextHop struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id      uint32 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Port    []byte `protobuf:"bytes,2,opt,name=port,proto3" json:"port,omitempty"`
+	MacAddr []byte `protobuf:"bytes,3,opt,name=mac_addr,json=macAddr,proto3" json:"mac_addr,omitempty"`
+}
+
+func (x *NextHop) Reset() {
+	*x = NextHop{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_admin_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NextHop) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NextHop) ProtoMessage() {}
+
+func (x *NextHop) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NextHop.ProtoReflect.Descriptor instead.
+func (*NextHop) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *NextHop) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *NextHop) GetPort() []byte {
+	if x != nil {
+		return x.Port
+	}
+	return nil
+}
+
+func (x *NextHop) GetMacAddr() []byte {
+	if x != nil {
+		return x.MacAddr
+	}
+	return nil
+}
+
+type PppoePunt struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PppoeCode  []byte `protobuf:"bytes,1,opt,name=pppoe_code,json=pppoeCode,proto3" json:"pppoe_code,omitempty"`
+	PppoeProto []byte `protobuf:"bytes,2,opt,name=pppoe_proto,json=pppoeProto,proto3" json:"pppoe_proto,omitempty"`
+}
+
+func (x *PppoePunt) Reset() {
+	*x = PppoePunt{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_admin_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PppoePunt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PppoePunt) ProtoMessage() {}
+
+func (x *PppoePunt) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PppoePunt.ProtoReflect.Descriptor instead.
+func (*PppoePunt) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *PppoePunt) GetPppoeCode() []byte {
+	if x != nil {
+		return x.PppoeCode
+	}
+	return nil
+}
+
+func (x *PppoePunt) GetPppoeProto() []byte {
+	if x != nil {
+		return x.PppoeProto
+	}
+	return nil
+}
+
+type LogicalObject struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id *ObjectId `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Types that are assignable to Object:
+	//	*LogicalObject_IfType
+	//	*LogicalObject_MyStation
+	//	*LogicalObject_Attachment
+	//	*LogicalObject_RouteV4
+	//	*LogicalObject_NextHopGroup
+	//	*LogicalObject_NextHop
+	//	*LogicalObject_Acl
+	//	*LogicalObject_PppoePunt
+	Object isLogicalObject_Object `protobuf_oneof:"object"`
+}
+
+func (x *LogicalObject) Reset() {
+	*x = LogicalObject{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_admin_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LogicalObject) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogicalObject) ProtoMessage() {}
+
+func (x *LogicalObject) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogicalObject.ProtoReflect.Descriptor instead.
+func (*LogicalObject) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *LogicalObject) GetId() *ObjectId {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (m *LogicalObject) GetObject() isLogicalObject_Object {
+	if m != nil {
+		return m.Object
+	}
+	return nil
+}
+
+func (x *LogicalObject) GetIfType() *IfType {
+	if x, ok := x.GetObject().(*LogicalObject_IfType); ok {
+		return x.IfType
+	}
+	return nil
+}
+
+func (x *LogicalObject) GetMyStation() *MyStation {
+	if x, ok := x.GetObject().(*LogicalObject_MyStation); ok {
+		return x.MyStation
+	}
+	return nil
+}
+
+func (x *LogicalObject) GetAttachment() *Attachment {
+	if x, ok := x.GetObject().(*LogicalObject_Attachment); ok {
+		return x.Attachment
+	}
+	return nil
+}
+
+func (x *LogicalObject) GetRouteV4() *RouteV4 {
+	if x, ok := x.GetObject().(*LogicalObject_RouteV4); ok {
+		return x.RouteV4
+	}
+	return nil
+}
+
+func (x *LogicalObject) GetNextHopGroup() *NextHopGroup {
+	if x, ok := x.GetObject().(*LogicalObject_NextHopGroup); ok {
+		return x.NextHopGroup
+	}
+	return nil
+}
+
+func (x *LogicalObject) GetNextHop() *NextHop {
+	if x, ok := x.GetObject().(*LogicalObject_NextHop); ok {
+		return x.NextHop
+	}
+	return nil
+}
+
+func (x *LogicalObject) GetAcl() *v1.TableEntry {
+	if x, ok := x.GetObject().(*LogicalObject_Acl); ok {
+		return x.Acl
+	}
+	return nil
+}
+
+func (x *LogicalObject) GetPppoePunt() *PppoePunt {
+	if x, ok := x.GetObject().(*LogicalObject_PppoePunt); ok {
+		return x.PppoePunt
+	}
+	return nil
+}
+
+type isLogicalObject_Object interface {
+	isLogicalObject_Object()
+}
+
+type LogicalObject_IfType struct {
+	IfType *IfType `protobuf:"bytes,2,opt,name=if_type,json=ifType,proto3,oneof"`
+}
+
+type LogicalObject_MyStation struct {
+	MyStation *MyStation `protobuf:"bytes,3,opt,name=my_station,json=myStation,proto3,oneof"`
+}
+
+type LogicalObject_Attachment struct {
+	Attachment *Attachment `protobuf:"bytes,4,opt,name=attachment,proto3,oneof"`
+}
+
+type LogicalObject_RouteV4 struct {
+	RouteV4 *RouteV4 `protobuf:"bytes,5,opt,name=route_v4,json=routeV4,proto3,oneof"`
+}
+
+type LogicalObject_NextHopGroup struct {
+	NextHopGroup *NextHopGroup `protobuf:"bytes,6,opt,name=next_hop_group,json=nextHopGroup,proto3,oneof"`
+}
+
+type LogicalObject_NextHop struct {
+	NextHop *NextHop `protobuf:"bytes,7,opt,name=next_hop,json=nextHop,proto3,oneof"`
+}
+
+type LogicalObject_Acl struct {
+	// ACL entries are stored as written by controllers.
+	Acl *v1.TableEntry `protobuf:"bytes,8,opt,name=acl,proto3,oneof"`
+}
+
+type LogicalObject_PppoePunt struct {
+	PppoePunt *PppoePunt `protobuf:"bytes,9,opt,name=pppoe_punt,json=pppoePunt,proto3,oneof"`
+}
+
+func (*LogicalObject_IfType) isLogicalObject_Object() {}
+
+func (*LogicalObject_MyStation) isLogicalObject_Object() {}
+
+func (*LogicalObject_Attachment) isLogicalObject_Object() {}
+
+func (*LogicalObject_RouteV4) isLogicalObject_Object() {}
+
+func (*LogicalObject_NextHopGroup) isLogicalObject_Object() {}
+
+func (*LogicalObject_NextHop) isLogicalObject_Object() {}
+
+func (*LogicalObject_Acl) isLogicalObject_Object() {}
+
+func (*LogicalObject_PppoePunt) isLogicalObject_Object() {}
+
+type GetLogicalStateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The kinds of objects to return, all if empty.
+	Kinds []string `protobuf:"bytes,1,rep,name=kinds,proto3" json:"kinds,omitempty"`
+}
+
+func (x *GetLogicalStateRequest) Reset() {
+	*x = GetLogicalStateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_admin_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetLogicalStateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLogicalStateRequest) ProtoMessage() {}
+
+func (x *GetLogicalStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLogicalStateRequest.ProtoReflect.Descriptor instead.
+func (*GetLogicalStateRequest) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetLogicalStateRequest) GetKinds() []string {
+	if x != nil {
+		return x.Kinds
+	}
+	return nil
+}
+
+type GetLogicalStateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Sorted by kind and key.
+	Objects []*LogicalObject `protobuf:"bytes,1,rep,name=objects,proto3" json:"objects,omitempty"`
+}
+
+func (x *GetLogicalStateResponse) Reset() {
+	*x = GetLogicalStateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_admin_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetLogicalStateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLogicalStateResponse) ProtoMessage() {}
+
+func (x *GetLogicalStateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLogicalStateResponse.ProtoReflect.Descriptor instead.
+func (*GetLogicalStateResponse) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetLogicalStateResponse) GetObjects() []*LogicalObject {
+	if x != nil {
+		return x.Objects
+	}
+	return nil
+}
+
+type GetTargetStateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetTargetStateRequest) Reset() {
+	*x = GetTargetStateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_admin_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetTargetStateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTargetStateRequest) ProtoMessage() {}
+
+func (x *GetTargetStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTargetStateRequest.ProtoReflect.Descriptor instead.
+func (*GetTargetStateRequest) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{11}
+}
+
+type GetTargetStateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// In dependency order, e.g., action profile members before groups.
+	Entities []*v1.Entity `protobuf:"bytes,1,rep,name=entities,proto3" json:"entities,omitempty"`
+}
+
+func (x *GetTargetStateResponse) Reset() {
+	*x = GetTargetStateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_admin_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetTargetStateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTargetStateResponse) ProtoMessage() {}
+
+func (x *GetTargetStateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTargetStateResponse.ProtoReflect.Descriptor instead.
+func (*GetTargetStateResponse) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetTargetStateResponse) GetEntities() []*v1.Entity {
+	if x != nil {
+		return x.Entities
+	}
+	return nil
+}
+
+type GetProvenanceRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Of:
+	//	*GetProvenanceRequest_Logical
+	//	*GetProvenanceRequest_Target
+	Of isGetProvenanceRequest_Of `protobuf_oneof:"of"`
+}
+
+func (x *GetProvenanceRequest) Reset() {
+	*x = GetProvenanceRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_admin_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetProvenanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProvenanceRequest) ProtoMessage() {}
+
+func (x *GetProvenanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProvenanceRequest.ProtoReflect.Descriptor instead.
+func (*GetProvenanceRequest) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{13}
+}
+
+func (m *GetProvenanceRequest) GetOf() isGetProvenanceRequest_Of {
+	if m != nil {
+		return m.Of
+	}
+	return nil
+}
+
+func (x *GetProvenanceRequest) GetLogical() *ObjectId {
+	if x, ok := x.GetOf().(*GetProvenanceRequest_Logical); ok {
+		return x.Logical
+	}
+	return nil
+}
+
+func (x *GetProvenanceRequest) GetTarget() *v1.Entity {
+	if x, ok := x.GetOf().(*GetProvenanceRequest_Target); ok {
+		return x.Target
+	}
+	return nil
+}
+
+type isGetProvenanceRequest_Of interface {
+	isGetProvenanceRequest_Of()
+}
+
+type GetProvenanceRequest_Logical struct {
+	// Returns the target entities produced by the given logical object.
+	Logical *ObjectId `protobuf:"bytes,1,opt,name=logical,proto3,oneof"`
+}
+
+type GetProvenanceRequest_Target struct {
+	// Returns the logical objects owning the given target entity, only its key fields are used.
+	Target *v1.Entity `protobuf:"bytes,2,opt,name=target,proto3,oneof"`
+}
+
+func (*GetProvenanceRequest_Logical) isGetProvenanceRequest_Of() {}
+
+func (*GetProvenanceRequest_Target) isGetProvenanceRequest_Of() {}
+
+type GetProvenanceResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The logical object, or the ones owning the target entity. Entities can be owned by multiple objects, e.g., the
+	// upstream and downstream attachments of a line share some entities, or by none, if written to the target by mapr
+	// itself.
+	Objects []*LogicalObject `protobuf:"bytes,1,rep,name=objects,proto3" json:"objects,omitempty"`
+	// The target entity, or the ones produced by the logical object.
+	Entities []*v1.Entity `protobuf:"bytes,2,rep,name=entities,proto3" json:"entities,omitempty"`
+}
+
+func (x *GetProvenanceResponse) Reset() {
+	*x = GetProvenanceResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_admin_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetProvenanceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProvenanceResponse) ProtoMessage() {}
+
+func (x *GetProvenanceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProvenanceResponse.ProtoReflect.Descriptor instead.
+func (*GetProvenanceResponse) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetProvenanceResponse) GetObjects() []*LogicalObject {
+	if x != nil {
+		return x.Objects
+	}
+	return nil
+}
+
+func (x *GetProvenanceResponse) GetEntities() []*v1.Entity {
+	if x != nil {
+		return x.Entities
+	}
+	return nil
+}
+
+type ListIncompleteAttachmentsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListIncompleteAttachmentsRequest) Reset() {
+	*x = ListIncompleteAttachmentsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_admin_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListIncompleteAttachmentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListIncompleteAttachmentsRequest) ProtoMessage() {}
+
+func (x *ListIncompleteAttachmentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListIncompleteAttachmentsRequest.ProtoReflect.Descriptor instead.
+func (*ListIncompleteAttachmentsRequest) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{15}
+}
+
+type ListIncompleteAttachmentsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Attachments []*Attachment `protobuf:"bytes,1,rep,name=attachments,proto3" json:"attachments,omitempty"`
+}
+
+func (x *ListIncompleteAttachmentsResponse) Reset() {
+	*x = ListIncompleteAttachmentsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_admin_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListIncompleteAttachmentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListIncompleteAttachmentsResponse) ProtoMessage() {}
+
+func (x *ListIncompleteAttachmentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListIncompleteAttachmentsResponse.ProtoReflect.Descriptor instead.
+func (*ListIncompleteAttachmentsResponse) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ListIncompleteAttachmentsResponse) GetAttachments() []*Attachment {
+	if x != nil {
+		return x.Attachments
+	}
+	return nil
+}
+
+// The VLAN tags of a subscriber on a port.
+type Vlans struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Port []byte `protobuf:"bytes,1,opt,name=port,proto3" json:"port,omitempty"`
+	STag []byte `protobuf:"bytes,2,opt,name=s_tag,json=sTag,proto3" json:"s_tag,omitempty"`
+	CTag []byte `protobuf:"bytes,3,opt,name=c_tag,json=cTag,proto3" json:"c_tag,omitempty"`
+}
+
+func (x *Vlans) Reset() {
+	*x = Vlans{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_admin_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Vlans) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Vlans) ProtoMessage() {}
+
+func (x *Vlans) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Vlans.ProtoReflect.Descriptor instead.
+func (*Vlans) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *Vlans) GetPort() []byte {
+	if x != nil {
+		return x.Port
+	}
+	return nil
+}
+
+func (x *Vlans) GetSTag() []byte {
+	if x != nil {
+		return x.STag
+	}
+	return nil
+}
+
+func (x *Vlans) GetCTag() []byte {
+	if x != nil {
+		return x.CTag
+	}
+	return nil
+}
+
+// The PPPoE session of a subscriber on a port.
+type PppoeSession struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Port        []byte `protobuf:"bytes,1,opt,name=port,proto3" json:"port,omitempty"`
+	PppoeSessId []byte `protobuf:"bytes,2,opt,name=pppoe_sess_id,json=pppoeSessId,proto3" json:"pppoe_sess_id,omitempty"`
+}
+
+func (x *PppoeSession) Reset() {
+	*x = PppoeSession{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_admin_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PppoeSession) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PppoeSession) ProtoMessage() {}
+
+func (x *PppoeSession) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PppoeSession.ProtoReflect.Descriptor instead.
+func (*PppoeSession) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *PppoeSession) GetPort() []byte {
+	if x != nil {
+		return x.Port
+	}
+	return nil
+}
+
+func (x *PppoeSession) GetPppoeSessId() []byte {
+	if x != nil {
+		return x.PppoeSessId
+	}
+	return nil
+}
+
+type LookupAttachmentsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to By:
+	//	*LookupAttachmentsRequest_Ipv4Addr
+	//	*LookupAttachmentsRequest_MacAddr
+	//	*LookupAttachmentsRequest_Vlans
+	//	*LookupAttachmentsRequest_PppoeSession
+	By isLookupAttachmentsRequest_By `protobuf_oneof:"by"`
+}
+
+func (x *LookupAttachmentsRequest) Reset() {
+	*x = LookupAttachmentsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_admin_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LookupAttachmentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LookupAttachmentsRequest) ProtoMessage() {}
+
+func (x *LookupAttachmentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LookupAttachmentsRequest.ProtoReflect.Descriptor instead.
+func (*LookupAttachmentsRequest) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{19}
+}
+
+func (m *LookupAttachmentsRequest) GetBy() isLookupAttachmentsRequest_By {
+	if m != nil {
+		return m.By
+	}
+	return nil
+}
+
+func (x *LookupAttachmentsRequest) GetIpv4Addr() []byte {
+	if x, ok := x.GetBy().(*LookupAttachmentsRequest_Ipv4Addr); ok {
+		return x.Ipv4Addr
+	}
+	return nil
+}
+
+func (x *LookupAttachmentsRequest) GetMacAddr() []byte {
+	if x, ok := x.GetBy().(*LookupAttachmentsRequest_MacAddr); ok {
+		return x.MacAddr
+	}
+	return nil
+}
+
+func (x *LookupAttachmentsRequest) GetVlans() *Vlans {
+	if x, ok := x.GetBy().(*LookupAttachmentsRequest_Vlans); ok {
+		return x.Vlans
+	}
+	return nil
+}
+
+func (x *LookupAttachmentsRequest) GetPppoeSession() *PppoeSession {
+	if x, ok := x.GetBy().(*LookupAttachmentsRequest_PppoeSession); ok {
+		return x.PppoeSession
+	}
+	return nil
+}
+
+type isLookupAttachmentsRequest_By interface {
+	isLookupAttachmentsRequest_By()
+}
+
+type LookupAttachmentsRequest_Ipv4Addr struct {
+	Ipv4Addr []byte `protobuf:"bytes,1,opt,name=ipv4_addr,json=ipv4Addr,proto3,oneof"`
+}
+
+type LookupAttachmentsRequest_MacAddr struct {
+	MacAddr []byte `protobuf:"bytes,2,opt,name=mac_addr,json=macAddr,proto3,oneof"`
+}
+
+type LookupAttachmentsRequest_Vlans struct {
+	Vlans *Vlans `protobuf:"bytes,3,opt,name=vlans,proto3,oneof"`
+}
+
+type LookupAttachmentsRequest_PppoeSession struct {
+	PppoeSession *PppoeSession `protobuf:"bytes,4,opt,name=pppoe_session,json=pppoeSession,proto3,oneof"`
+}
+
+func (*LookupAttachmentsRequest_Ipv4Addr) isLookupAttachmentsRequest_By() {}
+
+func (*LookupAttachmentsRequest_MacAddr) isLookupAttachmentsRequest_By() {}
+
+func (*LookupAttachmentsRequest_Vlans) isLookupAttachmentsRequest_By() {}
+
+func (*LookupAttachmentsRequest_PppoeSession) isLookupAttachmentsRequest_By() {}
+
+type LookupAttachmentsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Upstream before downstream, in ascending line ID order.
+	Attachments []*Attachment `protobuf:"bytes,1,rep,name=attachments,proto3" json:"attachments,omitempty"`
+}
+
+func (x *LookupAttachmentsResponse) Reset() {
+	*x = LookupAttachmentsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_admin_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LookupAttachmentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LookupAttachmentsResponse) ProtoMessage() {}
+
+func (x *LookupAttachmentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LookupAttachmentsResponse.ProtoReflect.Descriptor instead.
+func (*LookupAttachmentsResponse) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *LookupAttachmentsResponse) GetAttachments() []*Attachment {
+	if x != nil {
+		return x.Attachments
+	}
+	return nil
+}
+
+// A logical object not translated yet.
+type PendingObject struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Object *LogicalObject `protobuf:"bytes,1,opt,name=object,proto3" json:"object,omitempty"`
+	// The objects waiting for, i.e., missing MyStation entries or other pending objects. Empty if the object is ready,
+	// but translating it failed (see the log).
+	WaitingFor []*ObjectId `protobuf:"bytes,2,rep,name=waiting_for,json=waitingFor,proto3" json:"waiting_for,omitempty"`
+}
+
+func (x *PendingObject) Reset() {
+	*x = PendingObject{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_admin_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PendingObject) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PendingObject) ProtoMessage() {}
+
+func (x *PendingObject) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PendingObject.ProtoReflect.Descriptor instead.
+func (*PendingObject) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *PendingObject) GetObject() *LogicalObject {
+	if x != nil {
+		return x.Object
+	}
+	return nil
+}
+
+func (x *PendingObject) GetWaitingFor() []*ObjectId {
+	if x != nil {
+		return x.WaitingFor
+	}
+	return nil
+}
+
+type ListPendingObjectsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListPendingObjectsRequest) Reset() {
+	*x = ListPendingObjectsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_admin_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListPendingObjectsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPendingObjectsRequest) ProtoMessage() {}
+
+func (x *ListPendingObjectsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPendingObjectsRequest.ProtoReflect.Descriptor instead.
+func (*ListPendingObjectsRequest) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{22}
+}
+
+type ListPendingObjectsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// In ascending kind and key order.
+	Objects []*PendingObject `protobuf:"bytes,1,rep,name=objects,proto3" json:"objects,omitempty"`
+}
+
+func (x *ListPendingObjectsResponse) Reset() {
+	*x = ListPendingObjectsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_admin_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListPendingObjectsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPendingObjectsResponse) ProtoMessage() {}
+
+func (x *ListPendingObjectsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPendingObjectsResponse.ProtoReflect.Descriptor instead.
+func (*ListPendingObjectsResponse) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ListPendingObjectsResponse) GetObjects() []*PendingObject {
+	if x != nil {
+		return x.Objects
+	}
+	return nil
+}
+
+type NextHopGroup_Member struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	NextHopId uint32 `protobuf:"varint,1,opt,name=next_hop_id,json=nextHopId,proto3" json:"next_hop_id,omitempty"`
+	Weight    int32  `protobuf:"varint,2,opt,name=weight,proto3" json:"weight,omitempty"`
+}
+
+func (x *NextHopGroup_Member) Reset() {
+	*x = NextHopGroup_Member{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_admin_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NextHopGroup_Member) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NextHopGroup_Member) ProtoMessage() {}
+
+func (x *NextHopGroup_Member) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NextHopGroup_Member.ProtoReflect.Descriptor instead.
+func (*NextHopGroup_Member) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{5, 0}
+}
+
+func (x *NextHopGroup_Member) GetNextHopId() uint32 {
+	if x != nil {
+		return x.NextHopId
+	}
+	return 0
+}
+
+func (x *NextHopGroup_Member) GetWeight() int32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+var File_admin_proto protoreflect.FileDescriptor
+
+var file_admin_proto_rawDesc = []byte{
+	0x0a, 0x0b, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0d, 0x6d,
+	0x61, 0x70, 0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x1a, 0x15, 0x70, 0x34,
+	0x2f, 0x76, 0x31, 0x2f, 0x70, 0x34, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x22, 0x30, 0x0a, 0x08, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x49, 0x64, 0x12,
+	0x12, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6b,
+	0x69, 0x6e, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x03, 0x6b, 0x65, 0x79, 0x22, 0x35, 0x0a, 0x06, 0x49, 0x66, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x12, 0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x70,
+	0x6f, 0x72, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x69, 0x66, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x69, 0x66, 0x54, 0x79, 0x70, 0x65, 0x22, 0x38, 0x0a, 0x09,
+	0x4d, 0x79, 0x53, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f, 0x72,
+	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x17, 0x0a,
+	0x07, 0x65, 0x74, 0x68, 0x5f, 0x64, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06,
+	0x65, 0x74, 0x68, 0x44, 0x73, 0x74, 0x22, 0xba, 0x02, 0x0a, 0x0a, 0x41, 0x74, 0x74, 0x61, 0x63,
+	0x68, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x36, 0x0a, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x18, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x2e,
+	0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a,
+	0x04, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x70, 0x6f, 0x72,
+	0x74, 0x12, 0x17, 0x0a, 0x07, 0x6c, 0x69, 0x6e, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x06, 0x6c, 0x69, 0x6e, 0x65, 0x49, 0x64, 0x12, 0x13, 0x0a, 0x05, 0x73, 0x5f,
+	0x74, 0x61, 0x67, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x73, 0x54, 0x61, 0x67, 0x12,
+	0x13, 0x0a, 0x05, 0x63, 0x5f, 0x74, 0x61, 0x67, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04,
+	0x63, 0x54, 0x61, 0x67, 0x12, 0x19, 0x0a, 0x08, 0x6d, 0x61, 0x63, 0x5f, 0x61, 0x64, 0x64, 0x72,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x6d, 0x61, 0x63, 0x41, 0x64, 0x64, 0x72, 0x12,
+	0x1b, 0x0a, 0x09, 0x69, 0x70, 0x76, 0x34, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x08, 0x69, 0x70, 0x76, 0x34, 0x41, 0x64, 0x64, 0x72, 0x12, 0x22, 0x0a, 0x0d,
+	0x70, 0x70, 0x70, 0x6f, 0x65, 0x5f, 0x73, 0x65, 0x73, 0x73, 0x5f, 0x69, 0x64, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x0b, 0x70, 0x70, 0x70, 0x6f, 0x65, 0x53, 0x65, 0x73, 0x73, 0x49, 0x64,
+	0x12, 0x1a, 0x0a, 0x08, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x18, 0x09, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x08, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x25, 0x0a, 0x0e,
+	0x6d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x5f, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x18, 0x0a,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x0d, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x46, 0x69, 0x65,
+	0x6c, 0x64, 0x73, 0x22, 0xa8, 0x01, 0x0a, 0x07, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x56, 0x34, 0x12,
+	0x36, 0x0a, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x18, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e,
+	0x76, 0x31, 0x2e, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x64, 0x69,
+	0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1b, 0x0a, 0x09, 0x69, 0x70, 0x76, 0x34, 0x5f,
+	0x61, 0x64, 0x64, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x69, 0x70, 0x76, 0x34,
+	0x41, 0x64, 0x64, 0x72, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x5f, 0x6c,
+	0x65, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78,
+	0x4c, 0x65, 0x6e, 0x12, 0x29, 0x0a, 0x11, 0x6e, 0x65, 0x78, 0x74, 0x5f, 0x68, 0x6f, 0x70, 0x5f,
+	0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e,
+	0x6e, 0x65, 0x78, 0x74, 0x48, 0x6f, 0x70, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x22, 0xb9,
+	0x01, 0x0a, 0x0c, 0x4e, 0x65, 0x78, 0x74, 0x48, 0x6f, 0x70, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x02, 0x69, 0x64, 0x12,
+	0x3c, 0x0a, 0x07, 0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x22, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31,
+	0x2e, 0x4e, 0x65, 0x78, 0x74, 0x48, 0x6f, 0x70, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x2e, 0x4d, 0x65,
+	0x6d, 0x62, 0x65, 0x72, 0x52, 0x07, 0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x12, 0x19, 0x0a,
+	0x08, 0x6d, 0x61, 0x78, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x07, 0x6d, 0x61, 0x78, 0x53, 0x69, 0x7a, 0x65, 0x1a, 0x40, 0x0a, 0x06, 0x4d, 0x65, 0x6d, 0x62,
+	0x65, 0x72, 0x12, 0x1e, 0x0a, 0x0b, 0x6e, 0x65, 0x78, 0x74, 0x5f, 0x68, 0x6f, 0x70, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x6e, 0x65, 0x78, 0x74, 0x48, 0x6f, 0x70,
+	0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x22, 0x48, 0x0a, 0x07, 0x4e, 0x65,
+	0x78, 0x74, 0x48, 0x6f, 0x70, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x6d, 0x61, 0x63,
+	0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x6d, 0x61, 0x63,
+	0x41, 0x64, 0x64, 0x72, 0x22, 0x4b, 0x0a, 0x09, 0x50, 0x70, 0x70, 0x6f, 0x65, 0x50, 0x75, 0x6e,
+	0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x70, 0x70, 0x6f, 0x65, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x70, 0x70, 0x70, 0x6f, 0x65, 0x43, 0x6f, 0x64, 0x65,
+	0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x70, 0x70, 0x6f, 0x65, 0x5f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x70, 0x70, 0x70, 0x6f, 0x65, 0x50, 0x72, 0x6f, 0x74,
+	0x6f, 0x22, 0xfd, 0x03, 0x0a, 0x0d, 0x4c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x4f, 0x62, 0x6a,
+	0x65, 0x63, 0x74, 0x12, 0x27, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x17, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e,
+	0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x49, 0x64, 0x52, 0x02, 0x69, 0x64, 0x12, 0x30, 0x0a, 0x07,
+	0x69, 0x66, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e,
+	0x6d, 0x61, 0x70, 0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x66,
+	0x54, 0x79, 0x70, 0x65, 0x48, 0x00, 0x52, 0x06, 0x69, 0x66, 0x54, 0x79, 0x70, 0x65, 0x12, 0x39,
+	0x0a, 0x0a, 0x6d, 0x79, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x18, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e,
+	0x76, 0x31, 0x2e, 0x4d, 0x79, 0x53, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x09,
+	0x6d, 0x79, 0x53, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x3b, 0x0a, 0x0a, 0x61, 0x74, 0x74,
+	0x61, 0x63, 0x68, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e,
+	0x6d, 0x61, 0x70, 0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x74,
+	0x74, 0x61, 0x63, 0x68, 0x6d, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x0a, 0x61, 0x74, 0x74, 0x61,
+	0x63, 0x68, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x33, 0x0a, 0x08, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x5f,
+	0x76, 0x34, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x2e,
+	0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x56, 0x34,
+	0x48, 0x00, 0x52, 0x07, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x56, 0x34, 0x12, 0x43, 0x0a, 0x0e, 0x6e,
+	0x65, 0x78, 0x74, 0x5f, 0x68, 0x6f, 0x70, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e,
+	0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x65, 0x78, 0x74, 0x48, 0x6f, 0x70, 0x47, 0x72, 0x6f, 0x75, 0x70,
+	0x48, 0x00, 0x52, 0x0c, 0x6e, 0x65, 0x78, 0x74, 0x48, 0x6f, 0x70, 0x47, 0x72, 0x6f, 0x75, 0x70,
+	0x12, 0x33, 0x0a, 0x08, 0x6e, 0x65, 0x78, 0x74, 0x5f, 0x68, 0x6f, 0x70, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x16, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e,
+	0x76, 0x31, 0x2e, 0x4e, 0x65, 0x78, 0x74, 0x48, 0x6f, 0x70, 0x48, 0x00, 0x52, 0x07, 0x6e, 0x65,
+	0x78, 0x74, 0x48, 0x6f, 0x70, 0x12, 0x25, 0x0a, 0x03, 0x61, 0x63, 0x6c, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x11, 0x2e, 0x70, 0x34, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x62, 0x6c, 0x65,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x48, 0x00, 0x52, 0x03, 0x61, 0x63, 0x6c, 0x12, 0x39, 0x0a, 0x0a,
+	0x70, 0x70, 0x70, 0x6f, 0x65, 0x5f, 0x70, 0x75, 0x6e, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x18, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31,
+	0x2e, 0x50, 0x70, 0x70, 0x6f, 0x65, 0x50, 0x75, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x09, 0x70, 0x70,
+	0x70, 0x6f, 0x65, 0x50, 0x75, 0x6e, 0x74, 0x42, 0x08, 0x0a, 0x06, 0x6f, 0x62, 0x6a, 0x65, 0x63,
+	0x74, 0x22, 0x2e, 0x0a, 0x16, 0x47, 0x65, 0x74, 0x4c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6b,
+	0x69, 0x6e, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x6b, 0x69, 0x6e, 0x64,
+	0x73, 0x22, 0x51, 0x0a, 0x17, 0x47, 0x65, 0x74, 0x4c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x36, 0x0a, 0x07,
+	0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e,
+	0x6d, 0x61, 0x70, 0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f,
+	0x67, 0x69, 0x63, 0x61, 0x6c, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x07, 0x6f, 0x62, 0x6a,
+	0x65, 0x63, 0x74, 0x73, 0x22, 0x17, 0x0a, 0x15, 0x47, 0x65, 0x74, 0x54, 0x61, 0x72, 0x67, 0x65,
+	0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x43, 0x0a,
+	0x16, 0x47, 0x65, 0x74, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x29, 0x0a, 0x08, 0x65, 0x6e, 0x74, 0x69, 0x74,
+	0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x34, 0x2e, 0x76,
+	0x31, 0x2e, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x08, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69,
+	0x65, 0x73, 0x22, 0x7a, 0x0a, 0x14, 0x47, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x6e, 0x61,
+	0x6e, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x33, 0x0a, 0x07, 0x6c, 0x6f,
+	0x67, 0x69, 0x63, 0x61, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6d, 0x61,
+	0x70, 0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x62, 0x6a, 0x65,
+	0x63, 0x74, 0x49, 0x64, 0x48, 0x00, 0x52, 0x07, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x12,
+	0x27, 0x0a, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0d, 0x2e, 0x70, 0x34, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x48, 0x00,
+	0x52, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x42, 0x04, 0x0a, 0x02, 0x6f, 0x66, 0x22, 0x7a,
+	0x0a, 0x15, 0x47, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x36, 0x0a, 0x07, 0x6f, 0x62, 0x6a, 0x65, 0x63,
+	0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x2e,
+	0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c,
+	0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x07, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x73, 0x12,
+	0x29, 0x0a, 0x08, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x34, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79,
+	0x52, 0x08, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x22, 0x22, 0x0a, 0x20, 0x4c, 0x69,
+	0x73, 0x74, 0x49, 0x6e, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x41, 0x74, 0x74, 0x61,
+	0x63, 0x68, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x60,
+	0x0a, 0x21, 0x4c, 0x69, 0x73, 0x74, 0x49, 0x6e, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65,
+	0x41, 0x74, 0x74, 0x61, 0x63, 0x68, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x3b, 0x0a, 0x0b, 0x61, 0x74, 0x74, 0x61, 0x63, 0x68, 0x6d, 0x65, 0x6e,
+	0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x2e,
+	0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68, 0x6d,
+	0x65, 0x6e, 0x74, 0x52, 0x0b, 0x61, 0x74, 0x74, 0x61, 0x63, 0x68, 0x6d, 0x65, 0x6e, 0x74, 0x73,
+	0x22, 0x45, 0x0a, 0x05, 0x56, 0x6c, 0x61, 0x6e, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f, 0x72,
+	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x13, 0x0a,
+	0x05, 0x73, 0x5f, 0x74, 0x61, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x73, 0x54,
+	0x61, 0x67, 0x12, 0x13, 0x0a, 0x05, 0x63, 0x5f, 0x74, 0x61, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x04, 0x63, 0x54, 0x61, 0x67, 0x22, 0x46, 0x0a, 0x0c, 0x50, 0x70, 0x70, 0x6f, 0x65,
+	0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x22, 0x0a, 0x0d, 0x70,
+	0x70, 0x70, 0x6f, 0x65, 0x5f, 0x73, 0x65, 0x73, 0x73, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x0b, 0x70, 0x70, 0x70, 0x6f, 0x65, 0x53, 0x65, 0x73, 0x73, 0x49, 0x64, 0x22,
+	0xce, 0x01, 0x0a, 0x18, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68,
+	0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x09,
+	0x69, 0x70, 0x76, 0x34, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x48,
+	0x00, 0x52, 0x08, 0x69, 0x70, 0x76, 0x34, 0x41, 0x64, 0x64, 0x72, 0x12, 0x1b, 0x0a, 0x08, 0x6d,
+	0x61, 0x63, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52,
+	0x07, 0x6d, 0x61, 0x63, 0x41, 0x64, 0x64, 0x72, 0x12, 0x2c, 0x0a, 0x05, 0x76, 0x6c, 0x61, 0x6e,
+	0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x2e, 0x61,
+	0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x6c, 0x61, 0x6e, 0x73, 0x48, 0x00, 0x52,
+	0x05, 0x76, 0x6c, 0x61, 0x6e, 0x73, 0x12, 0x42, 0x0a, 0x0d, 0x70, 0x70, 0x70, 0x6f, 0x65, 0x5f,
+	0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e,
+	0x6d, 0x61, 0x70, 0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x70,
+	0x70, 0x6f, 0x65, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x0c, 0x70, 0x70,
+	0x70, 0x6f, 0x65, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x42, 0x04, 0x0a, 0x02, 0x62, 0x79,
+	0x22, 0x58, 0x0a, 0x19, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68,
+	0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3b, 0x0a,
+	0x0b, 0x61, 0x74, 0x74, 0x61, 0x63, 0x68, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e,
+	0x76, 0x31, 0x2e, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x0b, 0x61,
+	0x74, 0x74, 0x61, 0x63, 0x68, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x22, 0x7f, 0x0a, 0x0d, 0x50, 0x65,
+	0x6e, 0x64, 0x69, 0x6e, 0x67, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x34, 0x0a, 0x06, 0x6f,
+	0x62, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x6d, 0x61,
+	0x70, 0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x67, 0x69,
+	0x63, 0x61, 0x6c, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x06, 0x6f, 0x62, 0x6a, 0x65, 0x63,
+	0x74, 0x12, 0x38, 0x0a, 0x0b, 0x77, 0x61, 0x69, 0x74, 0x69, 0x6e, 0x67, 0x5f, 0x66, 0x6f, 0x72,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x2e, 0x61, 0x64,
+	0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x49, 0x64, 0x52,
+	0x0a, 0x77, 0x61, 0x69, 0x74, 0x69, 0x6e, 0x67, 0x46, 0x6f, 0x72, 0x22, 0x1b, 0x0a, 0x19, 0x4c,
+	0x69, 0x73, 0x74, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x54, 0x0a, 0x1a, 0x4c, 0x69, 0x73, 0x74,
+	0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x36, 0x0a, 0x07, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x2e, 0x61,
+	0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x4f,
+	0x62, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x07, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x73, 0x2a, 0x44,
+	0x0a, 0x09, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x19, 0x0a, 0x15, 0x44,
+	0x49, 0x52, 0x45, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49,
+	0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0c, 0x0a, 0x08, 0x55, 0x50, 0x53, 0x54, 0x52, 0x45,
+	0x41, 0x4d, 0x10, 0x01, 0x12, 0x0e, 0x0a, 0x0a, 0x44, 0x4f, 0x57, 0x4e, 0x53, 0x54, 0x52, 0x45,
+	0x41, 0x4d, 0x10, 0x02, 0x32, 0x84, 0x05, 0x0a, 0x05, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x12, 0x62,
+	0x0a, 0x0f, 0x47, 0x65, 0x74, 0x4c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x53, 0x74, 0x61, 0x74,
+	0x65, 0x12, 0x25, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76,
+	0x31, 0x2e, 0x47, 0x65, 0x74, 0x4c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x53, 0x74, 0x61, 0x74,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x26, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x2e,
+	0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x4c, 0x6f, 0x67, 0x69,
+	0x63, 0x61, 0x6c, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x00, 0x12, 0x5f, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x12, 0x24, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69,
+	0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e, 0x6d, 0x61, 0x70,
+	0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x61,
+	0x72, 0x67, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0x00, 0x12, 0x5c, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x6e,
+	0x61, 0x6e, 0x63, 0x65, 0x12, 0x23, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69,
+	0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x6e, 0x61, 0x6e,
+	0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x6d, 0x61, 0x70, 0x72,
+	0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x72, 0x6f,
+	0x76, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x00, 0x12, 0x80, 0x01, 0x0a, 0x19, 0x4c, 0x69, 0x73, 0x74, 0x49, 0x6e, 0x63, 0x6f, 0x6d, 0x70,
+	0x6c, 0x65, 0x74, 0x65, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12,
+	0x2f, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e,
+	0x4c, 0x69, 0x73, 0x74, 0x49, 0x6e, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x41, 0x74,
+	0x74, 0x61, 0x63, 0x68, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x30, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31,
+	0x2e, 0x4c, 0x69, 0x73, 0x74, 0x49, 0x6e, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x41,
+	0x74, 0x74, 0x61, 0x63, 0x68, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x00, 0x12, 0x68, 0x0a, 0x11, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x41, 0x74,
+	0x74, 0x61, 0x63, 0x68, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x27, 0x2e, 0x6d, 0x61, 0x70, 0x72,
+	0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70,
+	0x41, 0x74, 0x74, 0x61, 0x63, 0x68, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x28, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e,
+	0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68, 0x6d,
+	0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x6b,
+	0x0a, 0x12, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x4f, 0x62, 0x6a,
+	0x65, 0x63, 0x74, 0x73, 0x12, 0x28, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69,
+	0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67,
+	0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x29,
+	0x2e, 0x6d, 0x61, 0x70, 0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x4c,
+	0x69, 0x73, 0x74, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x14, 0x5a, 0x12, 0x6d,
+	0x61, 0x70, 0x72, 0x2f, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2f, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x70,
+	0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_admin_proto_rawDescOnce sync.Once
+	file_admin_proto_rawDescData = file_admin_proto_rawDesc
+)
+
+func file_admin_proto_rawDescGZIP() []byte {
+	file_admin_proto_rawDescOnce.Do(func() {
+		file_admin_proto_rawDescData = protoimpl.X.CompressGZIP(file_admin_proto_rawDescData)
+	})
+	return file_admin_proto_rawDescData
+}
+
+var file_admin_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_admin_proto_msgTypes = make([]protoimpl.MessageInfo, 25)
+var file_admin_proto_goTypes = []interface{}{
+	(Direction)(0),                            // 0: mapr.admin.v1.Direction
+	(*ObjectId)(nil),                          // 1: mapr.admin.v1.ObjectId
+	(*IfType)(nil),                            // 2: mapr.admin.v1.IfType
+	(*MyStation)(nil),                         // 3: mapr.admin.v1.MyStation
+	(*Attachment)(nil),                        // 4: mapr.admin.v1.Attachment
+	(*RouteV4)(nil),                           // 5: mapr.admin.v1.RouteV4
+	(*NextHopGroup)(nil),                      // 6: mapr.admin.v1.NextHopGroup
+	(*NextHop)(nil),                           // 7: mapr.admin.v1.NextHop
+	(*PppoePunt)(nil),                         // 8: mapr.admin.v1.PppoePunt
+	(*LogicalObject)(nil),                     // 9: mapr.admin.v1.LogicalObject
+	(*GetLogicalStateRequest)(nil),            // 10: mapr.admin.v1.GetLogicalStateRequest
+	(*GetLogicalStateResponse)(nil),           // 11: mapr.admin.v1.GetLogicalStateResponse
+	(*GetTargetStateRequest)(nil),             // 12: mapr.admin.v1.GetTargetStateRequest
+	(*GetTargetStateResponse)(nil),            // 13: mapr.admin.v1.GetTargetStateResponse
+	(*GetProvenanceRequest)(nil),              // 14: mapr.admin.v1.GetProvenanceRequest
+	(*GetProvenanceResponse)(nil),             // 15: mapr.admin.v1.GetProvenanceResponse
+	(*ListIncompleteAttachmentsRequest)(nil),  // 16: mapr.admin.v1.ListIncompleteAttachmentsRequest
+	(*ListIncompleteAttachmentsResponse)(nil), // 17: mapr.admin.v1.ListIncompleteAttachmentsResponse
+	(*Vlans)(nil),                             // 18: mapr.admin.v1.Vlans
+	(*PppoeSession)(nil),                      // 19: mapr.admin.v1.PppoeSession
+	(*LookupAttachmentsRequest)(nil),          // 20: mapr.admin.v1.LookupAttachmentsRequest
+	(*LookupAttachmentsResponse)(nil),         // 21: mapr.admin.v1.LookupAttachmentsResponse
+	(*PendingObject)(nil),                     // 22: mapr.admin.v1.PendingObject
+	(*ListPendingObjectsRequest)(nil),         // 23: mapr.admin.v1.ListPendingObjectsRequest
+	(*ListPendingObjectsResponse)(nil),        // 24: mapr.admin.v1.ListPendingObjectsResponse
+	(*NextHopGroup_Member)(nil),               // 25: mapr.admin.v1.NextHopGroup.Member
+	(*v1.TableEntry)(nil),                     // 26: p4.v1.TableEntry
+	(*v1.Entity)(nil),                         // 27: p4.v1.Entity
+}
+var file_admin_proto_depIdxs = []int32{
+	0,  // 0: mapr.admin.v1.Attachment.direction:type_name -> mapr.admin.v1.Direction
+	0,  // 1: mapr.admin.v1.RouteV4.direction:type_name -> mapr.admin.v1.Direction
+	25, // 2: mapr.admin.v1.NextHopGroup.members:type_name -> mapr.admin.v1.NextHopGroup.Member
+	1,  // 3: mapr.admin.v1.LogicalObject.id:type_name -> mapr.admin.v1.ObjectId
+	2,  // 4: mapr.admin.v1.LogicalObject.if_type:type_name -> mapr.admin.v1.IfType
+	3,  // 5: mapr.admin.v1.LogicalObject.my_station:type_name -> mapr.admin.v1.MyStation
+	4,  // 6: mapr.admin.v1.LogicalObject.attachment:type_name -> mapr.admin.v1.Attachment
+	5,  // 7: mapr.admin.v1.LogicalObject.route_v4:type_name -> mapr.admin.v1.RouteV4
+	6,  // 8: mapr.admin.v1.LogicalObject.next_hop_group:type_name -> mapr.admin.v1.NextHopGroup
+	7,  // 9: mapr.admin.v1.LogicalObject.next_hop:type_name -> mapr.admin.v1.NextHop
+	26, // 10: mapr.admin.v1.LogicalObject.acl:type_name -> p4.v1.TableEntry
+	8,  // 11: mapr.admin.v1.LogicalObject.pppoe_punt:type_name -> mapr.admin.v1.PppoePunt
+	9,  // 12: mapr.admin.v1.GetLogicalStateResponse.objects:type_name -> mapr.admin.v1.LogicalObject
+	27, // 13: mapr.admin.v1.GetTargetStateResponse.entities:type_name -> p4.v1.Entity
+	1,  // 14: mapr.admin.v1.GetProvenanceRequest.logical:type_name -> mapr.admin.v1.ObjectId
+	27, // 15: mapr.admin.v1.GetProvenanceRequest.target:type_name -> p4.v1.Entity
+	9,  // 16: mapr.admin.v1.GetProvenanceResponse.objects:type_name -> mapr.admin.v1.LogicalObject
+	27, // 17: mapr.admin.v1.GetProvenanceResponse.entities:type_name -> p4.v1.Entity
+	4,  // 18: mapr.admin.v1.ListIncompleteAttachmentsResponse.attachments:type_name -> mapr.admin.v1.Attachment
+	18, // 19: mapr.admin.v1.LookupAttachmentsRequest.vlans:type_name -> mapr.admin.v1.Vlans
+	19, // 20: mapr.admin.v1.LookupAttachmentsRequest.pppoe_session:type_name -> mapr.admin.v1.PppoeSession
+	4,  // 21: mapr.admin.v1.LookupAttachmentsResponse.attachments:type_name -> mapr.admin.v1.Attachment
+	9,  // 22: mapr.admin.v1.PendingObject.object:type_name -> mapr.admin.v1.LogicalObject
+	1,  // 23: mapr.admin.v1.PendingObject.waiting_for:type_name -> mapr.admin.v1.ObjectId
+	22, // 24: mapr.admin.v1.ListPendingObjectsResponse.objects:type_name -> mapr.admin.v1.PendingObject
+	10, // 25: mapr.admin.v1.Admin.GetLogicalState:input_type -> mapr.admin.v1.GetLogicalStateRequest
+	12, // 26: mapr.admin.v1.Admin.GetTargetState:input_type -> mapr.admin.v1.GetTargetStateRequest
+	14, // 27: mapr.admin.v1.Admin.GetProvenance:input_type -> mapr.admin.v1.GetProvenanceRequest
+	16, // 28: mapr.admin.v1.Admin.ListIncompleteAttachments:input_type -> mapr.admin.v1.ListIncompleteAttachmentsRequest
+	20, // 29: mapr.admin.v1.Admin.LookupAttachments:input_type -> mapr.admin.v1.LookupAttachmentsRequest
+	23, // 30: mapr.admin.v1.Admin.ListPendingObjects:input_type -> mapr.admin.v1.ListPendingObjectsRequest
+	11, // 31: mapr.admin.v1.Admin.GetLogicalState:output_type -> mapr.admin.v1.GetLogicalStateResponse
+	13, // 32: mapr.admin.v1.Admin.GetTargetState:output_type -> mapr.admin.v1.GetTargetStateResponse
+	15, // 33: mapr.admin.v1.Admin.GetProvenance:output_type -> mapr.admin.v1.GetProvenanceResponse
+	17, // 34: mapr.admin.v1.Admin.ListIncompleteAttachments:output_type -> mapr.admin.v1.ListIncompleteAttachmentsResponse
+	21, // 35: mapr.admin.v1.Admin.LookupAttachments:output_type -> mapr.admin.v1.LookupAttachmentsResponse
+	24, // 36: mapr.admin.v1.Admin.ListPendingObjects:output_type -> mapr.admin.v1.ListPendingObjectsResponse
+	31, // [31:37] is the sub-list for method output_type
+	25, // [25:31] is the sub-list for method input_type
+	25, // [25:25] is the sub-list for extension type_name
+	25, // [25:25] is the sub-list for extension extendee
+	0,  // [0:25] is the sub-list for field type_name
+}
+
+func init() { file_admin_proto_init() }
+func file_admin_proto_init() {
+	if File_admin_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_admin_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ObjectId); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_admin_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IfType); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_admin_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MyStation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_admin_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Attachment); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_admin_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RouteV4); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_admin_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NextHopGroup); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_admin_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NextHop); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_admin_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PppoePunt); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_admin_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LogicalObject); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_admin_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetLogicalStateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_admin_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetLogicalStateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_admin_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetTargetStateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_admin_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetTargetStateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_admin_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetProvenanceRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_admin_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetProvenanceResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_admin_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListIncompleteAttachmentsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_admin_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListIncompleteAttachmentsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_admin_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Vlans); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_admin_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PppoeSession); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_admin_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LookupAttachmentsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_admin_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LookupAttachmentsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_admin_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PendingObject); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_admin_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListPendingObjectsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_admin_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListPendingObjectsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_admin_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NextHopGroup_Member); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_admin_proto_msgTypes[8].OneofWrappers = []interface{}{
+		(*LogicalObject_IfType)(nil),
+		(*LogicalObject_MyStation)(nil),
+		(*LogicalObject_Attachment)(nil),
+		(*LogicalObject_RouteV4)(nil),
+		(*LogicalObject_NextHopGroup)(nil),
+		(*LogicalObject_NextHop)(nil),
+		(*LogicalObject_Acl)(nil),
+		(*LogicalObject_PppoePunt)(nil),
+	}
+	file_admin_proto_msgTypes[13].OneofWrappers = []interface{}{
+		(*GetProvenanceRequest_Logical)(nil),
+		(*GetProvenanceRequest_Target)(nil),
+	}
+	file_admin_proto_msgTypes[19].OneofWrappers = []interface{}{
+		(*LookupAttachmentsRequest_Ipv4Addr)(nil),
+		(*LookupAttachmentsRequest_MacAddr)(nil),
+		(*LookupAttachmentsRequest_Vlans)(nil),
+		(*LookupAttachmentsRequest_PppoeSession)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_admin_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   25,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_admin_proto_goTypes,
+		DependencyIndexes: file_admin_proto_depIdxs,
+		EnumInfos:         file_admin_proto_enumTypes,
+		MessageInfos:      file_admin_proto_msgTypes,
+	}.Build()
+	File_admin_proto = out.File
+	file_admin_proto_rawDesc = nil
+	file_admin_proto_goTypes = nil
+	file_admin_proto_depIdxs = nil
+}
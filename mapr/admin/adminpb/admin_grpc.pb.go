@@ -0,0 +1,297 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package adminpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// AdminClient is the client API for Admin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AdminClient interface {
+	// Returns the objects of the logical store, i.e., the logical state written by controllers as understood by mapr.
+	GetLogicalState(ctx context.Context, in *GetLogicalStateRequest, opts ...grpc.CallOption) (*GetLogicalStateResponse, error)
+	// Returns the mirror of the target state, i.e., the entities written by mapr to the target.
+	GetTargetState(ctx context.Context, in *GetTargetStateRequest, opts ...grpc.CallOption) (*GetTargetStateResponse, error)
+	// Returns the target entities produced by a logical object, or the logical objects owning a target entity.
+	GetProvenance(ctx context.Context, in *GetProvenanceRequest, opts ...grpc.CallOption) (*GetProvenanceResponse, error)
+	// Returns the attachments missing some fields, hence not programmed on the target.
+	ListIncompleteAttachments(ctx context.Context, in *ListIncompleteAttachmentsRequest, opts ...grpc.CallOption) (*ListIncompleteAttachmentsResponse, error)
+	// Returns the attachments of both directions with the given IPv4 address, MAC address, VLAN tags or PPPoE session,
+	// e.g., to find the line of a subscriber.
+	LookupAttachments(ctx context.Context, in *LookupAttachmentsRequest, opts ...grpc.CallOption) (*LookupAttachmentsResponse, error)
+	// Returns the logical objects not translated yet, as they wait for others, e.g., next hops written before the
+	// MyStation entry of their port.
+	ListPendingObjects(ctx context.Context, in *ListPendingObjectsRequest, opts ...grpc.CallOption) (*ListPendingObjectsResponse, error)
+}
+
+type adminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminClient(cc grpc.ClientConnInterface) AdminClient {
+	return &adminClient{cc}
+}
+
+func (c *adminClient) GetLogicalState(ctx context.Context, in *GetLogicalStateRequest, opts ...grpc.CallOption) (*GetLogicalStateResponse, error) {
+	out := new(GetLogicalStateResponse)
+	err := c.cc.Invoke(ctx, "/mapr.admin.v1.Admin/GetLogicalState", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) GetTargetState(ctx context.Context, in *GetTargetStateRequest, opts ...grpc.CallOption) (*GetTargetStateResponse, error) {
+	out := new(GetTargetStateResponse)
+	err := c.cc.Invoke(ctx, "/mapr.admin.v1.Admin/GetTargetState", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) GetProvenance(ctx context.Context, in *GetProvenanceRequest, opts ...grpc.CallOption) (*GetProvenanceResponse, error) {
+	out := new(GetProvenanceResponse)
+	err := c.cc.Invoke(ctx, "/mapr.admin.v1.Admin/GetProvenance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ListIncompleteAttachments(ctx context.Context, in *ListIncompleteAttachmentsRequest, opts ...grpc.CallOption) (*ListIncompleteAttachmentsResponse, error) {
+	out := new(ListIncompleteAttachmentsResponse)
+	err := c.cc.Invoke(ctx, "/mapr.admin.v1.Admin/ListIncompleteAttachments", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) LookupAttachments(ctx context.Context, in *LookupAttachmentsRequest, opts ...grpc.CallOption) (*LookupAttachmentsResponse, error) {
+	out := new(LookupAttachmentsResponse)
+	err := c.cc.Invoke(ctx, "/mapr.admin.v1.Admin/LookupAttachments", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ListPendingObjects(ctx context.Context, in *ListPendingObjectsRequest, opts ...grpc.CallOption) (*ListPendingObjectsResponse, error) {
+	out := new(ListPendingObjectsResponse)
+	err := c.cc.Invoke(ctx, "/mapr.admin.v1.Admin/ListPendingObjects", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServer is the server API for Admin service.
+// All implementations must embed UnimplementedAdminServer
+// for forward compatibility
+type AdminServer interface {
+	// Returns the objects of the logical store, i.e., the logical state written by controllers as understood by mapr.
+	GetLogicalState(context.Context, *GetLogicalStateRequest) (*GetLogicalStateResponse, error)
+	// Returns the mirror of the target state, i.e., the entities written by mapr to the target.
+	GetTargetState(context.Context, *GetTargetStateRequest) (*GetTargetStateResponse, error)
+	// Returns the target entities produced by a logical object, or the logical objects owning a target entity.
+	GetProvenance(context.Context, *GetProvenanceRequest) (*GetProvenanceResponse, error)
+	// Returns the attachments missing some fields, hence not programmed on the target.
+	ListIncompleteAttachments(context.Context, *ListIncompleteAttachmentsRequest) (*ListIncompleteAttachmentsResponse, error)
+	// Returns the attachments of both directions with the given IPv4 address, MAC address, VLAN tags or PPPoE session,
+	// e.g., to find the line of a subscriber.
+	LookupAttachments(context.Context, *LookupAttachmentsRequest) (*LookupAttachmentsResponse, error)
+	// Returns the logical objects not translated yet, as they wait for others, e.g., next hops written before the
+	// MyStation entry of their port.
+	ListPendingObjects(context.Context, *ListPendingObjectsRequest) (*ListPendingObjectsResponse, error)
+	mustEmbedUnimplementedAdminServer()
+}
+
+// UnimplementedAdminServer must be embedded to have forward compatible implementations.
+type UnimplementedAdminServer struct {
+}
+
+func (UnimplementedAdminServer) GetLogicalState(context.Context, *GetLogicalStateRequest) (*GetLogicalStateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLogicalState not implemented")
+}
+func (UnimplementedAdminServer) GetTargetState(context.Context, *GetTargetStateRequest) (*GetTargetStateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTargetState not implemented")
+}
+func (UnimplementedAdminServer) GetProvenance(context.Context, *GetProvenanceRequest) (*GetProvenanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProvenance not implemented")
+}
+func (UnimplementedAdminServer) ListIncompleteAttachments(context.Context, *ListIncompleteAttachmentsRequest) (*ListIncompleteAttachmentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListIncompleteAttachments not implemented")
+}
+func (UnimplementedAdminServer) LookupAttachments(context.Context, *LookupAttachmentsRequest) (*LookupAttachmentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LookupAttachments not implemented")
+}
+func (UnimplementedAdminServer) ListPendingObjects(context.Context, *ListPendingObjectsRequest) (*ListPendingObjectsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPendingObjects not implemented")
+}
+func (UnimplementedAdminServer) mustEmbedUnimplementedAdminServer() {}
+
+// UnsafeAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServer will
+// result in compilation errors.
+type UnsafeAdminServer interface {
+	mustEmbedUnimplementedAdminServer()
+}
+
+func RegisterAdminServer(s grpc.ServiceRegistrar, srv AdminServer) {
+	s.RegisterService(&Admin_ServiceDesc, srv)
+}
+
+func _Admin_GetLogicalState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLogicalStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).GetLogicalState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mapr.admin.v1.Admin/GetLogicalState",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).GetLogicalState(ctx, req.(*GetLogicalStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_GetTargetState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTargetStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).GetTargetState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mapr.admin.v1.Admin/GetTargetState",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).GetTargetState(ctx, req.(*GetTargetStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_GetProvenance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProvenanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).GetProvenance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mapr.admin.v1.Admin/GetProvenance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).GetProvenance(ctx, req.(*GetProvenanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ListIncompleteAttachments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListIncompleteAttachmentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ListIncompleteAttachments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mapr.admin.v1.Admin/ListIncompleteAttachments",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ListIncompleteAttachments(ctx, req.(*ListIncompleteAttachmentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_LookupAttachments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LookupAttachmentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).LookupAttachments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mapr.admin.v1.Admin/LookupAttachments",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).LookupAttachments(ctx, req.(*LookupAttachmentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ListPendingObjects_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPendingObjectsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ListPendingObjects(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mapr.admin.v1.Admin/ListPendingObjects",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ListPendingObjects(ctx, req.(*ListPendingObjectsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Admin_ServiceDesc is the grpc.ServiceDesc for Admin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Admin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "mapr.admin.v1.Admin",
+	HandlerType: (*AdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetLogicalState",
+			Handler:    _Admin_GetLogicalState_Handler,
+		},
+		{
+			MethodName: "GetTargetState",
+			Handler:    _Admin_GetTargetState_Handler,
+		},
+		{
+			MethodName: "GetProvenance",
+			Handler:    _Admin_GetProvenance_Handler,
+		},
+		{
+			MethodName: "ListIncompleteAttachments",
+			Handler:    _Admin_ListIncompleteAttachments_Handler,
+		},
+		{
+			MethodName: "LookupAttachments",
+			Handler:    _Admin_LookupAttachments_Handler,
+		},
+		{
+			MethodName: "ListPendingObjects",
+			Handler:    _Admin_ListPendingObjects_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "admin.proto",
+}
@@ -0,0 +1,124 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package admin
+
+import (
+	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
+	"mapr/admin/adminpb"
+	"mapr/translate"
+	"sort"
+)
+
+// Conversion of the objects of the LogicalStore to admin messages.
+
+var knownKinds = map[string]bool{
+	translate.ObjectIfType:       true,
+	translate.ObjectMyStation:    true,
+	translate.ObjectAttachment:   true,
+	translate.ObjectRouteV4:      true,
+	translate.ObjectNextHopGroup: true,
+	translate.ObjectNextHop:      true,
+	translate.ObjectAcl:          true,
+	translate.ObjectPppoePunt:    true,
+}
+
+func toObjectId(id translate.ObjectId) *adminpb.ObjectId {
+	return &adminpb.ObjectId{Kind: id.Kind, Key: id.Key}
+}
+
+func fromObjectId(id *adminpb.ObjectId) translate.ObjectId {
+	return translate.ObjectId{Kind: id.GetKind(), Key: id.GetKey()}
+}
+
+func toDirection(d translate.Direction) adminpb.Direction {
+	switch d {
+	case translate.DirectionUpstream:
+		return adminpb.Direction_UPSTREAM
+	case translate.DirectionDownstream:
+		return adminpb.Direction_DOWNSTREAM
+	default:
+		return adminpb.Direction_DIRECTION_UNSPECIFIED
+	}
+}
+
+func toAttachment(a *translate.AttachmentEntry) *adminpb.Attachment {
+	missing := a.MissingFields()
+	return &adminpb.Attachment{
+		Direction:     toDirection(a.Direction),
+		Port:          a.Port,
+		LineId:        a.LineId,
+		STag:          a.STag,
+		CTag:          a.CTag,
+		MacAddr:       a.MacAddr,
+		Ipv4Addr:      a.Ipv4Addr,
+		PppoeSessId:   a.PppoeSessId,
+		Complete:      len(missing) == 0,
+		MissingFields: missing,
+	}
+}
+
+func toNextHopGroup(g *translate.NextHopGroup) *adminpb.NextHopGroup {
+	x := &adminpb.NextHopGroup{Id: g.GroupId, MaxSize: g.MaxSize}
+	for _, m := range g.Members {
+		x.Members = append(x.Members, &adminpb.NextHopGroup_Member{NextHopId: m.MemberId, Weight: m.Weight})
+	}
+	return x
+}
+
+// Returns all the objects of the given store, sorted by kind and key.
+func logicalObjects(s *translate.LogicalStore) []*adminpb.LogicalObject {
+	var objects []*adminpb.LogicalObject
+	add := func(id translate.ObjectId, o *adminpb.LogicalObject) {
+		o.Id = toObjectId(id)
+		objects = append(objects, o)
+	}
+	s.IfTypes.Range(func(_ translate.PortKey, x *translate.IfTypeEntry) {
+		add(x.ObjectId(), &adminpb.LogicalObject{Object: &adminpb.LogicalObject_IfType{
+			IfType: &adminpb.IfType{Port: x.Port, IfType: x.IfType}}})
+	})
+	s.MyStations.Range(func(_ translate.PortKey, x *translate.MyStationEntry) {
+		add(x.ObjectId(), &adminpb.LogicalObject{Object: &adminpb.LogicalObject_MyStation{
+			MyStation: &adminpb.MyStation{Port: x.Port, EthDst: x.EthDst}}})
+	})
+	for _, attachments := range []*translate.AttachmentMap{&s.UpstreamAttachments, &s.DownstreamAttachments} {
+		attachments.Range(func(_ translate.LineIdKey, x *translate.AttachmentEntry) {
+			add(x.ObjectId(), &adminpb.LogicalObject{Object: &adminpb.LogicalObject_Attachment{
+				Attachment: toAttachment(x)}})
+		})
+	}
+	s.UpstreamRoutesV4.Range(func(_ translate.Ipv4LpmKey, x *translate.RouteV4Entry) {
+		add(x.ObjectId(), &adminpb.LogicalObject{Object: &adminpb.LogicalObject_RouteV4{RouteV4: &adminpb.RouteV4{
+			Direction:      toDirection(x.Direction),
+			Ipv4Addr:       x.Ipv4Addr,
+			PrefixLen:      x.PrefixLen,
+			NextHopGroupId: x.NextHopGroupId,
+		}}})
+	})
+	s.UpstreamNextHopGroups.Range(func(_ uint32, x *translate.NextHopGroup) {
+		add(x.ObjectId(), &adminpb.LogicalObject{Object: &adminpb.LogicalObject_NextHopGroup{
+			NextHopGroup: toNextHopGroup(x)}})
+	})
+	s.UpstreamNextHopEntries.Range(func(_ uint32, x *translate.NextHopEntry) {
+		add(x.ObjectId(), &adminpb.LogicalObject{Object: &adminpb.LogicalObject_NextHop{
+			NextHop: &adminpb.NextHop{Id: x.Id, Port: x.Port, MacAddr: x.MacAddr}}})
+	})
+	s.Acl.Range(func(_ translate.AclKey, x *translate.AclEntry) {
+		add(x.ObjectId(), &adminpb.LogicalObject{Object: &adminpb.LogicalObject_Acl{
+			Acl: (*p4v1.TableEntry)(x)}})
+	})
+	s.CtrlPunted.Range(func(_ translate.CtrlPuntedKey, x *translate.PppoePuntedEntry) {
+		add(x.ObjectId(), &adminpb.LogicalObject{Object: &adminpb.LogicalObject_PppoePunt{
+			PppoePunt: &adminpb.PppoePunt{PppoeCode: x.PppoeCode, PppoeProto: x.PppoeProto}}})
+	})
+	sort.Slice(objects, func(i, j int) bool {
+		if objects[i].Id.Kind != objects[j].Id.Kind {
+			return objects[i].Id.Kind < objects[j].Id.Kind
+		}
+		return objects[i].Id.Key < objects[j].Id.Key
+	})
+	return objects
+}
@@ -0,0 +1,312 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package arbitration implements P4Runtime mastership arbitration for the controllers connected to mapr.
+//
+// Controllers open a StreamChannel with mapr and send a MasterArbitrationUpdate with their role and election ID. For
+// each role, the controller with the highest election ID is the primary one, all others are backups. Every time the
+// primary of a role changes, all controllers of that role are notified. Only the primary of a role can write.
+package arbitration
+
+import (
+	"fmt"
+	"github.com/golang/protobuf/proto"
+	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"mapr/logging"
+	"sync"
+	"sync/atomic"
+)
+
+var log = logging.Subsystem("arbitration")
+
+// Max number of messages queued for a client. Messages exceeding it are dropped.
+const ClientQueueSize = 1024
+
+// A controller connected via StreamChannel.
+type Client struct {
+	id uint64
+	// The *clientArbitration of the last arbitration update, nil before the first one. Replaced by the arbitrator
+	// with mu held, and read without locking by the methods of the client, which can be called from any goroutine.
+	arbitration atomic.Value
+	out         chan *p4v1.StreamMessageResponse
+}
+
+// The role and election ID of a client, as per its last arbitration update. Never modified.
+type clientArbitration struct {
+	role       *p4v1.Role
+	electionId *p4v1.Uint128
+}
+
+// Returns the role of the client, nil if the client has not sent an arbitration update yet.
+func (c *Client) role() *p4v1.Role {
+	if x, ok := c.arbitration.Load().(*clientArbitration); ok {
+		return x.role
+	}
+	return nil
+}
+
+// Returns the election ID of the client, nil if the client has not sent an arbitration update yet.
+func (c *Client) electionId() *p4v1.Uint128 {
+	if x, ok := c.arbitration.Load().(*clientArbitration); ok {
+		return x.electionId
+	}
+	return nil
+}
+
+// Returns the channel of messages to be sent to the client.
+func (c *Client) Out() <-chan *p4v1.StreamMessageResponse {
+	return c.out
+}
+
+// Returns the role ID of the client, 0 (default role) if the client has not sent an arbitration update yet.
+func (c *Client) RoleId() uint64 {
+	return c.role().GetId()
+}
+
+func (c *Client) String() string {
+	x, _ := c.arbitration.Load().(*clientArbitration)
+	if x == nil {
+		x = &clientArbitration{}
+	}
+	return fmt.Sprintf("client#%d(role=%d, election_id=%s)", c.id, x.role.GetId(), electionIdString(x.electionId))
+}
+
+// Queues the given message for the client without blocking. Returns false if the message was dropped because the
+// client is not keeping up, i.e., the queue is full.
+func (c *Client) Send(msg *p4v1.StreamMessageResponse) bool {
+	select {
+	case c.out <- msg:
+		return true
+	default:
+		return false
+	}
+}
+
+// Clients of the same role.
+type roleClients struct {
+	clients map[*Client]bool
+	primary *Client
+}
+
+// Arbitrates mastership among the connected clients. Safe for concurrent use.
+type Arbitrator struct {
+	deviceId uint64
+	mu       sync.Mutex
+	lastId   uint64
+	roles    map[uint64]*roleClients
+}
+
+// Creates a new Arbitrator for the given device ID, i.e., the one controllers should use.
+func NewArbitrator(deviceId uint64) *Arbitrator {
+	return &Arbitrator{
+		deviceId: deviceId,
+		roles:    make(map[uint64]*roleClients),
+	}
+}
+
+// Returns a new client, to be called when a StreamChannel is opened.
+func (a *Arbitrator) Connect() *Client {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.lastId++
+	return &Client{
+		id:  a.lastId,
+		out: make(chan *p4v1.StreamMessageResponse, ClientQueueSize),
+	}
+}
+
+// Removes the given client, to be called when its StreamChannel is closed. If the client was primary, the client
+// with the next highest election ID for the same role becomes primary.
+func (a *Arbitrator) Disconnect(c *Client) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if c.electionId() == nil {
+		return
+	}
+	r := a.roles[c.RoleId()]
+	delete(r.clients, c)
+	if len(r.clients) == 0 {
+		delete(a.roles, c.RoleId())
+		return
+	}
+	if r.primary == c {
+		a.electPrimary(r)
+		a.notifyAll(r)
+	}
+}
+
+// Handles an arbitration update sent by the given client. Returns a gRPC status error if the update is invalid, in
+// which case the StreamChannel should be closed.
+func (a *Arbitrator) Arbitrate(c *Client, u *p4v1.MasterArbitrationUpdate) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if u.DeviceId != a.deviceId {
+		return status.Errorf(codes.NotFound, "invalid device ID %d", u.DeviceId)
+	}
+	if u.ElectionId == nil {
+		return status.Error(codes.InvalidArgument, "missing election ID")
+	}
+	if c.electionId() != nil && u.Role.GetId() != c.RoleId() {
+		return status.Errorf(codes.FailedPrecondition, "cannot change role from %d to %d", c.RoleId(), u.Role.GetId())
+	}
+	r, ok := a.roles[u.Role.GetId()]
+	if !ok {
+		r = &roleClients{clients: make(map[*Client]bool)}
+		a.roles[u.Role.GetId()] = r
+	}
+	for other := range r.clients {
+		if other != c && compare(other.electionId(), u.ElectionId) == 0 {
+			return status.Errorf(codes.InvalidArgument, "election ID %s is already used by another client",
+				electionIdString(u.ElectionId))
+		}
+	}
+	c.arbitration.Store(&clientArbitration{role: u.Role, electionId: u.ElectionId})
+	r.clients[c] = true
+	oldPrimary := r.primary
+	a.electPrimary(r)
+	log.Infof("Arbitration: %s, primary is %s", c, r.primary)
+	if r.primary != oldPrimary {
+		a.notifyAll(r)
+	} else {
+		a.notify(r, c)
+	}
+	return nil
+}
+
+// Returns true if the given client is the primary of its role.
+func (a *Arbitrator) IsPrimary(c *Client) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if c.electionId() == nil {
+		return false
+	}
+	return a.roles[c.RoleId()].primary == c
+}
+
+// Returns nil if the given device ID, role and election ID, as found in a WriteRequest, are the ones of the primary
+// client, otherwise a PERMISSION_DENIED gRPC status error (NOT_FOUND for an invalid device ID).
+func (a *Arbitrator) CheckPrimary(deviceId uint64, roleId uint64, electionId *p4v1.Uint128) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if deviceId != a.deviceId {
+		return status.Errorf(codes.NotFound, "invalid device ID %d", deviceId)
+	}
+	r, ok := a.roles[roleId]
+	if !ok || r.primary == nil {
+		return status.Errorf(codes.PermissionDenied, "no primary client for role %d", roleId)
+	}
+	if compare(r.primary.electionId(), electionId) != 0 {
+		return status.Errorf(codes.PermissionDenied, "election ID %s is not primary for role %d",
+			electionIdString(electionId), roleId)
+	}
+	return nil
+}
+
+// Returns the client of the given role with the given election ID, or the primary one if the election ID is nil. Returns
+// nil if there is no such client.
+func (a *Arbitrator) Lookup(roleId uint64, electionId *p4v1.Uint128) *Client {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	r, ok := a.roles[roleId]
+	if !ok {
+		return nil
+	}
+	if electionId == nil {
+		return r.primary
+	}
+	for c := range r.clients {
+		if compare(c.electionId(), electionId) == 0 {
+			return c
+		}
+	}
+	return nil
+}
+
+// Returns the primary clients of all roles.
+func (a *Arbitrator) Primaries() []*Client {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	primaries := make([]*Client, 0, len(a.roles))
+	for _, r := range a.roles {
+		if r.primary != nil {
+			primaries = append(primaries, r.primary)
+		}
+	}
+	return primaries
+}
+
+// Must be called with mu held.
+func (a *Arbitrator) electPrimary(r *roleClients) {
+	r.primary = nil
+	for c := range r.clients {
+		if r.primary == nil || compare(c.electionId(), r.primary.electionId()) > 0 {
+			r.primary = c
+		}
+	}
+}
+
+// Must be called with mu held.
+func (a *Arbitrator) notifyAll(r *roleClients) {
+	for c := range r.clients {
+		a.notify(r, c)
+	}
+}
+
+// Must be called with mu held.
+func (a *Arbitrator) notify(r *roleClients, c *Client) {
+	st := status.New(codes.OK, "")
+	if r.primary != c {
+		st = status.New(codes.AlreadyExists, "not primary")
+	}
+	msg := &p4v1.StreamMessageResponse{
+		Update: &p4v1.StreamMessageResponse_Arbitration{Arbitration: &p4v1.MasterArbitrationUpdate{
+			DeviceId:   a.deviceId,
+			Role:       c.role(),
+			ElectionId: proto.Clone(r.primary.electionId()).(*p4v1.Uint128),
+			Status:     st.Proto(),
+		}},
+	}
+	if !c.Send(msg) {
+		log.Warnf("Arbitration: dropping update for %s, queue is full", c)
+	}
+}
+
+// Returns -1, 0 or 1 if x is less than, equal to or greater than y, respectively. A nil election ID is less than any
+// other.
+func compare(x, y *p4v1.Uint128) int {
+	switch {
+	case x == nil && y == nil:
+		return 0
+	case x == nil:
+		return -1
+	case y == nil:
+		return 1
+	case x.High != y.High:
+		if x.High < y.High {
+			return -1
+		}
+		return 1
+	case x.Low != y.Low:
+		if x.Low < y.Low {
+			return -1
+		}
+		return 1
+	default:
+		return 0
+	}
+}
+
+func electionIdString(e *p4v1.Uint128) string {
+	if e == nil {
+		return "<none>"
+	}
+	if e.High == 0 {
+		return fmt.Sprintf("%d", e.Low)
+	}
+	return fmt.Sprintf("%d:%d", e.High, e.Low)
+}
@@ -0,0 +1,146 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package arbitration
+
+import (
+	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+const mockDeviceId = 1
+
+func arbitrationUpdate(roleId uint64, electionId uint64) *p4v1.MasterArbitrationUpdate {
+	u := &p4v1.MasterArbitrationUpdate{
+		DeviceId:   mockDeviceId,
+		ElectionId: &p4v1.Uint128{Low: electionId},
+	}
+	if roleId != 0 {
+		u.Role = &p4v1.Role{Id: roleId}
+	}
+	return u
+}
+
+// Returns the status code of the last arbitration update queued for the given client, and the election ID therein.
+func lastArbitration(t *testing.T, c *Client) (codes.Code, uint64) {
+	var last *p4v1.MasterArbitrationUpdate
+	for {
+		select {
+		case msg := <-c.Out():
+			last = msg.GetArbitration()
+			continue
+		default:
+		}
+		break
+	}
+	require.NotNil(t, last, "expected arbitration update for %s", c)
+	return codes.Code(last.Status.Code), last.ElectionId.Low
+}
+
+func Test_Arbitrator_Primary(t *testing.T) {
+	a := NewArbitrator(mockDeviceId)
+	c1 := a.Connect()
+	c2 := a.Connect()
+
+	require.NoError(t, a.Arbitrate(c1, arbitrationUpdate(0, 10)))
+	code, electionId := lastArbitration(t, c1)
+	assert.Equal(t, codes.OK, code)
+	assert.Equal(t, uint64(10), electionId)
+
+	require.NoError(t, a.Arbitrate(c2, arbitrationUpdate(0, 20)))
+	code, _ = lastArbitration(t, c1)
+	assert.Equal(t, codes.AlreadyExists, code, "c1 should be notified it's no longer primary")
+	code, electionId = lastArbitration(t, c2)
+	assert.Equal(t, codes.OK, code)
+	assert.Equal(t, uint64(20), electionId)
+
+	assert.False(t, a.IsPrimary(c1))
+	assert.True(t, a.IsPrimary(c2))
+	assert.NoError(t, a.CheckPrimary(mockDeviceId, 0, &p4v1.Uint128{Low: 20}))
+	assert.Equal(t, codes.PermissionDenied, status.Code(a.CheckPrimary(mockDeviceId, 0, &p4v1.Uint128{Low: 10})))
+	assert.Equal(t, codes.PermissionDenied, status.Code(a.CheckPrimary(mockDeviceId, 0, nil)))
+	assert.Equal(t, codes.NotFound, status.Code(a.CheckPrimary(mockDeviceId+1, 0, &p4v1.Uint128{Low: 20})))
+
+	a.Disconnect(c2)
+	code, electionId = lastArbitration(t, c1)
+	assert.Equal(t, codes.OK, code, "c1 should become primary when c2 disconnects")
+	assert.Equal(t, uint64(10), electionId)
+	assert.NoError(t, a.CheckPrimary(mockDeviceId, 0, &p4v1.Uint128{Low: 10}))
+}
+
+func Test_Arbitrator_Roles(t *testing.T) {
+	a := NewArbitrator(mockDeviceId)
+	c1 := a.Connect()
+	c2 := a.Connect()
+	require.NoError(t, a.Arbitrate(c1, arbitrationUpdate(1, 10)))
+	require.NoError(t, a.Arbitrate(c2, arbitrationUpdate(2, 10)))
+
+	assert.True(t, a.IsPrimary(c1))
+	assert.True(t, a.IsPrimary(c2))
+	assert.ElementsMatch(t, []*Client{c1, c2}, a.Primaries())
+	assert.NoError(t, a.CheckPrimary(mockDeviceId, 1, &p4v1.Uint128{Low: 10}))
+	assert.Equal(t, codes.PermissionDenied, status.Code(a.CheckPrimary(mockDeviceId, 3, &p4v1.Uint128{Low: 10})))
+	assert.Equal(t, codes.FailedPrecondition, status.Code(a.Arbitrate(c1, arbitrationUpdate(2, 11))),
+		"role cannot change")
+}
+
+func Test_Arbitrator_Invalid(t *testing.T) {
+	a := NewArbitrator(mockDeviceId)
+	c1 := a.Connect()
+	c2 := a.Connect()
+	require.NoError(t, a.Arbitrate(c1, arbitrationUpdate(0, 10)))
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(a.Arbitrate(c2, arbitrationUpdate(0, 10))),
+		"duplicate election ID")
+	u := arbitrationUpdate(0, 20)
+	u.DeviceId = mockDeviceId + 1
+	assert.Equal(t, codes.NotFound, status.Code(a.Arbitrate(c2, u)))
+	u = arbitrationUpdate(0, 20)
+	u.ElectionId = nil
+	assert.Equal(t, codes.InvalidArgument, status.Code(a.Arbitrate(c2, u)))
+	assert.False(t, a.IsPrimary(c2))
+}
+
+func Test_Arbitrator_Lookup(t *testing.T) {
+	a := NewArbitrator(mockDeviceId)
+	c1 := a.Connect()
+	c2 := a.Connect()
+	require.NoError(t, a.Arbitrate(c1, arbitrationUpdate(1, 10)))
+	require.NoError(t, a.Arbitrate(c2, arbitrationUpdate(1, 20)))
+
+	assert.Equal(t, c2, a.Lookup(1, nil))
+	assert.Equal(t, c1, a.Lookup(1, &p4v1.Uint128{Low: 10}))
+	assert.Nil(t, a.Lookup(1, &p4v1.Uint128{Low: 30}))
+	assert.Nil(t, a.Lookup(2, nil))
+}
+
+// Clients can be read from other goroutines, e.g., the one of the target session, while they are arbitrated.
+func Test_Client_Concurrent(t *testing.T) {
+	a := NewArbitrator(mockDeviceId)
+	c := a.Connect()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := uint64(1); i <= 1000; i++ {
+			assert.NoError(t, a.Arbitrate(c, arbitrationUpdate(1, i)))
+			<-c.Out()
+		}
+	}()
+	for {
+		select {
+		case <-done:
+			assert.Equal(t, "client#1(role=1, election_id=1000)", c.String())
+			return
+		default:
+		}
+		assert.Contains(t, []uint64{0, 1}, c.RoleId())
+		assert.Contains(t, c.String(), "client#1")
+	}
+}
@@ -0,0 +1,168 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package codec provides the building blocks used by the typed P4Runtime entity codecs generated by codec/gen.
+package codec
+
+import (
+	"fmt"
+	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
+)
+
+//go:generate go run ./gen -p4info ../../p4src/build/p4info.txt -pkg translate -o ../translate/p4info_codec.go
+//go:generate go run ./gen -p4info ../p4c-out/fabric/p4info.txt -pkg fabric -o ../fabric/p4info_codec.go
+
+// An action with typed parameters that can be converted to and from a P4Runtime Action.
+type Action interface {
+	// Returns the P4Runtime Action equivalent to this one.
+	ToAction() *p4v1.Action
+	// Populates this action with the content of the given P4Runtime Action. Returns an error if the action ID or any
+	// of the param IDs are not the expected ones.
+	FromAction(a *p4v1.Action) error
+}
+
+// A ternary match value.
+type Ternary struct {
+	Value []byte
+	Mask  []byte
+}
+
+func (t Ternary) String() string {
+	return fmt.Sprintf("%x&&&%x", t.Value, t.Mask)
+}
+
+// A longest-prefix match value.
+type Lpm struct {
+	Value     []byte
+	PrefixLen int32
+}
+
+func (l Lpm) String() string {
+	return fmt.Sprintf("%x/%d", l.Value, l.PrefixLen)
+}
+
+// A range match value.
+type Range struct {
+	Low  []byte
+	High []byte
+}
+
+func (r Range) String() string {
+	return fmt.Sprintf("%x..%x", r.Low, r.High)
+}
+
+// Returns an exact FieldMatch for the given field ID and value.
+func ExactMatch(fieldId uint32, value []byte) *p4v1.FieldMatch {
+	return &p4v1.FieldMatch{
+		FieldId: fieldId,
+		FieldMatchType: &p4v1.FieldMatch_Exact_{
+			Exact: &p4v1.FieldMatch_Exact{
+				Value: value,
+			}}}
+}
+
+// Returns a ternary FieldMatch for the given field ID and value.
+func TernaryMatch(fieldId uint32, t *Ternary) *p4v1.FieldMatch {
+	return &p4v1.FieldMatch{
+		FieldId: fieldId,
+		FieldMatchType: &p4v1.FieldMatch_Ternary_{
+			Ternary: &p4v1.FieldMatch_Ternary{
+				Value: t.Value,
+				Mask:  t.Mask,
+			}}}
+}
+
+// Returns an LPM FieldMatch for the given field ID and value.
+func LpmMatch(fieldId uint32, l *Lpm) *p4v1.FieldMatch {
+	return &p4v1.FieldMatch{
+		FieldId: fieldId,
+		FieldMatchType: &p4v1.FieldMatch_Lpm{
+			Lpm: &p4v1.FieldMatch_LPM{
+				Value:     l.Value,
+				PrefixLen: l.PrefixLen,
+			}}}
+}
+
+// Returns a range FieldMatch for the given field ID and value.
+func RangeMatch(fieldId uint32, r *Range) *p4v1.FieldMatch {
+	return &p4v1.FieldMatch{
+		FieldId: fieldId,
+		FieldMatchType: &p4v1.FieldMatch_Range_{
+			Range: &p4v1.FieldMatch_Range{
+				Low:  r.Low,
+				High: r.High,
+			}}}
+}
+
+// Returns an optional FieldMatch for the given field ID and value.
+func OptionalMatch(fieldId uint32, value []byte) *p4v1.FieldMatch {
+	return &p4v1.FieldMatch{
+		FieldId: fieldId,
+		FieldMatchType: &p4v1.FieldMatch_Optional_{
+			Optional: &p4v1.FieldMatch_Optional{
+				Value: value,
+			}}}
+}
+
+// Returns the value of the given exact FieldMatch, or an error if m is not exact.
+func GetExact(m *p4v1.FieldMatch) ([]byte, error) {
+	if m.GetExact() == nil {
+		return nil, fmt.Errorf("expected exact match for field ID %d but found %v", m.FieldId, m)
+	}
+	return m.GetExact().Value, nil
+}
+
+// Returns the value of the given ternary FieldMatch, or an error if m is not ternary.
+func GetTernary(m *p4v1.FieldMatch) (*Ternary, error) {
+	if m.GetTernary() == nil {
+		return nil, fmt.Errorf("expected ternary match for field ID %d but found %v", m.FieldId, m)
+	}
+	return &Ternary{Value: m.GetTernary().Value, Mask: m.GetTernary().Mask}, nil
+}
+
+// Returns the value of the given LPM FieldMatch, or an error if m is not LPM.
+func GetLpm(m *p4v1.FieldMatch) (*Lpm, error) {
+	if m.GetLpm() == nil {
+		return nil, fmt.Errorf("expected LPM match for field ID %d but found %v", m.FieldId, m)
+	}
+	return &Lpm{Value: m.GetLpm().Value, PrefixLen: m.GetLpm().PrefixLen}, nil
+}
+
+// Returns the value of the given range FieldMatch, or an error if m is not range.
+func GetRange(m *p4v1.FieldMatch) (*Range, error) {
+	if m.GetRange() == nil {
+		return nil, fmt.Errorf("expected range match for field ID %d but found %v", m.FieldId, m)
+	}
+	return &Range{Low: m.GetRange().Low, High: m.GetRange().High}, nil
+}
+
+// Returns the value of the given optional FieldMatch, or an error if m is not optional.
+func GetOptional(m *p4v1.FieldMatch) ([]byte, error) {
+	if m.GetOptional() == nil {
+		return nil, fmt.Errorf("expected optional match for field ID %d but found %v", m.FieldId, m)
+	}
+	return m.GetOptional().Value, nil
+}
+
+// Wraps the given action in a P4Runtime TableAction. Returns nil if a is nil.
+func DirectTableAction(a Action) *p4v1.TableAction {
+	if a == nil {
+		return nil
+	}
+	return &p4v1.TableAction{Type: &p4v1.TableAction_Action{Action: a.ToAction()}}
+}
+
+// Returns a P4Runtime TableAction referring to the given action profile group or member, preferring the group if both
+// are non-zero. Returns nil if both are zero.
+func IndirectTableAction(groupId uint32, memberId uint32) *p4v1.TableAction {
+	if groupId != 0 {
+		return &p4v1.TableAction{Type: &p4v1.TableAction_ActionProfileGroupId{ActionProfileGroupId: groupId}}
+	}
+	if memberId != 0 {
+		return &p4v1.TableAction{Type: &p4v1.TableAction_ActionProfileMemberId{ActionProfileMemberId: memberId}}
+	}
+	return nil
+}
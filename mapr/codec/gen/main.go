@@ -0,0 +1,466 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Command gen reads a P4Info and emits typed Go structs for each table, action and action profile, with methods to
+// convert them to and from the corresponding P4Runtime entities.
+//
+// The generated code refers to the ID constants produced by util/go-gen-p4-const.py, which are expected to be found in
+// the same package.
+//
+// Usage:
+//
+//	go run mapr/codec/gen -p4info p4info.txt -pkg translate -o translate/p4info_codec.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"github.com/golang/protobuf/proto"
+	p4confv1 "github.com/p4lang/p4runtime/go/p4/config/v1"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+var (
+	p4InfoPath = flag.String("p4info", "",
+		"Path to P4Info file, in text (.txt) or binary (.bin) format")
+	pkgName = flag.String("pkg", "",
+		"Name of the Go package of the generated file")
+	outPath = flag.String("o", "-",
+		"Path to output file, or - for stdout")
+)
+
+const header = `/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Code generated by mapr/codec/gen from %s. DO NOT EDIT.
+
+package %s
+
+import (
+	"fmt"
+	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
+	"mapr/codec"
+)
+
+`
+
+var (
+	firstCapRe = regexp.MustCompile("(.)([A-Z][a-z]+)")
+	allCapRe   = regexp.MustCompile("([a-z0-9])([A-Z])")
+)
+
+// Converts a P4 name to the CamelCase identifier used by util/go-gen-p4-const.py, e.g.
+// "IngressPipe.upstream.set_line" -> "IngressPipeUpstreamSetLine".
+func camel(name string) string {
+	s := firstCapRe.ReplaceAllString(name, "${1}_${2}")
+	s = allCapRe.ReplaceAllString(s, "${1}_${2}")
+	// Emulate str.title(), then drop separators.
+	var out strings.Builder
+	prevCased := false
+	for _, r := range s {
+		cased := unicode.IsLetter(r)
+		switch {
+		case r == '_' || r == '.':
+		case cased && !prevCased:
+			out.WriteRune(unicode.ToUpper(r))
+		case cased:
+			out.WriteRune(unicode.ToLower(r))
+		default:
+			out.WriteRune(r)
+		}
+		prevCased = cased
+	}
+	return out.String()
+}
+
+type generator struct {
+	p4info  *p4confv1.P4Info
+	actions map[uint32]*p4confv1.Action
+	buf     bytes.Buffer
+}
+
+func (g *generator) p(format string, args ...interface{}) {
+	_, _ = fmt.Fprintf(&g.buf, format, args...)
+	g.buf.WriteByte('\n')
+}
+
+func actionType(a *p4confv1.Action) string {
+	return camel(a.Preamble.Name) + "Action"
+}
+
+func (g *generator) genAction(a *p4confv1.Action) {
+	name := camel(a.Preamble.Name)
+	typ := actionType(a)
+	g.p("// Action %s", a.Preamble.Name)
+	g.p("type %s struct {", typ)
+	for _, prm := range a.Params {
+		g.p("\t%s []byte // bitwidth %d", camel(prm.Name), prm.Bitwidth)
+	}
+	g.p("}")
+	g.p("")
+	g.p("func (a *%s) ToAction() *p4v1.Action {", typ)
+	g.p("\tact := &p4v1.Action{ActionId: Action_%s}", name)
+	for _, prm := range a.Params {
+		f := camel(prm.Name)
+		g.p("\tif a.%s != nil {", f)
+		g.p("\t\tact.Params = append(act.Params, &p4v1.Action_Param{ParamId: ActionParam_%s_%s, Value: a.%s})", name, f, f)
+		g.p("\t}")
+	}
+	g.p("\treturn act")
+	g.p("}")
+	g.p("")
+	g.p("func (a *%s) FromAction(act *p4v1.Action) error {", typ)
+	g.p("\t*a = %s{}", typ)
+	g.p("\tif act == nil || act.ActionId != Action_%s {", name)
+	g.p("\t\treturn fmt.Errorf(\"invalid Action %%s\", act)")
+	g.p("\t}")
+	if len(a.Params) > 0 {
+		g.p("\tfor _, p := range act.Params {")
+		g.p("\t\tswitch p.ParamId {")
+		for _, prm := range a.Params {
+			f := camel(prm.Name)
+			g.p("\t\tcase ActionParam_%s_%s:", name, f)
+			g.p("\t\t\ta.%s = p.Value", f)
+		}
+		g.p("\t\tdefault:")
+		g.p("\t\t\treturn fmt.Errorf(\"invalid %%T ID %%d\", p, p.ParamId)")
+		g.p("\t\t}")
+		g.p("\t}")
+	} else {
+		g.p("\tif len(act.Params) > 0 {")
+		g.p("\t\treturn fmt.Errorf(\"invalid %%T ID %%d\", act.Params[0], act.Params[0].ParamId)")
+		g.p("\t}")
+	}
+	g.p("\treturn nil")
+	g.p("}")
+	g.p("")
+}
+
+func needsPriority(t *p4confv1.Table) bool {
+	for _, mf := range t.MatchFields {
+		switch mf.GetMatchType() {
+		case p4confv1.MatchField_TERNARY, p4confv1.MatchField_RANGE, p4confv1.MatchField_OPTIONAL:
+			return true
+		}
+	}
+	return false
+}
+
+func matchFieldType(mf *p4confv1.MatchField) (string, error) {
+	switch mf.GetMatchType() {
+	case p4confv1.MatchField_EXACT, p4confv1.MatchField_OPTIONAL:
+		return "[]byte", nil
+	case p4confv1.MatchField_TERNARY:
+		return "*codec.Ternary", nil
+	case p4confv1.MatchField_LPM:
+		return "*codec.Lpm", nil
+	case p4confv1.MatchField_RANGE:
+		return "*codec.Range", nil
+	default:
+		return "", fmt.Errorf("unsupported match type %v for field %s", mf.GetMatchType(), mf.Name)
+	}
+}
+
+func matchTypeName(mf *p4confv1.MatchField) string {
+	switch mf.GetMatchType() {
+	case p4confv1.MatchField_EXACT:
+		return "Exact"
+	case p4confv1.MatchField_TERNARY:
+		return "Ternary"
+	case p4confv1.MatchField_LPM:
+		return "Lpm"
+	case p4confv1.MatchField_RANGE:
+		return "Range"
+	default:
+		return "Optional"
+	}
+}
+
+func (g *generator) genTable(t *p4confv1.Table) error {
+	name := camel(t.Preamble.Name)
+	typ := name + "Entry"
+	indirect := t.ImplementationId != 0
+	g.p("// Table %s", t.Preamble.Name)
+	g.p("type %s struct {", typ)
+	for _, mf := range t.MatchFields {
+		ft, err := matchFieldType(mf)
+		if err != nil {
+			return fmt.Errorf("table %s: %v", t.Preamble.Name, err)
+		}
+		g.p("\t%s %s // %s, bitwidth %d", camel(mf.Name), ft, strings.ToLower(mf.GetMatchType().String()), mf.Bitwidth)
+	}
+	if needsPriority(t) {
+		g.p("\tPriority int32")
+	}
+	if indirect {
+		g.p("\tActionProfileGroupId uint32")
+		g.p("\tActionProfileMemberId uint32")
+	} else {
+		var refs []string
+		for _, ref := range t.ActionRefs {
+			if a := g.actions[ref.Id]; a != nil {
+				refs = append(refs, actionType(a))
+			}
+		}
+		g.p("\t// One of: %s", strings.Join(refs, ", "))
+		g.p("\tAction codec.Action")
+	}
+	g.p("}")
+	g.p("")
+
+	// ToTableEntry
+	g.p("func (e *%s) ToTableEntry() *p4v1.TableEntry {", typ)
+	g.p("\tt := &p4v1.TableEntry{TableId: Table_%s}", name)
+	for _, mf := range t.MatchFields {
+		f := camel(mf.Name)
+		g.p("\tif e.%s != nil {", f)
+		g.p("\t\tt.Match = append(t.Match, codec.%sMatch(Hdr_%s_%s, e.%s))", matchTypeName(mf), name, f, f)
+		g.p("\t}")
+	}
+	if needsPriority(t) {
+		g.p("\tt.Priority = e.Priority")
+	}
+	if indirect {
+		g.p("\tt.Action = codec.IndirectTableAction(e.ActionProfileGroupId, e.ActionProfileMemberId)")
+	} else {
+		g.p("\tt.Action = codec.DirectTableAction(e.Action)")
+	}
+	g.p("\treturn t")
+	g.p("}")
+	g.p("")
+
+	// FromTableEntry
+	g.p("func (e *%s) FromTableEntry(t *p4v1.TableEntry) error {", typ)
+	g.p("\t*e = %s{}", typ)
+	g.p("\tif t.TableId != Table_%s {", name)
+	g.p("\t\treturn fmt.Errorf(\"invalid table ID %%d\", t.TableId)")
+	g.p("\t}")
+	g.p("\tvar err error")
+	g.p("\tfor _, m := range t.Match {")
+	g.p("\t\tswitch m.FieldId {")
+	for _, mf := range t.MatchFields {
+		f := camel(mf.Name)
+		g.p("\t\tcase Hdr_%s_%s:", name, f)
+		g.p("\t\t\te.%s, err = codec.Get%s(m)", f, matchTypeName(mf))
+	}
+	g.p("\t\tdefault:")
+	g.p("\t\t\terr = fmt.Errorf(\"invalid %%T ID %%d\", m, m.FieldId)")
+	g.p("\t\t}")
+	g.p("\t\tif err != nil {")
+	g.p("\t\t\treturn err")
+	g.p("\t\t}")
+	g.p("\t}")
+	if needsPriority(t) {
+		g.p("\te.Priority = t.Priority")
+	}
+	if indirect {
+		g.p("\tswitch a := t.GetAction().GetType().(type) {")
+		g.p("\tcase nil:")
+		g.p("\tcase *p4v1.TableAction_ActionProfileGroupId:")
+		g.p("\t\te.ActionProfileGroupId = a.ActionProfileGroupId")
+		g.p("\tcase *p4v1.TableAction_ActionProfileMemberId:")
+		g.p("\t\te.ActionProfileMemberId = a.ActionProfileMemberId")
+		g.p("\tdefault:")
+		g.p("\t\treturn fmt.Errorf(\"invalid Action %%s\", t.GetAction())")
+		g.p("\t}")
+	} else {
+		g.p("\tact := t.GetAction().GetAction()")
+		g.p("\tif act == nil {")
+		g.p("\t\tif t.GetAction() != nil {")
+		g.p("\t\t\treturn fmt.Errorf(\"invalid Action %%s\", t.GetAction())")
+		g.p("\t\t}")
+		g.p("\t\treturn nil")
+		g.p("\t}")
+		g.p("\tswitch act.ActionId {")
+		for _, ref := range t.ActionRefs {
+			a := g.actions[ref.Id]
+			if a == nil {
+				continue
+			}
+			g.p("\tcase Action_%s:", camel(a.Preamble.Name))
+			g.p("\t\te.Action = &%s{}", actionType(a))
+		}
+		g.p("\tdefault:")
+		g.p("\t\treturn fmt.Errorf(\"invalid Action %%s\", t.GetAction())")
+		g.p("\t}")
+		g.p("\treturn e.Action.FromAction(act)")
+		g.p("}")
+		g.p("")
+		return nil
+	}
+	g.p("\treturn nil")
+	g.p("}")
+	g.p("")
+	return nil
+}
+
+func (g *generator) genActionProfile(ap *p4confv1.ActionProfile) {
+	name := camel(ap.Preamble.Name)
+	// Collect the actions that members can use, i.e., the union of the actions of all tables implemented by ap.
+	refs := make(map[uint32]bool)
+	for _, tid := range ap.TableIds {
+		for _, t := range g.p4info.Tables {
+			if t.Preamble.Id != tid {
+				continue
+			}
+			for _, ref := range t.ActionRefs {
+				if ref.Scope != p4confv1.ActionRef_DEFAULT_ONLY {
+					refs[ref.Id] = true
+				}
+			}
+		}
+	}
+	var actionIds []uint32
+	for id := range refs {
+		if g.actions[id] != nil {
+			actionIds = append(actionIds, id)
+		}
+	}
+	sort.Slice(actionIds, func(i, j int) bool { return actionIds[i] < actionIds[j] })
+
+	typ := name + "Member"
+	var names []string
+	for _, id := range actionIds {
+		names = append(names, actionType(g.actions[id]))
+	}
+	g.p("// Member of action profile %s", ap.Preamble.Name)
+	g.p("type %s struct {", typ)
+	g.p("\tMemberId uint32")
+	g.p("\t// One of: %s", strings.Join(names, ", "))
+	g.p("\tAction codec.Action")
+	g.p("}")
+	g.p("")
+	g.p("func (m *%s) ToActionProfileMember() *p4v1.ActionProfileMember {", typ)
+	g.p("\tmember := &p4v1.ActionProfileMember{ActionProfileId: ActionProfile_%s, MemberId: m.MemberId}", name)
+	g.p("\tif m.Action != nil {")
+	g.p("\t\tmember.Action = m.Action.ToAction()")
+	g.p("\t}")
+	g.p("\treturn member")
+	g.p("}")
+	g.p("")
+	g.p("func (m *%s) FromActionProfileMember(member *p4v1.ActionProfileMember) error {", typ)
+	g.p("\t*m = %s{}", typ)
+	g.p("\tif member.ActionProfileId != ActionProfile_%s {", name)
+	g.p("\t\treturn fmt.Errorf(\"invalid action profile ID %%d\", member.ActionProfileId)")
+	g.p("\t}")
+	g.p("\tm.MemberId = member.MemberId")
+	g.p("\tif member.Action == nil {")
+	g.p("\t\treturn nil")
+	g.p("\t}")
+	g.p("\tswitch member.Action.ActionId {")
+	for _, id := range actionIds {
+		a := g.actions[id]
+		g.p("\tcase Action_%s:", camel(a.Preamble.Name))
+		g.p("\t\tm.Action = &%s{}", actionType(a))
+	}
+	g.p("\tdefault:")
+	g.p("\t\treturn fmt.Errorf(\"invalid Action %%s\", member.Action)")
+	g.p("\t}")
+	g.p("\treturn m.Action.FromAction(member.Action)")
+	g.p("}")
+	g.p("")
+
+	typ = name + "Group"
+	g.p("// Group of action profile %s", ap.Preamble.Name)
+	g.p("type %s struct {", typ)
+	g.p("\tGroupId uint32")
+	g.p("\tMembers []*p4v1.ActionProfileGroup_Member")
+	g.p("\tMaxSize int32")
+	g.p("}")
+	g.p("")
+	g.p("func (g *%s) ToActionProfileGroup() *p4v1.ActionProfileGroup {", typ)
+	g.p("\treturn &p4v1.ActionProfileGroup{")
+	g.p("\t\tActionProfileId: ActionProfile_%s,", name)
+	g.p("\t\tGroupId: g.GroupId,")
+	g.p("\t\tMembers: g.Members,")
+	g.p("\t\tMaxSize: g.MaxSize,")
+	g.p("\t}")
+	g.p("}")
+	g.p("")
+	g.p("func (g *%s) FromActionProfileGroup(group *p4v1.ActionProfileGroup) error {", typ)
+	g.p("\t*g = %s{}", typ)
+	g.p("\tif group.ActionProfileId != ActionProfile_%s {", name)
+	g.p("\t\treturn fmt.Errorf(\"invalid action profile ID %%d\", group.ActionProfileId)")
+	g.p("\t}")
+	g.p("\tg.GroupId = group.GroupId")
+	g.p("\tg.Members = group.Members")
+	g.p("\tg.MaxSize = group.MaxSize")
+	g.p("\treturn nil")
+	g.p("}")
+	g.p("")
+}
+
+func (g *generator) generate(source string, pkg string) ([]byte, error) {
+	g.actions = make(map[uint32]*p4confv1.Action)
+	for _, a := range g.p4info.Actions {
+		g.actions[a.Preamble.Id] = a
+	}
+	_, _ = fmt.Fprintf(&g.buf, header, source, pkg)
+	for _, a := range g.p4info.Actions {
+		g.genAction(a)
+	}
+	for _, t := range g.p4info.Tables {
+		if err := g.genTable(t); err != nil {
+			return nil, err
+		}
+	}
+	for _, ap := range g.p4info.ActionProfiles {
+		g.genActionProfile(ap)
+	}
+	return format.Source(g.buf.Bytes())
+}
+
+func readP4Info(path string) (*p4confv1.P4Info, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	p4info := &p4confv1.P4Info{}
+	if strings.HasSuffix(path, ".txt") {
+		err = proto.UnmarshalText(string(b), p4info)
+	} else {
+		err = proto.Unmarshal(b, p4info)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return p4info, nil
+}
+
+func main() {
+	flag.Parse()
+	if *p4InfoPath == "" || *pkgName == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	p4info, err := readP4Info(*p4InfoPath)
+	if err != nil {
+		log.Fatalf("Unable to read P4Info: %v", err)
+	}
+	g := &generator{p4info: p4info}
+	src, err := g.generate(*p4InfoPath, *pkgName)
+	if err != nil {
+		log.Fatalf("Unable to generate code: %v", err)
+	}
+	if *outPath == "-" {
+		_, _ = os.Stdout.Write(src)
+		return
+	}
+	if err := ioutil.WriteFile(*outPath, src, 0644); err != nil {
+		log.Fatalf("Unable to write output: %v", err)
+	}
+}
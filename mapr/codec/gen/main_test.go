@@ -0,0 +1,62 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"testing"
+)
+
+func Test_camel(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"IngressPipe.if_types", "IngressPipeIfTypes"},
+		{"IngressPipe.upstream.routes_v4", "IngressPipeUpstreamRoutesV4"},
+		{"FabricIngress.bng_ingress.t_line_map", "FabricIngressBngIngressTLineMap"},
+		{"FabricIngress.next.hashed_selector", "FabricIngressNextHashedSelector"},
+		{"set_pppoe_attachment_v4", "SetPppoeAttachmentV4"},
+		{"ipv4_dst", "Ipv4Dst"},
+		{"NoAction", "NoAction"},
+		{"nop", "Nop"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, camel(tt.name), "camel() should match util/go-gen-p4-const.py")
+		})
+	}
+}
+
+// The generated files checked in are the golden files: they must be regenerated with go generate in mapr/codec when
+// the generator or the P4Info change.
+func Test_generator_generate(t *testing.T) {
+	tests := []struct {
+		name string
+		// Relative to mapr/codec, as in the go:generate directives.
+		p4info string
+		pkg    string
+		golden string
+	}{
+		{"logical", "../../p4src/build/p4info.txt", "translate", "../../translate/p4info_codec.go"},
+		{"fabric", "../p4c-out/fabric/p4info.txt", "fabric", "../../fabric/p4info_codec.go"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p4info, err := readP4Info("../" + tt.p4info)
+			require.NoError(t, err)
+			g := &generator{p4info: p4info}
+			src, err := g.generate(tt.p4info, tt.pkg)
+			require.NoError(t, err)
+			golden, err := ioutil.ReadFile(tt.golden)
+			require.NoError(t, err)
+			assert.Equal(t, string(golden), string(src), "%s should be regenerated with go generate", tt.golden)
+		})
+	}
+}
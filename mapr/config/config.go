@@ -0,0 +1,275 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package config defines the config of mapr, loaded from a JSON file, e.g.:
+//
+//	{
+//	  "server": {"bind_addr": "0.0.0.0", "port": 28001, "device_id": 1,
+//	             "tls": {"cert": "mapr.pem", "key": "mapr.key", "client_ca": "ca.pem"}},
+//	  "target": {"addr": "switch:28000", "device_id": 1, "election_id": 1,
+//	             "p4info": "fabric/p4info.bin", "device_config": "fabric/pipeline.bin",
+//	             "tls": {"enabled": true, "ca": "ca.pem"}},
+//	  "processor": "fabric",
+//	  "logical_p4info": "p4info.bin",
+//	  "ports": {"port_map": "ports.json"},
+//	  "fabric": {"internal_vlan": 4094, "default_priority": 1, "line_ids": {"min": 1, "max": 65535}},
+//	  "translate": {"cascade_deletes": ["next_hop_group"]},
+//	  "log": {"level": "info", "subsystems": {"fabric": "trace"}, "format": "json", "max_msg_len": 255,
+//	          "resolve_names": true},
+//	  "metrics": {"addr": ":9090"},
+//	  "tracing": {"otlp_endpoint": "localhost:4317"},
+//	  "punt": {"reasons": {"pppoe_padi": {"rate": 100}}}
+//	}
+//
+// Settings missing from the file keep the values given via flags. The log and punt settings can be changed while mapr
+// is running by editing the file or sending SIGHUP, see Watch, while all others require a restart.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"io/ioutil"
+	"mapr/fabric"
+	"mapr/logging"
+	"mapr/punt"
+	"mapr/translate"
+	"net"
+)
+
+// Max length of the protobuf messages printed in the log, by default.
+const DefaultMaxMsgLen = 255
+
+// TLS settings towards controllers.
+type ServerTls struct {
+	// PEM files with the server certificate and private key, enable TLS if set.
+	Cert string `json:"cert"`
+	Key  string `json:"key"`
+	// PEM file with the CAs of controller certificates, if set controllers must authenticate with a certificate.
+	ClientCa string `json:"client_ca"`
+}
+
+// The gRPC server controllers connect to.
+type Server struct {
+	BindAddr string    `json:"bind_addr"`
+	Port     int       `json:"port"`
+	DeviceId uint64    `json:"device_id"`
+	Tls      ServerTls `json:"tls"`
+}
+
+// TLS settings towards the target.
+type TargetTls struct {
+	Enabled bool `json:"enabled"`
+	// PEM file with the CAs of the target certificate, empty for the system ones.
+	Ca string `json:"ca"`
+	// PEM files with the certificate and private key presented to the target, optional.
+	Cert string `json:"cert"`
+	Key  string `json:"key"`
+	// Name expected in the target certificate, empty for the host of Addr.
+	ServerName string `json:"server_name"`
+}
+
+// The target mapr connects to.
+type Target struct {
+	// In the format of host:port.
+	Addr       string `json:"addr"`
+	DeviceId   uint64 `json:"device_id"`
+	ElectionId uint64 `json:"election_id"`
+	// Target P4Info (binary) and device config files, pushed in place of the logical ones.
+	P4Info       string    `json:"p4info"`
+	DeviceConfig string    `json:"device_config"`
+	Tls          TargetTls `json:"tls"`
+}
+
+// Port mapping between logical and target ports, from at most one of the files.
+type Ports struct {
+	// JSON file, see translate.LoadPortMapFile.
+	PortMap string `json:"port_map"`
+	// Chassis config of the target, see translate.LoadPortMapChassisConfig.
+	ChassisConfig string `json:"chassis_config"`
+}
+
+// Logging settings, reloadable.
+type Log struct {
+	// One of the logrus levels, e.g., "info" or "trace".
+	Level string `json:"level"`
+	// Levels of specific subsystems, e.g., {"fabric": "trace"}, overriding Level.
+	Subsystems map[string]string `json:"subsystems"`
+	// "text" or "json".
+	Format string `json:"format"`
+	// Max length of the protobuf messages printed in the log.
+	MaxMsgLen int `json:"max_msg_len"`
+	// Whether to print protobuf messages with the names of P4 entities in place of IDs, and with values formatted
+	// according to their type, e.g., as IPv4 or MAC addresses.
+	ResolveNames bool `json:"resolve_names"`
+}
+
+// Returns the settings applied to the loggers of all subsystems.
+func (l Log) Logging() logging.Config {
+	return logging.Config{Level: l.Level, Subsystems: l.Subsystems, Format: l.Format}
+}
+
+// Prometheus metrics settings.
+type Metrics struct {
+	// Address in the format of host:port on which metrics are served over HTTP at /metrics, and health checks at
+	// /readyz and /healthz, empty to disable.
+	Addr string `json:"addr"`
+}
+
+// OpenTelemetry tracing settings.
+type Tracing struct {
+	// Address in the format of host:port of the collector spans are exported to over OTLP/gRPC, empty to disable.
+	OtlpEndpoint string `json:"otlp_endpoint"`
+}
+
+type Config struct {
+	Server Server `json:"server"`
+	Target Target `json:"target"`
+	// Name of the processor translating logical entities to target ones, "dummy" or "fabric".
+	Processor string `json:"processor"`
+	// Logical P4Info file (binary), and older versions still accepted.
+	LogicalP4Info         string   `json:"logical_p4info"`
+	LogicalP4InfoVersions []string `json:"logical_p4info_versions"`
+	Ports                 Ports    `json:"ports"`
+	// Config of the fabric processor.
+	Fabric *fabric.Config `json:"fabric"`
+	// Config of the translator, for all processors but the dummy one.
+	Translate *translate.Config `json:"translate"`
+	Log       Log               `json:"log"`
+	Metrics   Metrics           `json:"metrics"`
+	Tracing   Tracing           `json:"tracing"`
+	// Packet-in rate limits and routing, reloadable. Nil for no limits.
+	Punt *punt.Config `json:"punt"`
+}
+
+// Returns the default config.
+func Default() *Config {
+	return &Config{
+		Server:    Server{BindAddr: "localhost", Port: 28001, DeviceId: 1},
+		Target:    Target{Addr: "127.0.0.1:28000", DeviceId: 1, ElectionId: 1},
+		Processor: "dummy",
+		Fabric:    fabric.DefaultConfig(),
+		Translate: translate.DefaultConfig(),
+		Log:       Log{Level: "trace", Format: logging.FormatText, MaxMsgLen: DefaultMaxMsgLen},
+	}
+}
+
+// Returns the config in the given JSON file, applied over a copy of the base one, and validated.
+func Load(path string, base *Config) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c := base.clone()
+	// Sections replacing the base ones rather than being merged with them.
+	basePunt := c.Punt
+	c.Punt = nil
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(c); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %v", path, err)
+	}
+	if c.Punt == nil {
+		c.Punt = basePunt
+	}
+	if err := c.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %v", path, err)
+	}
+	return c, nil
+}
+
+// Returns a deep copy of the config.
+func (c *Config) clone() *Config {
+	data, err := json.Marshal(c)
+	if err != nil {
+		panic(err)
+	}
+	clone := &Config{}
+	if err := json.Unmarshal(data, clone); err != nil {
+		panic(err)
+	}
+	return clone
+}
+
+// Returns an error if the config is invalid.
+func (c *Config) Validate() error {
+	if c.Server.Port <= 0 || c.Server.Port > 65535 {
+		return fmt.Errorf("server.port must be in [1, 65535]")
+	}
+	if (c.Server.Tls.Cert == "") != (c.Server.Tls.Key == "") {
+		return fmt.Errorf("server.tls: cert and key must be set together")
+	}
+	if c.Server.Tls.ClientCa != "" && c.Server.Tls.Cert == "" {
+		return fmt.Errorf("server.tls: client_ca requires cert and key")
+	}
+	if c.Target.Addr == "" {
+		return fmt.Errorf("target.addr is required")
+	}
+	if (c.Target.P4Info == "") != (c.Target.DeviceConfig == "") {
+		return fmt.Errorf("target: p4info and device_config must be set together")
+	}
+	if (c.Target.Tls.Cert == "") != (c.Target.Tls.Key == "") {
+		return fmt.Errorf("target.tls: cert and key must be set together")
+	}
+	switch c.Processor {
+	case "dummy":
+	case "fabric":
+		if c.LogicalP4Info == "" || c.Target.P4Info == "" {
+			return fmt.Errorf("processor %s requires logical_p4info and target p4info", c.Processor)
+		}
+	default:
+		return fmt.Errorf("unknown processor %q", c.Processor)
+	}
+	if len(c.LogicalP4InfoVersions) > 0 && c.LogicalP4Info == "" {
+		return fmt.Errorf("logical_p4info_versions requires logical_p4info")
+	}
+	if c.Ports.PortMap != "" && c.Ports.ChassisConfig != "" {
+		return fmt.Errorf("ports: port_map and chassis_config are mutually exclusive")
+	}
+	if c.Fabric == nil {
+		return fmt.Errorf("fabric config is required")
+	}
+	if err := c.Fabric.Validate(); err != nil {
+		return fmt.Errorf("fabric: %v", err)
+	}
+	if c.Translate == nil {
+		return fmt.Errorf("translate config is required")
+	}
+	if err := c.Translate.Validate(); err != nil {
+		return fmt.Errorf("translate: %v", err)
+	}
+	if _, err := logrus.ParseLevel(c.Log.Level); err != nil {
+		return fmt.Errorf("log.level: %v", err)
+	}
+	for name, level := range c.Log.Subsystems {
+		if _, err := logrus.ParseLevel(level); err != nil {
+			return fmt.Errorf("log.subsystems.%s: %v", name, err)
+		}
+	}
+	if c.Log.Format != logging.FormatText && c.Log.Format != logging.FormatJson {
+		return fmt.Errorf("log.format must be %q or %q", logging.FormatText, logging.FormatJson)
+	}
+	if c.Log.MaxMsgLen <= 0 {
+		return fmt.Errorf("log.max_msg_len must be positive")
+	}
+	if c.Metrics.Addr != "" {
+		if _, _, err := net.SplitHostPort(c.Metrics.Addr); err != nil {
+			return fmt.Errorf("metrics.addr: %v", err)
+		}
+	}
+	if c.Tracing.OtlpEndpoint != "" {
+		if _, _, err := net.SplitHostPort(c.Tracing.OtlpEndpoint); err != nil {
+			return fmt.Errorf("tracing.otlp_endpoint: %v", err)
+		}
+	}
+	if c.Punt != nil {
+		if err := c.Punt.Validate(); err != nil {
+			return fmt.Errorf("punt: %v", err)
+		}
+	}
+	return nil
+}
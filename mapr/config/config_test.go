@@ -0,0 +1,145 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package config
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"mapr/fabric"
+	"mapr/punt"
+	"mapr/translate"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeConfigFile(t *testing.T, dir, content string) string {
+	path := filepath.Join(dir, "config.json")
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func Test_Load(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	base := Default()
+	base.Target.Addr = "switch:28000"
+	base.Punt = &punt.Config{PerPort: &punt.Limit{Rate: 10}}
+	path := writeConfigFile(t, dir, `{
+		"server": {"bind_addr": "0.0.0.0"},
+		"fabric": {"internal_vlan": 100, "line_ids": {"min": 1, "max": 1000}},
+		"translate": {"cascade_deletes": ["next_hop_group"]},
+		"log": {"level": "info", "subsystems": {"fabric": "trace"}, "format": "json"},
+		"punt": {"reasons": {"acl": {"rate": 5}}}
+	}`)
+	c, err := Load(path, base)
+	require.NoError(t, err)
+	// Set by the file.
+	assert.Equal(t, "0.0.0.0", c.Server.BindAddr)
+	assert.Equal(t, &fabric.Config{InternalVlan: 100, DefaultPriority: 1, LineIds: &fabric.IdRange{Min: 1, Max: 1000}},
+		c.Fabric)
+	assert.Equal(t, &translate.Config{CascadeDeletes: []string{"next_hop_group"}}, c.Translate)
+	assert.Equal(t, Log{Level: "info", Subsystems: map[string]string{"fabric": "trace"}, Format: "json",
+		MaxMsgLen: DefaultMaxMsgLen}, c.Log)
+	assert.Equal(t, &punt.Config{Reasons: map[punt.Reason]*punt.ReasonConfig{"acl": {Limit: punt.Limit{Rate: 5}}}},
+		c.Punt)
+	// Kept from the base config, which is not modified.
+	assert.Equal(t, 28001, c.Server.Port)
+	assert.Equal(t, "switch:28000", c.Target.Addr)
+	assert.Equal(t, "localhost", base.Server.BindAddr)
+	assert.Equal(t, uint16(4094), base.Fabric.InternalVlan)
+
+	path = writeConfigFile(t, dir, `{"server": {"port": 1}}`)
+	c, err = Load(path, base)
+	require.NoError(t, err)
+	assert.Equal(t, base.Punt, c.Punt)
+}
+
+func Test_Load_Invalid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"syntax", `{`, "unexpected EOF"},
+		{"unknown field", `{"foo": 1}`, `json: unknown field "foo"`},
+		{"port", `{"server": {"port": 70000}}`, "server.port must be in [1, 65535]"},
+		{"server tls", `{"server": {"tls": {"cert": "mapr.pem"}}}`, "server.tls: cert and key must be set together"},
+		{"client ca", `{"server": {"tls": {"client_ca": "ca.pem"}}}`, "server.tls: client_ca requires cert and key"},
+		{"target config", `{"target": {"p4info": "p4info.bin"}}`,
+			"target: p4info and device_config must be set together"},
+		{"processor", `{"processor": "foo"}`, `unknown processor "foo"`},
+		{"fabric processor", `{"processor": "fabric"}`,
+			"processor fabric requires logical_p4info and target p4info"},
+		{"ports", `{"ports": {"port_map": "a", "chassis_config": "b"}}`,
+			"ports: port_map and chassis_config are mutually exclusive"},
+		{"internal vlan", `{"fabric": {"internal_vlan": 4095}}`, "fabric: internal_vlan must be in [1, 4094]"},
+		{"id ranges", `{"fabric": {"line_ids": {"min": 1, "max": 100}, "next_hop_ids": {"min": 100, "max": 200}}}`,
+			"fabric: line_ids and next_hop_ids must not overlap"},
+		{"id range", `{"fabric": {"line_ids": {"min": 10, "max": 1}}}`,
+			"fabric: line_ids: min must be positive and not greater than max"},
+		{"cascade deletes", `{"translate": {"cascade_deletes": ["attachment"]}}`,
+			`translate: cascade_deletes: "attachment" is not one of my_station, next_hop or next_hop_group`},
+		{"log level", `{"log": {"level": "foo"}}`, `log.level: not a valid logrus Level: "foo"`},
+		{"metrics addr", `{"metrics": {"addr": "9090"}}`, "metrics.addr: address 9090: missing port in address"},
+		{"otlp endpoint", `{"tracing": {"otlp_endpoint": "collector"}}`,
+			"tracing.otlp_endpoint: address collector: missing port in address"},
+		{"subsystem log level", `{"log": {"subsystems": {"fabric": "foo"}}}`,
+			`log.subsystems.fabric: not a valid logrus Level: "foo"`},
+		{"log format", `{"log": {"format": "xml"}}`, `log.format must be "text" or "json"`},
+		{"punt", `{"punt": {"reasons": {"foo": {}}}}`, `punt: unknown punt reason "foo"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfigFile(t, dir, tt.content)
+			_, err := Load(path, Default())
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func Test_watch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := writeConfigFile(t, dir, `{"log": {"level": "info"}}`)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ticks := make(chan time.Time)
+	hup := make(chan os.Signal)
+	applied := make(chan *Config, 1)
+	go watch(ctx, path, Default(), ticks, hup, func(c *Config) {
+		applied <- c
+	})
+
+	// Unchanged file.
+	ticks <- time.Now()
+	hup <- nil
+	assert.Equal(t, "info", (<-applied).Log.Level)
+
+	// Changed file.
+	writeConfigFile(t, dir, `{"log": {"level": "debug"}}`)
+	mtime := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(path, mtime, mtime))
+	ticks <- time.Now()
+	assert.Equal(t, "debug", (<-applied).Log.Level)
+
+	// Invalid file, ignored.
+	writeConfigFile(t, dir, `{"log": {"level": "foo"}}`)
+	hup <- nil
+	writeConfigFile(t, dir, `{"log": {"level": "warn"}}`)
+	hup <- nil
+	assert.Equal(t, "warn", (<-applied).Log.Level)
+}
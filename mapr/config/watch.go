@@ -0,0 +1,65 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package config
+
+import (
+	"context"
+	"mapr/logging"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+var log = logging.Subsystem("config")
+
+// Reloads the config from the given file, applied over the base one, every time the file changes or SIGHUP is
+// received, until the context is done. The file is checked for changes at the given interval. Valid configs are passed
+// to apply, while invalid ones are logged and ignored.
+func Watch(ctx context.Context, path string, base *Config, interval time.Duration, apply func(*Config)) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	watch(ctx, path, base, ticker.C, hup, apply)
+}
+
+func modTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+func watch(ctx context.Context, path string, base *Config, ticks <-chan time.Time, hup <-chan os.Signal,
+	apply func(*Config)) {
+	last := modTime(path)
+	reload := func() {
+		last = modTime(path)
+		c, err := Load(path, base)
+		if err != nil {
+			log.Errorf("Cannot reload config, keeping the current one: %v", err)
+			return
+		}
+		log.Infof("Reloaded config %s", path)
+		apply(c)
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			reload()
+		case <-ticks:
+			if t := modTime(path); !t.Equal(last) {
+				reload()
+			}
+		}
+	}
+}
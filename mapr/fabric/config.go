@@ -0,0 +1,82 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package fabric
+
+import "fmt"
+
+// A range of IDs, bounds included.
+type IdRange struct {
+	Min uint32 `json:"min"`
+	Max uint32 `json:"max"`
+}
+
+func (r *IdRange) contains(id uint32) bool {
+	return r == nil || (id >= r.Min && id <= r.Max)
+}
+
+func (r *IdRange) overlaps(other *IdRange) bool {
+	return r != nil && other != nil && r.Min <= other.Max && other.Min <= r.Max
+}
+
+// Config of the fabric processor.
+type Config struct {
+	// VLAN ID used to tag untagged packets received on core ports.
+	InternalVlan uint16 `json:"internal_vlan"`
+	// Priority of the ternary entries generated by the processor.
+	DefaultPriority int32 `json:"default_priority"`
+	// Logical line IDs, used as fabric next IDs and action profile group and member IDs for downstream attachments.
+	// Nil for no restrictions.
+	LineIds *IdRange `json:"line_ids"`
+	// Logical next hop and next hop group IDs, used as fabric next IDs and action profile group and member IDs. Must
+	// not overlap with LineIds. Nil for no restrictions.
+	NextHopIds *IdRange `json:"next_hop_ids"`
+}
+
+// Returns the default config.
+func DefaultConfig() *Config {
+	return &Config{
+		InternalVlan:    defaultInternalTag,
+		DefaultPriority: defaultPrio,
+	}
+}
+
+// Returns an error if the config is invalid.
+func (c *Config) Validate() error {
+	if c.InternalVlan == 0 || c.InternalVlan > 4094 {
+		return fmt.Errorf("internal_vlan must be in [1, 4094]")
+	}
+	if c.DefaultPriority <= 0 {
+		return fmt.Errorf("default_priority must be positive")
+	}
+	for name, r := range map[string]*IdRange{"line_ids": c.LineIds, "next_hop_ids": c.NextHopIds} {
+		if r != nil && (r.Min == 0 || r.Min > r.Max) {
+			return fmt.Errorf("%s: min must be positive and not greater than max", name)
+		}
+	}
+	if c.LineIds.overlaps(c.NextHopIds) {
+		return fmt.Errorf("line_ids and next_hop_ids must not overlap")
+	}
+	return nil
+}
+
+// Returns an error if the given logical line ID, used as fabric ID, is out of range.
+func (c *Config) checkLineId(id uint32) error {
+	if !c.LineIds.contains(id) {
+		return fmt.Errorf("line ID %d is out of range [%d, %d]", id, c.LineIds.Min, c.LineIds.Max)
+	}
+	return nil
+}
+
+// Returns an error if any of the given logical next hop or group IDs, used as fabric IDs, is out of range.
+func (c *Config) checkNextHopIds(ids ...uint32) error {
+	for _, id := range ids {
+		if !c.NextHopIds.contains(id) {
+			return fmt.Errorf("next hop ID %d is out of range [%d, %d]", id, c.NextHopIds.Min, c.NextHopIds.Max)
+		}
+	}
+	return nil
+}
@@ -9,10 +9,12 @@ package fabric
 import (
 	"fmt"
 	v1 "github.com/p4lang/p4runtime/go/p4/v1"
-	log "github.com/sirupsen/logrus"
+	"mapr/logging"
 	"mapr/translate"
 )
 
+var log = logging.Subsystem("fabric")
+
 // Implementation of a Processor interface for ONF's fabric.p4.
 
 const (
@@ -20,26 +22,40 @@ const (
 	defaultPrio        int32  = 1
 	FwdTypeIpv4Unicast byte   = 0x02
 	EthTypeIpv4        uint16 = 0x0800
+	// Bit width of port fields, same for both the logical pipeline and fabric.p4.
+	portBitwidth = 9
 )
 
 type fabricProcessor struct {
-	ctx translate.Context
+	ctx    translate.Context
+	config *Config
 }
 
-func NewFabricProcessor(ctx translate.Context) translate.Processor {
+// Creates a new fabric processor with the given config, nil for the default one.
+func NewFabricProcessor(ctx translate.Context, config *Config) translate.Processor {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	addTargetIndexes(ctx.Target())
 	return &fabricProcessor{
-		ctx: ctx,
+		ctx:    ctx,
+		config: config,
 	}
 }
 
 func (p fabricProcessor) HandleIfTypeEntry(e *translate.IfTypeEntry, uType v1.Update_Type) ([]*v1.Update, error) {
 	log.Tracef("IfTypeEntry={ %s }", e)
 	// TODO: check parameter of IfTypeEntry and return error
+	port, err := p.ctx.Ports().ToTarget(e.Port)
+	if err != nil {
+		return nil, err
+	}
 	switch e.IfType[0] {
 	case translate.IfTypeCore:
-		ingressPortVlanEntry := createIngressPortVlanEntryPermit(e.Port, nil, nil, getVlanIdValue(defaultInternalTag), defaultPrio)
-		egressPopVlanEntry := createEgressVlanPopEntry(e.Port, defaultInternalTag)
-		return []*v1.Update{createUpdateEntry(&ingressPortVlanEntry, uType), createUpdateEntry(&egressPopVlanEntry, uType)}, nil
+		ingressPortVlanEntry := createIngressPortVlanEntryPermit(port, nil, nil, getVlanIdValue(p.config.InternalVlan),
+			p.config.DefaultPriority)
+		egressPopVlanEntry := createEgressVlanPopEntry(port, p.config.InternalVlan)
+		return []*v1.Update{createUpdateEntry(ingressPortVlanEntry, uType), createUpdateEntry(egressPopVlanEntry, uType)}, nil
 	case translate.IfTypeAccess:
 		log.Warnf("fabricProcessor.HandleIfTypeEntry(): not implemented for ACCESS ports")
 	default:
@@ -51,28 +67,40 @@ func (p fabricProcessor) HandleIfTypeEntry(e *translate.IfTypeEntry, uType v1.Up
 func (p fabricProcessor) HandleMyStationEntry(e *translate.MyStationEntry, uType v1.Update_Type) ([]*v1.Update, error) {
 	log.Tracef("MyStationEntry={ %s }", e)
 	// TODO: check parameter of mystation entry and return error
-	phyTableEntry := createFwdClassifierEntry(e.Port, e.EthDst, defaultPrio)
-	return []*v1.Update{createUpdateEntry(&phyTableEntry, uType)}, nil
+	port, err := p.ctx.Ports().ToTarget(e.Port)
+	if err != nil {
+		return nil, err
+	}
+	phyTableEntry := createFwdClassifierEntry(port, e.EthDst, p.config.DefaultPriority)
+	return []*v1.Update{createUpdateEntry(phyTableEntry, uType)}, nil
 }
 
 func (p fabricProcessor) HandleAttachmentEntry(a *translate.AttachmentEntry, ok bool) (targetUpdateEntries []*v1.Update, err error) {
 	log.Tracef("AttachmentEntry={ %s }, complete=%v", a, ok)
+	// Logical port is still needed to look up the logical store.
+	targetPort, err := p.ctx.Ports().ToTarget(a.Port)
+	if err != nil {
+		return nil, err
+	}
 	if ok {
 		// The attachment is complete, generate the attachment-specific table entries
 		targetTableEntries := make([]*v1.TableEntry, 0)
 		switch a.Direction {
 		case translate.DirectionUpstream:
 			// Ingress Port Vlan for double tagged access port
-			ingressPortVlanEntry := createIngressPortVlanEntryPermit(a.Port, a.STag, a.CTag, nil, defaultPrio)
+			ingressPortVlanEntry := createIngressPortVlanEntryPermit(targetPort, a.STag, a.CTag, nil, p.config.DefaultPriority)
 			// t_line_map
 			lineMapEntry := createLineMapEntry(a.STag, a.CTag, a.LineId)
 			// t_pppoe_term_v4
 			pppoeTermV4Entry := createPppoeTermV4(a.LineId, a.Ipv4Addr, a.PppoeSessId)
-			targetTableEntries = append(targetTableEntries, &lineMapEntry, &ingressPortVlanEntry, &pppoeTermV4Entry)
+			targetTableEntries = append(targetTableEntries, lineMapEntry, ingressPortVlanEntry, pppoeTermV4Entry)
 			targetUpdateEntries = insertOrModifyTableEntries(p, targetTableEntries)
 		case translate.DirectionDownstream:
+			if err = p.config.checkLineId(getUInt32FromByteSlice(a.LineId)); err != nil {
+				return nil, err
+			}
 			// Need to retrieve the switchMac from the MyStation entry
-			x := p.ctx.Logical().MyStations[translate.ToPortKey(a.Port)]
+			x := p.ctx.Logical().MyStations.Get(translate.ToPortKey(a.Port))
 			if x == nil {
 				targetUpdateEntries = nil
 				err = fmt.Errorf("missing MyStation entry for port %x, cannot derive source MAC", a.Port)
@@ -84,23 +112,23 @@ func (p fabricProcessor) HandleAttachmentEntry(a *translate.AttachmentEntry, ok
 			nextHashedEntry := createNextHashedEntry(getUInt32FromByteSlice(a.LineId))
 			// hashedSelector member
 			// FIXME (daniele): Can member ID clash with other member ID? Currently we are using Line ID as Member ID
-			hashedSelectorMember := createHashedSelectorMember(getUInt32FromByteSlice(a.LineId), a.Port, a.MacAddr, x.EthDst)
-			memberKey := translate.KeyFromActProfMember(&hashedSelectorMember)
+			hashedSelectorMember := createHashedSelectorMember(getUInt32FromByteSlice(a.LineId), targetPort, a.MacAddr, x.EthDst)
+			memberKey := translate.KeyFromActProfMember(hashedSelectorMember)
 			updateTypeMember := v1.Update_INSERT
 			if targetSelectorMember := p.ctx.Target().GetActProfMember(&memberKey); targetSelectorMember != nil {
 				updateTypeMember = v1.Update_MODIFY
 			}
 			// hashedSelector group
-			actionProfileGroup := v1.ActionProfileGroup{
-				ActionProfileId: ActionProfile_FabricIngressNextHashedSelector,
-				GroupId:         getUInt32FromByteSlice(a.LineId),
+			hashedSelectorGroup := FabricIngressNextHashedSelectorGroup{
+				GroupId: getUInt32FromByteSlice(a.LineId),
 				Members: []*v1.ActionProfileGroup_Member{{
 					MemberId: getUInt32FromByteSlice(a.LineId),
 					Weight:   1,
 				}},
 				MaxSize: 1,
 			}
-			groupKey := translate.KeyFromActProfGroup(&actionProfileGroup)
+			actionProfileGroup := hashedSelectorGroup.ToActionProfileGroup()
+			groupKey := translate.KeyFromActProfGroup(actionProfileGroup)
 			updateTypeGroup := v1.Update_INSERT
 			if targetGroup := p.ctx.Target().GetActProfGroup(&groupKey); targetGroup != nil {
 				updateTypeGroup = v1.Update_MODIFY
@@ -112,11 +140,11 @@ func (p fabricProcessor) HandleAttachmentEntry(a *translate.AttachmentEntry, ok
 			lineMapEntry := createLineMapEntry(a.STag, a.CTag, a.LineId)
 			// t_line_sessionMap
 			lineSessionMap := createLineSessionMap(a.LineId, a.PppoeSessId)
-			targetTableEntries = append(targetTableEntries, &lineMapEntry, &lineSessionMap, &routeV4Entry, &nextHashedEntry, &pushDoubleVlan)
+			targetTableEntries = append(targetTableEntries, lineMapEntry, lineSessionMap, routeV4Entry, nextHashedEntry, pushDoubleVlan)
 
 			// Make sure to have member, group and then next.routing_hashed entry
-			targetUpdateEntries = append(targetUpdateEntries, createUpdateActProfMember(&hashedSelectorMember, updateTypeMember))
-			targetUpdateEntries = append(targetUpdateEntries, createUpdateActProfGroup(&actionProfileGroup, updateTypeGroup))
+			targetUpdateEntries = append(targetUpdateEntries, createUpdateActProfMember(hashedSelectorMember, updateTypeMember))
+			targetUpdateEntries = append(targetUpdateEntries, createUpdateActProfGroup(actionProfileGroup, updateTypeGroup))
 			targetUpdateEntries = append(targetUpdateEntries, insertOrModifyTableEntries(p, targetTableEntries)...)
 		}
 	} else {
@@ -126,7 +154,7 @@ func (p fabricProcessor) HandleAttachmentEntry(a *translate.AttachmentEntry, ok
 		case translate.DirectionUpstream:
 			if a.LineId != nil {
 				delEntries = append(delEntries, getTargetEntriesUpstreamByLineId(p, a.LineId)...)
-				if p.ctx.Logical().DownstreamAttachments[translate.ToLineIdKey(a.LineId)] != nil {
+				if p.ctx.Logical().DownstreamAttachments.Get(translate.ToLineIdKey(a.LineId)) != nil {
 					log.Trace("Leaving TLineMap entry on the target because Downstream Attachment is still present")
 					removeFirstTLineMap(delEntries)
 				}
@@ -135,8 +163,8 @@ func (p fabricProcessor) HandleAttachmentEntry(a *translate.AttachmentEntry, ok
 			if a.STag != nil && a.CTag != nil && a.Port != nil {
 				// FIXME: if the first Logical rule removed is the upstream.attachments_v4 we'll never reach this point when removing rules
 				// Create a "fake" rule just to get the key from the translate.KeyFromTableEntry helper method
-				tempRule := createIngressPortVlanEntryPermit(a.Port, a.STag, a.CTag, nil, defaultPrio)
-				key := translate.KeyFromTableEntry(&tempRule)
+				tempRule := createIngressPortVlanEntryPermit(targetPort, a.STag, a.CTag, nil, p.config.DefaultPriority)
+				key := translate.KeyFromTableEntry(tempRule)
 				// Otherwise it will append nil
 				if remEntry := p.ctx.Target().GetTableEntry(&key); remEntry != nil {
 					delEntries = append(delEntries, p.ctx.Target().GetTableEntry(&key))
@@ -147,7 +175,7 @@ func (p fabricProcessor) HandleAttachmentEntry(a *translate.AttachmentEntry, ok
 			downEntries := make([]*v1.Update, 0)
 			if a.LineId != nil {
 				delEntries = append(delEntries, getTargetEntriesDownstreamByLineId(p, a.LineId)...)
-				if p.ctx.Logical().UpstreamAttachments[translate.ToLineIdKey(a.LineId)] != nil {
+				if p.ctx.Logical().UpstreamAttachments.Get(translate.ToLineIdKey(a.LineId)) != nil {
 					log.Trace("Leaving TLineMap entry on the target because Upstream Attachment is still present")
 					removeFirstTLineMap(delEntries)
 				}
@@ -181,26 +209,39 @@ func removeFirstTLineMap(updateEntries []*v1.TableEntry) {
 
 func (p fabricProcessor) HandleRouteV4NextHopEntry(e *translate.NextHopEntry, uType v1.Update_Type) ([]*v1.Update, error) {
 	log.Tracef("NextHopEntry={ %s }", e)
-	x := p.ctx.Logical().MyStations[translate.ToPortKey(e.Port)]
+	if err := p.config.checkNextHopIds(e.Id); err != nil {
+		return nil, err
+	}
+	x := p.ctx.Logical().MyStations.Get(translate.ToPortKey(e.Port))
 	if x == nil {
 		return nil, fmt.Errorf("missing MyStation entry for port %x, cannot derive source MAC", e.Port)
 	}
-	m := createHashedSelectorMember(e.Id, e.Port, e.MacAddr, x.EthDst)
-	return []*v1.Update{createUpdateActProfMember(&m, uType)}, nil
+	port, err := p.ctx.Ports().ToTarget(e.Port)
+	if err != nil {
+		return nil, err
+	}
+	m := createHashedSelectorMember(e.Id, port, e.MacAddr, x.EthDst)
+	return []*v1.Update{createUpdateActProfMember(m, uType)}, nil
 }
 
 func (p fabricProcessor) HandleRouteV4NextHopGroup(g *translate.NextHopGroup, uType v1.Update_Type) ([]*v1.Update, error) {
 	log.Tracef("NextHopGroup={ %s }", g)
+	ids := []uint32{g.GroupId}
+	for _, m := range g.Members {
+		ids = append(ids, m.MemberId)
+	}
+	if err := p.config.checkNextHopIds(ids...); err != nil {
+		return nil, err
+	}
 	// Generating the target group is easy if we use the same IDs for the members and group.
-	group := v1.ActionProfileGroup{
-		ActionProfileId: ActionProfile_FabricIngressNextHashedSelector,
-		GroupId:         g.GroupId,
-		Members:         g.Members,
-		MaxSize:         g.MaxSize,
+	group := FabricIngressNextHashedSelectorGroup{
+		GroupId: g.GroupId,
+		Members: g.Members,
+		MaxSize: g.MaxSize,
 	}
-	groupUpdate := createUpdateActProfGroup(&group, uType)
+	groupUpdate := createUpdateActProfGroup(group.ToActionProfileGroup(), uType)
 	nextEntry := createNextHashedEntry(g.GroupId)
-	nextUpdate := createUpdateEntry(&nextEntry, uType)
+	nextUpdate := createUpdateEntry(nextEntry, uType)
 	if uType == v1.Update_DELETE {
 		// Table entry must be removed before group.
 		return []*v1.Update{nextUpdate, groupUpdate}, nil
@@ -211,11 +252,14 @@ func (p fabricProcessor) HandleRouteV4NextHopGroup(g *translate.NextHopGroup, uT
 
 func (p fabricProcessor) HandleRouteV4Entry(e *translate.RouteV4Entry, uType v1.Update_Type) ([]*v1.Update, error) {
 	log.Tracef("RouteV4Entry={ %s }", e)
+	if err := p.config.checkNextHopIds(e.NextHopGroupId); err != nil {
+		return nil, err
+	}
 	r := createRouteV4Entry(e.NextHopGroupId, e.Ipv4Addr, e.PrefixLen)
 	switch e.Direction {
 	case translate.DirectionUpstream:
-		v := createNextVlanEntry(e.NextHopGroupId, getVlanIdValue(defaultInternalTag), nil)
-		return []*v1.Update{createUpdateEntry(&r, uType), createUpdateEntry(&v, uType)}, nil
+		v := createNextVlanEntry(e.NextHopGroupId, getVlanIdValue(p.config.InternalVlan), nil)
+		return []*v1.Update{createUpdateEntry(r, uType), createUpdateEntry(v, uType)}, nil
 	default:
 		return nil, fmt.Errorf("undefined route direction")
 	}
@@ -223,15 +267,15 @@ func (p fabricProcessor) HandleRouteV4Entry(e *translate.RouteV4Entry, uType v1.
 
 func (p fabricProcessor) HandleAclEntry(e *translate.AclEntry, uType v1.Update_Type) ([]*v1.Update, error) {
 	log.Tracef("AclEntry={ %s }", e)
-	t, err := createAclEntry(e)
+	t, err := createAclEntry(e, p.ctx.Ports())
 	if err != nil {
 		return nil, err
 	}
-	return []*v1.Update{createUpdateEntry(&t, uType)}, nil
+	return []*v1.Update{createUpdateEntry(t, uType)}, nil
 }
 
 func (p fabricProcessor) HandlePpppoePunts(e *translate.PppoePuntedEntry, uType v1.Update_Type) ([]*v1.Update, error) {
 	log.Tracef("PppoePuntEntry={ %s }", e)
-	t := createPppoePuntEntry(e.PppoeCode, e.PppoeProto, defaultPrio)
-	return []*v1.Update{createUpdateEntry(&t, uType)}, nil
+	t := createPppoePuntEntry(e.PppoeCode, e.PppoeProto, p.config.DefaultPriority)
+	return []*v1.Update{createUpdateEntry(t, uType)}, nil
 }
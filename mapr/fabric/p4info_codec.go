@@ -0,0 +1,1793 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Code generated by mapr/codec/gen from ../p4c-out/fabric/p4info.txt. DO NOT EDIT.
+
+package fabric
+
+import (
+	"fmt"
+	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
+	"mapr/codec"
+)
+
+// Action nop
+type NopAction struct {
+}
+
+func (a *NopAction) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_Nop}
+	return act
+}
+
+func (a *NopAction) FromAction(act *p4v1.Action) error {
+	*a = NopAction{}
+	if act == nil || act.ActionId != Action_Nop {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	if len(act.Params) > 0 {
+		return fmt.Errorf("invalid %T ID %d", act.Params[0], act.Params[0].ParamId)
+	}
+	return nil
+}
+
+// Action FabricIngress.bng_ingress.upstream.punt_to_cpu
+type FabricIngressBngIngressUpstreamPuntToCpuAction struct {
+}
+
+func (a *FabricIngressBngIngressUpstreamPuntToCpuAction) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_FabricIngressBngIngressUpstreamPuntToCpu}
+	return act
+}
+
+func (a *FabricIngressBngIngressUpstreamPuntToCpuAction) FromAction(act *p4v1.Action) error {
+	*a = FabricIngressBngIngressUpstreamPuntToCpuAction{}
+	if act == nil || act.ActionId != Action_FabricIngressBngIngressUpstreamPuntToCpu {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	if len(act.Params) > 0 {
+		return fmt.Errorf("invalid %T ID %d", act.Params[0], act.Params[0].ParamId)
+	}
+	return nil
+}
+
+// Action FabricIngress.bng_ingress.upstream.term_disabled
+type FabricIngressBngIngressUpstreamTermDisabledAction struct {
+}
+
+func (a *FabricIngressBngIngressUpstreamTermDisabledAction) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_FabricIngressBngIngressUpstreamTermDisabled}
+	return act
+}
+
+func (a *FabricIngressBngIngressUpstreamTermDisabledAction) FromAction(act *p4v1.Action) error {
+	*a = FabricIngressBngIngressUpstreamTermDisabledAction{}
+	if act == nil || act.ActionId != Action_FabricIngressBngIngressUpstreamTermDisabled {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	if len(act.Params) > 0 {
+		return fmt.Errorf("invalid %T ID %d", act.Params[0], act.Params[0].ParamId)
+	}
+	return nil
+}
+
+// Action FabricIngress.bng_ingress.upstream.term_enabled_v4
+type FabricIngressBngIngressUpstreamTermEnabledV4Action struct {
+}
+
+func (a *FabricIngressBngIngressUpstreamTermEnabledV4Action) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_FabricIngressBngIngressUpstreamTermEnabledV4}
+	return act
+}
+
+func (a *FabricIngressBngIngressUpstreamTermEnabledV4Action) FromAction(act *p4v1.Action) error {
+	*a = FabricIngressBngIngressUpstreamTermEnabledV4Action{}
+	if act == nil || act.ActionId != Action_FabricIngressBngIngressUpstreamTermEnabledV4 {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	if len(act.Params) > 0 {
+		return fmt.Errorf("invalid %T ID %d", act.Params[0], act.Params[0].ParamId)
+	}
+	return nil
+}
+
+// Action FabricIngress.bng_ingress.downstream.set_session
+type FabricIngressBngIngressDownstreamSetSessionAction struct {
+	PppoeSessionId []byte // bitwidth 16
+}
+
+func (a *FabricIngressBngIngressDownstreamSetSessionAction) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_FabricIngressBngIngressDownstreamSetSession}
+	if a.PppoeSessionId != nil {
+		act.Params = append(act.Params, &p4v1.Action_Param{ParamId: ActionParam_FabricIngressBngIngressDownstreamSetSession_PppoeSessionId, Value: a.PppoeSessionId})
+	}
+	return act
+}
+
+func (a *FabricIngressBngIngressDownstreamSetSessionAction) FromAction(act *p4v1.Action) error {
+	*a = FabricIngressBngIngressDownstreamSetSessionAction{}
+	if act == nil || act.ActionId != Action_FabricIngressBngIngressDownstreamSetSession {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	for _, p := range act.Params {
+		switch p.ParamId {
+		case ActionParam_FabricIngressBngIngressDownstreamSetSession_PppoeSessionId:
+			a.PppoeSessionId = p.Value
+		default:
+			return fmt.Errorf("invalid %T ID %d", p, p.ParamId)
+		}
+	}
+	return nil
+}
+
+// Action FabricIngress.bng_ingress.downstream.drop
+type FabricIngressBngIngressDownstreamDropAction struct {
+}
+
+func (a *FabricIngressBngIngressDownstreamDropAction) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_FabricIngressBngIngressDownstreamDrop}
+	return act
+}
+
+func (a *FabricIngressBngIngressDownstreamDropAction) FromAction(act *p4v1.Action) error {
+	*a = FabricIngressBngIngressDownstreamDropAction{}
+	if act == nil || act.ActionId != Action_FabricIngressBngIngressDownstreamDrop {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	if len(act.Params) > 0 {
+		return fmt.Errorf("invalid %T ID %d", act.Params[0], act.Params[0].ParamId)
+	}
+	return nil
+}
+
+// Action FabricIngress.bng_ingress.downstream.qos_prio
+type FabricIngressBngIngressDownstreamQosPrioAction struct {
+}
+
+func (a *FabricIngressBngIngressDownstreamQosPrioAction) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_FabricIngressBngIngressDownstreamQosPrio}
+	return act
+}
+
+func (a *FabricIngressBngIngressDownstreamQosPrioAction) FromAction(act *p4v1.Action) error {
+	*a = FabricIngressBngIngressDownstreamQosPrioAction{}
+	if act == nil || act.ActionId != Action_FabricIngressBngIngressDownstreamQosPrio {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	if len(act.Params) > 0 {
+		return fmt.Errorf("invalid %T ID %d", act.Params[0], act.Params[0].ParamId)
+	}
+	return nil
+}
+
+// Action FabricIngress.bng_ingress.downstream.qos_besteff
+type FabricIngressBngIngressDownstreamQosBesteffAction struct {
+}
+
+func (a *FabricIngressBngIngressDownstreamQosBesteffAction) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_FabricIngressBngIngressDownstreamQosBesteff}
+	return act
+}
+
+func (a *FabricIngressBngIngressDownstreamQosBesteffAction) FromAction(act *p4v1.Action) error {
+	*a = FabricIngressBngIngressDownstreamQosBesteffAction{}
+	if act == nil || act.ActionId != Action_FabricIngressBngIngressDownstreamQosBesteff {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	if len(act.Params) > 0 {
+		return fmt.Errorf("invalid %T ID %d", act.Params[0], act.Params[0].ParamId)
+	}
+	return nil
+}
+
+// Action FabricIngress.bng_ingress.set_line
+type FabricIngressBngIngressSetLineAction struct {
+	LineId []byte // bitwidth 32
+}
+
+func (a *FabricIngressBngIngressSetLineAction) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_FabricIngressBngIngressSetLine}
+	if a.LineId != nil {
+		act.Params = append(act.Params, &p4v1.Action_Param{ParamId: ActionParam_FabricIngressBngIngressSetLine_LineId, Value: a.LineId})
+	}
+	return act
+}
+
+func (a *FabricIngressBngIngressSetLineAction) FromAction(act *p4v1.Action) error {
+	*a = FabricIngressBngIngressSetLineAction{}
+	if act == nil || act.ActionId != Action_FabricIngressBngIngressSetLine {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	for _, p := range act.Params {
+		switch p.ParamId {
+		case ActionParam_FabricIngressBngIngressSetLine_LineId:
+			a.LineId = p.Value
+		default:
+			return fmt.Errorf("invalid %T ID %d", p, p.ParamId)
+		}
+	}
+	return nil
+}
+
+// Action FabricIngress.filtering.deny
+type FabricIngressFilteringDenyAction struct {
+}
+
+func (a *FabricIngressFilteringDenyAction) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_FabricIngressFilteringDeny}
+	return act
+}
+
+func (a *FabricIngressFilteringDenyAction) FromAction(act *p4v1.Action) error {
+	*a = FabricIngressFilteringDenyAction{}
+	if act == nil || act.ActionId != Action_FabricIngressFilteringDeny {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	if len(act.Params) > 0 {
+		return fmt.Errorf("invalid %T ID %d", act.Params[0], act.Params[0].ParamId)
+	}
+	return nil
+}
+
+// Action FabricIngress.filtering.permit
+type FabricIngressFilteringPermitAction struct {
+}
+
+func (a *FabricIngressFilteringPermitAction) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_FabricIngressFilteringPermit}
+	return act
+}
+
+func (a *FabricIngressFilteringPermitAction) FromAction(act *p4v1.Action) error {
+	*a = FabricIngressFilteringPermitAction{}
+	if act == nil || act.ActionId != Action_FabricIngressFilteringPermit {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	if len(act.Params) > 0 {
+		return fmt.Errorf("invalid %T ID %d", act.Params[0], act.Params[0].ParamId)
+	}
+	return nil
+}
+
+// Action FabricIngress.filtering.permit_with_internal_vlan
+type FabricIngressFilteringPermitWithInternalVlanAction struct {
+	VlanId []byte // bitwidth 12
+}
+
+func (a *FabricIngressFilteringPermitWithInternalVlanAction) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_FabricIngressFilteringPermitWithInternalVlan}
+	if a.VlanId != nil {
+		act.Params = append(act.Params, &p4v1.Action_Param{ParamId: ActionParam_FabricIngressFilteringPermitWithInternalVlan_VlanId, Value: a.VlanId})
+	}
+	return act
+}
+
+func (a *FabricIngressFilteringPermitWithInternalVlanAction) FromAction(act *p4v1.Action) error {
+	*a = FabricIngressFilteringPermitWithInternalVlanAction{}
+	if act == nil || act.ActionId != Action_FabricIngressFilteringPermitWithInternalVlan {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	for _, p := range act.Params {
+		switch p.ParamId {
+		case ActionParam_FabricIngressFilteringPermitWithInternalVlan_VlanId:
+			a.VlanId = p.Value
+		default:
+			return fmt.Errorf("invalid %T ID %d", p, p.ParamId)
+		}
+	}
+	return nil
+}
+
+// Action FabricIngress.filtering.set_forwarding_type
+type FabricIngressFilteringSetForwardingTypeAction struct {
+	FwdType []byte // bitwidth 3
+}
+
+func (a *FabricIngressFilteringSetForwardingTypeAction) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_FabricIngressFilteringSetForwardingType}
+	if a.FwdType != nil {
+		act.Params = append(act.Params, &p4v1.Action_Param{ParamId: ActionParam_FabricIngressFilteringSetForwardingType_FwdType, Value: a.FwdType})
+	}
+	return act
+}
+
+func (a *FabricIngressFilteringSetForwardingTypeAction) FromAction(act *p4v1.Action) error {
+	*a = FabricIngressFilteringSetForwardingTypeAction{}
+	if act == nil || act.ActionId != Action_FabricIngressFilteringSetForwardingType {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	for _, p := range act.Params {
+		switch p.ParamId {
+		case ActionParam_FabricIngressFilteringSetForwardingType_FwdType:
+			a.FwdType = p.Value
+		default:
+			return fmt.Errorf("invalid %T ID %d", p, p.ParamId)
+		}
+	}
+	return nil
+}
+
+// Action FabricIngress.forwarding.set_next_id_bridging
+type FabricIngressForwardingSetNextIdBridgingAction struct {
+	NextId []byte // bitwidth 32
+}
+
+func (a *FabricIngressForwardingSetNextIdBridgingAction) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_FabricIngressForwardingSetNextIdBridging}
+	if a.NextId != nil {
+		act.Params = append(act.Params, &p4v1.Action_Param{ParamId: ActionParam_FabricIngressForwardingSetNextIdBridging_NextId, Value: a.NextId})
+	}
+	return act
+}
+
+func (a *FabricIngressForwardingSetNextIdBridgingAction) FromAction(act *p4v1.Action) error {
+	*a = FabricIngressForwardingSetNextIdBridgingAction{}
+	if act == nil || act.ActionId != Action_FabricIngressForwardingSetNextIdBridging {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	for _, p := range act.Params {
+		switch p.ParamId {
+		case ActionParam_FabricIngressForwardingSetNextIdBridging_NextId:
+			a.NextId = p.Value
+		default:
+			return fmt.Errorf("invalid %T ID %d", p, p.ParamId)
+		}
+	}
+	return nil
+}
+
+// Action FabricIngress.forwarding.pop_mpls_and_next
+type FabricIngressForwardingPopMplsAndNextAction struct {
+	NextId []byte // bitwidth 32
+}
+
+func (a *FabricIngressForwardingPopMplsAndNextAction) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_FabricIngressForwardingPopMplsAndNext}
+	if a.NextId != nil {
+		act.Params = append(act.Params, &p4v1.Action_Param{ParamId: ActionParam_FabricIngressForwardingPopMplsAndNext_NextId, Value: a.NextId})
+	}
+	return act
+}
+
+func (a *FabricIngressForwardingPopMplsAndNextAction) FromAction(act *p4v1.Action) error {
+	*a = FabricIngressForwardingPopMplsAndNextAction{}
+	if act == nil || act.ActionId != Action_FabricIngressForwardingPopMplsAndNext {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	for _, p := range act.Params {
+		switch p.ParamId {
+		case ActionParam_FabricIngressForwardingPopMplsAndNext_NextId:
+			a.NextId = p.Value
+		default:
+			return fmt.Errorf("invalid %T ID %d", p, p.ParamId)
+		}
+	}
+	return nil
+}
+
+// Action FabricIngress.forwarding.set_next_id_routing_v4
+type FabricIngressForwardingSetNextIdRoutingV4Action struct {
+	NextId []byte // bitwidth 32
+}
+
+func (a *FabricIngressForwardingSetNextIdRoutingV4Action) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_FabricIngressForwardingSetNextIdRoutingV4}
+	if a.NextId != nil {
+		act.Params = append(act.Params, &p4v1.Action_Param{ParamId: ActionParam_FabricIngressForwardingSetNextIdRoutingV4_NextId, Value: a.NextId})
+	}
+	return act
+}
+
+func (a *FabricIngressForwardingSetNextIdRoutingV4Action) FromAction(act *p4v1.Action) error {
+	*a = FabricIngressForwardingSetNextIdRoutingV4Action{}
+	if act == nil || act.ActionId != Action_FabricIngressForwardingSetNextIdRoutingV4 {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	for _, p := range act.Params {
+		switch p.ParamId {
+		case ActionParam_FabricIngressForwardingSetNextIdRoutingV4_NextId:
+			a.NextId = p.Value
+		default:
+			return fmt.Errorf("invalid %T ID %d", p, p.ParamId)
+		}
+	}
+	return nil
+}
+
+// Action FabricIngress.forwarding.nop_routing_v4
+type FabricIngressForwardingNopRoutingV4Action struct {
+}
+
+func (a *FabricIngressForwardingNopRoutingV4Action) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_FabricIngressForwardingNopRoutingV4}
+	return act
+}
+
+func (a *FabricIngressForwardingNopRoutingV4Action) FromAction(act *p4v1.Action) error {
+	*a = FabricIngressForwardingNopRoutingV4Action{}
+	if act == nil || act.ActionId != Action_FabricIngressForwardingNopRoutingV4 {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	if len(act.Params) > 0 {
+		return fmt.Errorf("invalid %T ID %d", act.Params[0], act.Params[0].ParamId)
+	}
+	return nil
+}
+
+// Action FabricIngress.acl.set_next_id_acl
+type FabricIngressAclSetNextIdAclAction struct {
+	NextId []byte // bitwidth 32
+}
+
+func (a *FabricIngressAclSetNextIdAclAction) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_FabricIngressAclSetNextIdAcl}
+	if a.NextId != nil {
+		act.Params = append(act.Params, &p4v1.Action_Param{ParamId: ActionParam_FabricIngressAclSetNextIdAcl_NextId, Value: a.NextId})
+	}
+	return act
+}
+
+func (a *FabricIngressAclSetNextIdAclAction) FromAction(act *p4v1.Action) error {
+	*a = FabricIngressAclSetNextIdAclAction{}
+	if act == nil || act.ActionId != Action_FabricIngressAclSetNextIdAcl {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	for _, p := range act.Params {
+		switch p.ParamId {
+		case ActionParam_FabricIngressAclSetNextIdAcl_NextId:
+			a.NextId = p.Value
+		default:
+			return fmt.Errorf("invalid %T ID %d", p, p.ParamId)
+		}
+	}
+	return nil
+}
+
+// Action FabricIngress.acl.punt_to_cpu
+type FabricIngressAclPuntToCpuAction struct {
+}
+
+func (a *FabricIngressAclPuntToCpuAction) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_FabricIngressAclPuntToCpu}
+	return act
+}
+
+func (a *FabricIngressAclPuntToCpuAction) FromAction(act *p4v1.Action) error {
+	*a = FabricIngressAclPuntToCpuAction{}
+	if act == nil || act.ActionId != Action_FabricIngressAclPuntToCpu {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	if len(act.Params) > 0 {
+		return fmt.Errorf("invalid %T ID %d", act.Params[0], act.Params[0].ParamId)
+	}
+	return nil
+}
+
+// Action FabricIngress.acl.set_clone_session_id
+type FabricIngressAclSetCloneSessionIdAction struct {
+	CloneId []byte // bitwidth 32
+}
+
+func (a *FabricIngressAclSetCloneSessionIdAction) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_FabricIngressAclSetCloneSessionId}
+	if a.CloneId != nil {
+		act.Params = append(act.Params, &p4v1.Action_Param{ParamId: ActionParam_FabricIngressAclSetCloneSessionId_CloneId, Value: a.CloneId})
+	}
+	return act
+}
+
+func (a *FabricIngressAclSetCloneSessionIdAction) FromAction(act *p4v1.Action) error {
+	*a = FabricIngressAclSetCloneSessionIdAction{}
+	if act == nil || act.ActionId != Action_FabricIngressAclSetCloneSessionId {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	for _, p := range act.Params {
+		switch p.ParamId {
+		case ActionParam_FabricIngressAclSetCloneSessionId_CloneId:
+			a.CloneId = p.Value
+		default:
+			return fmt.Errorf("invalid %T ID %d", p, p.ParamId)
+		}
+	}
+	return nil
+}
+
+// Action FabricIngress.acl.drop
+type FabricIngressAclDropAction struct {
+}
+
+func (a *FabricIngressAclDropAction) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_FabricIngressAclDrop}
+	return act
+}
+
+func (a *FabricIngressAclDropAction) FromAction(act *p4v1.Action) error {
+	*a = FabricIngressAclDropAction{}
+	if act == nil || act.ActionId != Action_FabricIngressAclDrop {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	if len(act.Params) > 0 {
+		return fmt.Errorf("invalid %T ID %d", act.Params[0], act.Params[0].ParamId)
+	}
+	return nil
+}
+
+// Action FabricIngress.acl.nop_acl
+type FabricIngressAclNopAclAction struct {
+}
+
+func (a *FabricIngressAclNopAclAction) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_FabricIngressAclNopAcl}
+	return act
+}
+
+func (a *FabricIngressAclNopAclAction) FromAction(act *p4v1.Action) error {
+	*a = FabricIngressAclNopAclAction{}
+	if act == nil || act.ActionId != Action_FabricIngressAclNopAcl {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	if len(act.Params) > 0 {
+		return fmt.Errorf("invalid %T ID %d", act.Params[0], act.Params[0].ParamId)
+	}
+	return nil
+}
+
+// Action FabricIngress.next.set_vlan
+type FabricIngressNextSetVlanAction struct {
+	VlanId []byte // bitwidth 12
+}
+
+func (a *FabricIngressNextSetVlanAction) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_FabricIngressNextSetVlan}
+	if a.VlanId != nil {
+		act.Params = append(act.Params, &p4v1.Action_Param{ParamId: ActionParam_FabricIngressNextSetVlan_VlanId, Value: a.VlanId})
+	}
+	return act
+}
+
+func (a *FabricIngressNextSetVlanAction) FromAction(act *p4v1.Action) error {
+	*a = FabricIngressNextSetVlanAction{}
+	if act == nil || act.ActionId != Action_FabricIngressNextSetVlan {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	for _, p := range act.Params {
+		switch p.ParamId {
+		case ActionParam_FabricIngressNextSetVlan_VlanId:
+			a.VlanId = p.Value
+		default:
+			return fmt.Errorf("invalid %T ID %d", p, p.ParamId)
+		}
+	}
+	return nil
+}
+
+// Action FabricIngress.next.set_double_vlan
+type FabricIngressNextSetDoubleVlanAction struct {
+	OuterVlanId []byte // bitwidth 12
+	InnerVlanId []byte // bitwidth 12
+}
+
+func (a *FabricIngressNextSetDoubleVlanAction) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_FabricIngressNextSetDoubleVlan}
+	if a.OuterVlanId != nil {
+		act.Params = append(act.Params, &p4v1.Action_Param{ParamId: ActionParam_FabricIngressNextSetDoubleVlan_OuterVlanId, Value: a.OuterVlanId})
+	}
+	if a.InnerVlanId != nil {
+		act.Params = append(act.Params, &p4v1.Action_Param{ParamId: ActionParam_FabricIngressNextSetDoubleVlan_InnerVlanId, Value: a.InnerVlanId})
+	}
+	return act
+}
+
+func (a *FabricIngressNextSetDoubleVlanAction) FromAction(act *p4v1.Action) error {
+	*a = FabricIngressNextSetDoubleVlanAction{}
+	if act == nil || act.ActionId != Action_FabricIngressNextSetDoubleVlan {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	for _, p := range act.Params {
+		switch p.ParamId {
+		case ActionParam_FabricIngressNextSetDoubleVlan_OuterVlanId:
+			a.OuterVlanId = p.Value
+		case ActionParam_FabricIngressNextSetDoubleVlan_InnerVlanId:
+			a.InnerVlanId = p.Value
+		default:
+			return fmt.Errorf("invalid %T ID %d", p, p.ParamId)
+		}
+	}
+	return nil
+}
+
+// Action FabricIngress.next.output_hashed
+type FabricIngressNextOutputHashedAction struct {
+	PortNum []byte // bitwidth 9
+}
+
+func (a *FabricIngressNextOutputHashedAction) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_FabricIngressNextOutputHashed}
+	if a.PortNum != nil {
+		act.Params = append(act.Params, &p4v1.Action_Param{ParamId: ActionParam_FabricIngressNextOutputHashed_PortNum, Value: a.PortNum})
+	}
+	return act
+}
+
+func (a *FabricIngressNextOutputHashedAction) FromAction(act *p4v1.Action) error {
+	*a = FabricIngressNextOutputHashedAction{}
+	if act == nil || act.ActionId != Action_FabricIngressNextOutputHashed {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	for _, p := range act.Params {
+		switch p.ParamId {
+		case ActionParam_FabricIngressNextOutputHashed_PortNum:
+			a.PortNum = p.Value
+		default:
+			return fmt.Errorf("invalid %T ID %d", p, p.ParamId)
+		}
+	}
+	return nil
+}
+
+// Action FabricIngress.next.routing_hashed
+type FabricIngressNextRoutingHashedAction struct {
+	PortNum []byte // bitwidth 9
+	Smac    []byte // bitwidth 48
+	Dmac    []byte // bitwidth 48
+}
+
+func (a *FabricIngressNextRoutingHashedAction) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_FabricIngressNextRoutingHashed}
+	if a.PortNum != nil {
+		act.Params = append(act.Params, &p4v1.Action_Param{ParamId: ActionParam_FabricIngressNextRoutingHashed_PortNum, Value: a.PortNum})
+	}
+	if a.Smac != nil {
+		act.Params = append(act.Params, &p4v1.Action_Param{ParamId: ActionParam_FabricIngressNextRoutingHashed_Smac, Value: a.Smac})
+	}
+	if a.Dmac != nil {
+		act.Params = append(act.Params, &p4v1.Action_Param{ParamId: ActionParam_FabricIngressNextRoutingHashed_Dmac, Value: a.Dmac})
+	}
+	return act
+}
+
+func (a *FabricIngressNextRoutingHashedAction) FromAction(act *p4v1.Action) error {
+	*a = FabricIngressNextRoutingHashedAction{}
+	if act == nil || act.ActionId != Action_FabricIngressNextRoutingHashed {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	for _, p := range act.Params {
+		switch p.ParamId {
+		case ActionParam_FabricIngressNextRoutingHashed_PortNum:
+			a.PortNum = p.Value
+		case ActionParam_FabricIngressNextRoutingHashed_Smac:
+			a.Smac = p.Value
+		case ActionParam_FabricIngressNextRoutingHashed_Dmac:
+			a.Dmac = p.Value
+		default:
+			return fmt.Errorf("invalid %T ID %d", p, p.ParamId)
+		}
+	}
+	return nil
+}
+
+// Action FabricIngress.next.mpls_routing_hashed
+type FabricIngressNextMplsRoutingHashedAction struct {
+	PortNum []byte // bitwidth 9
+	Smac    []byte // bitwidth 48
+	Dmac    []byte // bitwidth 48
+	Label   []byte // bitwidth 20
+}
+
+func (a *FabricIngressNextMplsRoutingHashedAction) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_FabricIngressNextMplsRoutingHashed}
+	if a.PortNum != nil {
+		act.Params = append(act.Params, &p4v1.Action_Param{ParamId: ActionParam_FabricIngressNextMplsRoutingHashed_PortNum, Value: a.PortNum})
+	}
+	if a.Smac != nil {
+		act.Params = append(act.Params, &p4v1.Action_Param{ParamId: ActionParam_FabricIngressNextMplsRoutingHashed_Smac, Value: a.Smac})
+	}
+	if a.Dmac != nil {
+		act.Params = append(act.Params, &p4v1.Action_Param{ParamId: ActionParam_FabricIngressNextMplsRoutingHashed_Dmac, Value: a.Dmac})
+	}
+	if a.Label != nil {
+		act.Params = append(act.Params, &p4v1.Action_Param{ParamId: ActionParam_FabricIngressNextMplsRoutingHashed_Label, Value: a.Label})
+	}
+	return act
+}
+
+func (a *FabricIngressNextMplsRoutingHashedAction) FromAction(act *p4v1.Action) error {
+	*a = FabricIngressNextMplsRoutingHashedAction{}
+	if act == nil || act.ActionId != Action_FabricIngressNextMplsRoutingHashed {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	for _, p := range act.Params {
+		switch p.ParamId {
+		case ActionParam_FabricIngressNextMplsRoutingHashed_PortNum:
+			a.PortNum = p.Value
+		case ActionParam_FabricIngressNextMplsRoutingHashed_Smac:
+			a.Smac = p.Value
+		case ActionParam_FabricIngressNextMplsRoutingHashed_Dmac:
+			a.Dmac = p.Value
+		case ActionParam_FabricIngressNextMplsRoutingHashed_Label:
+			a.Label = p.Value
+		default:
+			return fmt.Errorf("invalid %T ID %d", p, p.ParamId)
+		}
+	}
+	return nil
+}
+
+// Action FabricIngress.next.set_mcast_group_id
+type FabricIngressNextSetMcastGroupIdAction struct {
+	GroupId []byte // bitwidth 16
+}
+
+func (a *FabricIngressNextSetMcastGroupIdAction) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_FabricIngressNextSetMcastGroupId}
+	if a.GroupId != nil {
+		act.Params = append(act.Params, &p4v1.Action_Param{ParamId: ActionParam_FabricIngressNextSetMcastGroupId_GroupId, Value: a.GroupId})
+	}
+	return act
+}
+
+func (a *FabricIngressNextSetMcastGroupIdAction) FromAction(act *p4v1.Action) error {
+	*a = FabricIngressNextSetMcastGroupIdAction{}
+	if act == nil || act.ActionId != Action_FabricIngressNextSetMcastGroupId {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	for _, p := range act.Params {
+		switch p.ParamId {
+		case ActionParam_FabricIngressNextSetMcastGroupId_GroupId:
+			a.GroupId = p.Value
+		default:
+			return fmt.Errorf("invalid %T ID %d", p, p.ParamId)
+		}
+	}
+	return nil
+}
+
+// Action FabricEgress.bng_egress.downstream.encap_v4
+type FabricEgressBngEgressDownstreamEncapV4Action struct {
+}
+
+func (a *FabricEgressBngEgressDownstreamEncapV4Action) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_FabricEgressBngEgressDownstreamEncapV4}
+	return act
+}
+
+func (a *FabricEgressBngEgressDownstreamEncapV4Action) FromAction(act *p4v1.Action) error {
+	*a = FabricEgressBngEgressDownstreamEncapV4Action{}
+	if act == nil || act.ActionId != Action_FabricEgressBngEgressDownstreamEncapV4 {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	if len(act.Params) > 0 {
+		return fmt.Errorf("invalid %T ID %d", act.Params[0], act.Params[0].ParamId)
+	}
+	return nil
+}
+
+// Action FabricEgress.egress_next.pop_vlan
+type FabricEgressEgressNextPopVlanAction struct {
+}
+
+func (a *FabricEgressEgressNextPopVlanAction) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_FabricEgressEgressNextPopVlan}
+	return act
+}
+
+func (a *FabricEgressEgressNextPopVlanAction) FromAction(act *p4v1.Action) error {
+	*a = FabricEgressEgressNextPopVlanAction{}
+	if act == nil || act.ActionId != Action_FabricEgressEgressNextPopVlan {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	if len(act.Params) > 0 {
+		return fmt.Errorf("invalid %T ID %d", act.Params[0], act.Params[0].ParamId)
+	}
+	return nil
+}
+
+// Table FabricIngress.bng_ingress.upstream.t_pppoe_cp
+type FabricIngressBngIngressUpstreamTPppoeCpEntry struct {
+	PppoeCode     []byte         // exact, bitwidth 8
+	PppoeProtocol *codec.Ternary // ternary, bitwidth 16
+	Priority      int32
+	// One of: FabricIngressBngIngressUpstreamPuntToCpuAction, NopAction
+	Action codec.Action
+}
+
+func (e *FabricIngressBngIngressUpstreamTPppoeCpEntry) ToTableEntry() *p4v1.TableEntry {
+	t := &p4v1.TableEntry{TableId: Table_FabricIngressBngIngressUpstreamTPppoeCp}
+	if e.PppoeCode != nil {
+		t.Match = append(t.Match, codec.ExactMatch(Hdr_FabricIngressBngIngressUpstreamTPppoeCp_PppoeCode, e.PppoeCode))
+	}
+	if e.PppoeProtocol != nil {
+		t.Match = append(t.Match, codec.TernaryMatch(Hdr_FabricIngressBngIngressUpstreamTPppoeCp_PppoeProtocol, e.PppoeProtocol))
+	}
+	t.Priority = e.Priority
+	t.Action = codec.DirectTableAction(e.Action)
+	return t
+}
+
+func (e *FabricIngressBngIngressUpstreamTPppoeCpEntry) FromTableEntry(t *p4v1.TableEntry) error {
+	*e = FabricIngressBngIngressUpstreamTPppoeCpEntry{}
+	if t.TableId != Table_FabricIngressBngIngressUpstreamTPppoeCp {
+		return fmt.Errorf("invalid table ID %d", t.TableId)
+	}
+	var err error
+	for _, m := range t.Match {
+		switch m.FieldId {
+		case Hdr_FabricIngressBngIngressUpstreamTPppoeCp_PppoeCode:
+			e.PppoeCode, err = codec.GetExact(m)
+		case Hdr_FabricIngressBngIngressUpstreamTPppoeCp_PppoeProtocol:
+			e.PppoeProtocol, err = codec.GetTernary(m)
+		default:
+			err = fmt.Errorf("invalid %T ID %d", m, m.FieldId)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	e.Priority = t.Priority
+	act := t.GetAction().GetAction()
+	if act == nil {
+		if t.GetAction() != nil {
+			return fmt.Errorf("invalid Action %s", t.GetAction())
+		}
+		return nil
+	}
+	switch act.ActionId {
+	case Action_FabricIngressBngIngressUpstreamPuntToCpu:
+		e.Action = &FabricIngressBngIngressUpstreamPuntToCpuAction{}
+	case Action_Nop:
+		e.Action = &NopAction{}
+	default:
+		return fmt.Errorf("invalid Action %s", t.GetAction())
+	}
+	return e.Action.FromAction(act)
+}
+
+// Table FabricIngress.bng_ingress.upstream.t_pppoe_term_v4
+type FabricIngressBngIngressUpstreamTPppoeTermV4Entry struct {
+	LineId         []byte // exact, bitwidth 32
+	Ipv4Src        []byte // exact, bitwidth 32
+	PppoeSessionId []byte // exact, bitwidth 16
+	// One of: FabricIngressBngIngressUpstreamTermEnabledV4Action, FabricIngressBngIngressUpstreamTermDisabledAction
+	Action codec.Action
+}
+
+func (e *FabricIngressBngIngressUpstreamTPppoeTermV4Entry) ToTableEntry() *p4v1.TableEntry {
+	t := &p4v1.TableEntry{TableId: Table_FabricIngressBngIngressUpstreamTPppoeTermV4}
+	if e.LineId != nil {
+		t.Match = append(t.Match, codec.ExactMatch(Hdr_FabricIngressBngIngressUpstreamTPppoeTermV4_LineId, e.LineId))
+	}
+	if e.Ipv4Src != nil {
+		t.Match = append(t.Match, codec.ExactMatch(Hdr_FabricIngressBngIngressUpstreamTPppoeTermV4_Ipv4Src, e.Ipv4Src))
+	}
+	if e.PppoeSessionId != nil {
+		t.Match = append(t.Match, codec.ExactMatch(Hdr_FabricIngressBngIngressUpstreamTPppoeTermV4_PppoeSessionId, e.PppoeSessionId))
+	}
+	t.Action = codec.DirectTableAction(e.Action)
+	return t
+}
+
+func (e *FabricIngressBngIngressUpstreamTPppoeTermV4Entry) FromTableEntry(t *p4v1.TableEntry) error {
+	*e = FabricIngressBngIngressUpstreamTPppoeTermV4Entry{}
+	if t.TableId != Table_FabricIngressBngIngressUpstreamTPppoeTermV4 {
+		return fmt.Errorf("invalid table ID %d", t.TableId)
+	}
+	var err error
+	for _, m := range t.Match {
+		switch m.FieldId {
+		case Hdr_FabricIngressBngIngressUpstreamTPppoeTermV4_LineId:
+			e.LineId, err = codec.GetExact(m)
+		case Hdr_FabricIngressBngIngressUpstreamTPppoeTermV4_Ipv4Src:
+			e.Ipv4Src, err = codec.GetExact(m)
+		case Hdr_FabricIngressBngIngressUpstreamTPppoeTermV4_PppoeSessionId:
+			e.PppoeSessionId, err = codec.GetExact(m)
+		default:
+			err = fmt.Errorf("invalid %T ID %d", m, m.FieldId)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	act := t.GetAction().GetAction()
+	if act == nil {
+		if t.GetAction() != nil {
+			return fmt.Errorf("invalid Action %s", t.GetAction())
+		}
+		return nil
+	}
+	switch act.ActionId {
+	case Action_FabricIngressBngIngressUpstreamTermEnabledV4:
+		e.Action = &FabricIngressBngIngressUpstreamTermEnabledV4Action{}
+	case Action_FabricIngressBngIngressUpstreamTermDisabled:
+		e.Action = &FabricIngressBngIngressUpstreamTermDisabledAction{}
+	default:
+		return fmt.Errorf("invalid Action %s", t.GetAction())
+	}
+	return e.Action.FromAction(act)
+}
+
+// Table FabricIngress.bng_ingress.downstream.t_line_session_map
+type FabricIngressBngIngressDownstreamTLineSessionMapEntry struct {
+	LineId []byte // exact, bitwidth 32
+	// One of: NopAction, FabricIngressBngIngressDownstreamSetSessionAction, FabricIngressBngIngressDownstreamDropAction
+	Action codec.Action
+}
+
+func (e *FabricIngressBngIngressDownstreamTLineSessionMapEntry) ToTableEntry() *p4v1.TableEntry {
+	t := &p4v1.TableEntry{TableId: Table_FabricIngressBngIngressDownstreamTLineSessionMap}
+	if e.LineId != nil {
+		t.Match = append(t.Match, codec.ExactMatch(Hdr_FabricIngressBngIngressDownstreamTLineSessionMap_LineId, e.LineId))
+	}
+	t.Action = codec.DirectTableAction(e.Action)
+	return t
+}
+
+func (e *FabricIngressBngIngressDownstreamTLineSessionMapEntry) FromTableEntry(t *p4v1.TableEntry) error {
+	*e = FabricIngressBngIngressDownstreamTLineSessionMapEntry{}
+	if t.TableId != Table_FabricIngressBngIngressDownstreamTLineSessionMap {
+		return fmt.Errorf("invalid table ID %d", t.TableId)
+	}
+	var err error
+	for _, m := range t.Match {
+		switch m.FieldId {
+		case Hdr_FabricIngressBngIngressDownstreamTLineSessionMap_LineId:
+			e.LineId, err = codec.GetExact(m)
+		default:
+			err = fmt.Errorf("invalid %T ID %d", m, m.FieldId)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	act := t.GetAction().GetAction()
+	if act == nil {
+		if t.GetAction() != nil {
+			return fmt.Errorf("invalid Action %s", t.GetAction())
+		}
+		return nil
+	}
+	switch act.ActionId {
+	case Action_Nop:
+		e.Action = &NopAction{}
+	case Action_FabricIngressBngIngressDownstreamSetSession:
+		e.Action = &FabricIngressBngIngressDownstreamSetSessionAction{}
+	case Action_FabricIngressBngIngressDownstreamDrop:
+		e.Action = &FabricIngressBngIngressDownstreamDropAction{}
+	default:
+		return fmt.Errorf("invalid Action %s", t.GetAction())
+	}
+	return e.Action.FromAction(act)
+}
+
+// Table FabricIngress.bng_ingress.downstream.t_qos_v4
+type FabricIngressBngIngressDownstreamTQosV4Entry struct {
+	LineId   *codec.Ternary // ternary, bitwidth 32
+	Ipv4Src  *codec.Lpm     // lpm, bitwidth 32
+	Ipv4Dscp *codec.Ternary // ternary, bitwidth 6
+	Ipv4Ecn  *codec.Ternary // ternary, bitwidth 2
+	Priority int32
+	// One of: FabricIngressBngIngressDownstreamQosPrioAction, FabricIngressBngIngressDownstreamQosBesteffAction
+	Action codec.Action
+}
+
+func (e *FabricIngressBngIngressDownstreamTQosV4Entry) ToTableEntry() *p4v1.TableEntry {
+	t := &p4v1.TableEntry{TableId: Table_FabricIngressBngIngressDownstreamTQosV4}
+	if e.LineId != nil {
+		t.Match = append(t.Match, codec.TernaryMatch(Hdr_FabricIngressBngIngressDownstreamTQosV4_LineId, e.LineId))
+	}
+	if e.Ipv4Src != nil {
+		t.Match = append(t.Match, codec.LpmMatch(Hdr_FabricIngressBngIngressDownstreamTQosV4_Ipv4Src, e.Ipv4Src))
+	}
+	if e.Ipv4Dscp != nil {
+		t.Match = append(t.Match, codec.TernaryMatch(Hdr_FabricIngressBngIngressDownstreamTQosV4_Ipv4Dscp, e.Ipv4Dscp))
+	}
+	if e.Ipv4Ecn != nil {
+		t.Match = append(t.Match, codec.TernaryMatch(Hdr_FabricIngressBngIngressDownstreamTQosV4_Ipv4Ecn, e.Ipv4Ecn))
+	}
+	t.Priority = e.Priority
+	t.Action = codec.DirectTableAction(e.Action)
+	return t
+}
+
+func (e *FabricIngressBngIngressDownstreamTQosV4Entry) FromTableEntry(t *p4v1.TableEntry) error {
+	*e = FabricIngressBngIngressDownstreamTQosV4Entry{}
+	if t.TableId != Table_FabricIngressBngIngressDownstreamTQosV4 {
+		return fmt.Errorf("invalid table ID %d", t.TableId)
+	}
+	var err error
+	for _, m := range t.Match {
+		switch m.FieldId {
+		case Hdr_FabricIngressBngIngressDownstreamTQosV4_LineId:
+			e.LineId, err = codec.GetTernary(m)
+		case Hdr_FabricIngressBngIngressDownstreamTQosV4_Ipv4Src:
+			e.Ipv4Src, err = codec.GetLpm(m)
+		case Hdr_FabricIngressBngIngressDownstreamTQosV4_Ipv4Dscp:
+			e.Ipv4Dscp, err = codec.GetTernary(m)
+		case Hdr_FabricIngressBngIngressDownstreamTQosV4_Ipv4Ecn:
+			e.Ipv4Ecn, err = codec.GetTernary(m)
+		default:
+			err = fmt.Errorf("invalid %T ID %d", m, m.FieldId)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	e.Priority = t.Priority
+	act := t.GetAction().GetAction()
+	if act == nil {
+		if t.GetAction() != nil {
+			return fmt.Errorf("invalid Action %s", t.GetAction())
+		}
+		return nil
+	}
+	switch act.ActionId {
+	case Action_FabricIngressBngIngressDownstreamQosPrio:
+		e.Action = &FabricIngressBngIngressDownstreamQosPrioAction{}
+	case Action_FabricIngressBngIngressDownstreamQosBesteff:
+		e.Action = &FabricIngressBngIngressDownstreamQosBesteffAction{}
+	default:
+		return fmt.Errorf("invalid Action %s", t.GetAction())
+	}
+	return e.Action.FromAction(act)
+}
+
+// Table FabricIngress.bng_ingress.t_line_map
+type FabricIngressBngIngressTLineMapEntry struct {
+	STag []byte // exact, bitwidth 12
+	CTag []byte // exact, bitwidth 12
+	// One of: FabricIngressBngIngressSetLineAction
+	Action codec.Action
+}
+
+func (e *FabricIngressBngIngressTLineMapEntry) ToTableEntry() *p4v1.TableEntry {
+	t := &p4v1.TableEntry{TableId: Table_FabricIngressBngIngressTLineMap}
+	if e.STag != nil {
+		t.Match = append(t.Match, codec.ExactMatch(Hdr_FabricIngressBngIngressTLineMap_STag, e.STag))
+	}
+	if e.CTag != nil {
+		t.Match = append(t.Match, codec.ExactMatch(Hdr_FabricIngressBngIngressTLineMap_CTag, e.CTag))
+	}
+	t.Action = codec.DirectTableAction(e.Action)
+	return t
+}
+
+func (e *FabricIngressBngIngressTLineMapEntry) FromTableEntry(t *p4v1.TableEntry) error {
+	*e = FabricIngressBngIngressTLineMapEntry{}
+	if t.TableId != Table_FabricIngressBngIngressTLineMap {
+		return fmt.Errorf("invalid table ID %d", t.TableId)
+	}
+	var err error
+	for _, m := range t.Match {
+		switch m.FieldId {
+		case Hdr_FabricIngressBngIngressTLineMap_STag:
+			e.STag, err = codec.GetExact(m)
+		case Hdr_FabricIngressBngIngressTLineMap_CTag:
+			e.CTag, err = codec.GetExact(m)
+		default:
+			err = fmt.Errorf("invalid %T ID %d", m, m.FieldId)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	act := t.GetAction().GetAction()
+	if act == nil {
+		if t.GetAction() != nil {
+			return fmt.Errorf("invalid Action %s", t.GetAction())
+		}
+		return nil
+	}
+	switch act.ActionId {
+	case Action_FabricIngressBngIngressSetLine:
+		e.Action = &FabricIngressBngIngressSetLineAction{}
+	default:
+		return fmt.Errorf("invalid Action %s", t.GetAction())
+	}
+	return e.Action.FromAction(act)
+}
+
+// Table FabricIngress.filtering.ingress_port_vlan
+type FabricIngressFilteringIngressPortVlanEntry struct {
+	IgPort      []byte         // exact, bitwidth 9
+	VlanIsValid []byte         // exact, bitwidth 1
+	VlanId      *codec.Ternary // ternary, bitwidth 12
+	InnerVlanId *codec.Ternary // ternary, bitwidth 12
+	Priority    int32
+	// One of: FabricIngressFilteringDenyAction, FabricIngressFilteringPermitAction, FabricIngressFilteringPermitWithInternalVlanAction
+	Action codec.Action
+}
+
+func (e *FabricIngressFilteringIngressPortVlanEntry) ToTableEntry() *p4v1.TableEntry {
+	t := &p4v1.TableEntry{TableId: Table_FabricIngressFilteringIngressPortVlan}
+	if e.IgPort != nil {
+		t.Match = append(t.Match, codec.ExactMatch(Hdr_FabricIngressFilteringIngressPortVlan_IgPort, e.IgPort))
+	}
+	if e.VlanIsValid != nil {
+		t.Match = append(t.Match, codec.ExactMatch(Hdr_FabricIngressFilteringIngressPortVlan_VlanIsValid, e.VlanIsValid))
+	}
+	if e.VlanId != nil {
+		t.Match = append(t.Match, codec.TernaryMatch(Hdr_FabricIngressFilteringIngressPortVlan_VlanId, e.VlanId))
+	}
+	if e.InnerVlanId != nil {
+		t.Match = append(t.Match, codec.TernaryMatch(Hdr_FabricIngressFilteringIngressPortVlan_InnerVlanId, e.InnerVlanId))
+	}
+	t.Priority = e.Priority
+	t.Action = codec.DirectTableAction(e.Action)
+	return t
+}
+
+func (e *FabricIngressFilteringIngressPortVlanEntry) FromTableEntry(t *p4v1.TableEntry) error {
+	*e = FabricIngressFilteringIngressPortVlanEntry{}
+	if t.TableId != Table_FabricIngressFilteringIngressPortVlan {
+		return fmt.Errorf("invalid table ID %d", t.TableId)
+	}
+	var err error
+	for _, m := range t.Match {
+		switch m.FieldId {
+		case Hdr_FabricIngressFilteringIngressPortVlan_IgPort:
+			e.IgPort, err = codec.GetExact(m)
+		case Hdr_FabricIngressFilteringIngressPortVlan_VlanIsValid:
+			e.VlanIsValid, err = codec.GetExact(m)
+		case Hdr_FabricIngressFilteringIngressPortVlan_VlanId:
+			e.VlanId, err = codec.GetTernary(m)
+		case Hdr_FabricIngressFilteringIngressPortVlan_InnerVlanId:
+			e.InnerVlanId, err = codec.GetTernary(m)
+		default:
+			err = fmt.Errorf("invalid %T ID %d", m, m.FieldId)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	e.Priority = t.Priority
+	act := t.GetAction().GetAction()
+	if act == nil {
+		if t.GetAction() != nil {
+			return fmt.Errorf("invalid Action %s", t.GetAction())
+		}
+		return nil
+	}
+	switch act.ActionId {
+	case Action_FabricIngressFilteringDeny:
+		e.Action = &FabricIngressFilteringDenyAction{}
+	case Action_FabricIngressFilteringPermit:
+		e.Action = &FabricIngressFilteringPermitAction{}
+	case Action_FabricIngressFilteringPermitWithInternalVlan:
+		e.Action = &FabricIngressFilteringPermitWithInternalVlanAction{}
+	default:
+		return fmt.Errorf("invalid Action %s", t.GetAction())
+	}
+	return e.Action.FromAction(act)
+}
+
+// Table FabricIngress.filtering.fwd_classifier
+type FabricIngressFilteringFwdClassifierEntry struct {
+	IgPort    []byte         // exact, bitwidth 9
+	EthDst    *codec.Ternary // ternary, bitwidth 48
+	EthType   *codec.Ternary // ternary, bitwidth 16
+	IpEthType []byte         // exact, bitwidth 16
+	Priority  int32
+	// One of: FabricIngressFilteringSetForwardingTypeAction
+	Action codec.Action
+}
+
+func (e *FabricIngressFilteringFwdClassifierEntry) ToTableEntry() *p4v1.TableEntry {
+	t := &p4v1.TableEntry{TableId: Table_FabricIngressFilteringFwdClassifier}
+	if e.IgPort != nil {
+		t.Match = append(t.Match, codec.ExactMatch(Hdr_FabricIngressFilteringFwdClassifier_IgPort, e.IgPort))
+	}
+	if e.EthDst != nil {
+		t.Match = append(t.Match, codec.TernaryMatch(Hdr_FabricIngressFilteringFwdClassifier_EthDst, e.EthDst))
+	}
+	if e.EthType != nil {
+		t.Match = append(t.Match, codec.TernaryMatch(Hdr_FabricIngressFilteringFwdClassifier_EthType, e.EthType))
+	}
+	if e.IpEthType != nil {
+		t.Match = append(t.Match, codec.ExactMatch(Hdr_FabricIngressFilteringFwdClassifier_IpEthType, e.IpEthType))
+	}
+	t.Priority = e.Priority
+	t.Action = codec.DirectTableAction(e.Action)
+	return t
+}
+
+func (e *FabricIngressFilteringFwdClassifierEntry) FromTableEntry(t *p4v1.TableEntry) error {
+	*e = FabricIngressFilteringFwdClassifierEntry{}
+	if t.TableId != Table_FabricIngressFilteringFwdClassifier {
+		return fmt.Errorf("invalid table ID %d", t.TableId)
+	}
+	var err error
+	for _, m := range t.Match {
+		switch m.FieldId {
+		case Hdr_FabricIngressFilteringFwdClassifier_IgPort:
+			e.IgPort, err = codec.GetExact(m)
+		case Hdr_FabricIngressFilteringFwdClassifier_EthDst:
+			e.EthDst, err = codec.GetTernary(m)
+		case Hdr_FabricIngressFilteringFwdClassifier_EthType:
+			e.EthType, err = codec.GetTernary(m)
+		case Hdr_FabricIngressFilteringFwdClassifier_IpEthType:
+			e.IpEthType, err = codec.GetExact(m)
+		default:
+			err = fmt.Errorf("invalid %T ID %d", m, m.FieldId)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	e.Priority = t.Priority
+	act := t.GetAction().GetAction()
+	if act == nil {
+		if t.GetAction() != nil {
+			return fmt.Errorf("invalid Action %s", t.GetAction())
+		}
+		return nil
+	}
+	switch act.ActionId {
+	case Action_FabricIngressFilteringSetForwardingType:
+		e.Action = &FabricIngressFilteringSetForwardingTypeAction{}
+	default:
+		return fmt.Errorf("invalid Action %s", t.GetAction())
+	}
+	return e.Action.FromAction(act)
+}
+
+// Table FabricIngress.forwarding.bridging
+type FabricIngressForwardingBridgingEntry struct {
+	VlanId   []byte         // exact, bitwidth 12
+	EthDst   *codec.Ternary // ternary, bitwidth 48
+	Priority int32
+	// One of: FabricIngressForwardingSetNextIdBridgingAction, NopAction
+	Action codec.Action
+}
+
+func (e *FabricIngressForwardingBridgingEntry) ToTableEntry() *p4v1.TableEntry {
+	t := &p4v1.TableEntry{TableId: Table_FabricIngressForwardingBridging}
+	if e.VlanId != nil {
+		t.Match = append(t.Match, codec.ExactMatch(Hdr_FabricIngressForwardingBridging_VlanId, e.VlanId))
+	}
+	if e.EthDst != nil {
+		t.Match = append(t.Match, codec.TernaryMatch(Hdr_FabricIngressForwardingBridging_EthDst, e.EthDst))
+	}
+	t.Priority = e.Priority
+	t.Action = codec.DirectTableAction(e.Action)
+	return t
+}
+
+func (e *FabricIngressForwardingBridgingEntry) FromTableEntry(t *p4v1.TableEntry) error {
+	*e = FabricIngressForwardingBridgingEntry{}
+	if t.TableId != Table_FabricIngressForwardingBridging {
+		return fmt.Errorf("invalid table ID %d", t.TableId)
+	}
+	var err error
+	for _, m := range t.Match {
+		switch m.FieldId {
+		case Hdr_FabricIngressForwardingBridging_VlanId:
+			e.VlanId, err = codec.GetExact(m)
+		case Hdr_FabricIngressForwardingBridging_EthDst:
+			e.EthDst, err = codec.GetTernary(m)
+		default:
+			err = fmt.Errorf("invalid %T ID %d", m, m.FieldId)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	e.Priority = t.Priority
+	act := t.GetAction().GetAction()
+	if act == nil {
+		if t.GetAction() != nil {
+			return fmt.Errorf("invalid Action %s", t.GetAction())
+		}
+		return nil
+	}
+	switch act.ActionId {
+	case Action_FabricIngressForwardingSetNextIdBridging:
+		e.Action = &FabricIngressForwardingSetNextIdBridgingAction{}
+	case Action_Nop:
+		e.Action = &NopAction{}
+	default:
+		return fmt.Errorf("invalid Action %s", t.GetAction())
+	}
+	return e.Action.FromAction(act)
+}
+
+// Table FabricIngress.forwarding.mpls
+type FabricIngressForwardingMplsEntry struct {
+	MplsLabel []byte // exact, bitwidth 20
+	// One of: FabricIngressForwardingPopMplsAndNextAction, NopAction
+	Action codec.Action
+}
+
+func (e *FabricIngressForwardingMplsEntry) ToTableEntry() *p4v1.TableEntry {
+	t := &p4v1.TableEntry{TableId: Table_FabricIngressForwardingMpls}
+	if e.MplsLabel != nil {
+		t.Match = append(t.Match, codec.ExactMatch(Hdr_FabricIngressForwardingMpls_MplsLabel, e.MplsLabel))
+	}
+	t.Action = codec.DirectTableAction(e.Action)
+	return t
+}
+
+func (e *FabricIngressForwardingMplsEntry) FromTableEntry(t *p4v1.TableEntry) error {
+	*e = FabricIngressForwardingMplsEntry{}
+	if t.TableId != Table_FabricIngressForwardingMpls {
+		return fmt.Errorf("invalid table ID %d", t.TableId)
+	}
+	var err error
+	for _, m := range t.Match {
+		switch m.FieldId {
+		case Hdr_FabricIngressForwardingMpls_MplsLabel:
+			e.MplsLabel, err = codec.GetExact(m)
+		default:
+			err = fmt.Errorf("invalid %T ID %d", m, m.FieldId)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	act := t.GetAction().GetAction()
+	if act == nil {
+		if t.GetAction() != nil {
+			return fmt.Errorf("invalid Action %s", t.GetAction())
+		}
+		return nil
+	}
+	switch act.ActionId {
+	case Action_FabricIngressForwardingPopMplsAndNext:
+		e.Action = &FabricIngressForwardingPopMplsAndNextAction{}
+	case Action_Nop:
+		e.Action = &NopAction{}
+	default:
+		return fmt.Errorf("invalid Action %s", t.GetAction())
+	}
+	return e.Action.FromAction(act)
+}
+
+// Table FabricIngress.forwarding.routing_v4
+type FabricIngressForwardingRoutingV4Entry struct {
+	Ipv4Dst *codec.Lpm // lpm, bitwidth 32
+	// One of: FabricIngressForwardingSetNextIdRoutingV4Action, FabricIngressForwardingNopRoutingV4Action, NopAction
+	Action codec.Action
+}
+
+func (e *FabricIngressForwardingRoutingV4Entry) ToTableEntry() *p4v1.TableEntry {
+	t := &p4v1.TableEntry{TableId: Table_FabricIngressForwardingRoutingV4}
+	if e.Ipv4Dst != nil {
+		t.Match = append(t.Match, codec.LpmMatch(Hdr_FabricIngressForwardingRoutingV4_Ipv4Dst, e.Ipv4Dst))
+	}
+	t.Action = codec.DirectTableAction(e.Action)
+	return t
+}
+
+func (e *FabricIngressForwardingRoutingV4Entry) FromTableEntry(t *p4v1.TableEntry) error {
+	*e = FabricIngressForwardingRoutingV4Entry{}
+	if t.TableId != Table_FabricIngressForwardingRoutingV4 {
+		return fmt.Errorf("invalid table ID %d", t.TableId)
+	}
+	var err error
+	for _, m := range t.Match {
+		switch m.FieldId {
+		case Hdr_FabricIngressForwardingRoutingV4_Ipv4Dst:
+			e.Ipv4Dst, err = codec.GetLpm(m)
+		default:
+			err = fmt.Errorf("invalid %T ID %d", m, m.FieldId)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	act := t.GetAction().GetAction()
+	if act == nil {
+		if t.GetAction() != nil {
+			return fmt.Errorf("invalid Action %s", t.GetAction())
+		}
+		return nil
+	}
+	switch act.ActionId {
+	case Action_FabricIngressForwardingSetNextIdRoutingV4:
+		e.Action = &FabricIngressForwardingSetNextIdRoutingV4Action{}
+	case Action_FabricIngressForwardingNopRoutingV4:
+		e.Action = &FabricIngressForwardingNopRoutingV4Action{}
+	case Action_Nop:
+		e.Action = &NopAction{}
+	default:
+		return fmt.Errorf("invalid Action %s", t.GetAction())
+	}
+	return e.Action.FromAction(act)
+}
+
+// Table FabricIngress.acl.acl
+type FabricIngressAclAclEntry struct {
+	IgPort   *codec.Ternary // ternary, bitwidth 9
+	IpProto  *codec.Ternary // ternary, bitwidth 8
+	L4Sport  *codec.Ternary // ternary, bitwidth 16
+	L4Dport  *codec.Ternary // ternary, bitwidth 16
+	EthDst   *codec.Ternary // ternary, bitwidth 48
+	EthSrc   *codec.Ternary // ternary, bitwidth 48
+	VlanId   *codec.Ternary // ternary, bitwidth 12
+	EthType  *codec.Ternary // ternary, bitwidth 16
+	Ipv4Src  *codec.Ternary // ternary, bitwidth 32
+	Ipv4Dst  *codec.Ternary // ternary, bitwidth 32
+	IcmpType *codec.Ternary // ternary, bitwidth 8
+	IcmpCode *codec.Ternary // ternary, bitwidth 8
+	Priority int32
+	// One of: FabricIngressAclSetNextIdAclAction, FabricIngressAclPuntToCpuAction, FabricIngressAclSetCloneSessionIdAction, FabricIngressAclDropAction, FabricIngressAclNopAclAction
+	Action codec.Action
+}
+
+func (e *FabricIngressAclAclEntry) ToTableEntry() *p4v1.TableEntry {
+	t := &p4v1.TableEntry{TableId: Table_FabricIngressAclAcl}
+	if e.IgPort != nil {
+		t.Match = append(t.Match, codec.TernaryMatch(Hdr_FabricIngressAclAcl_IgPort, e.IgPort))
+	}
+	if e.IpProto != nil {
+		t.Match = append(t.Match, codec.TernaryMatch(Hdr_FabricIngressAclAcl_IpProto, e.IpProto))
+	}
+	if e.L4Sport != nil {
+		t.Match = append(t.Match, codec.TernaryMatch(Hdr_FabricIngressAclAcl_L4Sport, e.L4Sport))
+	}
+	if e.L4Dport != nil {
+		t.Match = append(t.Match, codec.TernaryMatch(Hdr_FabricIngressAclAcl_L4Dport, e.L4Dport))
+	}
+	if e.EthDst != nil {
+		t.Match = append(t.Match, codec.TernaryMatch(Hdr_FabricIngressAclAcl_EthDst, e.EthDst))
+	}
+	if e.EthSrc != nil {
+		t.Match = append(t.Match, codec.TernaryMatch(Hdr_FabricIngressAclAcl_EthSrc, e.EthSrc))
+	}
+	if e.VlanId != nil {
+		t.Match = append(t.Match, codec.TernaryMatch(Hdr_FabricIngressAclAcl_VlanId, e.VlanId))
+	}
+	if e.EthType != nil {
+		t.Match = append(t.Match, codec.TernaryMatch(Hdr_FabricIngressAclAcl_EthType, e.EthType))
+	}
+	if e.Ipv4Src != nil {
+		t.Match = append(t.Match, codec.TernaryMatch(Hdr_FabricIngressAclAcl_Ipv4Src, e.Ipv4Src))
+	}
+	if e.Ipv4Dst != nil {
+		t.Match = append(t.Match, codec.TernaryMatch(Hdr_FabricIngressAclAcl_Ipv4Dst, e.Ipv4Dst))
+	}
+	if e.IcmpType != nil {
+		t.Match = append(t.Match, codec.TernaryMatch(Hdr_FabricIngressAclAcl_IcmpType, e.IcmpType))
+	}
+	if e.IcmpCode != nil {
+		t.Match = append(t.Match, codec.TernaryMatch(Hdr_FabricIngressAclAcl_IcmpCode, e.IcmpCode))
+	}
+	t.Priority = e.Priority
+	t.Action = codec.DirectTableAction(e.Action)
+	return t
+}
+
+func (e *FabricIngressAclAclEntry) FromTableEntry(t *p4v1.TableEntry) error {
+	*e = FabricIngressAclAclEntry{}
+	if t.TableId != Table_FabricIngressAclAcl {
+		return fmt.Errorf("invalid table ID %d", t.TableId)
+	}
+	var err error
+	for _, m := range t.Match {
+		switch m.FieldId {
+		case Hdr_FabricIngressAclAcl_IgPort:
+			e.IgPort, err = codec.GetTernary(m)
+		case Hdr_FabricIngressAclAcl_IpProto:
+			e.IpProto, err = codec.GetTernary(m)
+		case Hdr_FabricIngressAclAcl_L4Sport:
+			e.L4Sport, err = codec.GetTernary(m)
+		case Hdr_FabricIngressAclAcl_L4Dport:
+			e.L4Dport, err = codec.GetTernary(m)
+		case Hdr_FabricIngressAclAcl_EthDst:
+			e.EthDst, err = codec.GetTernary(m)
+		case Hdr_FabricIngressAclAcl_EthSrc:
+			e.EthSrc, err = codec.GetTernary(m)
+		case Hdr_FabricIngressAclAcl_VlanId:
+			e.VlanId, err = codec.GetTernary(m)
+		case Hdr_FabricIngressAclAcl_EthType:
+			e.EthType, err = codec.GetTernary(m)
+		case Hdr_FabricIngressAclAcl_Ipv4Src:
+			e.Ipv4Src, err = codec.GetTernary(m)
+		case Hdr_FabricIngressAclAcl_Ipv4Dst:
+			e.Ipv4Dst, err = codec.GetTernary(m)
+		case Hdr_FabricIngressAclAcl_IcmpType:
+			e.IcmpType, err = codec.GetTernary(m)
+		case Hdr_FabricIngressAclAcl_IcmpCode:
+			e.IcmpCode, err = codec.GetTernary(m)
+		default:
+			err = fmt.Errorf("invalid %T ID %d", m, m.FieldId)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	e.Priority = t.Priority
+	act := t.GetAction().GetAction()
+	if act == nil {
+		if t.GetAction() != nil {
+			return fmt.Errorf("invalid Action %s", t.GetAction())
+		}
+		return nil
+	}
+	switch act.ActionId {
+	case Action_FabricIngressAclSetNextIdAcl:
+		e.Action = &FabricIngressAclSetNextIdAclAction{}
+	case Action_FabricIngressAclPuntToCpu:
+		e.Action = &FabricIngressAclPuntToCpuAction{}
+	case Action_FabricIngressAclSetCloneSessionId:
+		e.Action = &FabricIngressAclSetCloneSessionIdAction{}
+	case Action_FabricIngressAclDrop:
+		e.Action = &FabricIngressAclDropAction{}
+	case Action_FabricIngressAclNopAcl:
+		e.Action = &FabricIngressAclNopAclAction{}
+	default:
+		return fmt.Errorf("invalid Action %s", t.GetAction())
+	}
+	return e.Action.FromAction(act)
+}
+
+// Table FabricIngress.next.next_vlan
+type FabricIngressNextNextVlanEntry struct {
+	NextId []byte // exact, bitwidth 32
+	// One of: FabricIngressNextSetVlanAction, FabricIngressNextSetDoubleVlanAction, NopAction
+	Action codec.Action
+}
+
+func (e *FabricIngressNextNextVlanEntry) ToTableEntry() *p4v1.TableEntry {
+	t := &p4v1.TableEntry{TableId: Table_FabricIngressNextNextVlan}
+	if e.NextId != nil {
+		t.Match = append(t.Match, codec.ExactMatch(Hdr_FabricIngressNextNextVlan_NextId, e.NextId))
+	}
+	t.Action = codec.DirectTableAction(e.Action)
+	return t
+}
+
+func (e *FabricIngressNextNextVlanEntry) FromTableEntry(t *p4v1.TableEntry) error {
+	*e = FabricIngressNextNextVlanEntry{}
+	if t.TableId != Table_FabricIngressNextNextVlan {
+		return fmt.Errorf("invalid table ID %d", t.TableId)
+	}
+	var err error
+	for _, m := range t.Match {
+		switch m.FieldId {
+		case Hdr_FabricIngressNextNextVlan_NextId:
+			e.NextId, err = codec.GetExact(m)
+		default:
+			err = fmt.Errorf("invalid %T ID %d", m, m.FieldId)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	act := t.GetAction().GetAction()
+	if act == nil {
+		if t.GetAction() != nil {
+			return fmt.Errorf("invalid Action %s", t.GetAction())
+		}
+		return nil
+	}
+	switch act.ActionId {
+	case Action_FabricIngressNextSetVlan:
+		e.Action = &FabricIngressNextSetVlanAction{}
+	case Action_FabricIngressNextSetDoubleVlan:
+		e.Action = &FabricIngressNextSetDoubleVlanAction{}
+	case Action_Nop:
+		e.Action = &NopAction{}
+	default:
+		return fmt.Errorf("invalid Action %s", t.GetAction())
+	}
+	return e.Action.FromAction(act)
+}
+
+// Table FabricIngress.next.hashed
+type FabricIngressNextHashedEntry struct {
+	NextId                []byte // exact, bitwidth 32
+	ActionProfileGroupId  uint32
+	ActionProfileMemberId uint32
+}
+
+func (e *FabricIngressNextHashedEntry) ToTableEntry() *p4v1.TableEntry {
+	t := &p4v1.TableEntry{TableId: Table_FabricIngressNextHashed}
+	if e.NextId != nil {
+		t.Match = append(t.Match, codec.ExactMatch(Hdr_FabricIngressNextHashed_NextId, e.NextId))
+	}
+	t.Action = codec.IndirectTableAction(e.ActionProfileGroupId, e.ActionProfileMemberId)
+	return t
+}
+
+func (e *FabricIngressNextHashedEntry) FromTableEntry(t *p4v1.TableEntry) error {
+	*e = FabricIngressNextHashedEntry{}
+	if t.TableId != Table_FabricIngressNextHashed {
+		return fmt.Errorf("invalid table ID %d", t.TableId)
+	}
+	var err error
+	for _, m := range t.Match {
+		switch m.FieldId {
+		case Hdr_FabricIngressNextHashed_NextId:
+			e.NextId, err = codec.GetExact(m)
+		default:
+			err = fmt.Errorf("invalid %T ID %d", m, m.FieldId)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	switch a := t.GetAction().GetType().(type) {
+	case nil:
+	case *p4v1.TableAction_ActionProfileGroupId:
+		e.ActionProfileGroupId = a.ActionProfileGroupId
+	case *p4v1.TableAction_ActionProfileMemberId:
+		e.ActionProfileMemberId = a.ActionProfileMemberId
+	default:
+		return fmt.Errorf("invalid Action %s", t.GetAction())
+	}
+	return nil
+}
+
+// Table FabricIngress.next.multicast
+type FabricIngressNextMulticastEntry struct {
+	NextId []byte // exact, bitwidth 32
+	// One of: FabricIngressNextSetMcastGroupIdAction, NopAction
+	Action codec.Action
+}
+
+func (e *FabricIngressNextMulticastEntry) ToTableEntry() *p4v1.TableEntry {
+	t := &p4v1.TableEntry{TableId: Table_FabricIngressNextMulticast}
+	if e.NextId != nil {
+		t.Match = append(t.Match, codec.ExactMatch(Hdr_FabricIngressNextMulticast_NextId, e.NextId))
+	}
+	t.Action = codec.DirectTableAction(e.Action)
+	return t
+}
+
+func (e *FabricIngressNextMulticastEntry) FromTableEntry(t *p4v1.TableEntry) error {
+	*e = FabricIngressNextMulticastEntry{}
+	if t.TableId != Table_FabricIngressNextMulticast {
+		return fmt.Errorf("invalid table ID %d", t.TableId)
+	}
+	var err error
+	for _, m := range t.Match {
+		switch m.FieldId {
+		case Hdr_FabricIngressNextMulticast_NextId:
+			e.NextId, err = codec.GetExact(m)
+		default:
+			err = fmt.Errorf("invalid %T ID %d", m, m.FieldId)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	act := t.GetAction().GetAction()
+	if act == nil {
+		if t.GetAction() != nil {
+			return fmt.Errorf("invalid Action %s", t.GetAction())
+		}
+		return nil
+	}
+	switch act.ActionId {
+	case Action_FabricIngressNextSetMcastGroupId:
+		e.Action = &FabricIngressNextSetMcastGroupIdAction{}
+	case Action_Nop:
+		e.Action = &NopAction{}
+	default:
+		return fmt.Errorf("invalid Action %s", t.GetAction())
+	}
+	return e.Action.FromAction(act)
+}
+
+// Table FabricEgress.egress_next.egress_vlan
+type FabricEgressEgressNextEgressVlanEntry struct {
+	VlanId []byte // exact, bitwidth 12
+	EgPort []byte // exact, bitwidth 9
+	// One of: FabricEgressEgressNextPopVlanAction, NopAction
+	Action codec.Action
+}
+
+func (e *FabricEgressEgressNextEgressVlanEntry) ToTableEntry() *p4v1.TableEntry {
+	t := &p4v1.TableEntry{TableId: Table_FabricEgressEgressNextEgressVlan}
+	if e.VlanId != nil {
+		t.Match = append(t.Match, codec.ExactMatch(Hdr_FabricEgressEgressNextEgressVlan_VlanId, e.VlanId))
+	}
+	if e.EgPort != nil {
+		t.Match = append(t.Match, codec.ExactMatch(Hdr_FabricEgressEgressNextEgressVlan_EgPort, e.EgPort))
+	}
+	t.Action = codec.DirectTableAction(e.Action)
+	return t
+}
+
+func (e *FabricEgressEgressNextEgressVlanEntry) FromTableEntry(t *p4v1.TableEntry) error {
+	*e = FabricEgressEgressNextEgressVlanEntry{}
+	if t.TableId != Table_FabricEgressEgressNextEgressVlan {
+		return fmt.Errorf("invalid table ID %d", t.TableId)
+	}
+	var err error
+	for _, m := range t.Match {
+		switch m.FieldId {
+		case Hdr_FabricEgressEgressNextEgressVlan_VlanId:
+			e.VlanId, err = codec.GetExact(m)
+		case Hdr_FabricEgressEgressNextEgressVlan_EgPort:
+			e.EgPort, err = codec.GetExact(m)
+		default:
+			err = fmt.Errorf("invalid %T ID %d", m, m.FieldId)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	act := t.GetAction().GetAction()
+	if act == nil {
+		if t.GetAction() != nil {
+			return fmt.Errorf("invalid Action %s", t.GetAction())
+		}
+		return nil
+	}
+	switch act.ActionId {
+	case Action_FabricEgressEgressNextPopVlan:
+		e.Action = &FabricEgressEgressNextPopVlanAction{}
+	case Action_Nop:
+		e.Action = &NopAction{}
+	default:
+		return fmt.Errorf("invalid Action %s", t.GetAction())
+	}
+	return e.Action.FromAction(act)
+}
+
+// Member of action profile FabricIngress.next.hashed_selector
+type FabricIngressNextHashedSelectorMember struct {
+	MemberId uint32
+	// One of: FabricIngressNextMplsRoutingHashedAction, FabricIngressNextRoutingHashedAction, FabricIngressNextOutputHashedAction
+	Action codec.Action
+}
+
+func (m *FabricIngressNextHashedSelectorMember) ToActionProfileMember() *p4v1.ActionProfileMember {
+	member := &p4v1.ActionProfileMember{ActionProfileId: ActionProfile_FabricIngressNextHashedSelector, MemberId: m.MemberId}
+	if m.Action != nil {
+		member.Action = m.Action.ToAction()
+	}
+	return member
+}
+
+func (m *FabricIngressNextHashedSelectorMember) FromActionProfileMember(member *p4v1.ActionProfileMember) error {
+	*m = FabricIngressNextHashedSelectorMember{}
+	if member.ActionProfileId != ActionProfile_FabricIngressNextHashedSelector {
+		return fmt.Errorf("invalid action profile ID %d", member.ActionProfileId)
+	}
+	m.MemberId = member.MemberId
+	if member.Action == nil {
+		return nil
+	}
+	switch member.Action.ActionId {
+	case Action_FabricIngressNextMplsRoutingHashed:
+		m.Action = &FabricIngressNextMplsRoutingHashedAction{}
+	case Action_FabricIngressNextRoutingHashed:
+		m.Action = &FabricIngressNextRoutingHashedAction{}
+	case Action_FabricIngressNextOutputHashed:
+		m.Action = &FabricIngressNextOutputHashedAction{}
+	default:
+		return fmt.Errorf("invalid Action %s", member.Action)
+	}
+	return m.Action.FromAction(member.Action)
+}
+
+// Group of action profile FabricIngress.next.hashed_selector
+type FabricIngressNextHashedSelectorGroup struct {
+	GroupId uint32
+	Members []*p4v1.ActionProfileGroup_Member
+	MaxSize int32
+}
+
+func (g *FabricIngressNextHashedSelectorGroup) ToActionProfileGroup() *p4v1.ActionProfileGroup {
+	return &p4v1.ActionProfileGroup{
+		ActionProfileId: ActionProfile_FabricIngressNextHashedSelector,
+		GroupId:         g.GroupId,
+		Members:         g.Members,
+		MaxSize:         g.MaxSize,
+	}
+}
+
+func (g *FabricIngressNextHashedSelectorGroup) FromActionProfileGroup(group *p4v1.ActionProfileGroup) error {
+	*g = FabricIngressNextHashedSelectorGroup{}
+	if group.ActionProfileId != ActionProfile_FabricIngressNextHashedSelector {
+		return fmt.Errorf("invalid action profile ID %d", group.ActionProfileId)
+	}
+	g.GroupId = group.GroupId
+	g.Members = group.Members
+	g.MaxSize = group.MaxSize
+	return nil
+}
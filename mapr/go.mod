@@ -1,11 +1,17 @@
 module mapr
 
-go 1.14
+go 1.15
 
 require (
-	github.com/golang/protobuf v1.4.0
+	github.com/golang/protobuf v1.5.2
 	github.com/p4lang/p4runtime v1.1.1-0.20200501174942-71cbcdad8ea4
+	github.com/prometheus/client_golang v1.6.0
 	github.com/sirupsen/logrus v1.6.0
-	github.com/stretchr/testify v1.5.1
-	google.golang.org/grpc v1.28.1
+	github.com/stretchr/testify v1.7.0
+	go.opentelemetry.io/otel v1.0.1
+	go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc v1.0.1
+	go.opentelemetry.io/otel/sdk v1.0.1
+	go.opentelemetry.io/otel/trace v1.0.1
+	google.golang.org/grpc v1.41.0
+	google.golang.org/protobuf v1.27.1
 )
@@ -0,0 +1,118 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package health reports whether mapr can serve controllers, via the gRPC health service (grpc.health.v1.Health) and
+// an HTTP readiness endpoint. mapr is not serving while any of a set of problems is active, e.g., while the target is
+// unreachable.
+package health
+
+import (
+	"fmt"
+	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"mapr/logging"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var log = logging.Subsystem("health")
+
+// Problems preventing mapr from serving controllers.
+const (
+	TargetUnreachable = "target unreachable"
+	NoPipelineConfig  = "no pipeline config"
+	Reconciling       = "reconciling"
+)
+
+// Tracks the active problems and reports the serving status accordingly. Safe for concurrent use.
+type Checker struct {
+	mu       sync.Mutex
+	server   *grpchealth.Server
+	services []string
+	problems map[string]bool
+}
+
+// Returns a checker reporting the status of the given services, in addition to the overall one (the empty service
+// name), with the given problems initially active.
+func NewChecker(services []string, problems ...string) *Checker {
+	c := &Checker{
+		server:   grpchealth.NewServer(),
+		services: append([]string{""}, services...),
+		problems: make(map[string]bool),
+	}
+	for _, p := range problems {
+		c.problems[p] = true
+	}
+	c.update()
+	return c
+}
+
+// Sets whether the given problem is active.
+func (c *Checker) Set(problem string, active bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.problems[problem] == active {
+		return
+	}
+	if active {
+		c.problems[problem] = true
+		log.Warnf("Not serving: %s", problem)
+	} else {
+		delete(c.problems, problem)
+		log.Infof("Resolved: %s", problem)
+	}
+	c.update()
+}
+
+// Returns the active problems, sorted, empty if serving.
+func (c *Checker) Problems() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.problemsLocked()
+}
+
+// Must be called with mu held.
+func (c *Checker) problemsLocked() []string {
+	problems := make([]string, 0, len(c.problems))
+	for p := range c.problems {
+		problems = append(problems, p)
+	}
+	sort.Strings(problems)
+	return problems
+}
+
+// Must be called with mu held.
+func (c *Checker) update() {
+	status := healthpb.HealthCheckResponse_SERVING
+	if len(c.problems) > 0 {
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	for _, s := range c.services {
+		c.server.SetServingStatus(s, status)
+	}
+}
+
+// Registers the gRPC health service on the given server.
+func (c *Checker) Register(s *grpc.Server) {
+	healthpb.RegisterHealthServer(s, c.server)
+}
+
+// Returns an HTTP handler responding 200 if serving, otherwise 503 with the active problems, one per line.
+func (c *Checker) ReadyHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		problems := c.Problems()
+		if len(problems) > 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = fmt.Fprintln(w, strings.Join(problems, "\n"))
+			return
+		}
+		_, _ = fmt.Fprintln(w, "ok")
+	})
+}
@@ -0,0 +1,61 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package health
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func checkStatus(t *testing.T, c *Checker, service string) healthpb.HealthCheckResponse_ServingStatus {
+	response, err := c.server.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	require.NoError(t, err)
+	return response.Status
+}
+
+func checkReady(t *testing.T, c *Checker) (int, string) {
+	recorder := httptest.NewRecorder()
+	c.ReadyHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	body, err := ioutil.ReadAll(recorder.Body)
+	require.NoError(t, err)
+	return recorder.Code, string(body)
+}
+
+func Test_Checker(t *testing.T) {
+	c := NewChecker([]string{"p4.v1.P4Runtime"}, TargetUnreachable, NoPipelineConfig)
+	assert.Equal(t, []string{NoPipelineConfig, TargetUnreachable}, c.Problems())
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, checkStatus(t, c, ""))
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, checkStatus(t, c, "p4.v1.P4Runtime"))
+	code, body := checkReady(t, c)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "no pipeline config\ntarget unreachable\n", body)
+
+	c.Set(TargetUnreachable, false)
+	c.Set(NoPipelineConfig, false)
+	assert.Empty(t, c.Problems())
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, checkStatus(t, c, ""))
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, checkStatus(t, c, "p4.v1.P4Runtime"))
+	code, body = checkReady(t, c)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "ok\n", body)
+
+	c.Set(Reconciling, true)
+	c.Set(Reconciling, true)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, checkStatus(t, c, "p4.v1.P4Runtime"))
+	c.Set(Reconciling, false)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, checkStatus(t, c, "p4.v1.P4Runtime"))
+
+	// Unknown services.
+	_, err := c.server.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "foo"})
+	assert.Error(t, err)
+}
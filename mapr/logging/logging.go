@@ -0,0 +1,143 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package logging provides the loggers of the subsystems of mapr, e.g., "translate" or "fabric", whose level can be set
+// independently, and which write in the same format to the same output.
+//
+// Packages declare their logger as a package variable named log, which has the same methods as the logrus package:
+//
+//	var log = logging.Subsystem("fabric")
+package logging
+
+import (
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"io"
+	"os"
+	"sort"
+	"sync"
+)
+
+// Output formats.
+const (
+	FormatText = "text"
+	FormatJson = "json"
+)
+
+// Logging settings of all subsystems.
+type Config struct {
+	// Default level of all subsystems, one of the logrus levels, e.g., "info" or "trace".
+	Level string
+	// Levels of specific subsystems, overriding the default one.
+	Subsystems map[string]string
+	// FormatText or FormatJson.
+	Format string
+}
+
+var (
+	mu         sync.Mutex
+	subsystems = make(map[string]*logrus.Logger)
+	// Settings applied to subsystems created later.
+	level               = logrus.InfoLevel
+	levels              = make(map[string]logrus.Level)
+	formatter           = newFormatter(FormatText)
+	output    io.Writer = os.Stderr
+)
+
+func newFormatter(format string) logrus.Formatter {
+	if format == FormatJson {
+		return &logrus.JSONFormatter{}
+	}
+	return &logrus.TextFormatter{
+		ForceColors:   true,
+		FullTimestamp: true,
+		DisableQuote:  true,
+	}
+}
+
+// Returns the logger of the subsystem with the given name, creating it if needed. Entries are logged with the name in
+// the subsystem field.
+func Subsystem(name string) *logrus.Entry {
+	mu.Lock()
+	defer mu.Unlock()
+	logger, ok := subsystems[name]
+	if !ok {
+		logger = logrus.New()
+		logger.SetOutput(output)
+		logger.SetFormatter(formatter)
+		logger.SetLevel(levelOf(name))
+		subsystems[name] = logger
+	}
+	return logger.WithField("subsystem", name)
+}
+
+// Returns the names of the subsystems created so far, sorted.
+func Subsystems() []string {
+	mu.Lock()
+	defer mu.Unlock()
+	names := make([]string, 0, len(subsystems))
+	for name := range subsystems {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Must be called with mu held.
+func levelOf(name string) logrus.Level {
+	if l, ok := levels[name]; ok {
+		return l
+	}
+	return level
+}
+
+// Returns an error if the given config is invalid.
+func (c Config) Validate() error {
+	if _, err := logrus.ParseLevel(c.Level); err != nil {
+		return err
+	}
+	for name, l := range c.Subsystems {
+		if _, err := logrus.ParseLevel(l); err != nil {
+			return fmt.Errorf("subsystem %s: %v", name, err)
+		}
+	}
+	if c.Format != "" && c.Format != FormatText && c.Format != FormatJson {
+		return fmt.Errorf("unknown format %q", c.Format)
+	}
+	return nil
+}
+
+// Applies the given config to all subsystems, including the ones created later. The format also applies to the
+// standard logrus logger.
+func Configure(c Config) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	level, _ = logrus.ParseLevel(c.Level)
+	levels = make(map[string]logrus.Level, len(c.Subsystems))
+	for name, l := range c.Subsystems {
+		levels[name], _ = logrus.ParseLevel(l)
+	}
+	formatter = newFormatter(c.Format)
+	logrus.SetFormatter(formatter)
+	for name, logger := range subsystems {
+		logger.SetFormatter(formatter)
+		logger.SetLevel(levelOf(name))
+	}
+	return nil
+}
+
+// Sets the output of all subsystems, for tests.
+func setOutput(w io.Writer) {
+	mu.Lock()
+	defer mu.Unlock()
+	output = w
+	for _, logger := range subsystems {
+		logger.SetOutput(w)
+	}
+}
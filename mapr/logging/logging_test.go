@@ -0,0 +1,60 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"strings"
+	"testing"
+)
+
+func Test_Configure(t *testing.T) {
+	buf := &bytes.Buffer{}
+	setOutput(buf)
+	defer setOutput(os.Stderr)
+	defer func() {
+		require.NoError(t, Configure(Config{Level: "info"}))
+	}()
+
+	foo := Subsystem("foo")
+	require.NoError(t, Configure(Config{Level: "info", Subsystems: map[string]string{"bar": "trace"},
+		Format: FormatJson}))
+	// Created after Configure.
+	bar := Subsystem("bar")
+	foo.Debug("hidden")
+	bar.WithField("write_id", "1234").Debug("shown")
+	foo.Info("shown")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &entry))
+	assert.Equal(t, "bar", entry["subsystem"])
+	assert.Equal(t, "1234", entry["write_id"])
+	assert.Equal(t, "debug", entry["level"])
+	assert.Equal(t, "shown", entry["msg"])
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &entry))
+	assert.Equal(t, "foo", entry["subsystem"])
+	assert.Contains(t, Subsystems(), "bar")
+
+	// Subsystems not listed anymore go back to the default level.
+	buf.Reset()
+	require.NoError(t, Configure(Config{Level: "warn"}))
+	bar.Info("hidden")
+	assert.Empty(t, buf.String())
+}
+
+func Test_Configure_Invalid(t *testing.T) {
+	assert.EqualError(t, Configure(Config{Level: "foo"}), `not a valid logrus Level: "foo"`)
+	assert.EqualError(t, Configure(Config{Level: "info", Subsystems: map[string]string{"bar": "foo"}}),
+		`subsystem bar: not a valid logrus Level: "foo"`)
+	assert.EqualError(t, Configure(Config{Level: "info", Format: "xml"}), `unknown format "xml"`)
+}
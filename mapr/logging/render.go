@@ -0,0 +1,256 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package logging
+
+import (
+	"fmt"
+	"github.com/golang/protobuf/proto"
+	p4confv1 "github.com/p4lang/p4runtime/go/p4/config/v1"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"math/big"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// Renders P4Runtime messages in text format, like proto.CompactTextString, but with the IDs of tables, match fields,
+// actions, params, action profiles, counters, meters and packet metadata replaced by their names in a P4Info, and the
+// values of match fields, params and packet metadata shown according to their type, e.g., as IPv4 or MAC addresses.
+type Renderer struct {
+	tables      map[uint32]*p4confv1.Table
+	actions     map[uint32]*p4confv1.Action
+	resources   map[uint32]string
+	packetIn    map[uint32]*p4confv1.ControllerPacketMetadata_Metadata
+	packetOut   map[uint32]*p4confv1.ControllerPacketMetadata_Metadata
+	packetMetas map[string]map[uint32]*p4confv1.ControllerPacketMetadata_Metadata
+}
+
+// Returns a renderer using the names in the given P4Info.
+func NewRenderer(p4info *p4confv1.P4Info) *Renderer {
+	r := &Renderer{
+		tables:      make(map[uint32]*p4confv1.Table),
+		actions:     make(map[uint32]*p4confv1.Action),
+		resources:   make(map[uint32]string),
+		packetMetas: make(map[string]map[uint32]*p4confv1.ControllerPacketMetadata_Metadata),
+	}
+	for _, t := range p4info.GetTables() {
+		r.tables[t.Preamble.Id] = t
+	}
+	for _, a := range p4info.GetActions() {
+		r.actions[a.Preamble.Id] = a
+	}
+	for _, a := range p4info.GetActionProfiles() {
+		r.resources[a.Preamble.Id] = a.Preamble.Name
+	}
+	for _, c := range p4info.GetCounters() {
+		r.resources[c.Preamble.Id] = c.Preamble.Name
+	}
+	for _, c := range p4info.GetDirectCounters() {
+		r.resources[c.Preamble.Id] = c.Preamble.Name
+	}
+	for _, m := range p4info.GetMeters() {
+		r.resources[m.Preamble.Id] = m.Preamble.Name
+	}
+	for _, m := range p4info.GetDirectMeters() {
+		r.resources[m.Preamble.Id] = m.Preamble.Name
+	}
+	for _, reg := range p4info.GetRegisters() {
+		r.resources[reg.Preamble.Id] = reg.Preamble.Name
+	}
+	for _, v := range p4info.GetValueSets() {
+		r.resources[v.Preamble.Id] = v.Preamble.Name
+	}
+	for _, h := range p4info.GetControllerPacketMetadata() {
+		metas := make(map[uint32]*p4confv1.ControllerPacketMetadata_Metadata)
+		for _, m := range h.Metadata {
+			metas[m.Id] = m
+		}
+		r.packetMetas[h.Preamble.Name] = metas
+	}
+	r.packetIn = r.packetMetas["packet_in"]
+	r.packetOut = r.packetMetas["packet_out"]
+	return r
+}
+
+// A named value of known width, e.g., a match field.
+type typedValue struct {
+	name     string
+	bitwidth int32
+}
+
+// What the renderer knows about the enclosing messages.
+type scope struct {
+	table    *p4confv1.Table
+	action   *p4confv1.Action
+	metadata map[uint32]*p4confv1.ControllerPacketMetadata_Metadata
+	// The value whose bytes fields are being rendered, if known.
+	value *typedValue
+}
+
+// Fields holding IDs resolved to names, by the full name of their message.
+var idFields = map[protoreflect.FullName]protoreflect.Name{
+	"p4.v1.TableEntry":          "table_id",
+	"p4.v1.FieldMatch":          "field_id",
+	"p4.v1.Action":              "action_id",
+	"p4.v1.Action.Param":        "param_id",
+	"p4.v1.ActionProfileMember": "action_profile_id",
+	"p4.v1.ActionProfileGroup":  "action_profile_id",
+	"p4.v1.CounterEntry":        "counter_id",
+	"p4.v1.MeterEntry":          "meter_id",
+	"p4.v1.RegisterEntry":       "register_id",
+	"p4.v1.ValueSetEntry":       "value_set_id",
+	"p4.v1.PacketMetadata":      "metadata_id",
+}
+
+// Returns the given message in text format.
+func (r *Renderer) Render(msg proto.Message) string {
+	b := &strings.Builder{}
+	r.message(b, proto.MessageReflect(msg), scope{})
+	return b.String()
+}
+
+func (r *Renderer) message(b *strings.Builder, m protoreflect.Message, s scope) {
+	desc := m.Descriptor()
+	var id uint32
+	if idField, ok := idFields[desc.FullName()]; ok {
+		id = uint32(m.Get(desc.Fields().ByName(idField)).Uint())
+	}
+	// The name replacing the ID, empty if unknown.
+	var name string
+	switch desc.FullName() {
+	case "p4.v1.TableEntry":
+		s.table = r.tables[id]
+		name = s.table.GetPreamble().GetName()
+	case "p4.v1.FieldMatch":
+		s.value = nil
+		for _, f := range s.table.GetMatchFields() {
+			if f.Id == id {
+				s.value = &typedValue{name: f.Name, bitwidth: f.Bitwidth}
+				name = f.Name
+			}
+		}
+	case "p4.v1.Action":
+		s.action = r.actions[id]
+		name = s.action.GetPreamble().GetName()
+	case "p4.v1.Action.Param":
+		s.value = nil
+		for _, p := range s.action.GetParams() {
+			if p.Id == id {
+				s.value = &typedValue{name: p.Name, bitwidth: p.Bitwidth}
+				name = p.Name
+			}
+		}
+	case "p4.v1.PacketIn":
+		s.metadata = r.packetIn
+	case "p4.v1.PacketOut":
+		s.metadata = r.packetOut
+	case "p4.v1.PacketMetadata":
+		s.value = nil
+		if meta, ok := s.metadata[id]; ok {
+			s.value = &typedValue{name: meta.Name, bitwidth: meta.Bitwidth}
+			name = meta.Name
+		}
+	case "p4.v1.ActionProfileMember", "p4.v1.ActionProfileGroup", "p4.v1.CounterEntry", "p4.v1.MeterEntry",
+		"p4.v1.RegisterEntry", "p4.v1.ValueSetEntry":
+		name = r.resources[id]
+	}
+	fields := desc.Fields()
+	first := true
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if !m.Has(fd) {
+			continue
+		}
+		if fd.Name() == idFields[desc.FullName()] && name != "" {
+			r.separate(b, &first)
+			b.WriteString(string(fd.Name()))
+			b.WriteString(": ")
+			b.WriteString(strconv.Quote(name))
+			continue
+		}
+		v := m.Get(fd)
+		switch {
+		case fd.IsList():
+			list := v.List()
+			for j := 0; j < list.Len(); j++ {
+				r.separate(b, &first)
+				r.field(b, fd, list.Get(j), s)
+			}
+		case fd.IsMap():
+			// P4Runtime messages have no maps, render them as the text format does.
+			v.Map().Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+				r.separate(b, &first)
+				fmt.Fprintf(b, "%s { key: %v value: %v }", fd.Name(), k.Interface(), v.Interface())
+				return true
+			})
+		default:
+			r.separate(b, &first)
+			r.field(b, fd, v, s)
+		}
+	}
+}
+
+func (r *Renderer) separate(b *strings.Builder, first *bool) {
+	if !*first {
+		b.WriteByte(' ')
+	}
+	*first = false
+}
+
+func (r *Renderer) field(b *strings.Builder, fd protoreflect.FieldDescriptor, v protoreflect.Value, s scope) {
+	b.WriteString(string(fd.Name()))
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		b.WriteString(" { ")
+		r.message(b, v.Message(), s)
+		b.WriteString(" }")
+		return
+	}
+	b.WriteString(": ")
+	switch fd.Kind() {
+	case protoreflect.BytesKind:
+		b.WriteString(formatValue(v.Bytes(), s.value))
+	case protoreflect.StringKind:
+		b.WriteString(strconv.Quote(v.String()))
+	case protoreflect.EnumKind:
+		if e := fd.Enum().Values().ByNumber(v.Enum()); e != nil {
+			b.WriteString(string(e.Name()))
+		} else {
+			b.WriteString(strconv.Itoa(int(v.Enum())))
+		}
+	default:
+		fmt.Fprint(b, v.Interface())
+	}
+}
+
+// Returns the given bytes formatted according to the given value, nil if unknown: 32-bit values whose name suggests
+// an IPv4 address as such, 48-bit values as MAC addresses, other values up to 64 bits as decimal numbers (e.g., ports
+// and VLAN IDs), and others in hex.
+func formatValue(bytes []byte, value *typedValue) string {
+	if value == nil || len(bytes) > (int(value.bitwidth)+7)/8 {
+		return fmt.Sprintf("0x%x", bytes)
+	}
+	switch {
+	case value.bitwidth == 32 && isIpv4Name(value.name):
+		ip := make(net.IP, 4)
+		copy(ip[4-len(bytes):], bytes)
+		return ip.String()
+	case value.bitwidth == 48:
+		mac := make(net.HardwareAddr, 6)
+		copy(mac[6-len(bytes):], bytes)
+		return mac.String()
+	case value.bitwidth <= 64:
+		return new(big.Int).SetBytes(bytes).String()
+	default:
+		return fmt.Sprintf("0x%x", bytes)
+	}
+}
+
+func isIpv4Name(name string) bool {
+	name = strings.ToLower(name)
+	return strings.Contains(name, "ipv4") || strings.Contains(name, "ip_addr") || strings.HasSuffix(name, "_ip")
+}
@@ -0,0 +1,136 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package logging
+
+import (
+	"github.com/golang/protobuf/proto"
+	p4confv1 "github.com/p4lang/p4runtime/go/p4/config/v1"
+	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"testing"
+)
+
+func logicalP4Info(t *testing.T) *p4confv1.P4Info {
+	bytes, err := ioutil.ReadFile("../../p4src/build/p4info.txt")
+	require.NoError(t, err)
+	p4info := &p4confv1.P4Info{}
+	require.NoError(t, proto.UnmarshalText(string(bytes), p4info))
+	return p4info
+}
+
+func Test_Renderer(t *testing.T) {
+	r := NewRenderer(logicalP4Info(t))
+	tests := []struct {
+		name string
+		msg  proto.Message
+		want string
+	}{
+		{
+			name: "route",
+			msg: &p4v1.WriteRequest{DeviceId: 1, Updates: []*p4v1.Update{{
+				Type: p4v1.Update_INSERT,
+				Entity: &p4v1.Entity{Entity: &p4v1.Entity_TableEntry{TableEntry: &p4v1.TableEntry{
+					TableId: 40314915,
+					Match: []*p4v1.FieldMatch{{FieldId: 1, FieldMatchType: &p4v1.FieldMatch_Lpm{
+						Lpm: &p4v1.FieldMatch_LPM{Value: []byte{10, 0, 0, 0}, PrefixLen: 8}}}},
+					Action: &p4v1.TableAction{Type: &p4v1.TableAction_ActionProfileGroupId{ActionProfileGroupId: 7}},
+				}}},
+			}}},
+			want: `device_id: 1 updates { type: INSERT entity { table_entry { table_id: "IngressPipe.upstream.routes_v4" ` +
+				`match { field_id: "ipv4_dst" lpm { value: 10.0.0.0 prefix_len: 8 } } action { ` +
+				`action_profile_group_id: 7 } } } }`,
+		},
+		{
+			name: "line",
+			msg: &p4v1.TableEntry{
+				TableId: 33956689,
+				Match: []*p4v1.FieldMatch{
+					{FieldId: 1, FieldMatchType: &p4v1.FieldMatch_Exact_{Exact: &p4v1.FieldMatch_Exact{Value: []byte{1, 4}}}},
+					{FieldId: 2, FieldMatchType: &p4v1.FieldMatch_Exact_{Exact: &p4v1.FieldMatch_Exact{Value: []byte{0x0f, 0xa0}}}},
+				},
+				Action: &p4v1.TableAction{Type: &p4v1.TableAction_Action{Action: &p4v1.Action{
+					ActionId: 17659136,
+					Params:   []*p4v1.Action_Param{{ParamId: 1, Value: []byte{0, 0, 0, 42}}},
+				}}},
+			},
+			want: `table_id: "IngressPipe.upstream.lines" match { field_id: "port" exact { value: 260 } } ` +
+				`match { field_id: "c_tag" exact { value: 4000 } } action { action { ` +
+				`action_id: "IngressPipe.upstream.set_line" params { param_id: "line_id" value: 42 } } }`,
+		},
+		{
+			name: "member",
+			msg: &p4v1.ActionProfileMember{
+				ActionProfileId: 286372544,
+				MemberId:        3,
+				Action: &p4v1.Action{
+					ActionId: 31033793,
+					Params: []*p4v1.Action_Param{
+						{ParamId: 1, Value: []byte{1}},
+						{ParamId: 2, Value: []byte{0, 0xaa, 0, 0, 0, 1}},
+					},
+				},
+			},
+			want: `action_profile_id: "IngressPipe.upstream.ecmp" member_id: 3 action { ` +
+				`action_id: "IngressPipe.upstream.route_v4" params { param_id: "port" value: 1 } ` +
+				`params { param_id: "dmac" value: 00:aa:00:00:00:01 } }`,
+		},
+		{
+			name: "packet-in",
+			msg: &p4v1.StreamMessageResponse{Update: &p4v1.StreamMessageResponse_Packet{Packet: &p4v1.PacketIn{
+				Payload:  []byte{0xca, 0xfe},
+				Metadata: []*p4v1.PacketMetadata{{MetadataId: 1, Value: []byte{1, 4}}},
+			}}},
+			want: `packet { payload: 0xcafe metadata { metadata_id: "ingress_port" value: 260 } }`,
+		},
+		{
+			name: "unknown IDs",
+			msg: &p4v1.TableEntry{
+				TableId: 1,
+				Match: []*p4v1.FieldMatch{{FieldId: 1, FieldMatchType: &p4v1.FieldMatch_Exact_{
+					Exact: &p4v1.FieldMatch_Exact{Value: []byte{1, 4}}}}},
+			},
+			want: `table_id: 1 match { field_id: 1 exact { value: 0x0104 } }`,
+		},
+		{
+			name: "value too wide",
+			msg: &p4v1.Action{
+				ActionId: 17659136,
+				Params:   []*p4v1.Action_Param{{ParamId: 1, Value: []byte{1, 0, 0, 0, 0}}},
+			},
+			want: `action_id: "IngressPipe.upstream.set_line" params { param_id: "line_id" value: 0x0100000000 }`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, r.Render(tt.msg))
+		})
+	}
+}
+
+func Test_formatValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		bytes []byte
+		value *typedValue
+		want  string
+	}{
+		{"unknown", []byte{0, 1}, nil, "0x0001"},
+		{"ipv4", []byte{192, 168, 0, 1}, &typedValue{"ipv4_src", 32}, "192.168.0.1"},
+		{"ipv4 canonical", []byte{1}, &typedValue{"hdr.ipv4.dst_addr", 32}, "0.0.0.1"},
+		{"not ipv4", []byte{0, 0, 1, 0}, &typedValue{"line_id", 32}, "256"},
+		{"mac", []byte{0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff}, &typedValue{"eth_dst", 48}, "aa:bb:cc:dd:ee:ff"},
+		{"vlan", []byte{0x0f, 0xff}, &typedValue{"s_tag", 12}, "4095"},
+		{"wide", []byte{1, 2}, &typedValue{"foo", 128}, "0x0102"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, formatValue(tt.bytes, tt.value))
+		})
+	}
+}
@@ -8,38 +8,113 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"flag"
 	"fmt"
 	"github.com/golang/protobuf/proto"
 	p4confv1 "github.com/p4lang/p4runtime/go/p4/config/v1"
 	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
-	log "github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"io"
 	"io/ioutil"
+	"mapr/admin"
+	"mapr/arbitration"
+	"mapr/config"
 	"mapr/fabric"
+	"mapr/health"
+	"mapr/logging"
+	"mapr/metrics"
+	"mapr/punt"
+	"mapr/roles"
+	"mapr/tlsutil"
+	"mapr/tracing"
 	"mapr/translate"
 	"net"
+	"net/http"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 var (
 	target p4v1.P4RuntimeClient
 	port   = flag.Int("port", 28001,
 		"The server port")
+	bindAddr = flag.String("bind_addr", "localhost",
+		"The address the server listens on, e.g., `0.0.0.0` for all interfaces")
+	tlsCertPath = flag.String("tls_cert", "",
+		"Path to the PEM certificate presented to controllers, enables TLS together with tls_key")
+	tlsKeyPath = flag.String("tls_key", "",
+		"Path to the PEM private key of tls_cert")
+	tlsClientCaPath = flag.String("tls_client_ca", "",
+		"Path to the PEM CAs of controller certificates, if set controllers must authenticate with a certificate")
 	targetAddr = flag.String("target_addr", "127.0.0.1:28000",
 		"The target address in the format of host:port")
+	targetTls = flag.Bool("target_tls", false,
+		"Use TLS to connect to the target")
+	targetTlsCaPath = flag.String("target_tls_ca", "",
+		"Path to the PEM CAs of the target certificate, system CAs are used if empty")
+	targetTlsCertPath = flag.String("target_tls_cert", "",
+		"Path to the PEM certificate presented to the target, if it requests one")
+	targetTlsKeyPath = flag.String("target_tls_key", "",
+		"Path to the PEM private key of target_tls_cert")
+	targetTlsServerName = flag.String("target_tls_server_name", "",
+		"The name expected in the target certificate, defaults to the host of target_addr")
 	processorName = flag.String("proc", "dummy",
 		"Processor to use")
 	logicalP4InfoPath = flag.String("logical_p4info", "",
 		"Path to logical P4Info file in binary format, e.g., `p4info.bin`")
+	logicalP4InfoVersionPaths = flag.String("logical_p4info_versions", "",
+		"Comma-separated paths to older versions of the logical P4Info still accepted, e.g., `p4info-v1.bin`")
 	targetP4ConfigPaths = flag.String("target_p4_config", "",
 		"Path to P4 pipeline config files to apply to target, e.g., `p4info.bin,bmv2.json`")
+	portMapPath = flag.String("port_map", "",
+		"Path to JSON file mapping logical ports to target ones, e.g., `[{\"logical_port\": 1, \"target_port\": 260}]`")
+	chassisConfigPath = flag.String("chassis_config", "",
+		"Path to the target's chassis config file to learn the port mapping from, e.g., `chassis_config.pb.txt`")
+	deviceId = flag.Uint64("device_id", 1,
+		"The device ID controllers should use")
+	targetDeviceId = flag.Uint64("target_device_id", 1,
+		"The device ID of the target")
+	targetElectionId = flag.Uint64("target_election_id", 1,
+		"The election ID used by mapr to be primary on the target")
+	puntConfigPath = flag.String("punt_config", "",
+		"Path to JSON file with rate limits and routing of packet-ins per punt reason and port, e.g., `punt.json`")
+	metricsAddr = flag.String("metrics_addr", "",
+		"The address in the format of host:port on which Prometheus metrics are served at /metrics, and health "+
+			"checks at /readyz and /healthz, disabled if empty")
+	otlpEndpoint = flag.String("otlp_endpoint", "",
+		"The address in the format of host:port of the collector traces are exported to over OTLP, disabled if empty")
+	configPath = flag.String("config", "",
+		"Path to JSON config file overriding flags, log and punt settings are reloaded on change or SIGHUP")
 )
 
-const MaxMsgLen = 255
+var log = logging.Subsystem("server")
+
+// Interval at which the config file is checked for changes.
+const configWatchInterval = 2 * time.Second
+
+// Max length of the protobuf messages printed in the log, accessed atomically as it can be reloaded.
+var maxMsgLen int32 = config.DefaultMaxMsgLen
+
+// Whether protobuf messages are printed with names in place of IDs, accessed atomically as it can be reloaded.
+var resolveNames int32
+
+// Resolve names in the messages exchanged with controllers and the target, set when creating the server.
+var ctrlRenderer, targetRenderer *logging.Renderer
+
+// Max number of updates per WriteRequest sent to the target when reconciling its state.
+const reconcileBatchSize = 1000
 
 type MsgDirection string
 
@@ -51,40 +126,318 @@ const (
 )
 
 func logMsg(dir MsgDirection, msg proto.Message) {
-	msgString := proto.CompactTextString(msg)
+	logMsgWith(log, dir, msg)
+}
+
+// Logs the given message with the given logger, e.g., one with the correlation ID of a Write.
+func logMsgWith(logger *logrus.Entry, dir MsgDirection, msg proto.Message) {
+	if !logger.Logger.IsLevelEnabled(logrus.DebugLevel) {
+		return
+	}
+	renderer := ctrlRenderer
+	if dir == FromTarget || dir == ToTarget {
+		renderer = targetRenderer
+	}
+	var msgString string
+	if atomic.LoadInt32(&resolveNames) != 0 && renderer != nil {
+		msgString = renderer.Render(msg)
+	} else {
+		msgString = proto.CompactTextString(msg)
+	}
 	msgLen := len(msgString)
-	if msgLen > MaxMsgLen {
-		msgString = msgString[:MaxMsgLen] + fmt.Sprintf("... truncated %d bytes", msgLen-MaxMsgLen)
+	if maxLen := int(atomic.LoadInt32(&maxMsgLen)); msgLen > maxLen {
+		msgString = msgString[:maxLen] + fmt.Sprintf("... truncated %d bytes", msgLen-maxLen)
 	}
-	log.WithField("proto", msgString).Debugf("%s %T", dir, msg)
+	logger.WithField("proto", msgString).Debugf("%s %T", dir, msg)
 }
 
 type Server struct {
+	// The config mapr was started with.
+	Config *config.Config
 	// Holds the logical P4RT entities.
 	P4RtStore translate.P4RtStore
 	// Handles translation of logical updates to physical ones.
 	Translator translate.Translator
+	// Handles translation of packet metadata, nil if packet I/O should be relayed untouched.
+	PacketIo *translate.PacketIoTranslator
+	// Set if packet metadata cannot be translated, in which case StreamChannel is rejected.
+	PacketIoErr error
+	// Arbitrates mastership among controllers.
+	Arbitrator *arbitration.Arbitrator
+	// The session with the target, shared by all controllers.
+	Session *targetSession
+	// Restricts the entities each role can access.
+	Roles *roles.Registry
+	// Rate-limits and routes packet-ins.
+	Punt *punt.Limiter
+	// The logical pipeline config set by controllers.
+	Pipeline *pipelineConfig
+	// The logical P4Info controllers are expected to push, nil if any P4Info is accepted.
+	LogicalP4Info *p4confv1.P4Info
+	// Older versions of the logical P4Info, also accepted.
+	LogicalVersions []*logicalVersion
+	// The config pushed to the target in place of the logical one, nil to push the logical one as is.
+	TargetConfig *p4v1.ForwardingPipelineConfig
+	// Collects metrics about RPCs, translation, packet I/O and stores.
+	Metrics *metrics.Metrics
+	// Reports whether mapr can serve controllers.
+	Health *health.Checker
+	// Serves the Admin service, to inspect the logical and target state.
+	Admin *admin.Server
+	// Serializes writes and pipeline config changes, as the stores expect modifications from a single goroutine.
+	writeMu *sync.Mutex
+	// ID of the ingress_port metadata of logical packet-ins, 0 if unknown.
+	inPortMetaId uint32
+}
+
+// An older version of the logical P4Info.
+type logicalVersion struct {
+	path     string
+	p4info   *p4confv1.P4Info
+	upgrader *translate.VersionUpgrader
+}
+
+// Name of the P4Runtime service, whose status is reported by the health service.
+const p4RuntimeService = "p4.v1.P4Runtime"
+
+// gRPC metadata key used by controllers to specify their role in Read RPCs, as ReadRequest has no role field.
+const readRoleMetadataKey = "role_id"
+
+// Reads a P4Info from the given file in binary format.
+func readP4Info(path string) (*p4confv1.P4Info, error) {
+	bytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	p4Info := &p4confv1.P4Info{}
+	if err := proto.Unmarshal(bytes, p4Info); err != nil {
+		return nil, err
+	}
+	return p4Info, nil
+}
+
+// Returns the ID of the packet-in metadata with the given name, 0 if not found.
+func findPacketInMetadataId(p4info *p4confv1.P4Info, name string) uint32 {
+	for _, h := range p4info.GetControllerPacketMetadata() {
+		if h.GetPreamble().GetName() != translate.ControllerHeaderPacketIn {
+			continue
+		}
+		for _, m := range h.Metadata {
+			if m.Name == name {
+				return m.Id
+			}
+		}
+	}
+	return 0
+}
+
+// Returns the config specified via flags, without validating it.
+func configFromFlags() (*config.Config, error) {
+	c := config.Default()
+	c.Server.BindAddr = *bindAddr
+	c.Server.Port = *port
+	c.Server.DeviceId = *deviceId
+	c.Server.Tls = config.ServerTls{Cert: *tlsCertPath, Key: *tlsKeyPath, ClientCa: *tlsClientCaPath}
+	c.Target.Addr = *targetAddr
+	c.Target.DeviceId = *targetDeviceId
+	c.Target.ElectionId = *targetElectionId
+	if *targetP4ConfigPaths != "" {
+		pieces := strings.Split(*targetP4ConfigPaths, ",")
+		if len(pieces) != 2 {
+			return nil, fmt.Errorf("expected P4Info and device config paths, found %q", *targetP4ConfigPaths)
+		}
+		c.Target.P4Info, c.Target.DeviceConfig = pieces[0], pieces[1]
+	}
+	c.Target.Tls = config.TargetTls{
+		Enabled:    *targetTls,
+		Ca:         *targetTlsCaPath,
+		Cert:       *targetTlsCertPath,
+		Key:        *targetTlsKeyPath,
+		ServerName: *targetTlsServerName,
+	}
+	c.Processor = *processorName
+	c.LogicalP4Info = *logicalP4InfoPath
+	if *logicalP4InfoVersionPaths != "" {
+		c.LogicalP4InfoVersions = strings.Split(*logicalP4InfoVersionPaths, ",")
+	}
+	c.Ports = config.Ports{PortMap: *portMapPath, ChassisConfig: *chassisConfigPath}
+	c.Metrics.Addr = *metricsAddr
+	c.Tracing.OtlpEndpoint = *otlpEndpoint
+	if *puntConfigPath != "" {
+		puntConfig, err := punt.LoadConfigFile(*puntConfigPath)
+		if err != nil {
+			return nil, err
+		}
+		c.Punt = puntConfig
+	}
+	return c, nil
+}
+
+// Returns the config specified via flags and config file, validated.
+func loadConfig() (*config.Config, error) {
+	c, err := configFromFlags()
+	if err != nil {
+		return nil, err
+	}
+	if *configPath != "" {
+		return config.Load(*configPath, c)
+	}
+	return c, c.Validate()
+}
+
+// Returns the given port mapping, or nil if none.
+func loadPortMap(c config.Ports) (*translate.PortMap, error) {
+	switch {
+	case c.PortMap != "":
+		return translate.LoadPortMapFile(c.PortMap)
+	case c.ChassisConfig != "":
+		return translate.LoadPortMapChassisConfig(c.ChassisConfig)
+	default:
+		return nil, nil
+	}
+}
+
+// Loads the given older versions of the logical P4Info.
+func loadLogicalVersions(paths []string, current *p4confv1.P4Info) ([]*logicalVersion, error) {
+	var versions []*logicalVersion
+	for _, path := range paths {
+		p4info, err := readP4Info(path)
+		if err != nil {
+			return nil, err
+		}
+		upgrader, err := translate.NewVersionUpgrader(p4info, current)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		versions = append(versions, &logicalVersion{path: path, p4info: p4info, upgrader: upgrader})
+	}
+	return versions, nil
+}
+
+// Loads the target pipeline config from the given files, nil if none.
+func loadTargetConfig(c config.Target) (*p4v1.ForwardingPipelineConfig, error) {
+	if c.P4Info == "" {
+		return nil, nil
+	}
+	p4Info, err := readP4Info(c.P4Info)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read target P4Info: %v", err)
+	}
+	deviceConfig, err := ioutil.ReadFile(c.DeviceConfig)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read target device config: %v", err)
+	}
+	return &p4v1.ForwardingPipelineConfig{P4Info: p4Info, P4DeviceConfig: deviceConfig}, nil
 }
 
-func NewServer() *Server {
-	ctx := translate.NewContext()
+// Creates a new server with the given config, which must be valid.
+func NewServer(c *config.Config) *Server {
+	ports, err := loadPortMap(c.Ports)
+	if err != nil {
+		log.Fatalf("Failed to load port map: %v", err)
+	}
+	var logicalP4Info *p4confv1.P4Info
+	if c.LogicalP4Info != "" {
+		if logicalP4Info, err = readP4Info(c.LogicalP4Info); err != nil {
+			log.Fatalf("Failed to read logical P4Info: %v", err)
+		}
+	}
+	logicalVersions, err := loadLogicalVersions(c.LogicalP4InfoVersions, logicalP4Info)
+	if err != nil {
+		log.Fatalf("Failed to load logical P4Info versions: %v", err)
+	}
+	targetConfig, err := loadTargetConfig(c.Target)
+	if err != nil {
+		log.Fatalf("Failed to load target pipeline config: %v", err)
+	}
+	var ctx translate.Context
 	var trn translate.Translator
-	if *processorName == "dummy" {
+	var pktIo *translate.PacketIoTranslator
+	var pktIoErr error
+	if c.Processor == "dummy" {
+		if ports != nil {
+			log.Warn("Port mapping is not supported by the dummy processor, ignoring...")
+		}
 		trn = translate.NewDummyTranslator()
 	} else {
+		// Checked by config validation, but NewServer may be given any config.
+		if logicalP4Info == nil || targetConfig == nil {
+			log.Fatalf("Processor %s requires a logical P4Info (-logical_p4info) and a target pipeline config "+
+				"(-target_p4_config)", c.Processor)
+		}
+		pktIo, pktIoErr = translate.NewPacketIoTranslator(logicalP4Info, targetConfig.P4Info, ports)
+		if pktIoErr != nil {
+			log.Errorf("Packet I/O will be rejected: %v", pktIoErr)
+		}
+		ctx = translate.NewContext(ports)
 		var proc translate.Processor
-		switch *processorName {
+		switch c.Processor {
 		case "fabric":
-			proc = fabric.NewFabricProcessor(ctx)
+			proc = fabric.NewFabricProcessor(ctx, c.Fabric)
 		default:
 			panic("Unknown processor")
 		}
-		trn = translate.NewTranslator(proc, ctx)
+		trn = translate.NewTranslator(proc, ctx, c.Translate)
+	}
+	s := &Server{
+		Config:          c,
+		P4RtStore:       translate.NewP4RtStore("logical"),
+		Translator:      trn,
+		PacketIo:        pktIo,
+		PacketIoErr:     pktIoErr,
+		Arbitrator:      arbitration.NewArbitrator(c.Server.DeviceId),
+		Roles:           roles.NewRegistry(logicalP4Info),
+		Punt:            punt.NewLimiter(c.Punt),
+		Pipeline:        &pipelineConfig{},
+		LogicalP4Info:   logicalP4Info,
+		LogicalVersions: logicalVersions,
+		TargetConfig:    targetConfig,
+		Metrics:         metrics.New(logicalP4Info),
+		Health:          health.NewChecker([]string{p4RuntimeService}, health.TargetUnreachable, health.NoPipelineConfig),
+		Admin:           admin.NewServer(ctx),
+		writeMu:         &sync.Mutex{},
+	}
+	// The dummy translator has no context, as the target state is the same as the logical one.
+	s.Metrics.RegisterStores(s.P4RtStore, ctx, targetConfig.GetP4Info())
+	s.Metrics.RegisterPunt(s.Punt)
+	ctrlRenderer = logging.NewRenderer(logicalP4Info)
+	targetRenderer = ctrlRenderer
+	if targetConfig != nil {
+		targetRenderer = logging.NewRenderer(targetConfig.P4Info)
 	}
-	return &Server{
-		P4RtStore:  translate.NewP4RtStore("logical"),
-		Translator: trn,
+	if s.inPortMetaId = findPacketInMetadataId(logicalP4Info, "ingress_port"); s.inPortMetaId == 0 {
+		log.Warn("Unknown packet-in ingress_port metadata, packet-ins will not be limited per port")
 	}
+	s.Session = newTargetSession(c.Target.DeviceId, c.Target.ElectionId, s.handlePacketIn, func(primary bool) {
+		s.Health.Set(health.TargetUnreachable, !primary)
+	})
+	return s
+}
+
+// Applies the reloadable settings of the given config.
+func (s Server) applyConfig(c *config.Config) {
+	applyLogConfig(c.Log)
+	s.Punt.SetConfig(c.Punt)
+}
+
+func applyLogConfig(c config.Log) {
+	// Validated with the config.
+	_ = logging.Configure(c.Logging())
+	known := make(map[string]bool)
+	for _, name := range logging.Subsystems() {
+		known[name] = true
+	}
+	for name := range c.Subsystems {
+		if !known[name] {
+			log.Warnf("Unknown log subsystem %s, expected one of %v", name, logging.Subsystems())
+		}
+	}
+	atomic.StoreInt32(&maxMsgLen, int32(c.MaxMsgLen))
+	var resolve int32
+	if c.ResolveNames {
+		resolve = 1
+	}
+	atomic.StoreInt32(&resolveNames, resolve)
 }
 
 func (s Server) Capabilities(ctx context.Context, request *p4v1.CapabilitiesRequest) (*p4v1.CapabilitiesResponse, error) {
@@ -97,15 +450,46 @@ func (s Server) Capabilities(ctx context.Context, request *p4v1.CapabilitiesRequ
 	return response, nil
 }
 
-var globalWriteCount = 1
+// gRPC trailer key with the correlation ID of a Write, which is logged with all messages and errors of the RPC.
+const writeIdMetadataKey = "mapr-write-id"
+
+// Returns a new random correlation ID.
+func newWriteId() string {
+	var id [8]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(id[:])
+}
 
 func (s Server) Write(ctx context.Context, logicalReq *p4v1.WriteRequest) (*p4v1.WriteResponse, error) {
-	writeCount := globalWriteCount
-	globalWriteCount++
-	log.Debugf("@@@@@@ BEGIN WRITE REQUEST #%d @@@@@@ ", writeCount)
-	defer log.Debugf("@@@@@@ END WRITE REQUEST #%d @@@@@@", writeCount)
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	writeId := newWriteId()
+	wlog := log.WithField("write_id", writeId)
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("mapr.write_id", writeId))
+	// Let the controller correlate its request with the log.
+	_ = grpc.SetTrailer(ctx, metadata.Pairs(writeIdMetadataKey, writeId))
+	wlog.Debug("@@@@@@ BEGIN WRITE REQUEST @@@@@@")
+	defer wlog.Debug("@@@@@@ END WRITE REQUEST @@@@@@")
+
+	logMsgWith(wlog, FromCtrl, logicalReq)
+
+	if err := s.Arbitrator.CheckPrimary(logicalReq.DeviceId, logicalReq.RoleId, logicalReq.ElectionId); err != nil {
+		return nil, err
+	}
 
-	logMsg(FromCtrl, logicalReq)
+	if err := s.upgradeUpdates(logicalReq); err != nil {
+		return nil, err
+	}
+
+	policy := s.Roles.Get(logicalReq.RoleId)
+	for _, logicalUpdate := range logicalReq.Updates {
+		if err := policy.CheckWrite(logicalUpdate.Entity); err != nil {
+			return nil, err
+		}
+	}
 
 	if logicalReq.Atomicity != p4v1.WriteRequest_CONTINUE_ON_ERROR {
 		return nil, status.Errorf(codes.Unimplemented, "Atomicity should be CONTINUE_ON_ERROR")
@@ -113,58 +497,27 @@ func (s Server) Write(ctx context.Context, logicalReq *p4v1.WriteRequest) (*p4v1
 
 	// Template WriteRequest for the target.
 	// We'll emit one or none for each Update in the logical request.
+	// mapr is the only client of the target, hence we use its own session.
 	physicalRequest := p4v1.WriteRequest{
-		DeviceId:   logicalReq.DeviceId,
-		RoleId:     logicalReq.RoleId,
-		ElectionId: logicalReq.ElectionId,
+		DeviceId:   s.Session.deviceId,
+		ElectionId: s.Session.electionId,
 		Updates:    nil,
 		Atomicity:  logicalReq.Atomicity,
 	}
 
 	ok := true
 	for _, logicalUpdate := range logicalReq.Updates {
-		// Validate update against P4RT store, to catch duplicate entries, and other P4RT-level errors.
-		if err := s.P4RtStore.ApplyUpdate(logicalUpdate, true); err != nil {
-			log.Errorf("ServerStore.ApplyUpdate(dry_run=true): %v [%v]", err, logicalUpdate)
-			ok = false
-			continue // next update
-		}
-
-		// Translate logical update to zero or more physical ones to write on the target.
-		targetUpdates, err := s.Translator.Translate(logicalUpdate)
-		if err != nil {
-			log.Errorf("Translator.Translate(): %v [%v]", err, logicalUpdate)
+		if err := s.writeUpdate(ctx, wlog, policy, &physicalRequest, logicalUpdate); err != nil {
 			ok = false
-			continue // next update
-		}
-
-		if targetUpdates != nil && len(targetUpdates) > 0 {
-			// Write physical updates to target.
-			physicalRequest.Updates = targetUpdates
-			logMsg(ToTarget, &physicalRequest)
-			_, err = target.Write(ctx, &physicalRequest)
-			if err != nil {
-				// TODO (carmelo): unpack and log P4RT error trailers from target.
-				log.Errorf("%s %v", FromTarget, err)
-				ok = false
-				continue // next update
-			}
-			// Write RPC was successful!
-		}
-
-		// Update internal stores (there should be no errors since we did a dry run before)
-		if err := s.P4RtStore.ApplyUpdate(logicalUpdate, false); err != nil {
-			panic(err)
-		}
-		if err := s.Translator.ApplyUpdate(logicalUpdate, targetUpdates); err != nil {
-			panic(err)
+		} else {
+			s.writeReady(ctx, wlog, &physicalRequest)
 		}
 	}
 
 	// Send WriteResponse or error to controller.
 	if ok {
 		response := &p4v1.WriteResponse{}
-		logMsg(ToCtrl, response)
+		logMsgWith(wlog, ToCtrl, response)
 		return response, nil
 	} else {
 		// FIXME (carmelo): return errors compliant with P4RT spec. I.e., append trailers with details for each update
@@ -173,79 +526,426 @@ func (s Server) Write(ctx context.Context, logicalReq *p4v1.WriteRequest) (*p4v1
 	}
 }
 
+// Validates, translates and writes to the target the given logical update, then applies it to the stores. Deletes
+// cascading to other objects are written first, if allowed by the given policy. Errors are logged with the given
+// logger, and returned to be reported to the controller.
+func (s Server) writeUpdate(ctx context.Context, wlog *logrus.Entry, policy *roles.Policy,
+	physicalRequest *p4v1.WriteRequest, logicalUpdate *p4v1.Update) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "update", tracing.UpdateAttributes(logicalUpdate)...)
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	// Validate update against P4RT store, to catch duplicate entries, and other P4RT-level errors.
+	_, validateSpan := tracing.StartSpan(ctx, "validate")
+	err = s.P4RtStore.ApplyUpdate(logicalUpdate, true)
+	tracing.EndSpan(validateSpan, err)
+	if err != nil {
+		wlog.Errorf("ServerStore.ApplyUpdate(dry_run=true): %v [%v]", err, logicalUpdate)
+		return err
+	}
+
+	// Delete the objects referring to the deleted one first, if the delete cascades to them.
+	cascaded, err := s.Translator.Cascade(logicalUpdate)
+	if err != nil {
+		wlog.Errorf("Translator.Cascade(): %v [%v]", err, logicalUpdate)
+		return err
+	}
+	for _, u := range cascaded {
+		if err = policy.CheckWrite(u.Entity); err != nil {
+			wlog.Errorf("Cannot cascade delete: %v [%v]", err, u)
+			return err
+		}
+		wlog.Infof("Cascading delete [%v]", u)
+		if err = s.writeUpdate(ctx, wlog, policy, physicalRequest, u); err != nil {
+			return err
+		}
+	}
+
+	// Translate logical update to zero or more physical ones to write on the target.
+	_, translateSpan := tracing.StartSpan(ctx, "translate")
+	targetUpdates, err := s.Translator.Translate(logicalUpdate)
+	translateSpan.SetAttributes(attribute.Int("mapr.target_updates", len(targetUpdates)))
+	tracing.EndSpan(translateSpan, err)
+	s.Metrics.ObserveTranslation(logicalUpdate, len(targetUpdates), err)
+	if err != nil {
+		wlog.Errorf("Translator.Translate(): %v [%v]", err, logicalUpdate)
+		return err
+	}
+
+	if targetUpdates != nil && len(targetUpdates) > 0 {
+		// Write physical updates to target, traced by the client interceptor.
+		physicalRequest.Updates = targetUpdates
+		logMsgWith(wlog, ToTarget, physicalRequest)
+		if _, err = target.Write(ctx, physicalRequest); err != nil {
+			// TODO (carmelo): unpack and log P4RT error trailers from target.
+			wlog.Errorf("%s %v", FromTarget, err)
+			return err
+		}
+		// Write RPC was successful!
+	}
+
+	// Update internal stores (there should be no errors since we did a dry run before)
+	_, applySpan := tracing.StartSpan(ctx, "apply")
+	defer applySpan.End()
+	if err := s.P4RtStore.ApplyUpdate(logicalUpdate, false); err != nil {
+		panic(err)
+	}
+	if err := s.Translator.ApplyUpdate(logicalUpdate, targetUpdates); err != nil {
+		panic(err)
+	}
+	return nil
+}
+
+// Translates and writes to the target the pending logical objects that are ready, e.g., after writing the MyStation
+// entry they were waiting for, and re-translates the stale ones, e.g., the next hops using the MAC address of a
+// modified MyStation entry, until no more become ready. As they are already in the P4RtStore, only the translator
+// context is updated. Objects failing to translate stay pending or stale, their errors are only logged.
+func (s Server) writeReady(ctx context.Context, wlog *logrus.Entry, physicalRequest *p4v1.WriteRequest) {
+	for {
+		progress := false
+		for _, logicalUpdate := range s.Translator.Ready() {
+			if err := s.writeReadyUpdate(ctx, wlog, physicalRequest, logicalUpdate); err == nil {
+				progress = true
+			}
+		}
+		if !progress {
+			return
+		}
+	}
+}
+
+func (s Server) writeReadyUpdate(ctx context.Context, wlog *logrus.Entry, physicalRequest *p4v1.WriteRequest,
+	logicalUpdate *p4v1.Update) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "ready", tracing.UpdateAttributes(logicalUpdate)...)
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	targetUpdates, err := s.Translator.Translate(logicalUpdate)
+	s.Metrics.ObserveTranslation(logicalUpdate, len(targetUpdates), err)
+	if err != nil {
+		wlog.Errorf("Translator.Translate() of pending or stale object: %v [%v]", err, logicalUpdate)
+		return err
+	}
+	if len(targetUpdates) > 0 {
+		physicalRequest.Updates = targetUpdates
+		logMsgWith(wlog, ToTarget, physicalRequest)
+		if _, err = target.Write(ctx, physicalRequest); err != nil {
+			wlog.Errorf("%s %v", FromTarget, err)
+			return err
+		}
+	}
+	if err := s.Translator.ApplyUpdate(logicalUpdate, targetUpdates); err != nil {
+		panic(err)
+	}
+	if logicalUpdate.Type == p4v1.Update_MODIFY {
+		wlog.Infof("Re-translated stale object [%v]", logicalUpdate)
+	} else {
+		wlog.Infof("Translated pending object [%v]", logicalUpdate)
+	}
+	return nil
+}
+
+// Translates the entities of the given request to the current logical P4Info, if the committed config uses an older
+// version.
+func (s Server) upgradeUpdates(request *p4v1.WriteRequest) error {
+	upgrader := s.Pipeline.Upgrader()
+	if upgrader == nil {
+		return nil
+	}
+	updates := make([]*p4v1.Update, len(request.Updates))
+	for i, u := range request.Updates {
+		e, err := upgrader.UpgradeEntity(u.Entity)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "mapr: %v", err)
+		}
+		updates[i] = &p4v1.Update{Type: u.Type, Entity: e}
+	}
+	request.Updates = updates
+	return nil
+}
+
+// Number of entities per ReadResponse, to keep responses below the gRPC message size limit.
+const readResponseEntities = 1000
+
+// Serves reads from a snapshot of the logical P4RtStore, i.e., with the logical entities written by controllers, as
+// the target has the translated ones. In particular, ports are the logical ones, and need no mapping back from target
+// ports. Reads never block writes, and see the stores as of the start of the RPC.
 func (s Server) Read(request *p4v1.ReadRequest, toClient p4v1.P4Runtime_ReadServer) error {
-	// TODO: read from store, not from target
 	logMsg(FromCtrl, request)
 	ctx, cancel := context.WithCancel(toClient.Context())
 	defer cancel()
-	fromTarget, err := target.Read(ctx, request)
+	// ReadRequest has no role field in p4runtime v1.1.1, hence controllers specify their role in the role_id metadata
+	// key, which is trusted as is, see the README. The policy applies to the logical entities, whose IDs are the ones
+	// of the logical P4Info.
+	policy, err := s.readPolicy(ctx)
 	if err != nil {
 		return err
 	}
-	for {
-		response, err := fromTarget.Recv()
-		if err == io.EOF {
-			return nil
+	upgrader := s.Pipeline.Upgrader()
+	store := s.P4RtStore.Snapshot()
+	var entities []*p4v1.Entity
+	for _, e := range request.Entities {
+		if upgrader != nil {
+			if e, err = upgrader.UpgradeEntity(e); err != nil {
+				return status.Errorf(codes.InvalidArgument, "mapr: %v", err)
+			}
+		}
+		if err := policy.CheckRead(e); err != nil {
+			return err
 		}
+		found, err := s.readEntity(ctx, store, e)
 		if err != nil {
 			return err
 		}
+		entities = append(entities, found...)
+	}
+	if policy != nil {
+		// Filter out entities not in the scope of the role, e.g., returned by wildcard reads.
+		readable := entities[:0]
+		for _, e := range entities {
+			if policy.CanRead(e) {
+				readable = append(readable, e)
+			}
+		}
+		entities = readable
+	}
+	if upgrader != nil {
+		// Entities that do not exist in the older version, e.g., written by controllers of other roles using the
+		// current one, are skipped.
+		downgraded := entities[:0]
+		for _, e := range entities {
+			if e, err = upgrader.DowngradeEntity(e); err != nil {
+				log.Debugf("Skipping entity in read response: %v", err)
+				continue
+			}
+			downgraded = append(downgraded, e)
+		}
+		entities = downgraded
+	}
+	for len(entities) > 0 {
+		n := len(entities)
+		if n > readResponseEntities {
+			n = readResponseEntities
+		}
+		response := &p4v1.ReadResponse{Entities: entities[:n]}
 		logMsg(ToCtrl, response)
 		if err := toClient.Send(response); err != nil {
 			return err
 		}
+		entities = entities[n:]
+	}
+	return nil
+}
+
+// Returns the logical entities of the given store matching the given entity of a ReadRequest. Counters and registers
+// are not stored, see translate.ReadStore: with the dummy processor, they are read from the target, as its entities
+// are the logical ones, otherwise they are not supported.
+func (s Server) readEntity(ctx context.Context, store translate.P4RtStore, e *p4v1.Entity) ([]*p4v1.Entity, error) {
+	entities, err := translate.ReadStore(store, e)
+	if status.Code(err) != codes.Unimplemented || s.Config.Processor != "dummy" {
+		return entities, err
+	}
+	request := &p4v1.ReadRequest{DeviceId: s.Session.deviceId, Entities: []*p4v1.Entity{e}}
+	logMsg(ToTarget, request)
+	fromTarget, err := target.Read(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		response, err := fromTarget.Recv()
+		if err == io.EOF {
+			return entities, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		logMsg(FromTarget, response)
+		entities = append(entities, response.Entities...)
+	}
+}
+
+// Returns the policy of the role specified by the controller in the metadata of a Read RPC, nil for full access. Once
+// some role is restricted, the metadata is required, as reads without it would bypass the restrictions.
+func (s Server) readPolicy(ctx context.Context) (*roles.Policy, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(readRoleMetadataKey)
+	if len(values) == 0 {
+		if s.Roles.Restricted() {
+			return nil, status.Errorf(codes.PermissionDenied, "mapr: missing %s metadata, roles are restricted",
+				readRoleMetadataKey)
+		}
+		return nil, nil
+	}
+	roleId, err := strconv.ParseUint(values[0], 10, 64)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "mapr: invalid %s %q", readRoleMetadataKey, values[0])
 	}
+	return s.Roles.Get(roleId), nil
 }
 
 func (s Server) SetForwardingPipelineConfig(ctx context.Context, request *p4v1.SetForwardingPipelineConfigRequest) (
 	*p4v1.SetForwardingPipelineConfigResponse, error) {
 	logMsg(FromCtrl, request)
-	// Compare P4Info in request with the one passed via flags. Return error if not equal.
-	if bytes, err := ioutil.ReadFile(*logicalP4InfoPath); err == nil {
-		logicalP4Info := &p4confv1.P4Info{}
-		if err := proto.Unmarshal(bytes, logicalP4Info); err != nil {
-			panic(err)
+	if err := s.Arbitrator.CheckPrimary(request.DeviceId, request.RoleId, request.ElectionId); err != nil {
+		return nil, err
+	}
+	if !s.Roles.Get(request.RoleId).CanPushPipeline() {
+		return nil, status.Errorf(codes.PermissionDenied, "mapr: role %d cannot set the pipeline config",
+			request.RoleId)
+	}
+	// No writes while changing the pipeline and the stores.
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	var upgrader *translate.VersionUpgrader
+	var err error
+	switch request.Action {
+	case p4v1.SetForwardingPipelineConfigRequest_VERIFY,
+		p4v1.SetForwardingPipelineConfigRequest_VERIFY_AND_SAVE,
+		p4v1.SetForwardingPipelineConfigRequest_VERIFY_AND_COMMIT,
+		p4v1.SetForwardingPipelineConfigRequest_RECONCILE_AND_COMMIT:
+		if upgrader, err = s.verifyPipelineConfig(request.Config); err != nil {
+			return nil, err
 		}
-		if !proto.Equal(request.Config.P4Info, logicalP4Info) {
-			return nil, status.Error(codes.InvalidArgument, "mapr: P4Info not supported")
+	case p4v1.SetForwardingPipelineConfigRequest_COMMIT:
+		if !s.Pipeline.HasSaved() {
+			return nil, status.Error(codes.FailedPrecondition, "mapr: no saved pipeline config to commit")
 		}
-	} else {
-		panic(err)
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "mapr: invalid action %s", request.Action)
 	}
-	// Modify request by swapping config with target one
-	pieces := strings.Split(*targetP4ConfigPaths, ",")
-	// Read and parse physical p4info.bin
-	if bytes, err := ioutil.ReadFile(pieces[0]); err == nil {
-		if err := proto.Unmarshal(bytes, request.Config.P4Info); err != nil {
-			panic(err)
-		}
-	} else {
-		panic(err)
+
+	// Forward the same action with the target config using mapr's session. COMMIT carries no config, the target
+	// commits the one it saved.
+	targetRequest := &p4v1.SetForwardingPipelineConfigRequest{
+		DeviceId:   s.Session.deviceId,
+		ElectionId: s.Session.electionId,
+		Action:     request.Action,
 	}
-	// Read P4 config blob
-	if bytes, err := ioutil.ReadFile(pieces[1]); err == nil {
-		request.Config.P4DeviceConfig = bytes
-	} else {
-		panic(err)
+	if request.Action != p4v1.SetForwardingPipelineConfigRequest_COMMIT {
+		targetRequest.Config = s.targetPipelineConfig(request.Config)
 	}
-	// Forward modified request
-	logMsg(ToTarget, request)
-	if response, err := target.SetForwardingPipelineConfig(ctx, request); err == nil {
-		logMsg(ToCtrl, response)
-		return response, nil
-	} else {
+	logMsg(ToTarget, targetRequest)
+	if _, err := target.SetForwardingPipelineConfig(ctx, targetRequest); err != nil {
+		log.Errorf("%s %v", FromTarget, err)
 		return nil, err
 	}
+
+	switch request.Action {
+	case p4v1.SetForwardingPipelineConfigRequest_VERIFY_AND_SAVE:
+		s.Pipeline.Save(request.Config, upgrader)
+	case p4v1.SetForwardingPipelineConfigRequest_VERIFY_AND_COMMIT:
+		// The target forwarding state is cleared.
+		s.resetState()
+		s.Pipeline.Set(request.Config, upgrader)
+		s.Health.Set(health.NoPipelineConfig, false)
+	case p4v1.SetForwardingPipelineConfigRequest_COMMIT:
+		s.resetState()
+		s.Pipeline.Commit()
+		s.Health.Set(health.NoPipelineConfig, false)
+	case p4v1.SetForwardingPipelineConfigRequest_RECONCILE_AND_COMMIT:
+		// The target forwarding state is preserved, bring it in sync with the re-translated logical state.
+		s.Health.Set(health.Reconciling, true)
+		err := s.reconcile(ctx)
+		s.Health.Set(health.Reconciling, false)
+		if err != nil {
+			s.resetState()
+			return nil, status.Errorf(codes.Internal,
+				"mapr: cannot reconcile forwarding state, state has been reset and should be pushed again: %v", err)
+		}
+		s.Pipeline.Set(request.Config, upgrader)
+		s.Health.Set(health.NoPipelineConfig, false)
+	}
+	response := &p4v1.SetForwardingPipelineConfigResponse{}
+	logMsg(ToCtrl, response)
+	return response, nil
+}
+
+// Returns an INVALID_ARGUMENT error listing the incompatibilities if the P4Info of the given logical config is not
+// compatible with the current logical P4Info or any of its older versions. Otherwise, returns the upgrader of the
+// compatible version, nil for the current one.
+func (s Server) verifyPipelineConfig(config *p4v1.ForwardingPipelineConfig) (*translate.VersionUpgrader, error) {
+	if config == nil {
+		return nil, status.Error(codes.InvalidArgument, "mapr: missing pipeline config")
+	}
+	// Without a logical P4Info (dummy processor only), the config is relayed to the target as is.
+	if s.LogicalP4Info == nil {
+		return nil, nil
+	}
+	incompatibilities := translate.CheckP4Info(config.P4Info, s.LogicalP4Info)
+	if len(incompatibilities) == 0 {
+		return nil, nil
+	}
+	for _, v := range s.LogicalVersions {
+		if len(translate.CheckP4Info(config.P4Info, v.p4info)) == 0 {
+			log.Infof("Pipeline config uses older logical P4Info %s", v.path)
+			return v.upgrader, nil
+		}
+	}
+	return nil, status.Errorf(codes.InvalidArgument, "mapr: P4Info not supported: %s",
+		strings.Join(incompatibilities, "; "))
+}
+
+// Returns the target config to push in place of the given logical one, with the same cookie.
+func (s Server) targetPipelineConfig(config *p4v1.ForwardingPipelineConfig) *p4v1.ForwardingPipelineConfig {
+	if s.TargetConfig == nil {
+		return config
+	}
+	return &p4v1.ForwardingPipelineConfig{
+		P4Info:         s.TargetConfig.P4Info,
+		P4DeviceConfig: s.TargetConfig.P4DeviceConfig,
+		Cookie:         config.Cookie,
+	}
+}
+
+// Clears the logical store and the translator context, to be called when the target forwarding state is cleared.
+func (s Server) resetState() {
+	log.Info("Clearing logical and target state")
+	s.P4RtStore.Clear()
+	s.Translator.Reset()
 }
 
+// Re-translates the logical state and writes to the target the updates needed to reconcile its state.
+func (s Server) reconcile(ctx context.Context) error {
+	updates, err := s.Translator.Reconcile(s.P4RtStore)
+	if err != nil {
+		return err
+	}
+	log.Infof("Reconciling target state with %d updates", len(updates))
+	for len(updates) > 0 {
+		n := len(updates)
+		if n > reconcileBatchSize {
+			n = reconcileBatchSize
+		}
+		request := &p4v1.WriteRequest{
+			DeviceId:   s.Session.deviceId,
+			ElectionId: s.Session.electionId,
+			Updates:    updates[:n],
+		}
+		logMsg(ToTarget, request)
+		if _, err := target.Write(ctx, request); err != nil {
+			log.Errorf("%s %v", FromTarget, err)
+			return err
+		}
+		updates = updates[n:]
+	}
+	return nil
+}
+
+// Returns the logical pipeline config set by controllers, not the target one.
 func (s Server) GetForwardingPipelineConfig(ctx context.Context, request *p4v1.GetForwardingPipelineConfigRequest) (
-	// TODO: implement returning logical config instead of physical one
 	*p4v1.GetForwardingPipelineConfigResponse, error) {
 	logMsg(FromCtrl, request)
-	response, err := target.GetForwardingPipelineConfig(ctx, request)
+	if request.DeviceId != s.Config.Server.DeviceId {
+		return nil, status.Errorf(codes.NotFound, "mapr: invalid device ID %d", request.DeviceId)
+	}
+	config, err := s.Pipeline.Response(request.ResponseType)
 	if err != nil {
 		return nil, err
 	}
+	response := &p4v1.GetForwardingPipelineConfigResponse{Config: config}
 	logMsg(ToCtrl, response)
 	return response, nil
 }
@@ -254,26 +954,28 @@ func (s Server) StreamChannel(inStream p4v1.P4Runtime_StreamChannelServer) error
 	log.Println("StreamChannel opened!")
 	defer log.Println("StreamChannel closed!")
 
-	outCtx, outCancel := context.WithCancel(inStream.Context())
-	defer outCancel()
-	outStream, err := target.StreamChannel(outCtx)
-	if err != nil {
-		return err
+	if s.PacketIoErr != nil {
+		return status.Errorf(codes.FailedPrecondition, "mapr: cannot translate packet metadata: %v", s.PacketIoErr)
 	}
 
-	waiterr := make(chan error)
+	client := s.Arbitrator.Connect()
+	defer s.Arbitrator.Disconnect(client)
 
+	waiterr := make(chan error, 2)
+
+	// Arbitration updates and packet-ins are queued by the arbitrator and the target session.
 	go func() {
 		for {
-			response, err := outStream.Recv()
-			if err != nil {
-				waiterr <- err
-				return
-			}
-			logMsg(FromTarget, response)
-			if err := inStream.Send(response); err != nil {
-				waiterr <- err
+			select {
+			case <-inStream.Context().Done():
+				waiterr <- inStream.Context().Err()
 				return
+			case response := <-client.Out():
+				logMsg(ToCtrl, response)
+				if err := inStream.Send(response); err != nil {
+					waiterr <- err
+					return
+				}
 			}
 		}
 	}()
@@ -282,16 +984,54 @@ func (s Server) StreamChannel(inStream p4v1.P4Runtime_StreamChannelServer) error
 		for {
 			request, err := inStream.Recv()
 			if err != nil {
-				if err == io.EOF {
-					err = outStream.CloseSend()
-				}
 				waiterr <- err
 				return
 			}
 			logMsg(FromCtrl, request)
-			if err := outStream.Send(request); err != nil {
-				waiterr <- err
-				return
+			switch x := request.Update.(type) {
+			case *p4v1.StreamMessageRequest_Arbitration:
+				policy, err := s.Roles.Compile(x.Arbitration.Role)
+				if err != nil {
+					waiterr <- status.Errorf(codes.InvalidArgument, "mapr: %v", err)
+					return
+				}
+				if err := s.Arbitrator.Arbitrate(client, x.Arbitration); err != nil {
+					waiterr <- err
+					return
+				}
+				// The config of a role is the one sent by its primary controller.
+				if x.Arbitration.Role.GetConfig() != nil && s.Arbitrator.IsPrimary(client) {
+					s.Roles.Set(client.RoleId(), policy)
+				}
+			case *p4v1.StreamMessageRequest_Packet:
+				if !s.Arbitrator.IsPrimary(client) {
+					log.Warnf("Dropping packet-out from non-primary %s", client)
+					s.Metrics.PacketOut(metrics.PacketOutNotPrimary)
+					continue
+				}
+				packet := x.Packet
+				if upgrader := s.Pipeline.Upgrader(); upgrader != nil {
+					if packet, err = upgrader.UpgradePacketOut(packet); err != nil {
+						s.Metrics.PacketOut(metrics.PacketOutInvalid)
+						waiterr <- status.Errorf(codes.InvalidArgument, "mapr: %v", err)
+						return
+					}
+				}
+				if s.PacketIo != nil {
+					if packet, err = s.PacketIo.TranslatePacketOut(packet); err != nil {
+						s.Metrics.PacketOut(metrics.PacketOutInvalid)
+						waiterr <- status.Errorf(codes.InvalidArgument, "mapr: %v", err)
+						return
+					}
+				}
+				if err := s.Session.SendPacketOut(packet); err != nil {
+					log.Errorf("Dropping packet-out: %v", err)
+					s.Metrics.PacketOut(metrics.PacketOutQueueFull)
+				} else {
+					s.Metrics.PacketOut(metrics.PacketOutSent)
+				}
+			default:
+				log.Warnf("Ignoring %T from %s", x, client)
 			}
 		}
 	}()
@@ -303,9 +1043,162 @@ func (s Server) StreamChannel(inStream p4v1.P4Runtime_StreamChannelServer) error
 	}
 }
 
-func Start(port int, targetAddr string) {
+// Returns the logical ingress port of the given packet-in, 0 if unknown.
+func (s Server) packetInPort(packet *p4v1.PacketIn) uint32 {
+	for _, m := range packet.Metadata {
+		if s.inPortMetaId != 0 && m.MetadataId == s.inPortMetaId {
+			var port uint32
+			for _, b := range m.Value {
+				port = port<<8 | uint32(b)
+			}
+			return port
+		}
+	}
+	return 0
+}
+
+// Relays the given packet-in from the target to the controller its punt reason is routed to, if any, otherwise to the
+// primary controllers of the roles accepting it.
+func (s Server) handlePacketIn(packet *p4v1.PacketIn) {
+	if s.PacketIo != nil {
+		var err error
+		if packet, err = s.PacketIo.TranslatePacketIn(packet); err != nil {
+			// Not the controller's fault, drop the packet.
+			log.Errorf("Dropping packet-in: %v", err)
+			s.Metrics.PacketIn(metrics.PacketInTranslationError)
+			return
+		}
+	}
+	reason := punt.Classify(packet.Payload)
+	port := s.packetInPort(packet)
+	if !s.Punt.Allow(reason, port) {
+		// Might be a storm, don't flood the log.
+		log.Tracef("Dropping %s packet-in from port %d, rate limit exceeded", reason, port)
+		s.Metrics.PacketIn(metrics.PacketInRateLimited)
+		return
+	}
+	// Policies and punt reasons apply to the current logical P4Info, controllers get the version they pushed.
+	toController := packet
+	if upgrader := s.Pipeline.Upgrader(); upgrader != nil {
+		var err error
+		if toController, err = upgrader.DowngradePacketIn(packet); err != nil {
+			log.Errorf("Dropping packet-in: %v", err)
+			s.Metrics.PacketIn(metrics.PacketInTranslationError)
+			return
+		}
+	}
+	response := &p4v1.StreamMessageResponse{
+		Update: &p4v1.StreamMessageResponse_Packet{Packet: toController},
+	}
+	if route := s.Punt.Route(reason); route != nil {
+		var electionId *p4v1.Uint128
+		if route.ElectionId != 0 {
+			electionId = &p4v1.Uint128{Low: route.ElectionId}
+		}
+		c := s.Arbitrator.Lookup(route.RoleId, electionId)
+		if c == nil {
+			log.Debugf("Dropping %s packet-in, no controller for role %d", reason, route.RoleId)
+			s.Metrics.PacketIn(metrics.PacketInNoController)
+		} else if !c.Send(response) {
+			log.Warnf("Dropping packet-in for %s, queue is full", c)
+			s.Metrics.PacketIn(metrics.PacketInQueueFull)
+		} else {
+			s.Metrics.PacketIn(metrics.PacketInSent)
+		}
+		return
+	}
+	// Counted as sent if at least one controller got it.
+	result := metrics.PacketInNoController
+	for _, c := range s.Arbitrator.Primaries() {
+		if !s.Roles.Get(c.RoleId()).AcceptsPacketIn(packet) {
+			continue
+		}
+		if !c.Send(response) {
+			log.Warnf("Dropping packet-in for %s, queue is full", c)
+			if result == metrics.PacketInNoController {
+				result = metrics.PacketInQueueFull
+			}
+		} else {
+			result = metrics.PacketInSent
+		}
+	}
+	s.Metrics.PacketIn(result)
+}
+
+// Returns the transport credentials towards the given target.
+func targetDialOption(c config.Target) (grpc.DialOption, error) {
+	if !c.Tls.Enabled {
+		return grpc.WithInsecure(), nil
+	}
+	serverName := c.Tls.ServerName
+	if serverName == "" {
+		host, _, err := net.SplitHostPort(c.Addr)
+		if err != nil {
+			return nil, err
+		}
+		serverName = host
+	}
+	tlsConfig, err := tlsutil.ClientConfig(tlsutil.ClientOptions{
+		CAFile:     c.Tls.Ca,
+		CertFile:   c.Tls.Cert,
+		KeyFile:    c.Tls.Key,
+		ServerName: serverName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)), nil
+}
+
+// Returns the server options for the given transport credentials towards controllers.
+func serverOptions(c config.ServerTls) ([]grpc.ServerOption, error) {
+	if c.Cert == "" {
+		return nil, nil
+	}
+	tlsConfig, err := tlsutil.ServerConfig(tlsutil.ServerOptions{
+		CertFile:     c.Cert,
+		KeyFile:      c.Key,
+		ClientCAFile: c.ClientCa,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []grpc.ServerOption{grpc.Creds(credentials.NewTLS(tlsConfig))}, nil
+}
+
+// Serves over HTTP on the given address the metrics at /metrics, the readiness at /readyz (503 if not serving
+// controllers), and the liveness at /healthz.
+func serveHttp(addr string, s *Server) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", s.Metrics.Handler())
+	mux.Handle("/readyz", s.Health.ReadyHandler())
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintln(w, "ok")
+	})
+	log.Printf("Serving metrics and health checks on http://%s", addr)
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		log.Errorf("Cannot serve metrics and health checks: %v", err)
+	}
+}
+
+func Start(c *config.Config) {
 	// Client to target
-	conn, err := grpc.Dial(targetAddr, grpc.WithInsecure())
+	dialOption, err := targetDialOption(c.Target)
+	if err != nil {
+		log.Fatalf("Invalid target TLS config: %v", err)
+	}
+	if c.Tracing.OtlpEndpoint != "" {
+		shutdown, err := tracing.Start(context.Background(), c.Tracing.OtlpEndpoint)
+		if err != nil {
+			log.Fatalf("Failed to start tracing: %v", err)
+		}
+		defer func() {
+			_ = shutdown(context.Background())
+		}()
+	}
+	mapr := NewServer(c)
+	conn, err := grpc.Dial(c.Target.Addr, dialOption,
+		grpc.WithChainUnaryInterceptor(mapr.Metrics.UnaryClientInterceptor(), tracing.UnaryClientInterceptor()))
 	if err != nil {
 		log.Fatalf("Failed to dial target: %v", err)
 	}
@@ -315,22 +1208,40 @@ func Start(port int, targetAddr string) {
 	target = p4v1.NewP4RuntimeClient(conn)
 
 	// Server
-	lis, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", port))
+	serverOpts, err := serverOptions(c.Server.Tls)
+	if err != nil {
+		log.Fatalf("Invalid TLS config: %v", err)
+	}
+	lis, err := net.Listen("tcp", net.JoinHostPort(c.Server.BindAddr, strconv.Itoa(c.Server.Port)))
 	if err != nil {
 		log.Fatalf("Failed to listen: %v", err)
 	}
-	server := grpc.NewServer()
-	p4v1.RegisterP4RuntimeServer(server, NewServer())
-	log.Printf("Listening for controller on port %d, talking to target on %s...\n", port, targetAddr)
+	serverOpts = append(serverOpts,
+		grpc.ChainUnaryInterceptor(mapr.Metrics.UnaryServerInterceptor(), tracing.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(mapr.Metrics.StreamServerInterceptor()))
+	server := grpc.NewServer(serverOpts...)
+	if c.Metrics.Addr != "" {
+		go serveHttp(c.Metrics.Addr, mapr)
+	}
+	go mapr.Session.Run(context.Background())
+	if *configPath != "" {
+		// Reloaded configs are applied over the flags, as the startup one.
+		base, _ := configFromFlags()
+		go config.Watch(context.Background(), *configPath, base, configWatchInterval, mapr.applyConfig)
+	}
+	p4v1.RegisterP4RuntimeServer(server, mapr)
+	mapr.Health.Register(server)
+	mapr.Admin.Register(server)
+	log.Printf("Listening for controller on %s, talking to target on %s...\n", lis.Addr(), c.Target.Addr)
 	_ = server.Serve(lis)
 }
 
 func main() {
 	flag.Parse()
-	log.SetFormatter(&log.TextFormatter{
-		ForceColors:   true,
-		FullTimestamp: true,
-		DisableQuote:  true})
-	log.SetLevel(log.TraceLevel)
-	Start(*port, *targetAddr)
+	c, err := loadConfig()
+	if err != nil {
+		log.Fatalf("Invalid config: %v", err)
+	}
+	applyLogConfig(c.Log)
+	Start(c)
 }
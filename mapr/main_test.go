@@ -0,0 +1,175 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"context"
+	"github.com/golang/protobuf/proto"
+	p4confv1 "github.com/p4lang/p4runtime/go/p4/config/v1"
+	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"io/ioutil"
+	"mapr/config"
+	"mapr/fabric"
+	"mapr/roles"
+	"mapr/translate"
+	"testing"
+)
+
+func logicalP4Info(t *testing.T) *p4confv1.P4Info {
+	bytes, err := ioutil.ReadFile("../p4src/build/p4info.txt")
+	require.NoError(t, err)
+	p4info := &p4confv1.P4Info{}
+	require.NoError(t, proto.UnmarshalText(string(bytes), p4info))
+	return p4info
+}
+
+// A Read stream collecting the entities sent to the controller.
+type readStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	entities []*p4v1.Entity
+}
+
+func (r *readStream) Context() context.Context {
+	return r.ctx
+}
+
+func (r *readStream) Send(response *p4v1.ReadResponse) error {
+	r.entities = append(r.entities, response.Entities...)
+	return nil
+}
+
+// Returns a server with the given logical entities in its store.
+func newReadServer(t *testing.T, entities ...*p4v1.Entity) Server {
+	p4info := logicalP4Info(t)
+	s := Server{
+		Config:        &config.Config{Processor: "fabric"},
+		P4RtStore:     translate.NewP4RtStore("logical"),
+		Roles:         roles.NewRegistry(p4info),
+		Pipeline:      &pipelineConfig{},
+		LogicalP4Info: p4info,
+	}
+	for _, e := range entities {
+		require.NoError(t, s.P4RtStore.ApplyUpdate(&p4v1.Update{Type: p4v1.Update_INSERT, Entity: e}, false))
+	}
+	return s
+}
+
+// Reads the given entities with the given role, 0 for the default one.
+func read(t *testing.T, s Server, roleId string, entities ...*p4v1.Entity) []*p4v1.Entity {
+	stream := &readStream{ctx: metadata.NewIncomingContext(context.Background(),
+		metadata.Pairs(readRoleMetadataKey, roleId))}
+	require.NoError(t, s.Read(&p4v1.ReadRequest{Entities: entities}, stream))
+	return stream.entities
+}
+
+func tableEntryEntity(t *p4v1.TableEntry) *p4v1.Entity {
+	return &p4v1.Entity{Entity: &p4v1.Entity_TableEntry{TableEntry: t}}
+}
+
+var readIfType = tableEntryEntity((&translate.IngressPipeIfTypesEntry{
+	Port:   []byte{0, 1},
+	Action: &translate.IngressPipeSetIfTypeAction{IfType: []byte{translate.IfTypeCore}},
+}).ToTableEntry())
+
+var readMyStation = tableEntryEntity((&translate.IngressPipeMyStationsEntry{
+	Port:   []byte{0, 1},
+	EthDst: []byte{0xaa, 0, 0, 0, 0, 1},
+	Action: &translate.IngressPipeSetMyStationAction{},
+}).ToTableEntry())
+
+func Test_Server_Read(t *testing.T) {
+	s := newReadServer(t, readIfType, readMyStation)
+	all := tableEntryEntity(&p4v1.TableEntry{})
+
+	assert.ElementsMatch(t, []*p4v1.Entity{readIfType, readMyStation}, read(t, s, "0", all))
+	assert.Equal(t, []*p4v1.Entity{readMyStation}, read(t, s, "0",
+		tableEntryEntity(&p4v1.TableEntry{TableId: translate.Table_IngressPipeMyStations})))
+}
+
+// The policy of a role applies to the logical entities, filtering out the ones of wildcard reads out of its scope.
+func Test_Server_Read_Policy(t *testing.T) {
+	s := newReadServer(t, readIfType, readMyStation)
+	policy, err := roles.NewPolicy(1, &roles.Config{ReadOnlyEntities: []string{"if_types"}}, s.LogicalP4Info)
+	require.NoError(t, err)
+	s.Roles.Set(1, policy)
+
+	assert.Equal(t, []*p4v1.Entity{readIfType}, read(t, s, "1", tableEntryEntity(&p4v1.TableEntry{})))
+	stream := &readStream{ctx: metadata.NewIncomingContext(context.Background(),
+		metadata.Pairs(readRoleMetadataKey, "1"))}
+	assert.Error(t, s.Read(&p4v1.ReadRequest{Entities: []*p4v1.Entity{readMyStation}}, stream))
+
+	// Once a role is restricted, reads must specify theirs, the default role keeps full access.
+	err = s.Read(&p4v1.ReadRequest{Entities: []*p4v1.Entity{readMyStation}}, &readStream{ctx: context.Background()})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.ElementsMatch(t, []*p4v1.Entity{readIfType, readMyStation}, read(t, s, "0",
+		tableEntryEntity(&p4v1.TableEntry{})))
+}
+
+// Without restricted roles, reads without role have full access.
+func Test_Server_Read_NoRole(t *testing.T) {
+	s := newReadServer(t, readIfType)
+	stream := &readStream{ctx: context.Background()}
+	require.NoError(t, s.Read(&p4v1.ReadRequest{Entities: []*p4v1.Entity{tableEntryEntity(&p4v1.TableEntry{})}}, stream))
+	assert.Equal(t, []*p4v1.Entity{readIfType}, stream.entities)
+}
+
+// Controllers using an older version of the logical P4Info read the logical entities translated to their version.
+func Test_Server_Read_OlderVersion(t *testing.T) {
+	s := newReadServer(t, readIfType, readMyStation)
+	older := proto.Clone(s.LogicalP4Info).(*p4confv1.P4Info)
+	for _, x := range older.Tables {
+		if x.Preamble.Name == "IngressPipe.if_types" {
+			x.Preamble.Id = 1000
+		}
+	}
+	upgrader, err := translate.NewVersionUpgrader(older, s.LogicalP4Info)
+	require.NoError(t, err)
+	s.Pipeline.Set(&p4v1.ForwardingPipelineConfig{P4Info: older}, upgrader)
+
+	olderIfType := proto.Clone(readIfType).(*p4v1.Entity)
+	olderIfType.GetTableEntry().TableId = 1000
+	assert.Equal(t, []*p4v1.Entity{olderIfType}, read(t, s, "0", tableEntryEntity(&p4v1.TableEntry{TableId: 1000})))
+	assert.ElementsMatch(t, []*p4v1.Entity{olderIfType, readMyStation}, read(t, s, "0",
+		tableEntryEntity(&p4v1.TableEntry{})))
+}
+
+// Reads return the logical ports written by controllers, while the target entities have the mapped ones.
+func Test_Server_Read_Ports(t *testing.T) {
+	s := newReadServer(t)
+	ports, err := translate.NewPortMap([]translate.PortMapEntry{{LogicalPort: 1, TargetPort: 260}})
+	require.NoError(t, err)
+	ctx := translate.NewContext(ports)
+	s.Translator = translate.NewTranslator(fabric.NewFabricProcessor(ctx, fabric.DefaultConfig()), ctx, nil)
+	// As written by Server.writeUpdate, without the target.
+	u := &p4v1.Update{Type: p4v1.Update_INSERT, Entity: readMyStation}
+	target, err := s.Translator.Translate(u)
+	require.NoError(t, err)
+	require.NoError(t, s.P4RtStore.ApplyUpdate(u, false))
+	require.NoError(t, s.Translator.ApplyUpdate(u, target))
+
+	require.Len(t, target, 1)
+	assert.Equal(t, []byte{0x01, 0x04}, target[0].Entity.GetTableEntry().Match[0].GetExact().Value)
+	assert.Equal(t, []*p4v1.Entity{readMyStation}, read(t, s, "0", tableEntryEntity(&p4v1.TableEntry{})))
+}
+
+// The fabric processor without a target pipeline config is rejected at startup, rather than failing in NewServer.
+func Test_loadConfig_FabricWithoutTargetConfig(t *testing.T) {
+	defer func(proc, logical string) {
+		*processorName, *logicalP4InfoPath = proc, logical
+	}(*processorName, *logicalP4InfoPath)
+	*processorName, *logicalP4InfoPath = "fabric", "p4info.bin"
+
+	_, err := loadConfig()
+	assert.EqualError(t, err, "processor fabric requires logical_p4info and target p4info")
+}
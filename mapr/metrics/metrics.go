@@ -0,0 +1,241 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package metrics collects Prometheus metrics about the RPCs served by mapr and sent to the target, the translation of
+// logical updates, packet I/O, and the size of the stores.
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"github.com/golang/protobuf/proto"
+	p4confv1 "github.com/p4lang/p4runtime/go/p4/config/v1"
+	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"net/http"
+	"time"
+)
+
+const namespace = "mapr"
+
+// Outcomes of packet-ins received from the target.
+const (
+	PacketInSent             = "sent"
+	PacketInTranslationError = "translation_error"
+	PacketInRateLimited      = "rate_limited"
+	PacketInNoController     = "no_controller"
+	PacketInQueueFull        = "queue_full"
+)
+
+// Outcomes of packet-outs received from controllers.
+const (
+	PacketOutSent       = "sent"
+	PacketOutNotPrimary = "not_primary"
+	PacketOutInvalid    = "invalid"
+	PacketOutQueueFull  = "queue_full"
+)
+
+// Number of target updates produced by the translation of a logical update.
+var fanoutBuckets = []float64{0, 1, 2, 4, 8, 16, 32, 64, 128}
+
+// The metrics of a mapr instance, kept in their own registry.
+type Metrics struct {
+	registry *prometheus.Registry
+	// Resolves the IDs of the logical P4Info.
+	logical *names
+
+	rpcRequests       *prometheus.CounterVec
+	rpcDuration       *prometheus.HistogramVec
+	targetRpcDuration *prometheus.HistogramVec
+	streams           *prometheus.GaugeVec
+	streamMessages    *prometheus.CounterVec
+	fanout            *prometheus.HistogramVec
+	translationErrors *prometheus.CounterVec
+	packetIns         *prometheus.CounterVec
+	packetOuts        *prometheus.CounterVec
+}
+
+// Returns new metrics, labelling logical entities with the names in the given P4Info, nil to use IDs.
+func New(logicalP4Info *p4confv1.P4Info) *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		logical:  newNames(logicalP4Info),
+		rpcRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rpc_requests_total",
+			Help:      "RPCs served to controllers, by method and status code.",
+		}, []string{"method", "code"}),
+		rpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "rpc_duration_seconds",
+			Help:      "Latency of the unary RPCs served to controllers, by method and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "code"}),
+		targetRpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "target_rpc_duration_seconds",
+			Help:      "Latency of the unary RPCs sent to the target, e.g., Write, by method and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "code"}),
+		streams: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "streams",
+			Help:      "Open streams with controllers, by method.",
+		}, []string{"method"}),
+		streamMessages: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "stream_messages_total",
+			Help:      "Messages received (in) and sent (out) on streams with controllers, by method and type.",
+		}, []string{"method", "direction", "type"}),
+		fanout: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "translation_fanout",
+			Help:      "Target updates produced by the translation of a logical update, by logical table.",
+			Buckets:   fanoutBuckets,
+		}, []string{"table"}),
+		translationErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "translation_errors_total",
+			Help:      "Logical updates that could not be translated, by logical table.",
+		}, []string{"table"}),
+		packetIns: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "packet_ins_total",
+			Help:      "Packet-ins received from the target, by result.",
+		}, []string{"result"}),
+		packetOuts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "packet_outs_total",
+			Help:      "Packet-outs received from controllers, by result.",
+		}, []string{"result"}),
+	}
+	m.registry.MustRegister(m.rpcRequests, m.rpcDuration, m.targetRpcDuration, m.streams, m.streamMessages,
+		m.fanout, m.translationErrors, m.packetIns, m.packetOuts,
+		prometheus.NewGoCollector(), prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}))
+	return m
+}
+
+// Returns the HTTP handler serving the metrics in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Returns an interceptor counting and timing the unary RPCs served to controllers.
+func (m *Metrics) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (
+		interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		code := status.Code(err).String()
+		m.rpcRequests.WithLabelValues(info.FullMethod, code).Inc()
+		m.rpcDuration.WithLabelValues(info.FullMethod, code).Observe(time.Since(start).Seconds())
+		return resp, err
+	}
+}
+
+// Returns an interceptor counting the streams opened by controllers and the messages exchanged on them.
+func (m *Metrics) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		streams := m.streams.WithLabelValues(info.FullMethod)
+		streams.Inc()
+		defer streams.Dec()
+		err := handler(srv, &countingStream{ServerStream: ss, method: info.FullMethod, messages: m.streamMessages})
+		m.rpcRequests.WithLabelValues(info.FullMethod, status.Code(err).String()).Inc()
+		return err
+	}
+}
+
+// Returns an interceptor timing the unary RPCs sent to the target.
+func (m *Metrics) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		m.targetRpcDuration.WithLabelValues(method, status.Code(err).String()).Observe(time.Since(start).Seconds())
+		return err
+	}
+}
+
+// Records the translation of the given logical update, which produced n target updates or failed with err.
+func (m *Metrics) ObserveTranslation(logical *p4v1.Update, n int, err error) {
+	table := m.logical.entityName(logical.GetEntity())
+	if err != nil {
+		m.translationErrors.WithLabelValues(table).Inc()
+		return
+	}
+	m.fanout.WithLabelValues(table).Observe(float64(n))
+}
+
+// Records a packet-in received from the target with the given result.
+func (m *Metrics) PacketIn(result string) {
+	m.packetIns.WithLabelValues(result).Inc()
+}
+
+// Records a packet-out received from a controller with the given result.
+func (m *Metrics) PacketOut(result string) {
+	m.packetOuts.WithLabelValues(result).Inc()
+}
+
+type countingStream struct {
+	grpc.ServerStream
+	method   string
+	messages *prometheus.CounterVec
+}
+
+func (s *countingStream) RecvMsg(msg interface{}) error {
+	err := s.ServerStream.RecvMsg(msg)
+	if err == nil {
+		s.messages.WithLabelValues(s.method, "in", messageType(msg)).Inc()
+	}
+	return err
+}
+
+func (s *countingStream) SendMsg(msg interface{}) error {
+	err := s.ServerStream.SendMsg(msg)
+	if err == nil {
+		s.messages.WithLabelValues(s.method, "out", messageType(msg)).Inc()
+	}
+	return err
+}
+
+// Returns the type of the given stream message, i.e., the name of the field set in the update oneof of P4Runtime
+// stream messages, or the message name for other streams.
+func messageType(msg interface{}) string {
+	switch x := msg.(type) {
+	case *p4v1.StreamMessageRequest:
+		switch x.Update.(type) {
+		case *p4v1.StreamMessageRequest_Arbitration:
+			return "arbitration"
+		case *p4v1.StreamMessageRequest_Packet:
+			return "packet"
+		case *p4v1.StreamMessageRequest_DigestAck:
+			return "digest_ack"
+		case *p4v1.StreamMessageRequest_Other:
+			return "other"
+		}
+	case *p4v1.StreamMessageResponse:
+		switch x.Update.(type) {
+		case *p4v1.StreamMessageResponse_Arbitration:
+			return "arbitration"
+		case *p4v1.StreamMessageResponse_Packet:
+			return "packet"
+		case *p4v1.StreamMessageResponse_Digest:
+			return "digest"
+		case *p4v1.StreamMessageResponse_IdleTimeoutNotification:
+			return "idle_timeout_notification"
+		case *p4v1.StreamMessageResponse_Other:
+			return "other"
+		case *p4v1.StreamMessageResponse_Error:
+			return "error"
+		}
+	case proto.Message:
+		return proto.MessageName(x)
+	}
+	return fmt.Sprintf("%T", msg)
+}
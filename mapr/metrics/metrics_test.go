@@ -0,0 +1,223 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"github.com/golang/protobuf/proto"
+	p4confv1 "github.com/p4lang/p4runtime/go/p4/config/v1"
+	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"io/ioutil"
+	"mapr/punt"
+	"mapr/translate"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var testP4Info = &p4confv1.P4Info{
+	Tables: []*p4confv1.Table{
+		{Preamble: &p4confv1.Preamble{Id: 1, Name: "IngressPipe.upstream.lines"}},
+	},
+	ActionProfiles: []*p4confv1.ActionProfile{
+		{Preamble: &p4confv1.Preamble{Id: 2, Name: "IngressPipe.upstream.ecmp"}},
+	},
+}
+
+func tableUpdate(tableId uint32) *p4v1.Update {
+	return &p4v1.Update{
+		Type: p4v1.Update_INSERT,
+		Entity: &p4v1.Entity{Entity: &p4v1.Entity_TableEntry{TableEntry: &p4v1.TableEntry{
+			TableId: tableId,
+			Match: []*p4v1.FieldMatch{{FieldId: 1, FieldMatchType: &p4v1.FieldMatch_Exact_{
+				Exact: &p4v1.FieldMatch_Exact{Value: []byte{byte(tableId)}}}}},
+		}}},
+	}
+}
+
+func memberUpdate(memberId uint32) *p4v1.Update {
+	return &p4v1.Update{
+		Type: p4v1.Update_INSERT,
+		Entity: &p4v1.Entity{Entity: &p4v1.Entity_ActionProfileMember{ActionProfileMember: &p4v1.ActionProfileMember{
+			ActionProfileId: 2,
+			MemberId:        memberId,
+		}}},
+	}
+}
+
+func Test_ObserveTranslation(t *testing.T) {
+	m := New(testP4Info)
+	m.ObserveTranslation(tableUpdate(1), 3, nil)
+	m.ObserveTranslation(tableUpdate(1), 0, nil)
+	m.ObserveTranslation(tableUpdate(1), 0, fmt.Errorf("foo"))
+	m.ObserveTranslation(tableUpdate(9), 0, fmt.Errorf("foo"))
+	m.ObserveTranslation(memberUpdate(1), 1, nil)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.translationErrors.WithLabelValues("IngressPipe.upstream.lines")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.translationErrors.WithLabelValues("table 9")))
+	err := testutil.CollectAndCompare(m.fanout, strings.NewReader(`
+# HELP mapr_translation_fanout Target updates produced by the translation of a logical update, by logical table.
+# TYPE mapr_translation_fanout histogram
+mapr_translation_fanout_bucket{table="IngressPipe.upstream.ecmp",le="0"} 0
+mapr_translation_fanout_bucket{table="IngressPipe.upstream.ecmp",le="1"} 1
+mapr_translation_fanout_bucket{table="IngressPipe.upstream.ecmp",le="2"} 1
+mapr_translation_fanout_bucket{table="IngressPipe.upstream.ecmp",le="4"} 1
+mapr_translation_fanout_bucket{table="IngressPipe.upstream.ecmp",le="8"} 1
+mapr_translation_fanout_bucket{table="IngressPipe.upstream.ecmp",le="16"} 1
+mapr_translation_fanout_bucket{table="IngressPipe.upstream.ecmp",le="32"} 1
+mapr_translation_fanout_bucket{table="IngressPipe.upstream.ecmp",le="64"} 1
+mapr_translation_fanout_bucket{table="IngressPipe.upstream.ecmp",le="128"} 1
+mapr_translation_fanout_bucket{table="IngressPipe.upstream.ecmp",le="+Inf"} 1
+mapr_translation_fanout_sum{table="IngressPipe.upstream.ecmp"} 1
+mapr_translation_fanout_count{table="IngressPipe.upstream.ecmp"} 1
+mapr_translation_fanout_bucket{table="IngressPipe.upstream.lines",le="0"} 1
+mapr_translation_fanout_bucket{table="IngressPipe.upstream.lines",le="1"} 1
+mapr_translation_fanout_bucket{table="IngressPipe.upstream.lines",le="2"} 1
+mapr_translation_fanout_bucket{table="IngressPipe.upstream.lines",le="4"} 2
+mapr_translation_fanout_bucket{table="IngressPipe.upstream.lines",le="8"} 2
+mapr_translation_fanout_bucket{table="IngressPipe.upstream.lines",le="16"} 2
+mapr_translation_fanout_bucket{table="IngressPipe.upstream.lines",le="32"} 2
+mapr_translation_fanout_bucket{table="IngressPipe.upstream.lines",le="64"} 2
+mapr_translation_fanout_bucket{table="IngressPipe.upstream.lines",le="128"} 2
+mapr_translation_fanout_bucket{table="IngressPipe.upstream.lines",le="+Inf"} 2
+mapr_translation_fanout_sum{table="IngressPipe.upstream.lines"} 3
+mapr_translation_fanout_count{table="IngressPipe.upstream.lines"} 2
+`))
+	assert.NoError(t, err)
+}
+
+func Test_UnaryServerInterceptor(t *testing.T) {
+	m := New(nil)
+	interceptor := m.UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/p4.v1.P4Runtime/Write"}
+	for _, err := range []error{nil, nil, status.Error(codes.InvalidArgument, "foo")} {
+		_, got := interceptor(context.Background(), nil, info, func(context.Context, interface{}) (interface{}, error) {
+			return nil, err
+		})
+		assert.Equal(t, err, got)
+	}
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.rpcRequests.WithLabelValues("/p4.v1.P4Runtime/Write", "OK")))
+	assert.Equal(t, 1.0,
+		testutil.ToFloat64(m.rpcRequests.WithLabelValues("/p4.v1.P4Runtime/Write", "InvalidArgument")))
+	assert.Equal(t, 2, testutil.CollectAndCount(m.rpcDuration))
+}
+
+// A stream receiving the given messages, then EOF.
+type fakeStream struct {
+	grpc.ServerStream
+	in []proto.Message
+}
+
+func (s *fakeStream) Context() context.Context {
+	return metadata.NewIncomingContext(context.Background(), nil)
+}
+
+func (s *fakeStream) RecvMsg(msg interface{}) error {
+	if len(s.in) == 0 {
+		return status.Error(codes.Canceled, "EOF")
+	}
+	proto.Merge(msg.(proto.Message), s.in[0])
+	s.in = s.in[1:]
+	return nil
+}
+
+func (s *fakeStream) SendMsg(interface{}) error {
+	return nil
+}
+
+func Test_StreamServerInterceptor(t *testing.T) {
+	m := New(nil)
+	interceptor := m.StreamServerInterceptor()
+	info := &grpc.StreamServerInfo{FullMethod: "/p4.v1.P4Runtime/StreamChannel"}
+	stream := &fakeStream{in: []proto.Message{
+		&p4v1.StreamMessageRequest{Update: &p4v1.StreamMessageRequest_Arbitration{}},
+		&p4v1.StreamMessageRequest{Update: &p4v1.StreamMessageRequest_Packet{Packet: &p4v1.PacketOut{}}},
+		&p4v1.StreamMessageRequest{Update: &p4v1.StreamMessageRequest_Packet{Packet: &p4v1.PacketOut{}}},
+	}}
+	err := interceptor(nil, stream, info, func(srv interface{}, ss grpc.ServerStream) error {
+		assert.Equal(t, 1.0, testutil.ToFloat64(m.streams.WithLabelValues(info.FullMethod)))
+		for {
+			request := &p4v1.StreamMessageRequest{}
+			if err := ss.RecvMsg(request); err != nil {
+				return err
+			}
+			if err := ss.SendMsg(&p4v1.StreamMessageResponse{
+				Update: &p4v1.StreamMessageResponse_Arbitration{}}); err != nil {
+				return err
+			}
+		}
+	})
+	assert.Equal(t, codes.Canceled, status.Code(err))
+	assert.Equal(t, 0.0, testutil.ToFloat64(m.streams.WithLabelValues(info.FullMethod)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.streamMessages.WithLabelValues(info.FullMethod, "in", "arbitration")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.streamMessages.WithLabelValues(info.FullMethod, "in", "packet")))
+	assert.Equal(t, 3.0, testutil.ToFloat64(m.streamMessages.WithLabelValues(info.FullMethod, "out", "arbitration")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.rpcRequests.WithLabelValues(info.FullMethod, "Canceled")))
+}
+
+func Test_RegisterStores(t *testing.T) {
+	m := New(testP4Info)
+	logical := translate.NewP4RtStore("logical")
+	for _, u := range []*p4v1.Update{tableUpdate(1), tableUpdate(3), memberUpdate(1), memberUpdate(2)} {
+		require.NoError(t, logical.ApplyUpdate(u, false))
+	}
+	ctx := translate.NewContext(nil)
+	require.NoError(t, ctx.Target().ApplyUpdate(tableUpdate(1), false))
+	m.RegisterStores(logical, ctx, &p4confv1.P4Info{Tables: []*p4confv1.Table{
+		{Preamble: &p4confv1.Preamble{Id: 1, Name: "FabricIngress.filtering.ingress_port_vlan"}},
+	}})
+
+	server := httptest.NewServer(m.Handler())
+	defer server.Close()
+	response, err := server.Client().Get(server.URL)
+	require.NoError(t, err)
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(response.Body)
+	require.NoError(t, err)
+	for _, line := range []string{
+		`mapr_store_entries{kind="table entry",name="IngressPipe.upstream.lines",store="logical"} 1`,
+		`mapr_store_entries{kind="table entry",name="table 3",store="logical"} 1`,
+		`mapr_store_entries{kind="action profile member",name="IngressPipe.upstream.ecmp",store="logical"} 2`,
+		`mapr_store_entries{kind="multicast group",name="",store="logical"} 0`,
+		`mapr_store_entries{kind="table entry",name="FabricIngress.filtering.ingress_port_vlan",store="target"} 1`,
+		`mapr_store_entries{kind="map",name="UpstreamAttachments",store="context"} 0`,
+	} {
+		assert.Contains(t, string(body), line)
+	}
+
+	// Sizes are read at each collection.
+	require.NoError(t, logical.ApplyUpdate(tableUpdate(5), false))
+	problems, err := testutil.GatherAndLint(m.registry, "mapr_store_entries")
+	require.NoError(t, err)
+	assert.Empty(t, problems)
+	assert.Equal(t, 3+1+8, testutil.CollectAndCount(&storeCollector{logical: logical, logicalNames: m.logical}))
+}
+
+func Test_RegisterPunt(t *testing.T) {
+	m := New(nil)
+	l := punt.NewLimiter(&punt.Config{PerPort: &punt.Limit{Rate: 1}})
+	m.RegisterPunt(l)
+	l.Allow(punt.ReasonAcl, 1)
+	l.Allow(punt.ReasonAcl, 1)
+	l.Allow(punt.ReasonPppoePadi, 1)
+
+	err := testutil.GatherAndCompare(m.registry, strings.NewReader(`
+# HELP mapr_packet_ins_dropped_total Packet-ins dropped by rate limits, by punt reason, logical ingress port, and exceeded limit (reason or port).
+# TYPE mapr_packet_ins_dropped_total counter
+mapr_packet_ins_dropped_total{limit="port",port="1",reason="acl"} 1
+mapr_packet_ins_dropped_total{limit="port",port="1",reason="pppoe_padi"} 1
+`), "mapr_packet_ins_dropped_total")
+	assert.NoError(t, err)
+}
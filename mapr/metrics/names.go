@@ -0,0 +1,76 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package metrics
+
+import (
+	"fmt"
+	p4confv1 "github.com/p4lang/p4runtime/go/p4/config/v1"
+	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
+)
+
+// Resolves P4Info IDs to names, used as label values.
+type names struct {
+	tables      map[uint32]string
+	actProfiles map[uint32]string
+}
+
+// Returns the names in the given P4Info, which might be nil.
+func newNames(p4info *p4confv1.P4Info) *names {
+	n := &names{
+		tables:      make(map[uint32]string),
+		actProfiles: make(map[uint32]string),
+	}
+	for _, t := range p4info.GetTables() {
+		n.tables[t.Preamble.Id] = t.Preamble.Name
+	}
+	for _, a := range p4info.GetActionProfiles() {
+		n.actProfiles[a.Preamble.Id] = a.Preamble.Name
+	}
+	return n
+}
+
+func (n *names) table(id uint32) string {
+	if name, ok := n.tables[id]; ok {
+		return name
+	}
+	return fmt.Sprintf("table %d", id)
+}
+
+func (n *names) actProfile(id uint32) string {
+	if name, ok := n.actProfiles[id]; ok {
+		return name
+	}
+	return fmt.Sprintf("action profile %d", id)
+}
+
+// Returns the name of the table or action profile of the given entity, or the kind of entity for others.
+func (n *names) entityName(e *p4v1.Entity) string {
+	switch x := e.GetEntity().(type) {
+	case *p4v1.Entity_TableEntry:
+		return n.table(x.TableEntry.TableId)
+	case *p4v1.Entity_ActionProfileMember:
+		return n.actProfile(x.ActionProfileMember.ActionProfileId)
+	case *p4v1.Entity_ActionProfileGroup:
+		return n.actProfile(x.ActionProfileGroup.ActionProfileId)
+	case *p4v1.Entity_MeterEntry:
+		return "meter entry"
+	case *p4v1.Entity_DirectMeterEntry:
+		return "direct meter entry"
+	case *p4v1.Entity_CounterEntry:
+		return "counter entry"
+	case *p4v1.Entity_DirectCounterEntry:
+		return "direct counter entry"
+	case *p4v1.Entity_PacketReplicationEngineEntry:
+		return "packet replication engine entry"
+	case *p4v1.Entity_ValueSetEntry:
+		return "value set entry"
+	case *p4v1.Entity_RegisterEntry:
+		return "register entry"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,38 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"mapr/punt"
+	"strconv"
+)
+
+var packetInsDroppedDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "packet_ins_dropped_total"),
+	"Packet-ins dropped by rate limits, by punt reason, logical ingress port, and exceeded limit (reason or port).",
+	[]string{"reason", "port", "limit"}, nil)
+
+// Reports the drop counters of a punt.Limiter at each scrape.
+type puntCollector struct {
+	limiter *punt.Limiter
+}
+
+// Reports the packet-ins dropped by the given limiter.
+func (m *Metrics) RegisterPunt(l *punt.Limiter) {
+	m.registry.MustRegister(&puntCollector{limiter: l})
+}
+
+func (c *puntCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- packetInsDroppedDesc
+}
+
+func (c *puntCollector) Collect(ch chan<- prometheus.Metric) {
+	for d, n := range c.limiter.Dropped() {
+		ch <- prometheus.MustNewConstMetric(packetInsDroppedDesc, prometheus.CounterValue, float64(n), string(d.Reason),
+			strconv.FormatUint(uint64(d.Port), 10), d.Limit)
+	}
+}
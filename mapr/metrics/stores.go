@@ -0,0 +1,111 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package metrics
+
+import (
+	p4confv1 "github.com/p4lang/p4runtime/go/p4/config/v1"
+	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	"mapr/translate"
+)
+
+var storeEntriesDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "store_entries"),
+	"Entries in the stores of mapr, by store, kind of entry and table, action profile or map.",
+	[]string{"store", "kind", "name"}, nil)
+
+// Reports the size of the stores at each scrape. Stores are read through snapshots, so scrapes do not block writes,
+// at the cost of the next writes copying the shards of the maps they modify.
+type storeCollector struct {
+	logical      translate.P4RtStore
+	ctx          translate.Context
+	logicalNames *names
+	targetNames  *names
+}
+
+// Reports the size of the given logical store and, if ctx is not nil, of its logical and target stores. Target IDs are
+// resolved with the given P4Info, nil to use IDs.
+func (m *Metrics) RegisterStores(logical translate.P4RtStore, ctx translate.Context, targetP4Info *p4confv1.P4Info) {
+	m.registry.MustRegister(&storeCollector{
+		logical:      logical,
+		ctx:          ctx,
+		logicalNames: m.logical,
+		targetNames:  newNames(targetP4Info),
+	})
+}
+
+func (c *storeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- storeEntriesDesc
+}
+
+func (c *storeCollector) Collect(ch chan<- prometheus.Metric) {
+	collectP4RtStore(ch, "logical", c.logical.Snapshot(), c.logicalNames)
+	if c.ctx == nil {
+		return
+	}
+	snapshot := c.ctx.Snapshot()
+	collectP4RtStore(ch, "target", snapshot.Target(), c.targetNames)
+	collectLogicalStore(ch, "context", snapshot.Logical())
+}
+
+func storeEntries(ch chan<- prometheus.Metric, store string, kind string, name string, n int) {
+	ch <- prometheus.MustNewConstMetric(storeEntriesDesc, prometheus.GaugeValue, float64(n), store, kind, name)
+}
+
+func collectP4RtStore(ch chan<- prometheus.Metric, store string, s translate.P4RtStore, n *names) {
+	tables := make(map[uint32]int)
+	s.FilterTableEntries(func(e *p4v1.TableEntry) bool {
+		tables[e.TableId]++
+		return false
+	})
+	for id, count := range tables {
+		storeEntries(ch, store, "table entry", n.table(id), count)
+	}
+	members := make(map[uint32]int)
+	s.FilterActProfMembers(func(m *p4v1.ActionProfileMember) bool {
+		members[m.ActionProfileId]++
+		return false
+	})
+	for id, count := range members {
+		storeEntries(ch, store, "action profile member", n.actProfile(id), count)
+	}
+	groups := make(map[uint32]int)
+	s.FilterActProfGroups(func(g *p4v1.ActionProfileGroup) bool {
+		groups[g.ActionProfileId]++
+		return false
+	})
+	for id, count := range groups {
+		storeEntries(ch, store, "action profile group", n.actProfile(id), count)
+	}
+	for kind, count := range map[string]int{
+		"counter entry":        s.CounterEntryCount(),
+		"direct counter entry": s.DirectCounterEntryCount(),
+		"meter entry":          s.MeterEntryCount(),
+		"direct meter entry":   s.DirectMeterEntryCount(),
+		"register entry":       s.RegisterEntryCount(),
+		"multicast group":      s.MulticastGroupCount(),
+		"clone session":        s.CloneSessionCount(),
+		"value set entry":      s.ValueSetEntryCount(),
+	} {
+		storeEntries(ch, store, kind, "", count)
+	}
+}
+
+func collectLogicalStore(ch chan<- prometheus.Metric, store string, s *translate.LogicalStore) {
+	for name, count := range map[string]int{
+		"IfTypes":                s.IfTypes.Len(),
+		"MyStations":             s.MyStations.Len(),
+		"Acl":                    s.Acl.Len(),
+		"CtrlPunted":             s.CtrlPunted.Len(),
+		"UpstreamAttachments":    s.UpstreamAttachments.Len(),
+		"DownstreamAttachments":  s.DownstreamAttachments.Len(),
+		"UpstreamRoutesV4":       s.UpstreamRoutesV4.Len(),
+		"UpstreamNextHopGroups":  s.UpstreamNextHopGroups.Len(),
+		"UpstreamNextHopEntries": s.UpstreamNextHopEntries.Len(),
+	} {
+		storeEntries(ch, store, "map", name, count)
+	}
+}
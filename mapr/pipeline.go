@@ -0,0 +1,104 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"github.com/golang/protobuf/proto"
+	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"mapr/translate"
+	"sync"
+)
+
+// Holds the logical forwarding pipeline config set by controllers, as opposed to the target one used by mapr. Safe for
+// concurrent use.
+type pipelineConfig struct {
+	mu sync.RWMutex
+	// The committed config.
+	config *p4v1.ForwardingPipelineConfig
+	// Translates entities of the committed config to the current logical P4Info, nil if it is the current one.
+	upgrader *translate.VersionUpgrader
+	// The config saved with VERIFY_AND_SAVE, waiting to be committed, and its upgrader.
+	saved         *p4v1.ForwardingPipelineConfig
+	savedUpgrader *translate.VersionUpgrader
+}
+
+// Stores a copy of the given logical config as the committed one, discarding the saved one, if any. The upgrader is
+// nil if the config uses the current logical P4Info.
+func (p *pipelineConfig) Set(config *p4v1.ForwardingPipelineConfig, upgrader *translate.VersionUpgrader) {
+	config = proto.Clone(config).(*p4v1.ForwardingPipelineConfig)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.config = config
+	p.upgrader = upgrader
+	p.saved = nil
+	p.savedUpgrader = nil
+}
+
+// Stores a copy of the given logical config as the saved one.
+func (p *pipelineConfig) Save(config *p4v1.ForwardingPipelineConfig, upgrader *translate.VersionUpgrader) {
+	config = proto.Clone(config).(*p4v1.ForwardingPipelineConfig)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.saved = config
+	p.savedUpgrader = upgrader
+}
+
+// Returns true if there is a saved config waiting to be committed.
+func (p *pipelineConfig) HasSaved() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.saved != nil
+}
+
+// Makes the saved config the committed one.
+func (p *pipelineConfig) Commit() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.saved != nil {
+		p.config = p.saved
+		p.upgrader = p.savedUpgrader
+		p.saved = nil
+		p.savedUpgrader = nil
+	}
+}
+
+// Returns the stored logical config, nil if not set. The returned config must not be modified.
+func (p *pipelineConfig) Get() *p4v1.ForwardingPipelineConfig {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.config
+}
+
+// Returns the upgrader of the committed config, nil if it uses the current logical P4Info or if no config is set.
+func (p *pipelineConfig) Upgrader() *translate.VersionUpgrader {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.upgrader
+}
+
+// Returns the parts of the stored logical config requested with the given response type.
+func (p *pipelineConfig) Response(responseType p4v1.GetForwardingPipelineConfigRequest_ResponseType) (
+	*p4v1.ForwardingPipelineConfig, error) {
+	config := p.Get()
+	if config == nil {
+		return nil, status.Error(codes.FailedPrecondition, "mapr: no forwarding pipeline config has been set")
+	}
+	switch responseType {
+	case p4v1.GetForwardingPipelineConfigRequest_ALL:
+		return config, nil
+	case p4v1.GetForwardingPipelineConfigRequest_COOKIE_ONLY:
+		return &p4v1.ForwardingPipelineConfig{Cookie: config.Cookie}, nil
+	case p4v1.GetForwardingPipelineConfigRequest_P4INFO_AND_COOKIE:
+		return &p4v1.ForwardingPipelineConfig{P4Info: config.P4Info, Cookie: config.Cookie}, nil
+	case p4v1.GetForwardingPipelineConfigRequest_DEVICE_CONFIG_AND_COOKIE:
+		return &p4v1.ForwardingPipelineConfig{P4DeviceConfig: config.P4DeviceConfig, Cookie: config.Cookie}, nil
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "mapr: invalid response type %v", responseType)
+	}
+}
@@ -0,0 +1,351 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package punt classifies the packet-ins punted by the logical pipeline by reason, rate-limits them per reason and per
+// ingress port, and determines which controller should receive them.
+//
+// The logical pipeline does not carry the punt reason in the packet-in metadata, hence packets are classified by
+// parsing their headers: PPPoE discovery and session control packets are the ones punted by the upstream pppoe_punts
+// table, all others are assumed to be punted by the ACL.
+package punt
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"time"
+)
+
+// The reason why a packet was punted.
+type Reason string
+
+const (
+	// PPPoE discovery packets, by code.
+	ReasonPppoePadi Reason = "pppoe_padi"
+	ReasonPppoePado Reason = "pppoe_pado"
+	ReasonPppoePadr Reason = "pppoe_padr"
+	ReasonPppoePads Reason = "pppoe_pads"
+	ReasonPppoePadt Reason = "pppoe_padt"
+	// PPPoE discovery packets with any other code.
+	ReasonPppoeDiscovery Reason = "pppoe_discovery"
+	// PPPoE session packets carrying a PPP control protocol (e.g., LCP, PAP, CHAP, IPCP).
+	ReasonPppoeControl Reason = "pppoe_control"
+	// All other packets.
+	ReasonAcl Reason = "acl"
+)
+
+// All reasons, used to validate configs.
+var reasons = map[Reason]bool{
+	ReasonPppoePadi:      true,
+	ReasonPppoePado:      true,
+	ReasonPppoePadr:      true,
+	ReasonPppoePads:      true,
+	ReasonPppoePadt:      true,
+	ReasonPppoeDiscovery: true,
+	ReasonPppoeControl:   true,
+	ReasonAcl:            true,
+}
+
+const (
+	ethertypeVlan   = 0x8100
+	ethertypeQinq   = 0x88a8
+	ethertypeQinq2  = 0x9100
+	ethertypePppoed = 0x8863
+	ethertypePppoes = 0x8864
+	ethHeaderLen    = 14
+	vlanHeaderLen   = 4
+	pppoeHeaderLen  = 6
+)
+
+var pppoeCodes = map[byte]Reason{
+	0x09: ReasonPppoePadi,
+	0x07: ReasonPppoePado,
+	0x19: ReasonPppoePadr,
+	0x65: ReasonPppoePads,
+	0xa7: ReasonPppoePadt,
+}
+
+// Returns the punt reason of the given Ethernet frame.
+func Classify(frame []byte) Reason {
+	if len(frame) < ethHeaderLen {
+		return ReasonAcl
+	}
+	offset := ethHeaderLen - 2
+	ethertype := binary.BigEndian.Uint16(frame[offset:])
+	for ethertype == ethertypeVlan || ethertype == ethertypeQinq || ethertype == ethertypeQinq2 {
+		offset += vlanHeaderLen
+		if len(frame) < offset+2 {
+			return ReasonAcl
+		}
+		ethertype = binary.BigEndian.Uint16(frame[offset:])
+	}
+	pppoe := frame[offset+2:]
+	switch ethertype {
+	case ethertypePppoed:
+		if len(pppoe) < pppoeHeaderLen {
+			return ReasonAcl
+		}
+		if r, ok := pppoeCodes[pppoe[1]]; ok {
+			return r
+		}
+		return ReasonPppoeDiscovery
+	case ethertypePppoes:
+		if len(pppoe) < pppoeHeaderLen+2 {
+			return ReasonAcl
+		}
+		// PPP protocols in the 0x8000-0xffff range are network/link control ones.
+		if binary.BigEndian.Uint16(pppoe[pppoeHeaderLen:]) >= 0x8000 {
+			return ReasonPppoeControl
+		}
+	}
+	return ReasonAcl
+}
+
+// A token bucket limit. A zero rate means unlimited.
+type Limit struct {
+	// Packets per second.
+	Rate float64 `json:"rate"`
+	// Max number of packets that can be sent back-to-back, defaults to the rate (i.e., one second worth of packets).
+	Burst float64 `json:"burst"`
+}
+
+// The config of a punt reason.
+type ReasonConfig struct {
+	Limit
+	// If set, packets are sent only to the controller of the given role, instead of the primary controllers of all
+	// roles accepting them as per their role config.
+	RoleId *uint64 `json:"role_id"`
+	// If set, packets are sent to the controller of RoleId with the given election ID (its lower 64 bits), even if
+	// not primary, instead of the primary one.
+	ElectionId *uint64 `json:"election_id"`
+}
+
+// The config of the packet-in Limiter, e.g.:
+//
+//	{
+//	  "reasons": {"pppoe_padi": {"rate": 100, "burst": 200}, "acl": {"rate": 1000, "role_id": 2}},
+//	  "per_port": {"rate": 50},
+//	  "ports": {"1": {"rate": 200}}
+//	}
+type Config struct {
+	// Limits and routing per reason. Reasons not in the map are not limited.
+	Reasons map[Reason]*ReasonConfig `json:"reasons"`
+	// Limit applied to each ingress port, across all reasons.
+	PerPort *Limit `json:"per_port"`
+	// Limit of specific ingress ports, overriding PerPort.
+	Ports map[uint32]*Limit `json:"ports"`
+}
+
+// Loads a Config from the given JSON file.
+func LoadConfigFile(path string) (*Config, error) {
+	bytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	config := &Config{}
+	if err := json.Unmarshal(bytes, config); err != nil {
+		return nil, fmt.Errorf("invalid punt config file %s: %v", path, err)
+	}
+	return config, config.Validate()
+}
+
+func (l *Limit) validate() error {
+	if l == nil {
+		return nil
+	}
+	if l.Rate < 0 || l.Burst < 0 {
+		return fmt.Errorf("rate and burst must be positive")
+	}
+	if l.Rate > 0 && l.Burst > 0 && l.Burst < 1 {
+		return fmt.Errorf("burst must be at least 1")
+	}
+	return nil
+}
+
+// Returns an error if the config is invalid.
+func (c *Config) Validate() error {
+	for r, rc := range c.Reasons {
+		if !reasons[r] {
+			return fmt.Errorf("unknown punt reason %q", r)
+		}
+		if rc == nil {
+			continue
+		}
+		if err := rc.validate(); err != nil {
+			return fmt.Errorf("reason %s: %v", r, err)
+		}
+		if rc.ElectionId != nil && rc.RoleId == nil {
+			return fmt.Errorf("reason %s: election_id requires role_id", r)
+		}
+	}
+	if err := c.PerPort.validate(); err != nil {
+		return fmt.Errorf("per_port: %v", err)
+	}
+	for p, l := range c.Ports {
+		if err := l.validate(); err != nil {
+			return fmt.Errorf("port %d: %v", p, err)
+		}
+	}
+	return nil
+}
+
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(l *Limit, now time.Time) *tokenBucket {
+	if l == nil || l.Rate == 0 {
+		return nil
+	}
+	burst := l.Burst
+	if burst == 0 {
+		burst = l.Rate
+	}
+	return &tokenBucket{rate: l.Rate, burst: burst, tokens: burst, last: now}
+}
+
+// Refills the bucket, and returns true if a token is available. A nil bucket is unlimited.
+func (b *tokenBucket) available(now time.Time) bool {
+	if b == nil {
+		return true
+	}
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens += elapsed * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+		b.last = now
+	}
+	return b.tokens >= 1
+}
+
+// Takes a token, which must be available.
+func (b *tokenBucket) take() {
+	if b != nil {
+		b.tokens--
+	}
+}
+
+// The limits of a Limiter.
+const (
+	LimitReason = "reason"
+	LimitPort   = "port"
+)
+
+// Packets dropped by a Limiter, with their reason and ingress port, and the limit they exceeded.
+type Drop struct {
+	Reason Reason
+	Port   uint32
+	// LimitReason or LimitPort.
+	Limit string
+}
+
+// Where to send the packets of a punt reason.
+type Route struct {
+	RoleId uint64
+	// Zero for the primary controller of the role.
+	ElectionId uint64
+}
+
+// Rate-limits and routes packet-ins. Safe for concurrent use.
+type Limiter struct {
+	mu      sync.Mutex
+	config  *Config
+	now     func() time.Time
+	reasons map[Reason]*tokenBucket
+	ports   map[uint32]*tokenBucket
+	dropped map[Drop]uint64
+}
+
+// Creates a new Limiter with the given config, nil for no limits.
+func NewLimiter(config *Config) *Limiter {
+	l := &Limiter{
+		now:     time.Now,
+		dropped: make(map[Drop]uint64),
+	}
+	l.SetConfig(config)
+	return l
+}
+
+// Replaces the config of the limiter, resetting all buckets but not the drop counters.
+func (l *Limiter) SetConfig(config *Config) {
+	if config == nil {
+		config = &Config{}
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.config = config
+	l.reasons = make(map[Reason]*tokenBucket)
+	now := l.now()
+	for r, rc := range config.Reasons {
+		if rc != nil {
+			l.reasons[r] = newTokenBucket(&rc.Limit, now)
+		}
+	}
+	// Per port buckets are created on demand.
+	l.ports = make(map[uint32]*tokenBucket)
+}
+
+// Returns true if a packet with the given reason, received on the given (logical) ingress port, should be sent to the
+// controller, false if it should be dropped. Tokens are taken only from the buckets of sent packets, e.g., a packet
+// dropped by the limit of its port does not count against the limit of its reason.
+func (l *Limiter) Allow(reason Reason, port uint32) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	r := l.reasons[reason]
+	p, ok := l.ports[port]
+	if !ok {
+		limit, ok := l.config.Ports[port]
+		if !ok {
+			limit = l.config.PerPort
+		}
+		p = newTokenBucket(limit, now)
+		l.ports[port] = p
+	}
+	if !r.available(now) {
+		l.dropped[Drop{Reason: reason, Port: port, Limit: LimitReason}]++
+		return false
+	}
+	if !p.available(now) {
+		l.dropped[Drop{Reason: reason, Port: port, Limit: LimitPort}]++
+		return false
+	}
+	r.take()
+	p.take()
+	return true
+}
+
+// Returns where to send packets with the given reason, nil for the primary controllers of all roles accepting them.
+func (l *Limiter) Route(reason Reason) *Route {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	rc := l.config.Reasons[reason]
+	if rc == nil || rc.RoleId == nil {
+		return nil
+	}
+	r := &Route{RoleId: *rc.RoleId}
+	if rc.ElectionId != nil {
+		r.ElectionId = *rc.ElectionId
+	}
+	return r
+}
+
+// Returns the number of packets dropped since the limiter was created, including before config changes.
+func (l *Limiter) Dropped() map[Drop]uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	dropped := make(map[Drop]uint64, len(l.dropped))
+	for d, n := range l.dropped {
+		dropped[d] = n
+	}
+	return dropped
+}
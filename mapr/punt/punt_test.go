@@ -0,0 +1,207 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package punt
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+var ethAddrs = []byte{
+	0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+	0x00, 0xaa, 0x00, 0x00, 0x00, 0x01,
+}
+
+// Returns an Ethernet frame with the given VLAN tags and ethertype, followed by the given payload.
+func frame(vlans int, ethertype []byte, payload ...byte) []byte {
+	f := append([]byte{}, ethAddrs...)
+	for i := 0; i < vlans; i++ {
+		f = append(f, 0x81, 0x00, 0x00, byte(10+i))
+	}
+	f = append(f, ethertype...)
+	return append(f, payload...)
+}
+
+var (
+	pppoed = []byte{0x88, 0x63}
+	pppoes = []byte{0x88, 0x64}
+	ipv4   = []byte{0x08, 0x00}
+)
+
+func Test_Classify(t *testing.T) {
+	tests := []struct {
+		name  string
+		frame []byte
+		want  Reason
+	}{
+		{"padi", frame(2, pppoed, 0x11, 0x09, 0x00, 0x00, 0x00, 0x00), ReasonPppoePadi},
+		{"padr untagged", frame(0, pppoed, 0x11, 0x19, 0x00, 0x00, 0x00, 0x00), ReasonPppoePadr},
+		{"padt", frame(1, pppoed, 0x11, 0xa7, 0x00, 0x01, 0x00, 0x00), ReasonPppoePadt},
+		{"other discovery", frame(2, pppoed, 0x11, 0x42, 0x00, 0x00, 0x00, 0x00), ReasonPppoeDiscovery},
+		{"lcp", frame(2, pppoes, 0x11, 0x00, 0x00, 0x01, 0x00, 0x02, 0xc0, 0x21), ReasonPppoeControl},
+		{"ipcp", frame(2, pppoes, 0x11, 0x00, 0x00, 0x01, 0x00, 0x02, 0x80, 0x21), ReasonPppoeControl},
+		{"pppoe ipv4", frame(2, pppoes, 0x11, 0x00, 0x00, 0x01, 0x00, 0x02, 0x00, 0x21), ReasonAcl},
+		{"ipv4", frame(0, ipv4, 0x45), ReasonAcl},
+		{"truncated pppoe", frame(2, pppoed, 0x11), ReasonAcl},
+		{"truncated vlan", frame(0, []byte{0x81, 0x00}), ReasonAcl},
+		{"runt", []byte{0x00}, ReasonAcl},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Classify(tt.frame))
+		})
+	}
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func newTestLimiter(config *Config) (*Limiter, *fakeClock) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	l := NewLimiter(nil)
+	l.now = clock.Now
+	l.SetConfig(config)
+	return l, clock
+}
+
+func uint64Ptr(v uint64) *uint64 {
+	return &v
+}
+
+func Test_Limiter_Reason(t *testing.T) {
+	l, clock := newTestLimiter(&Config{
+		Reasons: map[Reason]*ReasonConfig{
+			ReasonPppoePadi: {Limit: Limit{Rate: 10, Burst: 2}},
+		},
+	})
+	assert.True(t, l.Allow(ReasonPppoePadi, 1))
+	assert.True(t, l.Allow(ReasonPppoePadi, 2))
+	assert.False(t, l.Allow(ReasonPppoePadi, 3), "burst exhausted")
+	for i := 0; i < 100; i++ {
+		assert.True(t, l.Allow(ReasonAcl, 1), "other reasons are not limited")
+	}
+	clock.now = clock.now.Add(100 * time.Millisecond)
+	assert.True(t, l.Allow(ReasonPppoePadi, 1), "one token refilled")
+	assert.False(t, l.Allow(ReasonPppoePadi, 1))
+	clock.now = clock.now.Add(time.Hour)
+	assert.True(t, l.Allow(ReasonPppoePadi, 1))
+	assert.True(t, l.Allow(ReasonPppoePadi, 1))
+	assert.False(t, l.Allow(ReasonPppoePadi, 1), "refill is capped to burst")
+
+	assert.Equal(t, map[Drop]uint64{
+		{Reason: ReasonPppoePadi, Port: 1, Limit: LimitReason}: 2,
+		{Reason: ReasonPppoePadi, Port: 3, Limit: LimitReason}: 1,
+	}, l.Dropped())
+}
+
+func Test_Limiter_Port(t *testing.T) {
+	l, clock := newTestLimiter(&Config{
+		PerPort: &Limit{Rate: 1},
+		Ports:   map[uint32]*Limit{2: {Rate: 2}},
+	})
+	assert.True(t, l.Allow(ReasonAcl, 1))
+	assert.False(t, l.Allow(ReasonPppoePadi, 1), "limit is across reasons")
+	assert.True(t, l.Allow(ReasonAcl, 2))
+	assert.True(t, l.Allow(ReasonAcl, 2))
+	assert.False(t, l.Allow(ReasonAcl, 2))
+	assert.True(t, l.Allow(ReasonAcl, 3), "each port has its own bucket")
+	clock.now = clock.now.Add(time.Second)
+	assert.True(t, l.Allow(ReasonAcl, 1))
+
+	assert.Equal(t, map[Drop]uint64{
+		{Reason: ReasonPppoePadi, Port: 1, Limit: LimitPort}: 1,
+		{Reason: ReasonAcl, Port: 2, Limit: LimitPort}:       1,
+	}, l.Dropped())
+}
+
+// Packets dropped by one limit do not take tokens from the other.
+func Test_Limiter_ReasonAndPort(t *testing.T) {
+	l, _ := newTestLimiter(&Config{
+		Reasons: map[Reason]*ReasonConfig{ReasonPppoePadi: {Limit: Limit{Rate: 2}}},
+		Ports:   map[uint32]*Limit{1: {Rate: 1}, 2: {Rate: 2}},
+	})
+	assert.True(t, l.Allow(ReasonPppoePadi, 1))
+	assert.False(t, l.Allow(ReasonPppoePadi, 1), "port limit exceeded")
+	assert.True(t, l.Allow(ReasonPppoePadi, 2), "reason token not taken by the dropped packet")
+	assert.False(t, l.Allow(ReasonPppoePadi, 2), "reason limit exceeded")
+	assert.True(t, l.Allow(ReasonAcl, 2), "port token not taken by the dropped packet")
+
+	assert.Equal(t, map[Drop]uint64{
+		{Reason: ReasonPppoePadi, Port: 1, Limit: LimitPort}:   1,
+		{Reason: ReasonPppoePadi, Port: 2, Limit: LimitReason}: 1,
+	}, l.Dropped())
+}
+
+// A flood over the limit of a single port leaves the reason limit to the other ports.
+func Test_Limiter_PortFlood(t *testing.T) {
+	l, _ := newTestLimiter(&Config{
+		Reasons: map[Reason]*ReasonConfig{ReasonPppoePadi: {Limit: Limit{Rate: 3}}},
+		Ports:   map[uint32]*Limit{1: {Rate: 1}},
+	})
+	assert.True(t, l.Allow(ReasonPppoePadi, 1))
+	for i := 0; i < 10; i++ {
+		assert.False(t, l.Allow(ReasonPppoePadi, 1), "port limit exceeded")
+	}
+	assert.True(t, l.Allow(ReasonPppoePadi, 2))
+	assert.True(t, l.Allow(ReasonPppoePadi, 2))
+	assert.False(t, l.Allow(ReasonPppoePadi, 2), "reason limit exceeded")
+
+	assert.Equal(t, map[Drop]uint64{
+		{Reason: ReasonPppoePadi, Port: 1, Limit: LimitPort}:   10,
+		{Reason: ReasonPppoePadi, Port: 2, Limit: LimitReason}: 1,
+	}, l.Dropped())
+}
+
+func Test_Limiter_Route(t *testing.T) {
+	l := NewLimiter(&Config{
+		Reasons: map[Reason]*ReasonConfig{
+			ReasonPppoePadi: {RoleId: uint64Ptr(1)},
+			ReasonAcl:       {RoleId: uint64Ptr(2), ElectionId: uint64Ptr(10)},
+		},
+	})
+	assert.Equal(t, &Route{RoleId: 1}, l.Route(ReasonPppoePadi))
+	assert.Equal(t, &Route{RoleId: 2, ElectionId: 10}, l.Route(ReasonAcl))
+	assert.Nil(t, l.Route(ReasonPppoeControl))
+	assert.True(t, l.Allow(ReasonPppoePadi, 1), "no limit")
+}
+
+func Test_Config_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *Config
+		wantErr bool
+	}{
+		{"empty", &Config{}, false},
+		{"valid", &Config{
+			Reasons: map[Reason]*ReasonConfig{ReasonPppoePadi: {Limit: Limit{Rate: 100, Burst: 200}}},
+			PerPort: &Limit{Rate: 10},
+		}, false},
+		{"unknown reason", &Config{Reasons: map[Reason]*ReasonConfig{"foo": {}}}, true},
+		{"negative rate", &Config{PerPort: &Limit{Rate: -1}}, true},
+		{"small burst", &Config{Ports: map[uint32]*Limit{1: {Rate: 1, Burst: 0.5}}}, true},
+		{"election without role", &Config{
+			Reasons: map[Reason]*ReasonConfig{ReasonAcl: {ElectionId: uint64Ptr(1)}},
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
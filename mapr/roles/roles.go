@@ -0,0 +1,405 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package roles implements P4Runtime role configs, restricting the logical P4 entities each role can read and write,
+// and the packet-ins it receives.
+//
+// Controllers send the config of their role in the MasterArbitrationUpdate, as a google.protobuf.Struct packed in the
+// Role.Config Any, with the fields of Config, e.g., for a PPPoE session manager:
+//
+//	{
+//	  "entities": ["upstream.lines", "upstream.attachments_v4", "downstream.*"],
+//	  "read_only_entities": ["if_types", "my_stations"],
+//	  "receives_packet_ins": true,
+//	  "packet_in_filter": {"metadata": "ingress_port", "value": 1}
+//	}
+//
+// As per the P4Runtime spec, the default role (ID 0) and roles without a config have full pipeline access.
+package roles
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/ptypes"
+	structpb "github.com/golang/protobuf/ptypes/struct"
+	p4confv1 "github.com/p4lang/p4runtime/go/p4/config/v1"
+	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"mapr/translate"
+	"path"
+	"strings"
+	"sync"
+)
+
+// The config of a role, as sent by controllers.
+type Config struct {
+	// Names of the logical P4 entities (tables, action profiles, counters, meters, registers, value sets and digests)
+	// the role can read and write. A name matches any entity whose fully qualified name ends with it, on a dot
+	// boundary (e.g., "upstream.lines" matches "IngressPipe.upstream.lines"), and can contain shell patterns (e.g.,
+	// "downstream.*" matches all entities under IngressPipe.downstream).
+	Entities []string `json:"entities"`
+	// Names of the logical P4 entities the role can read but not write, with the same syntax as Entities.
+	ReadOnlyEntities []string `json:"read_only_entities"`
+	// Whether the role can read and write multicast groups and clone sessions.
+	PacketReplication bool `json:"packet_replication"`
+	// Whether the role can set the forwarding pipeline config.
+	CanPushPipeline bool `json:"can_push_pipeline"`
+	// Whether the primary controller of the role receives packet-ins.
+	ReceivesPacketIns bool `json:"receives_packet_ins"`
+	// If set, only packet-ins matching the filter are sent to the role.
+	PacketInFilter *PacketInFilter `json:"packet_in_filter"`
+}
+
+// Matches packet-ins with the given value for the packet_in metadata with the given name.
+type PacketInFilter struct {
+	Metadata string `json:"metadata"`
+	Value    uint64 `json:"value"`
+}
+
+// The compiled config of a role. A nil *Policy grants full pipeline access.
+type Policy struct {
+	roleId uint64
+	// P4 entity IDs the role can read, a superset of the writable ones.
+	readable map[uint32]bool
+	writable map[uint32]bool
+	// Same as the corresponding Config fields.
+	packetReplication bool
+	canPushPipeline   bool
+	receivesPacketIns bool
+	// Zero if packet-ins are not filtered.
+	filterMetaId uint32
+	filterValue  []byte
+}
+
+// Returns the Config packed in the given Role, or nil if the role has no config.
+func UnpackConfig(role *p4v1.Role) (*Config, error) {
+	if role.GetConfig() == nil {
+		return nil, nil
+	}
+	s := &structpb.Struct{}
+	if err := ptypes.UnmarshalAny(role.Config, s); err != nil {
+		return nil, fmt.Errorf("role config must be a google.protobuf.Struct: %v", err)
+	}
+	js, err := (&jsonpb.Marshaler{}).MarshalToString(s)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(strings.NewReader(js))
+	decoder.DisallowUnknownFields()
+	config := &Config{}
+	if err := decoder.Decode(config); err != nil {
+		return nil, fmt.Errorf("invalid role config: %v", err)
+	}
+	return config, nil
+}
+
+// Returns the given Config packed in an Any, to be used as a Role.Config.
+func PackConfig(config *Config) (*p4v1.Role, error) {
+	js, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	s := &structpb.Struct{}
+	if err := jsonpb.UnmarshalString(string(js), s); err != nil {
+		return nil, err
+	}
+	a, err := ptypes.MarshalAny(s)
+	if err != nil {
+		return nil, err
+	}
+	return &p4v1.Role{Config: a}, nil
+}
+
+// Returns true if the given pattern matches the given fully qualified name, or any of its dot-separated suffixes.
+func matchName(pattern string, name string) bool {
+	for {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+		i := strings.Index(name, ".")
+		if i < 0 {
+			return false
+		}
+		name = name[i+1:]
+	}
+}
+
+// Returns the preambles of all the P4 entities that can be the subject of a role config.
+func entityPreambles(p4info *p4confv1.P4Info) []*p4confv1.Preamble {
+	var preambles []*p4confv1.Preamble
+	for _, x := range p4info.GetTables() {
+		preambles = append(preambles, x.Preamble)
+	}
+	for _, x := range p4info.GetActionProfiles() {
+		preambles = append(preambles, x.Preamble)
+	}
+	for _, x := range p4info.GetCounters() {
+		preambles = append(preambles, x.Preamble)
+	}
+	for _, x := range p4info.GetMeters() {
+		preambles = append(preambles, x.Preamble)
+	}
+	for _, x := range p4info.GetRegisters() {
+		preambles = append(preambles, x.Preamble)
+	}
+	for _, x := range p4info.GetValueSets() {
+		preambles = append(preambles, x.Preamble)
+	}
+	for _, x := range p4info.GetDigests() {
+		preambles = append(preambles, x.Preamble)
+	}
+	return preambles
+}
+
+// Adds to ids the IDs of the entities matching the given patterns. Returns an error if a pattern matches no entity,
+// likely a typo.
+func resolveNames(patterns []string, preambles []*p4confv1.Preamble, ids map[uint32]bool) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid entity name %q: %v", pattern, err)
+		}
+		found := false
+		for _, p := range preambles {
+			if matchName(pattern, p.Name) {
+				ids[p.Id] = true
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("entity %q not found in P4Info", pattern)
+		}
+	}
+	return nil
+}
+
+// Compiles the given Config for the given role ID, resolving entity names against the given logical P4Info.
+func NewPolicy(roleId uint64, config *Config, p4info *p4confv1.P4Info) (*Policy, error) {
+	if p4info == nil {
+		return nil, fmt.Errorf("role configs require the logical P4Info")
+	}
+	p := &Policy{
+		roleId:            roleId,
+		readable:          make(map[uint32]bool),
+		writable:          make(map[uint32]bool),
+		packetReplication: config.PacketReplication,
+		canPushPipeline:   config.CanPushPipeline,
+		receivesPacketIns: config.ReceivesPacketIns,
+	}
+	preambles := entityPreambles(p4info)
+	if err := resolveNames(config.Entities, preambles, p.writable); err != nil {
+		return nil, err
+	}
+	if err := resolveNames(config.ReadOnlyEntities, preambles, p.readable); err != nil {
+		return nil, err
+	}
+	for id := range p.writable {
+		p.readable[id] = true
+	}
+	if f := config.PacketInFilter; f != nil {
+		if !config.ReceivesPacketIns {
+			return nil, fmt.Errorf("packet_in_filter requires receives_packet_ins")
+		}
+		for _, h := range p4info.GetControllerPacketMetadata() {
+			if h.GetPreamble().GetName() != translate.ControllerHeaderPacketIn {
+				continue
+			}
+			for _, m := range h.Metadata {
+				if m.Name == f.Metadata {
+					p.filterMetaId = m.Id
+				}
+			}
+		}
+		if p.filterMetaId == 0 {
+			return nil, fmt.Errorf("%s metadata %q not found in P4Info", translate.ControllerHeaderPacketIn, f.Metadata)
+		}
+		p.filterValue = canonicalUint64(f.Value)
+	}
+	return p, nil
+}
+
+// Returns the given value as big-endian bytes without leading zeros, as mandated by the P4Runtime spec.
+func canonicalUint64(v uint64) []byte {
+	b := []byte{byte(v)}
+	for v >>= 8; v != 0; v >>= 8 {
+		b = append([]byte{byte(v)}, b...)
+	}
+	return b
+}
+
+func canonicalBytes(b []byte) []byte {
+	for len(b) > 1 && b[0] == 0 {
+		b = b[1:]
+	}
+	return b
+}
+
+// Returns the ID of the P4 entity targeted by the given P4Runtime entity, 0 for a wildcard. The second return value
+// is true for packet replication engine entries, which have no P4 entity ID.
+func entityId(e *p4v1.Entity) (uint32, bool) {
+	switch x := e.Entity.(type) {
+	case *p4v1.Entity_TableEntry:
+		return x.TableEntry.TableId, false
+	case *p4v1.Entity_ActionProfileMember:
+		return x.ActionProfileMember.ActionProfileId, false
+	case *p4v1.Entity_ActionProfileGroup:
+		return x.ActionProfileGroup.ActionProfileId, false
+	case *p4v1.Entity_CounterEntry:
+		return x.CounterEntry.CounterId, false
+	case *p4v1.Entity_DirectCounterEntry:
+		return x.DirectCounterEntry.GetTableEntry().GetTableId(), false
+	case *p4v1.Entity_MeterEntry:
+		return x.MeterEntry.MeterId, false
+	case *p4v1.Entity_DirectMeterEntry:
+		return x.DirectMeterEntry.GetTableEntry().GetTableId(), false
+	case *p4v1.Entity_RegisterEntry:
+		return x.RegisterEntry.RegisterId, false
+	case *p4v1.Entity_ValueSetEntry:
+		return x.ValueSetEntry.ValueSetId, false
+	case *p4v1.Entity_DigestEntry:
+		return x.DigestEntry.DigestId, false
+	case *p4v1.Entity_PacketReplicationEngineEntry:
+		return 0, true
+	default:
+		// E.g., extern entries, never in the scope of a restricted role.
+		return 0, false
+	}
+}
+
+// Returns the ID of the role.
+func (p *Policy) RoleId() uint64 {
+	if p == nil {
+		return 0
+	}
+	return p.roleId
+}
+
+// Returns true if the role can read the given entity, which must not be a wildcard.
+func (p *Policy) CanRead(e *p4v1.Entity) bool {
+	if p == nil {
+		return true
+	}
+	id, pre := entityId(e)
+	if pre {
+		return p.packetReplication
+	}
+	return p.readable[id]
+}
+
+// Returns nil if the role can issue a read for the given entity, otherwise a PERMISSION_DENIED gRPC status error.
+// Wildcard reads (i.e., without an entity ID) are always allowed, and their results should be filtered with CanRead.
+func (p *Policy) CheckRead(e *p4v1.Entity) error {
+	if p == nil {
+		return nil
+	}
+	if _, extern := e.Entity.(*p4v1.Entity_ExternEntry); !extern {
+		if id, pre := entityId(e); id == 0 && !pre {
+			return nil
+		}
+	}
+	if !p.CanRead(e) {
+		return status.Errorf(codes.PermissionDenied, "role %d cannot read %s", p.roleId, entityString(e))
+	}
+	return nil
+}
+
+// Returns nil if the role can write the given entity, otherwise a PERMISSION_DENIED gRPC status error.
+func (p *Policy) CheckWrite(e *p4v1.Entity) error {
+	if p == nil {
+		return nil
+	}
+	id, pre := entityId(e)
+	if (pre && !p.packetReplication) || (!pre && !p.writable[id]) {
+		return status.Errorf(codes.PermissionDenied, "role %d cannot write %s", p.roleId, entityString(e))
+	}
+	return nil
+}
+
+// Returns true if the role can set the forwarding pipeline config.
+func (p *Policy) CanPushPipeline() bool {
+	return p == nil || p.canPushPipeline
+}
+
+// Returns true if the given (logical) packet-in should be sent to the role.
+func (p *Policy) AcceptsPacketIn(packet *p4v1.PacketIn) bool {
+	if p == nil {
+		return true
+	}
+	if !p.receivesPacketIns {
+		return false
+	}
+	if p.filterMetaId == 0 {
+		return true
+	}
+	for _, m := range packet.Metadata {
+		if m.MetadataId == p.filterMetaId {
+			return string(canonicalBytes(m.Value)) == string(p.filterValue)
+		}
+	}
+	return false
+}
+
+func entityString(e *p4v1.Entity) string {
+	id, pre := entityId(e)
+	if pre {
+		return "packet replication engine entries"
+	}
+	return fmt.Sprintf("%s with ID %d", strings.TrimPrefix(fmt.Sprintf("%T", e.Entity), "*v1.Entity_"), id)
+}
+
+// Holds the policies of all roles. Safe for concurrent use.
+type Registry struct {
+	p4info   *p4confv1.P4Info
+	mu       sync.RWMutex
+	policies map[uint64]*Policy
+}
+
+// Creates a new Registry resolving entity names against the given logical P4Info, nil if not available, in which case
+// role configs are rejected.
+func NewRegistry(p4info *p4confv1.P4Info) *Registry {
+	return &Registry{
+		p4info:   p4info,
+		policies: make(map[uint64]*Policy),
+	}
+}
+
+// Returns the policy of the given role, compiled from its config. Returns nil without error if the role has no config.
+func (r *Registry) Compile(role *p4v1.Role) (*Policy, error) {
+	config, err := UnpackConfig(role)
+	if err != nil || config == nil {
+		return nil, err
+	}
+	if role.Id == 0 {
+		return nil, fmt.Errorf("the default role cannot be restricted")
+	}
+	return NewPolicy(role.Id, config, r.p4info)
+}
+
+// Sets the policy of the given role.
+func (r *Registry) Set(roleId uint64, p *Policy) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if p == nil {
+		delete(r.policies, roleId)
+	} else {
+		r.policies[roleId] = p
+	}
+}
+
+// Returns the policy of the given role, nil if the role has full pipeline access.
+func (r *Registry) Get(roleId uint64) *Policy {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.policies[roleId]
+}
+
+// Returns true if some role has a policy, i.e., does not have full pipeline access.
+func (r *Registry) Restricted() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.policies) > 0
+}
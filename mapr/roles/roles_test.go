@@ -0,0 +1,164 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package roles
+
+import (
+	"github.com/golang/protobuf/proto"
+	p4confv1 "github.com/p4lang/p4runtime/go/p4/config/v1"
+	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io/ioutil"
+	"mapr/translate"
+	"testing"
+)
+
+func logicalP4Info(t *testing.T) *p4confv1.P4Info {
+	bytes, err := ioutil.ReadFile("../../p4src/build/p4info.txt")
+	require.NoError(t, err)
+	p4info := &p4confv1.P4Info{}
+	require.NoError(t, proto.UnmarshalText(string(bytes), p4info))
+	return p4info
+}
+
+func tableEntity(tableId uint32) *p4v1.Entity {
+	return &p4v1.Entity{Entity: &p4v1.Entity_TableEntry{TableEntry: &p4v1.TableEntry{TableId: tableId}}}
+}
+
+func memberEntity(actProfId uint32) *p4v1.Entity {
+	return &p4v1.Entity{Entity: &p4v1.Entity_ActionProfileMember{
+		ActionProfileMember: &p4v1.ActionProfileMember{ActionProfileId: actProfId}}}
+}
+
+func preEntity() *p4v1.Entity {
+	return &p4v1.Entity{Entity: &p4v1.Entity_PacketReplicationEngineEntry{
+		PacketReplicationEngineEntry: &p4v1.PacketReplicationEngineEntry{}}}
+}
+
+func packetIn(port byte) *p4v1.PacketIn {
+	return &p4v1.PacketIn{Metadata: []*p4v1.PacketMetadata{{MetadataId: 1, Value: []byte{0x00, port}}}}
+}
+
+var sessionManagerConfig = &Config{
+	Entities:          []string{"upstream.lines", "upstream.attachments_v4", "downstream.*"},
+	ReadOnlyEntities:  []string{"if_types"},
+	ReceivesPacketIns: true,
+	PacketInFilter:    &PacketInFilter{Metadata: "ingress_port", Value: 1},
+}
+
+var routingConfig = &Config{
+	Entities: []string{"routes_v4", "IngressPipe.upstream.ecmp"},
+}
+
+func Test_Policy(t *testing.T) {
+	p4info := logicalP4Info(t)
+	sessionManager, err := NewPolicy(1, sessionManagerConfig, p4info)
+	require.NoError(t, err)
+	routing, err := NewPolicy(2, routingConfig, p4info)
+	require.NoError(t, err)
+	var full *Policy
+
+	tests := []struct {
+		name     string
+		policy   *Policy
+		entity   *p4v1.Entity
+		canRead  bool
+		canWrite bool
+	}{
+		{"lines", sessionManager, tableEntity(translate.Table_IngressPipeUpstreamLines), true, true},
+		{"attachments", sessionManager, tableEntity(translate.Table_IngressPipeUpstreamAttachmentsV4), true, true},
+		{"downstream", sessionManager, tableEntity(translate.Table_IngressPipeDownstreamLinesV4), true, true},
+		{"downstream cos", sessionManager, tableEntity(translate.Table_IngressPipeDownstreamCosServicesV4), true, true},
+		{"if types", sessionManager, tableEntity(translate.Table_IngressPipeIfTypes), true, false},
+		{"routes", sessionManager, tableEntity(translate.Table_IngressPipeUpstreamRoutesV4), false, false},
+		{"pre", sessionManager, preEntity(), false, false},
+		{"routing routes", routing, tableEntity(translate.Table_IngressPipeUpstreamRoutesV4), true, true},
+		{"routing ecmp", routing, memberEntity(translate.ActionProfile_IngressPipeUpstreamEcmp), true, true},
+		{"routing lines", routing, tableEntity(translate.Table_IngressPipeUpstreamLines), false, false},
+		{"full", full, tableEntity(translate.Table_IngressPipeUpstreamLines), true, true},
+		{"full pre", full, preEntity(), true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.canRead, tt.policy.CanRead(tt.entity))
+			if tt.canRead {
+				assert.NoError(t, tt.policy.CheckRead(tt.entity))
+			} else {
+				assert.Equal(t, codes.PermissionDenied, status.Code(tt.policy.CheckRead(tt.entity)))
+			}
+			if tt.canWrite {
+				assert.NoError(t, tt.policy.CheckWrite(tt.entity))
+			} else {
+				assert.Equal(t, codes.PermissionDenied, status.Code(tt.policy.CheckWrite(tt.entity)))
+			}
+		})
+	}
+
+	// Wildcard reads are allowed, results are filtered.
+	assert.NoError(t, routing.CheckRead(tableEntity(0)))
+
+	assert.True(t, sessionManager.AcceptsPacketIn(packetIn(1)))
+	assert.False(t, sessionManager.AcceptsPacketIn(packetIn(2)))
+	assert.False(t, routing.AcceptsPacketIn(packetIn(1)))
+	assert.True(t, full.AcceptsPacketIn(packetIn(2)))
+
+	assert.False(t, routing.CanPushPipeline())
+	assert.True(t, full.CanPushPipeline())
+}
+
+func Test_NewPolicy_Invalid(t *testing.T) {
+	p4info := logicalP4Info(t)
+	tests := []struct {
+		name   string
+		config *Config
+	}{
+		{"unknown entity", &Config{Entities: []string{"upstream.foo"}}},
+		{"bad pattern", &Config{Entities: []string{"upstream.[lines"}}},
+		{"unknown metadata", &Config{ReceivesPacketIns: true, PacketInFilter: &PacketInFilter{Metadata: "foo"}}},
+		{"filter without packet-ins", &Config{PacketInFilter: &PacketInFilter{Metadata: "ingress_port"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewPolicy(1, tt.config, p4info)
+			assert.Error(t, err)
+		})
+	}
+	_, err := NewPolicy(1, routingConfig, nil)
+	assert.Error(t, err, "P4Info is required")
+}
+
+func Test_Registry(t *testing.T) {
+	r := NewRegistry(logicalP4Info(t))
+	role, err := PackConfig(routingConfig)
+	require.NoError(t, err)
+	role.Id = 2
+
+	p, err := r.Compile(role)
+	require.NoError(t, err)
+	require.NotNil(t, p)
+	assert.Equal(t, uint64(2), p.RoleId())
+	assert.False(t, r.Restricted())
+	r.Set(2, p)
+	assert.Equal(t, p, r.Get(2))
+	assert.Nil(t, r.Get(1), "roles without config have full access")
+	assert.True(t, r.Restricted())
+
+	p, err = r.Compile(&p4v1.Role{Id: 3})
+	assert.NoError(t, err)
+	assert.Nil(t, p)
+
+	role.Id = 0
+	_, err = r.Compile(role)
+	assert.Error(t, err, "default role cannot be restricted")
+
+	role.Id = 2
+	role.Config.Value = []byte{0xFF}
+	_, err = r.Compile(role)
+	assert.Error(t, err)
+}
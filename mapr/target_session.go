@@ -0,0 +1,135 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
+	"google.golang.org/grpc/codes"
+	"mapr/logging"
+	"time"
+)
+
+var targetLog = logging.Subsystem("target")
+
+// Max number of packet-outs queued while waiting to be sent to the target.
+const targetQueueSize = 1024
+
+// Delay before re-opening the target session after a failure.
+const targetReconnectDelay = time.Second
+
+// The single StreamChannel between mapr and the target. mapr arbitrates controllers on its own, and uses this session
+// to be primary on the target, and to relay packet I/O on behalf of all controllers.
+type targetSession struct {
+	deviceId   uint64
+	electionId *p4v1.Uint128
+	// Invoked for each packet-in received from the target.
+	onPacketIn func(*p4v1.PacketIn)
+	// Invoked with true when mapr becomes primary on the target, and with false when it is not anymore, e.g., because
+	// the session failed.
+	onPrimary func(bool)
+	out       chan *p4v1.StreamMessageRequest
+}
+
+func newTargetSession(deviceId uint64, electionId uint64, onPacketIn func(*p4v1.PacketIn),
+	onPrimary func(bool)) *targetSession {
+	return &targetSession{
+		deviceId:   deviceId,
+		electionId: &p4v1.Uint128{High: 0, Low: electionId},
+		onPacketIn: onPacketIn,
+		onPrimary:  onPrimary,
+		out:        make(chan *p4v1.StreamMessageRequest, targetQueueSize),
+	}
+}
+
+// Keeps the session open until the given context is done, re-opening it on failures.
+func (t *targetSession) Run(ctx context.Context) {
+	for {
+		err := t.runOnce(ctx)
+		t.onPrimary(false)
+		if ctx.Err() != nil {
+			return
+		}
+		targetLog.Errorf("Target session failed, re-opening in %s: %v", targetReconnectDelay, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(targetReconnectDelay):
+		}
+	}
+}
+
+func (t *targetSession) runOnce(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := target.StreamChannel(ctx)
+	if err != nil {
+		return err
+	}
+	arbitration := &p4v1.StreamMessageRequest{
+		Update: &p4v1.StreamMessageRequest_Arbitration{Arbitration: &p4v1.MasterArbitrationUpdate{
+			DeviceId:   t.deviceId,
+			ElectionId: t.electionId,
+		}},
+	}
+	logMsgWith(targetLog, ToTarget, arbitration)
+	if err := stream.Send(arbitration); err != nil {
+		return err
+	}
+
+	waiterr := make(chan error, 1)
+	go func() {
+		for {
+			response, err := stream.Recv()
+			if err != nil {
+				waiterr <- err
+				return
+			}
+			logMsgWith(targetLog, FromTarget, response)
+			switch x := response.Update.(type) {
+			case *p4v1.StreamMessageResponse_Arbitration:
+				if code := codes.Code(x.Arbitration.GetStatus().GetCode()); code != codes.OK {
+					targetLog.Errorf("mapr is not primary on the target: %s %s", code, x.Arbitration.GetStatus().GetMessage())
+					t.onPrimary(false)
+				} else {
+					targetLog.Infof("mapr is primary on the target")
+					t.onPrimary(true)
+				}
+			case *p4v1.StreamMessageResponse_Packet:
+				t.onPacketIn(x.Packet)
+			default:
+				targetLog.Warnf("Ignoring %T from target", x)
+			}
+		}
+	}()
+
+	for {
+		select {
+		case err := <-waiterr:
+			return err
+		case request := <-t.out:
+			logMsgWith(targetLog, ToTarget, request)
+			if err := stream.Send(request); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Queues the given packet-out to be sent to the target. Returns an error if the queue is full.
+func (t *targetSession) SendPacketOut(p *p4v1.PacketOut) error {
+	request := &p4v1.StreamMessageRequest{
+		Update: &p4v1.StreamMessageRequest_Packet{Packet: p},
+	}
+	select {
+	case t.out <- request:
+		return nil
+	default:
+		return fmt.Errorf("target queue is full")
+	}
+}
@@ -0,0 +1,252 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package tlsutil builds the TLS configs used by mapr towards controllers (server side) and the target (client side).
+//
+// Certificates, keys and CAs are read from PEM files, and reloaded when the files are rotated: the modification time
+// of the files is checked at every handshake, and the files are read again if changed. If the new files cannot be
+// loaded, e.g., because the certificate has been replaced but the key not yet, the previous ones are used until the
+// next handshake.
+package tlsutil
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"mapr/logging"
+	"os"
+	"sync"
+	"time"
+)
+
+var log = logging.Subsystem("tlsutil")
+
+// Returns the latest modification time of the given files.
+func modTime(paths ...string) (time.Time, error) {
+	var latest time.Time
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
+
+// A certificate and private key reloaded when their files change.
+type keyPair struct {
+	certFile string
+	keyFile  string
+	mu       sync.Mutex
+	cert     *tls.Certificate
+	modTime  time.Time
+}
+
+func newKeyPair(certFile, keyFile string) (*keyPair, error) {
+	k := &keyPair{certFile: certFile, keyFile: keyFile}
+	if err := k.reload(); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// Must be called with mu held, or before the key pair is shared.
+func (k *keyPair) reload() error {
+	t, err := modTime(k.certFile, k.keyFile)
+	if err != nil {
+		return err
+	}
+	cert, err := tls.LoadX509KeyPair(k.certFile, k.keyFile)
+	if err != nil {
+		return err
+	}
+	k.cert = &cert
+	k.modTime = t
+	return nil
+}
+
+// Returns the current certificate, reloading it if its files changed.
+func (k *keyPair) get() *tls.Certificate {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if t, err := modTime(k.certFile, k.keyFile); err == nil && !t.Equal(k.modTime) {
+		if err := k.reload(); err != nil {
+			log.Warnf("Cannot reload certificate %s, using the previous one: %v", k.certFile, err)
+		} else {
+			log.Infof("Reloaded certificate %s", k.certFile)
+		}
+	}
+	return k.cert
+}
+
+// A pool of CA certificates reloaded when its file changes.
+type certPool struct {
+	file    string
+	mu      sync.Mutex
+	pool    *x509.CertPool
+	modTime time.Time
+}
+
+func newCertPool(file string) (*certPool, error) {
+	p := &certPool{file: file}
+	if err := p.reload(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Must be called with mu held, or before the pool is shared.
+func (p *certPool) reload() error {
+	t, err := modTime(p.file)
+	if err != nil {
+		return err
+	}
+	pem, err := ioutil.ReadFile(p.file)
+	if err != nil {
+		return err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return fmt.Errorf("no certificates found in %s", p.file)
+	}
+	p.pool = pool
+	p.modTime = t
+	return nil
+}
+
+// Returns the current pool, reloading it if its file changed.
+func (p *certPool) get() *x509.CertPool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if t, err := modTime(p.file); err == nil && !t.Equal(p.modTime) {
+		if err := p.reload(); err != nil {
+			log.Warnf("Cannot reload CA certificates %s, using the previous ones: %v", p.file, err)
+		} else {
+			log.Infof("Reloaded CA certificates %s", p.file)
+		}
+	}
+	return p.pool
+}
+
+// Options of the TLS server towards controllers.
+type ServerOptions struct {
+	// PEM files with the server certificate and private key.
+	CertFile string
+	KeyFile  string
+	// PEM file with the CAs of client certificates. If set, controllers must present a certificate signed by one of
+	// them, otherwise client certificates are not requested.
+	ClientCAFile string
+}
+
+// Returns a TLS config for a server with the given options.
+func ServerConfig(o ServerOptions) (*tls.Config, error) {
+	if o.CertFile == "" || o.KeyFile == "" {
+		return nil, fmt.Errorf("server certificate and key are required")
+	}
+	cert, err := newKeyPair(o.CertFile, o.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load server certificate: %v", err)
+	}
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return cert.get(), nil
+		},
+	}
+	if o.ClientCAFile == "" {
+		return config, nil
+	}
+	clientCAs, err := newCertPool(o.ClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load client CAs: %v", err)
+	}
+	config.ClientAuth = tls.RequireAndVerifyClientCert
+	// ClientCAs cannot be reloaded in place, hence each handshake uses a copy of the config with the current ones.
+	base := config.Clone()
+	config.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		c := base.Clone()
+		c.ClientCAs = clientCAs.get()
+		return c, nil
+	}
+	return config, nil
+}
+
+// Options of the TLS client towards the target.
+type ClientOptions struct {
+	// PEM file with the CAs of the target certificate, empty to use the system ones.
+	CAFile string
+	// PEM files with the client certificate and private key presented to the target, if it requests one. Optional.
+	CertFile string
+	KeyFile  string
+	// Name expected in the target certificate.
+	ServerName string
+}
+
+// Returns a TLS config for a client with the given options.
+func ClientConfig(o ClientOptions) (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: o.ServerName,
+	}
+	if (o.CertFile == "") != (o.KeyFile == "") {
+		return nil, fmt.Errorf("client certificate and key must be set together")
+	}
+	if o.CertFile != "" {
+		cert, err := newKeyPair(o.CertFile, o.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load client certificate: %v", err)
+		}
+		config.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return cert.get(), nil
+		}
+	}
+	if o.CAFile == "" {
+		return config, nil
+	}
+	if o.ServerName == "" {
+		return nil, fmt.Errorf("server name is required to verify the server certificate")
+	}
+	rootCAs, err := newCertPool(o.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load CAs: %v", err)
+	}
+	// RootCAs cannot be reloaded in place, hence the built-in verification is replaced by an equivalent one using the
+	// current CAs.
+	config.InsecureSkipVerify = true
+	config.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		return verifyServer(rawCerts, rootCAs.get(), o.ServerName)
+	}
+	return config, nil
+}
+
+// Verifies the given server certificate chain against the given CAs and server name, as done by crypto/tls.
+func verifyServer(rawCerts [][]byte, roots *x509.CertPool, serverName string) error {
+	if len(rawCerts) == 0 {
+		return fmt.Errorf("no server certificate")
+	}
+	certs := make([]*x509.Certificate, len(rawCerts))
+	for i, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return fmt.Errorf("invalid server certificate: %v", err)
+		}
+		certs[i] = cert
+	}
+	opts := x509.VerifyOptions{
+		Roots:         roots,
+		DNSName:       serverName,
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, cert := range certs[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	_, err := certs[0].Verify(opts)
+	return err
+}
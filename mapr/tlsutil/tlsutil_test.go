@@ -0,0 +1,188 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package tlsutil
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+var lastSerial int64
+
+// Returns a new certificate for the given name, signed by the given CA, self-signed if nil.
+func newTestCert(t *testing.T, name string, ca *testCert) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	lastSerial++
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(lastSerial),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	parent, parentKey := template, key
+	if ca == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+	} else {
+		template.DNSNames = []string{name}
+		parent, parentKey = ca.cert, ca.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCert{cert: cert, key: key}
+}
+
+// Writes the certificate and key to the given files, with a modification time in the future to make sure it
+// differs from the previous one.
+func (c *testCert) write(t *testing.T, certFile, keyFile string, version int) {
+	require.NoError(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.cert.Raw}),
+		0600))
+	mtime := time.Now().Add(time.Duration(version) * time.Minute)
+	require.NoError(t, os.Chtimes(certFile, mtime, mtime))
+	if keyFile == "" {
+		return
+	}
+	der, err := x509.MarshalECPrivateKey(c.key)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}),
+		0600))
+	require.NoError(t, os.Chtimes(keyFile, mtime, mtime))
+}
+
+// Performs a handshake between the given configs, returning the server certificate seen by the client, and the
+// handshake errors of the client and the server.
+func handshake(t *testing.T, client, server *tls.Config) (*x509.Certificate, error, error) {
+	// A buffered connection, as the server might send an alert while the client is writing.
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer lis.Close()
+	serverErr := make(chan error, 1)
+	go func() {
+		conn, err := lis.Accept()
+		if err != nil {
+			serverErr <- err
+			return
+		}
+		defer conn.Close()
+		c := tls.Server(conn, server)
+		err = c.Handshake()
+		if err == nil {
+			// With TLS 1.3, the client certificate is verified when reading.
+			_, err = c.Read(make([]byte, 1))
+		}
+		serverErr <- err
+	}()
+	conn, err := net.Dial("tcp", lis.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	c := tls.Client(conn, client)
+	clientErr := c.Handshake()
+	var cert *x509.Certificate
+	if clientErr == nil {
+		cert = c.ConnectionState().PeerCertificates[0]
+		_, clientErr = c.Write([]byte{0})
+	}
+	return cert, clientErr, <-serverErr
+}
+
+func Test_TLS(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tlsutil")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := func(name string) string {
+		return filepath.Join(dir, name)
+	}
+	ca := newTestCert(t, "ca", nil)
+	ca.write(t, path("ca.pem"), "", 0)
+	server := newTestCert(t, "mapr", ca)
+	server.write(t, path("server.pem"), path("server.key"), 0)
+	client := newTestCert(t, "controller", ca)
+	client.write(t, path("client.pem"), path("client.key"), 0)
+
+	serverConfig, err := ServerConfig(ServerOptions{CertFile: path("server.pem"), KeyFile: path("server.key"),
+		ClientCAFile: path("ca.pem")})
+	require.NoError(t, err)
+	clientConfig, err := ClientConfig(ClientOptions{CAFile: path("ca.pem"), CertFile: path("client.pem"),
+		KeyFile: path("client.key"), ServerName: "mapr"})
+	require.NoError(t, err)
+	cert, clientErr, serverErr := handshake(t, clientConfig, serverConfig)
+	require.NoError(t, clientErr)
+	require.NoError(t, serverErr)
+	assert.Equal(t, server.cert.SerialNumber, cert.SerialNumber)
+
+	// Clients without a certificate are rejected.
+	noCertConfig, err := ClientConfig(ClientOptions{CAFile: path("ca.pem"), ServerName: "mapr"})
+	require.NoError(t, err)
+	_, _, serverErr = handshake(t, noCertConfig, serverConfig)
+	assert.Error(t, serverErr)
+
+	// Wrong server name.
+	wrongNameConfig, err := ClientConfig(ClientOptions{CAFile: path("ca.pem"), CertFile: path("client.pem"),
+		KeyFile: path("client.key"), ServerName: "foo"})
+	require.NoError(t, err)
+	_, clientErr, _ = handshake(t, wrongNameConfig, serverConfig)
+	assert.Error(t, clientErr)
+
+	// Rotated server certificate.
+	rotated := newTestCert(t, "mapr", ca)
+	rotated.write(t, path("server.pem"), path("server.key"), 1)
+	cert, clientErr, serverErr = handshake(t, clientConfig, serverConfig)
+	require.NoError(t, clientErr)
+	require.NoError(t, serverErr)
+	assert.Equal(t, rotated.cert.SerialNumber, cert.SerialNumber)
+
+	// Half-rotated server certificate, the previous one is used.
+	newTestCert(t, "mapr", ca).write(t, path("server.pem"), "", 2)
+	cert, clientErr, serverErr = handshake(t, clientConfig, serverConfig)
+	require.NoError(t, clientErr)
+	require.NoError(t, serverErr)
+	assert.Equal(t, rotated.cert.SerialNumber, cert.SerialNumber)
+
+	// Rotated CA, certificates signed by the previous one are rejected on both sides.
+	newTestCert(t, "ca", nil).write(t, path("ca.pem"), "", 3)
+	_, clientErr, serverErr = handshake(t, clientConfig, serverConfig)
+	assert.Error(t, clientErr)
+	assert.Error(t, serverErr)
+}
+
+func Test_Config_Invalid(t *testing.T) {
+	_, err := ServerConfig(ServerOptions{CertFile: "server.pem"})
+	assert.EqualError(t, err, "server certificate and key are required")
+	_, err = ServerConfig(ServerOptions{CertFile: "missing.pem", KeyFile: "missing.key"})
+	assert.Error(t, err)
+	_, err = ClientConfig(ClientOptions{CertFile: "client.pem"})
+	assert.EqualError(t, err, "client certificate and key must be set together")
+	_, err = ClientConfig(ClientOptions{CAFile: "ca.pem"})
+	assert.EqualError(t, err, "server name is required to verify the server certificate")
+	config, err := ClientConfig(ClientOptions{ServerName: "target"})
+	assert.NoError(t, err)
+	assert.Equal(t, "target", config.ServerName)
+}
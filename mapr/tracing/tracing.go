@@ -0,0 +1,166 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package tracing provides OpenTelemetry tracing of the RPCs served by mapr and sent to the target. The trace context
+// of controllers is read from the W3C traceparent header in the gRPC metadata of incoming RPCs, and propagated in the
+// same way to the target, even when spans are not exported.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"strings"
+)
+
+const instrumentationName = "mapr"
+
+func init() {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+}
+
+// Exports spans over OTLP/gRPC to the collector at the given address in the format of host:port, usually a local one,
+// hence without TLS. Returns a function flushing pending spans and stopping the export.
+func Start(ctx context.Context, endpoint string) (func(context.Context) error, error) {
+	exporter, err := otlptracegrpc.New(ctx, otlptracegrpc.WithEndpoint(endpoint), otlptracegrpc.WithInsecure())
+	if err != nil {
+		return nil, fmt.Errorf("cannot create OTLP exporter: %v", err)
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceNameKey.String(instrumentationName))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Starts a span with the given name, child of the span in the given context, if any.
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// Ends the given span, recording the given error, if any.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Returns the attributes describing the given update.
+func UpdateAttributes(u *p4v1.Update) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{attribute.String("p4.update.type", u.Type.String())}
+	switch x := u.GetEntity().GetEntity().(type) {
+	case *p4v1.Entity_TableEntry:
+		attrs = append(attrs, attribute.String("p4.entity", "table_entry"),
+			attribute.Int64("p4.table_id", int64(x.TableEntry.TableId)))
+	case *p4v1.Entity_ActionProfileMember:
+		attrs = append(attrs, attribute.String("p4.entity", "action_profile_member"),
+			attribute.Int64("p4.action_profile_id", int64(x.ActionProfileMember.ActionProfileId)))
+	case *p4v1.Entity_ActionProfileGroup:
+		attrs = append(attrs, attribute.String("p4.entity", "action_profile_group"),
+			attribute.Int64("p4.action_profile_id", int64(x.ActionProfileGroup.ActionProfileId)))
+	case nil:
+	default:
+		attrs = append(attrs, attribute.String("p4.entity", fmt.Sprintf("%T", x)))
+	}
+	return attrs
+}
+
+// Adapts gRPC metadata to the carrier of the propagators.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if v := metadata.MD(c).Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key string, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Returns the context with the trace context found in the incoming gRPC metadata, if any.
+func extract(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	return otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+}
+
+// Returns the context with the trace context added to the outgoing gRPC metadata.
+func inject(ctx context.Context) context.Context {
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	otel.GetTextMapPropagator().Inject(ctx, metadataCarrier(md))
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// Returns the span name of the given full gRPC method name, e.g., p4.v1.P4Runtime/Write.
+func spanName(method string) string {
+	return strings.TrimPrefix(method, "/")
+}
+
+// Returns the attributes describing the given gRPC method and status.
+func rpcAttributes(method string, err error) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{semconv.RPCSystemKey.String("grpc")}
+	if i := strings.LastIndex(method, "/"); i > 0 {
+		attrs = append(attrs, semconv.RPCServiceKey.String(method[1:i]), semconv.RPCMethodKey.String(method[i+1:]))
+	}
+	return append(attrs, semconv.RPCGRPCStatusCodeKey.Int64(int64(status.Code(err))))
+}
+
+// Returns an interceptor starting a server span for each unary RPC, child of the span of the controller, if any.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (
+		interface{}, error) {
+		ctx, span := otel.Tracer(instrumentationName).Start(extract(ctx), spanName(info.FullMethod),
+			trace.WithSpanKind(trace.SpanKindServer))
+		resp, err := handler(ctx, req)
+		span.SetAttributes(rpcAttributes(info.FullMethod, err)...)
+		EndSpan(span, err)
+		return resp, err
+	}
+}
+
+// Returns an interceptor starting a client span for each unary RPC sent to the target, and propagating its trace
+// context.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, span := otel.Tracer(instrumentationName).Start(ctx, spanName(method),
+			trace.WithSpanKind(trace.SpanKindClient))
+		err := invoker(inject(ctx), method, req, reply, cc, opts...)
+		span.SetAttributes(rpcAttributes(method, err)...)
+		EndSpan(span, err)
+		return err
+	}
+}
@@ -0,0 +1,91 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"testing"
+)
+
+const (
+	controllerTraceId = "4bf92f3577b34da6a3ce929d0e0e4736"
+	controllerSpanId  = "00f067aa0ba902b7"
+	traceparent       = "00-" + controllerTraceId + "-" + controllerSpanId + "-01"
+)
+
+// Sets the global tracer provider for the duration of the test.
+func setTracerProvider(t *testing.T, provider trace.TracerProvider) {
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+	})
+}
+
+// Serves a unary RPC with the given incoming metadata, invoking the target from the handler. Returns the outgoing
+// metadata seen by the target.
+func serve(t *testing.T, incoming metadata.MD, handlerErr error) metadata.MD {
+	var outgoing metadata.MD
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn,
+		opts ...grpc.CallOption) error {
+		outgoing, _ = metadata.FromOutgoingContext(ctx)
+		return nil
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		ctx, span := StartSpan(ctx, "update")
+		defer EndSpan(span, handlerErr)
+		require.NoError(t, UnaryClientInterceptor()(ctx, "/p4.v1.P4Runtime/Write", nil, nil, nil, invoker))
+		return nil, handlerErr
+	}
+	ctx := metadata.NewIncomingContext(context.Background(), incoming)
+	_, err := UnaryServerInterceptor()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/p4.v1.P4Runtime/Write"}, handler)
+	assert.Equal(t, handlerErr, err)
+	return outgoing
+}
+
+func Test_Interceptors(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	setTracerProvider(t, sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	outgoing := serve(t, metadata.Pairs("traceparent", traceparent), fmt.Errorf("foo"))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	client, update, server := spans[0], spans[1], spans[2]
+	assert.Equal(t, "p4.v1.P4Runtime/Write", server.Name())
+	assert.Equal(t, trace.SpanKindServer, server.SpanKind())
+	assert.Equal(t, controllerTraceId, server.Parent().TraceID().String())
+	assert.Equal(t, controllerSpanId, server.Parent().SpanID().String())
+	assert.True(t, server.Parent().IsRemote())
+	assert.Equal(t, otelcodes.Error, server.Status().Code)
+	assert.Equal(t, server.SpanContext().SpanID(), update.Parent().SpanID())
+	assert.Equal(t, update.SpanContext().SpanID(), client.Parent().SpanID())
+	assert.Equal(t, trace.SpanKindClient, client.SpanKind())
+	assert.Equal(t, otelcodes.Unset, client.Status().Code)
+
+	// The target sees the client span as parent.
+	assert.Equal(t, []string{fmt.Sprintf("00-%s-%s-01", controllerTraceId, client.SpanContext().SpanID())},
+		outgoing.Get("traceparent"))
+}
+
+func Test_Interceptors_NotExported(t *testing.T) {
+	// Without an SDK, the trace context of the controller is propagated as is.
+	setTracerProvider(t, trace.NewNoopTracerProvider())
+	outgoing := serve(t, metadata.Pairs("traceparent", traceparent), nil)
+	assert.Equal(t, []string{traceparent}, outgoing.Get("traceparent"))
+	outgoing = serve(t, nil, nil)
+	assert.Empty(t, outgoing.Get("traceparent"))
+}
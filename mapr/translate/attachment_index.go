@@ -0,0 +1,159 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package translate
+
+import (
+	"sort"
+)
+
+// Secondary indexes of the attachments of both directions, to look up subscribers by IPv4 address, MAC address, port
+// and VLAN tags, or port and PPPoE session ID, rather than by line ID.
+
+// Identifies an attachment in the LogicalStore.
+type AttachmentRef struct {
+	Direction Direction
+	LineId    LineIdKey
+}
+
+// A value of one of the fields indexed, see the Attachment*Key functions.
+type AttachmentIndexKey string
+
+// Prefixes telling apart the keys of different indexes.
+const (
+	attachmentIndexIpv4  = "i"
+	attachmentIndexMac   = "m"
+	attachmentIndexVlans = "v"
+	attachmentIndexPppoe = "p"
+)
+
+// Returns the given values canonicalized and prefixed with their length, such that they can be concatenated.
+func appendIndexValues(dst []byte, values ...[]byte) []byte {
+	for _, v := range values {
+		dst = appendKeyBytes(dst, v)
+	}
+	return dst
+}
+
+// Returns the key to look up attachments by IPv4 address.
+func AttachmentIpv4Key(addr []byte) AttachmentIndexKey {
+	return AttachmentIndexKey(appendIndexValues([]byte(attachmentIndexIpv4), addr))
+}
+
+// Returns the key to look up attachments by MAC address.
+func AttachmentMacKey(mac []byte) AttachmentIndexKey {
+	return AttachmentIndexKey(appendIndexValues([]byte(attachmentIndexMac), mac))
+}
+
+// Returns the key to look up attachments by port, S-tag and C-tag.
+func AttachmentVlansKey(port []byte, sTag []byte, cTag []byte) AttachmentIndexKey {
+	return AttachmentIndexKey(appendIndexValues([]byte(attachmentIndexVlans), port, sTag, cTag))
+}
+
+// Returns the key to look up attachments by port and PPPoE session ID.
+func AttachmentPppoeKey(port []byte, pppoeSessId []byte) AttachmentIndexKey {
+	return AttachmentIndexKey(appendIndexValues([]byte(attachmentIndexPppoe), port, pppoeSessId))
+}
+
+func (a AttachmentEntry) Ref() AttachmentRef {
+	return AttachmentRef{Direction: a.Direction, LineId: ToLineIdKey(a.LineId)}
+}
+
+// Returns the index keys of the attachment, for the fields that are known.
+func (a AttachmentEntry) indexKeys() []AttachmentIndexKey {
+	var keys []AttachmentIndexKey
+	if a.Ipv4Addr != nil {
+		keys = append(keys, AttachmentIpv4Key(a.Ipv4Addr))
+	}
+	if a.MacAddr != nil {
+		keys = append(keys, AttachmentMacKey(a.MacAddr))
+	}
+	if a.Port != nil && a.STag != nil && a.CTag != nil {
+		keys = append(keys, AttachmentVlansKey(a.Port, a.STag, a.CTag))
+	}
+	if a.Port != nil && a.PppoeSessId != nil {
+		keys = append(keys, AttachmentPppoeKey(a.Port, a.PppoeSessId))
+	}
+	return keys
+}
+
+// Slices of refs in the index are shared with snapshots, hence they are replaced rather than modified.
+
+func addAttachmentRef(refs []AttachmentRef, ref AttachmentRef) []AttachmentRef {
+	for _, x := range refs {
+		if x == ref {
+			return refs
+		}
+	}
+	return append(refs[:len(refs):len(refs)], ref)
+}
+
+func removeAttachmentRef(refs []AttachmentRef, ref AttachmentRef) []AttachmentRef {
+	result := make([]AttachmentRef, 0, len(refs))
+	for _, x := range refs {
+		if x != ref {
+			result = append(result, x)
+		}
+	}
+	return result
+}
+
+func (s *LogicalStore) attachments(d Direction) *AttachmentMap {
+	if d == DirectionUpstream {
+		return &s.UpstreamAttachments
+	}
+	return &s.DownstreamAttachments
+}
+
+// Returns the attachment with the given ref, nil if not found.
+func (s *LogicalStore) Attachment(ref AttachmentRef) *AttachmentEntry {
+	return s.attachments(ref.Direction).Get(ref.LineId)
+}
+
+// Stores the given attachment, replacing the one with the same direction and line ID, and updates the indexes.
+func (s *LogicalStore) putAttachment(a *AttachmentEntry) {
+	ref := a.Ref()
+	s.removeAttachment(ref)
+	s.attachments(a.Direction).Put(ref.LineId, a)
+	for _, k := range a.indexKeys() {
+		s.AttachmentIndex.Put(k, addAttachmentRef(s.AttachmentIndex.Get(k), ref))
+	}
+}
+
+// Removes the attachment with the given ref, if any, and updates the indexes.
+func (s *LogicalStore) removeAttachment(ref AttachmentRef) {
+	old := s.Attachment(ref)
+	if old == nil {
+		return
+	}
+	for _, k := range old.indexKeys() {
+		if refs := removeAttachmentRef(s.AttachmentIndex.Get(k), ref); len(refs) > 0 {
+			s.AttachmentIndex.Put(k, refs)
+		} else {
+			s.AttachmentIndex.Delete(k)
+		}
+	}
+	s.attachments(ref.Direction).Delete(ref.LineId)
+}
+
+// Returns the attachments with the given index key, upstream before downstream, in ascending line ID order.
+func (s *LogicalStore) AttachmentsByIndex(k AttachmentIndexKey) []*AttachmentEntry {
+	refs := s.AttachmentIndex.Get(k)
+	result := make([]*AttachmentEntry, 0, len(refs))
+	for _, ref := range refs {
+		if a := s.Attachment(ref); a != nil {
+			result = append(result, a)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Direction != result[j].Direction {
+			return result[i].Direction == DirectionUpstream
+		}
+		ki, kj := ToLineIdKey(result[i].LineId), ToLineIdKey(result[j].LineId)
+		return string(ki[:]) < string(kj[:])
+	})
+	return result
+}
@@ -0,0 +1,126 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package translate
+
+import (
+	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// A processor producing no target updates, to test the logical store.
+type nopProcessor struct{}
+
+func (nopProcessor) HandleIfTypeEntry(*IfTypeEntry, p4v1.Update_Type) ([]*p4v1.Update, error) {
+	return nil, nil
+}
+
+func (nopProcessor) HandleMyStationEntry(*MyStationEntry, p4v1.Update_Type) ([]*p4v1.Update, error) {
+	return nil, nil
+}
+
+func (nopProcessor) HandleAttachmentEntry(*AttachmentEntry, bool) ([]*p4v1.Update, error) {
+	return nil, nil
+}
+
+func (nopProcessor) HandleRouteV4NextHopEntry(*NextHopEntry, p4v1.Update_Type) ([]*p4v1.Update, error) {
+	return nil, nil
+}
+
+func (nopProcessor) HandleRouteV4NextHopGroup(*NextHopGroup, p4v1.Update_Type) ([]*p4v1.Update, error) {
+	return nil, nil
+}
+
+func (nopProcessor) HandleRouteV4Entry(*RouteV4Entry, p4v1.Update_Type) ([]*p4v1.Update, error) {
+	return nil, nil
+}
+
+func (nopProcessor) HandleAclEntry(*AclEntry, p4v1.Update_Type) ([]*p4v1.Update, error) {
+	return nil, nil
+}
+
+func (nopProcessor) HandlePpppoePunts(*PppoePuntedEntry, p4v1.Update_Type) ([]*p4v1.Update, error) {
+	return nil, nil
+}
+
+// Returns a translator with a nopProcessor, and a function applying a logical update to it.
+func newNopTranslator(t *testing.T) (Context, func(p4v1.Update_Type, *p4v1.Entity) error) {
+	ctx := NewContext(nil)
+	trn := NewTranslator(nopProcessor{}, ctx, nil)
+	return ctx, func(uType p4v1.Update_Type, e *p4v1.Entity) error {
+		u := &p4v1.Update{Type: uType, Entity: e}
+		target, err := trn.Translate(u)
+		if err != nil {
+			return err
+		}
+		require.NoError(t, trn.ApplyUpdate(u, target))
+		return nil
+	}
+}
+
+func upstreamLineEntity(lineId byte, port byte, sTag byte, cTag byte) *p4v1.Entity {
+	return tableEntryEntity((&IngressPipeUpstreamLinesEntry{
+		Port:   []byte{0, port},
+		STag:   []byte{0, sTag},
+		CTag:   []byte{0, cTag},
+		Action: &IngressPipeUpstreamSetLineAction{LineId: []byte{0, 0, 0, lineId}},
+	}).ToTableEntry())
+}
+
+func upstreamAttachmentEntity(lineId byte, mac byte, ipv4 byte, pppoeSessId byte) *p4v1.Entity {
+	return tableEntryEntity((&IngressPipeUpstreamAttachmentsV4Entry{
+		LineId:      []byte{0, 0, 0, lineId},
+		EthSrc:      []byte{0xaa, 0, 0, 0, 0, mac},
+		Ipv4Src:     []byte{10, 0, 0, ipv4},
+		PppoeSessId: []byte{0, pppoeSessId},
+		Action:      &NopAction{},
+	}).ToTableEntry())
+}
+
+func downstreamLineEntity(lineId byte, ipv4 byte) *p4v1.Entity {
+	return tableEntryEntity((&IngressPipeDownstreamLinesV4Entry{
+		Ipv4Dst: []byte{10, 0, 0, ipv4},
+		Action:  &IngressPipeDownstreamSetLineAction{LineId: []byte{0, 0, 0, lineId}},
+	}).ToTableEntry())
+}
+
+func lineIds(attachments []*AttachmentEntry) []string {
+	ids := make([]string, 0, len(attachments))
+	for _, a := range attachments {
+		ids = append(ids, a.ObjectId().Key)
+	}
+	return ids
+}
+
+func Test_LogicalStore_AttachmentsByIndex(t *testing.T) {
+	ctx, write := newNopTranslator(t)
+	require.NoError(t, write(p4v1.Update_INSERT, upstreamLineEntity(1, 3, 100, 200)))
+	require.NoError(t, write(p4v1.Update_INSERT, upstreamLineEntity(2, 3, 100, 201)))
+	require.NoError(t, write(p4v1.Update_INSERT, upstreamAttachmentEntity(1, 1, 1, 1)))
+	require.NoError(t, write(p4v1.Update_INSERT, downstreamLineEntity(1, 1)))
+
+	s := ctx.Logical()
+	// Widths of the values do not matter.
+	assert.Equal(t, []string{"up/1", "down/1"}, lineIds(s.AttachmentsByIndex(AttachmentIpv4Key([]byte{0x0a, 0, 0, 1}))))
+	assert.Equal(t, []string{"up/1"}, lineIds(s.AttachmentsByIndex(AttachmentMacKey([]byte{0xaa, 0, 0, 0, 0, 1}))))
+	assert.Equal(t, []string{"up/2"}, lineIds(s.AttachmentsByIndex(AttachmentVlansKey([]byte{3}, []byte{100}, []byte{0, 201}))))
+	assert.Equal(t, []string{"up/1"}, lineIds(s.AttachmentsByIndex(AttachmentPppoeKey([]byte{0, 3}, []byte{1}))))
+	assert.Empty(t, s.AttachmentsByIndex(AttachmentPppoeKey([]byte{0, 4}, []byte{1})))
+
+	// Modifying and deleting attachments updates the index, snapshots are not affected.
+	snapshot := ctx.Snapshot().Logical()
+	require.NoError(t, write(p4v1.Update_MODIFY, upstreamAttachmentEntity(1, 1, 2, 1)))
+	assert.Equal(t, []string{"down/1"}, lineIds(s.AttachmentsByIndex(AttachmentIpv4Key([]byte{10, 0, 0, 1}))))
+	assert.Equal(t, []string{"up/1"}, lineIds(s.AttachmentsByIndex(AttachmentIpv4Key([]byte{10, 0, 0, 2}))))
+	require.NoError(t, write(p4v1.Update_DELETE, upstreamLineEntity(2, 3, 100, 201)))
+	assert.Empty(t, s.AttachmentsByIndex(AttachmentVlansKey([]byte{3}, []byte{100}, []byte{201})))
+	assert.Equal(t, []string{"up/1", "down/1"},
+		lineIds(snapshot.AttachmentsByIndex(AttachmentIpv4Key([]byte{10, 0, 0, 1}))))
+	assert.Equal(t, []string{"up/2"},
+		lineIds(snapshot.AttachmentsByIndex(AttachmentVlansKey([]byte{3}, []byte{100}, []byte{201}))))
+}
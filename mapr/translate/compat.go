@@ -0,0 +1,240 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package translate
+
+import (
+	"fmt"
+	p4confv1 "github.com/p4lang/p4runtime/go/p4/config/v1"
+	"sort"
+)
+
+// Compatibility checks between P4Infos.
+//
+// A P4Info is compatible with a reference one if it defines the same schema mapr relies on when parsing logical
+// entities: tables with their match fields and actions, action profiles, counters, meters and controller packet
+// metadata, with the same names, IDs, match types and bit widths. Everything else, such as documentation,
+// annotations, sizes, and the order in which entities or fields are listed, is ignored. Entities defined only by the
+// checked P4Info are allowed, as controllers cannot write them without mapr rejecting the updates.
+
+// Collects the incompatibilities found by a check.
+type incompatibilities []string
+
+func (c *incompatibilities) add(format string, args ...interface{}) {
+	*c = append(*c, fmt.Sprintf(format, args...))
+}
+
+func matchTypeString(f *p4confv1.MatchField) string {
+	if other := f.GetOtherMatchType(); other != "" {
+		return other
+	}
+	return f.GetMatchType().String()
+}
+
+func tablesByName(p4info *p4confv1.P4Info) map[string]*p4confv1.Table {
+	m := make(map[string]*p4confv1.Table)
+	for _, x := range p4info.GetTables() {
+		m[x.Preamble.GetName()] = x
+	}
+	return m
+}
+
+func actionsByName(p4info *p4confv1.P4Info) map[string]*p4confv1.Action {
+	m := make(map[string]*p4confv1.Action)
+	for _, x := range p4info.GetActions() {
+		m[x.Preamble.GetName()] = x
+	}
+	return m
+}
+
+// Returns the names of all the P4 entities of the given P4Info by ID, tables and actions included.
+func namesById(p4info *p4confv1.P4Info) map[uint32]string {
+	m := make(map[uint32]string)
+	for _, x := range p4info.GetTables() {
+		m[x.Preamble.GetId()] = x.Preamble.GetName()
+	}
+	for _, x := range p4info.GetActions() {
+		m[x.Preamble.GetId()] = x.Preamble.GetName()
+	}
+	for _, x := range p4info.GetActionProfiles() {
+		m[x.Preamble.GetId()] = x.Preamble.GetName()
+	}
+	return m
+}
+
+// Returns the preambles of the action profiles, counters and meters of the given P4Info, by name, each prefixed by
+// its kind.
+func resourcesByName(p4info *p4confv1.P4Info) map[string]*p4confv1.Preamble {
+	m := make(map[string]*p4confv1.Preamble)
+	for _, x := range p4info.GetActionProfiles() {
+		m["action profile "+x.Preamble.GetName()] = x.Preamble
+	}
+	for _, x := range p4info.GetCounters() {
+		m["counter "+x.Preamble.GetName()] = x.Preamble
+	}
+	for _, x := range p4info.GetDirectCounters() {
+		m["direct counter "+x.Preamble.GetName()] = x.Preamble
+	}
+	for _, x := range p4info.GetMeters() {
+		m["meter "+x.Preamble.GetName()] = x.Preamble
+	}
+	for _, x := range p4info.GetDirectMeters() {
+		m["direct meter "+x.Preamble.GetName()] = x.Preamble
+	}
+	return m
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Returns a description of each incompatibility of the given P4Info with the reference one, sorted, or nil if
+// compatible.
+func CheckP4Info(p4info *p4confv1.P4Info, reference *p4confv1.P4Info) []string {
+	var c incompatibilities
+	tables := tablesByName(p4info)
+	names := namesById(p4info)
+	refNames := namesById(reference)
+	for _, ref := range reference.GetTables() {
+		name := ref.Preamble.GetName()
+		t, ok := tables[name]
+		if !ok {
+			c.add("table %s: missing", name)
+			continue
+		}
+		if t.Preamble.GetId() != ref.Preamble.GetId() {
+			c.add("table %s: ID is %d, expected %d", name, t.Preamble.GetId(), ref.Preamble.GetId())
+		}
+		fields := make(map[string]*p4confv1.MatchField)
+		for _, f := range t.MatchFields {
+			fields[f.Name] = f
+		}
+		for _, rf := range ref.MatchFields {
+			f, ok := fields[rf.Name]
+			if !ok {
+				c.add("table %s: match field %s: missing", name, rf.Name)
+				continue
+			}
+			delete(fields, rf.Name)
+			if f.Id != rf.Id {
+				c.add("table %s: match field %s: ID is %d, expected %d", name, rf.Name, f.Id, rf.Id)
+			}
+			if f.Bitwidth != rf.Bitwidth {
+				c.add("table %s: match field %s: bitwidth is %d, expected %d", name, rf.Name, f.Bitwidth, rf.Bitwidth)
+			}
+			if matchTypeString(f) != matchTypeString(rf) {
+				c.add("table %s: match field %s: match type is %s, expected %s", name, rf.Name, matchTypeString(f),
+					matchTypeString(rf))
+			}
+		}
+		for _, f := range t.MatchFields {
+			if _, ok := fields[f.Name]; ok {
+				c.add("table %s: match field %s: unexpected", name, f.Name)
+			}
+		}
+		actions := make(map[string]bool)
+		for _, a := range t.ActionRefs {
+			actions[names[a.Id]] = true
+		}
+		for _, ra := range ref.ActionRefs {
+			if !actions[refNames[ra.Id]] {
+				c.add("table %s: action %s: missing", name, refNames[ra.Id])
+			}
+		}
+		if names[t.ImplementationId] != refNames[ref.ImplementationId] {
+			c.add("table %s: implementation is %q, expected %q", name, names[t.ImplementationId],
+				refNames[ref.ImplementationId])
+		}
+	}
+	actions := actionsByName(p4info)
+	for _, ref := range reference.GetActions() {
+		name := ref.Preamble.GetName()
+		a, ok := actions[name]
+		if !ok {
+			c.add("action %s: missing", name)
+			continue
+		}
+		if a.Preamble.GetId() != ref.Preamble.GetId() {
+			c.add("action %s: ID is %d, expected %d", name, a.Preamble.GetId(), ref.Preamble.GetId())
+		}
+		params := make(map[string]*p4confv1.Action_Param)
+		for _, p := range a.Params {
+			params[p.Name] = p
+		}
+		for _, rp := range ref.Params {
+			p, ok := params[rp.Name]
+			if !ok {
+				c.add("action %s: param %s: missing", name, rp.Name)
+				continue
+			}
+			delete(params, rp.Name)
+			if p.Id != rp.Id {
+				c.add("action %s: param %s: ID is %d, expected %d", name, rp.Name, p.Id, rp.Id)
+			}
+			if p.Bitwidth != rp.Bitwidth {
+				c.add("action %s: param %s: bitwidth is %d, expected %d", name, rp.Name, p.Bitwidth, rp.Bitwidth)
+			}
+		}
+		for _, p := range a.Params {
+			if _, ok := params[p.Name]; ok {
+				c.add("action %s: param %s: unexpected", name, p.Name)
+			}
+		}
+	}
+	resources := resourcesByName(p4info)
+	for kindName, ref := range resourcesByName(reference) {
+		r, ok := resources[kindName]
+		if !ok {
+			c.add("%s: missing", kindName)
+		} else if r.GetId() != ref.GetId() {
+			c.add("%s: ID is %d, expected %d", kindName, r.GetId(), ref.GetId())
+		}
+	}
+	for _, ref := range reference.GetControllerPacketMetadata() {
+		name := ref.Preamble.GetName()
+		h := findControllerHeader(p4info, name)
+		if h == nil {
+			c.add("controller header %s: missing", name)
+			continue
+		}
+		if h.Preamble.GetId() != ref.Preamble.GetId() {
+			c.add("controller header %s: ID is %d, expected %d", name, h.Preamble.GetId(), ref.Preamble.GetId())
+		}
+		metadata := make(map[string]*p4confv1.ControllerPacketMetadata_Metadata)
+		for _, m := range h.Metadata {
+			metadata[m.Name] = m
+		}
+		for _, rm := range ref.Metadata {
+			m, ok := metadata[rm.Name]
+			if !ok {
+				c.add("controller header %s: metadata %s: missing", name, rm.Name)
+				continue
+			}
+			delete(metadata, rm.Name)
+			if m.Id != rm.Id {
+				c.add("controller header %s: metadata %s: ID is %d, expected %d", name, rm.Name, m.Id, rm.Id)
+			}
+			if m.Bitwidth != rm.Bitwidth {
+				c.add("controller header %s: metadata %s: bitwidth is %d, expected %d", name, rm.Name, m.Bitwidth,
+					rm.Bitwidth)
+			}
+		}
+		unexpected := make(map[string]bool)
+		for name := range metadata {
+			unexpected[name] = true
+		}
+		for _, m := range sortedKeys(unexpected) {
+			c.add("controller header %s: metadata %s: unexpected", name, m)
+		}
+	}
+	sort.Strings(c)
+	return c
+}
@@ -0,0 +1,301 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package translate
+
+import (
+	"github.com/golang/protobuf/proto"
+	p4confv1 "github.com/p4lang/p4runtime/go/p4/config/v1"
+	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"testing"
+)
+
+func logicalP4Info(t *testing.T) *p4confv1.P4Info {
+	bytes, err := ioutil.ReadFile("../../p4src/build/p4info.txt")
+	require.NoError(t, err)
+	p4info := &p4confv1.P4Info{}
+	require.NoError(t, proto.UnmarshalText(string(bytes), p4info))
+	return p4info
+}
+
+func findTable(p4info *p4confv1.P4Info, name string) *p4confv1.Table {
+	return tablesByName(p4info)[name]
+}
+
+func findMatchField(table *p4confv1.Table, name string) *p4confv1.MatchField {
+	for _, f := range table.MatchFields {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+func findAction(p4info *p4confv1.P4Info, name string) *p4confv1.Action {
+	return actionsByName(p4info)[name]
+}
+
+func exactMatch(id uint32, value ...byte) *p4v1.FieldMatch {
+	return &p4v1.FieldMatch{FieldId: id, FieldMatchType: &p4v1.FieldMatch_Exact_{
+		Exact: &p4v1.FieldMatch_Exact{Value: value}}}
+}
+
+func ternaryMatch(id uint32, value, mask []byte) *p4v1.FieldMatch {
+	return &p4v1.FieldMatch{FieldId: id, FieldMatchType: &p4v1.FieldMatch_Ternary_{
+		Ternary: &p4v1.FieldMatch_Ternary{Value: value, Mask: mask}}}
+}
+
+func rangeMatch(id uint32, low, high []byte) *p4v1.FieldMatch {
+	return &p4v1.FieldMatch{FieldId: id, FieldMatchType: &p4v1.FieldMatch_Range_{
+		Range: &p4v1.FieldMatch_Range{Low: low, High: high}}}
+}
+
+// Returns an action with a single param with ID 1.
+func directAction(id uint32, value ...byte) *p4v1.TableAction {
+	return &p4v1.TableAction{Type: &p4v1.TableAction_Action{Action: &p4v1.Action{
+		ActionId: id, Params: []*p4v1.Action_Param{{ParamId: 1, Value: value}}}}}
+}
+
+func Test_CheckP4Info(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(p *p4confv1.P4Info)
+		want   []string
+	}{
+		{
+			name:   "identical",
+			modify: func(p *p4confv1.P4Info) {},
+		},
+		{
+			name: "docs, annotations and order",
+			modify: func(p *p4confv1.P4Info) {
+				lines := findTable(p, "IngressPipe.upstream.lines")
+				lines.Preamble.Doc = &p4confv1.Documentation{Brief: "Lines"}
+				lines.Preamble.Annotations = append(lines.Preamble.Annotations, "@foo")
+				lines.MatchFields[0], lines.MatchFields[2] = lines.MatchFields[2], lines.MatchFields[0]
+				lines.Size = 1
+				p.Tables[0], p.Tables[1] = p.Tables[1], p.Tables[0]
+				p.Actions[0], p.Actions[1] = p.Actions[1], p.Actions[0]
+			},
+		},
+		{
+			name: "extra table",
+			modify: func(p *p4confv1.P4Info) {
+				p.Tables = append(p.Tables, &p4confv1.Table{Preamble: &p4confv1.Preamble{Id: 1, Name: "foo"}})
+			},
+		},
+		{
+			name: "table",
+			modify: func(p *p4confv1.P4Info) {
+				lines := findTable(p, "IngressPipe.upstream.lines")
+				lines.Preamble.Id = 1
+				findMatchField(lines, "s_tag").Bitwidth = 16
+				findMatchField(lines, "c_tag").Match = &p4confv1.MatchField_MatchType_{MatchType: p4confv1.MatchField_TERNARY}
+				lines.MatchFields = append(lines.MatchFields, &p4confv1.MatchField{Id: 4, Name: "foo"})
+				lines.ActionRefs = nil
+			},
+			want: []string{
+				"table IngressPipe.upstream.lines: ID is 1, expected 33956689",
+				"table IngressPipe.upstream.lines: action IngressPipe.upstream.set_line: missing",
+				"table IngressPipe.upstream.lines: match field c_tag: match type is TERNARY, expected EXACT",
+				"table IngressPipe.upstream.lines: match field foo: unexpected",
+				"table IngressPipe.upstream.lines: match field s_tag: bitwidth is 16, expected 12",
+			},
+		},
+		{
+			name: "missing table",
+			modify: func(p *p4confv1.P4Info) {
+				findTable(p, "IngressPipe.upstream.lines").Preamble.Name = "foo"
+			},
+			want: []string{"table IngressPipe.upstream.lines: missing"},
+		},
+		{
+			name: "action",
+			modify: func(p *p4confv1.P4Info) {
+				a := findAction(p, "IngressPipe.upstream.set_line")
+				a.Params[0].Name = "foo"
+			},
+			want: []string{
+				"action IngressPipe.upstream.set_line: param foo: unexpected",
+				"action IngressPipe.upstream.set_line: param line_id: missing",
+			},
+		},
+		{
+			name: "action profile",
+			modify: func(p *p4confv1.P4Info) {
+				p.ActionProfiles[0].Preamble.Id = 1
+				findTable(p, "IngressPipe.upstream.routes_v4").ImplementationId = 1
+			},
+			want: []string{"action profile IngressPipe.upstream.ecmp: ID is 1, expected 286372544"},
+		},
+		{
+			name: "packet metadata",
+			modify: func(p *p4confv1.P4Info) {
+				h := findControllerHeader(p, ControllerHeaderPacketIn)
+				h.Metadata[0].Bitwidth = 16
+				h.Metadata = append(h.Metadata, &p4confv1.ControllerPacketMetadata_Metadata{Id: 3, Name: "reason"})
+			},
+			want: []string{
+				"controller header packet_in: metadata ingress_port: bitwidth is 16, expected 9",
+				"controller header packet_in: metadata reason: unexpected",
+			},
+		},
+	}
+	reference := logicalP4Info(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p4info := proto.Clone(reference).(*p4confv1.P4Info)
+			tt.modify(p4info)
+			assert.Equal(t, tt.want, CheckP4Info(p4info, reference))
+		})
+	}
+}
+
+// Returns an older version of the given P4Info, with different IDs and narrower fields.
+func olderP4Info(current *p4confv1.P4Info) *p4confv1.P4Info {
+	p := proto.Clone(current).(*p4confv1.P4Info)
+	lines := findTable(p, "IngressPipe.upstream.lines")
+	lines.Preamble.Id = 1000
+	findMatchField(lines, "s_tag").Id = 10
+	findMatchField(lines, "s_tag").Bitwidth = 8
+	lines.ActionRefs[0].Id = 2000
+	setLine := findAction(p, "IngressPipe.upstream.set_line")
+	setLine.Preamble.Id = 2000
+	setLine.Params[0].Bitwidth = 16
+	services := findTable(p, "IngressPipe.upstream.cos.services_v4")
+	findMatchField(services, "ipv4_proto").Bitwidth = 4
+	findMatchField(services, "l4_sport").Bitwidth = 8
+	h := findControllerHeader(p, ControllerHeaderPacketOut)
+	h.Metadata[0].Id = 5
+	return p
+}
+
+func Test_NewVersionUpgrader(t *testing.T) {
+	current := logicalP4Info(t)
+	_, err := NewVersionUpgrader(olderP4Info(current), current)
+	assert.NoError(t, err)
+	// Current fields cannot be narrower.
+	_, err = NewVersionUpgrader(current, olderP4Info(current))
+	assert.EqualError(t, err, "cannot upgrade P4Info: ["+
+		"action IngressPipe.upstream.set_line: param line_id: bitwidth is 16, expected at least 32 "+
+		"table IngressPipe.upstream.cos.services_v4: match field ipv4_proto: bitwidth is 4, expected at least 8 "+
+		"table IngressPipe.upstream.cos.services_v4: match field l4_sport: bitwidth is 8, expected at least 16 "+
+		"table IngressPipe.upstream.lines: match field s_tag: bitwidth is 8, expected at least 12]")
+	// LPM fields cannot be widened.
+	older := proto.Clone(current).(*p4confv1.P4Info)
+	routes := findTable(older, "IngressPipe.upstream.routes_v4")
+	routes.MatchFields[0].Bitwidth = 16
+	_, err = NewVersionUpgrader(older, current)
+	assert.EqualError(t, err, "cannot upgrade P4Info: [table IngressPipe.upstream.routes_v4: match field "+
+		routes.MatchFields[0].Name+": LPM bitwidth is 32, expected 16]")
+}
+
+func Test_VersionUpgrader_Entity(t *testing.T) {
+	current := logicalP4Info(t)
+	u, err := NewVersionUpgrader(olderP4Info(current), current)
+	require.NoError(t, err)
+	servicesId := findTable(current, "IngressPipe.upstream.cos.services_v4").Preamble.Id
+	tests := []struct {
+		name    string
+		older   *p4v1.Entity
+		current *p4v1.Entity
+	}{
+		{
+			name: "exact",
+			older: tableEntryEntity(&p4v1.TableEntry{
+				TableId: 1000,
+				Match:   []*p4v1.FieldMatch{exactMatch(Hdr_IngressPipeUpstreamLines_Port, 0x01), exactMatch(10, 0x07)},
+				Action:  directAction(2000, 0x00, 0x05),
+			}),
+			current: tableEntryEntity(&p4v1.TableEntry{
+				TableId: Table_IngressPipeUpstreamLines,
+				Match: []*p4v1.FieldMatch{exactMatch(Hdr_IngressPipeUpstreamLines_Port, 0x00, 0x01),
+					exactMatch(Hdr_IngressPipeUpstreamLines_STag, 0x00, 0x07)},
+				Action: directAction(Action_IngressPipeUpstreamSetLine, 0x00, 0x00, 0x00, 0x05),
+			}),
+		},
+		{
+			name: "full ternary mask and range",
+			older: tableEntryEntity(&p4v1.TableEntry{
+				TableId: servicesId,
+				Match: []*p4v1.FieldMatch{
+					ternaryMatch(3, []byte{0x06}, []byte{0x0F}),
+					rangeMatch(4, []byte{0x00}, []byte{0xFF}),
+				},
+			}),
+			current: tableEntryEntity(&p4v1.TableEntry{
+				TableId: servicesId,
+				Match: []*p4v1.FieldMatch{
+					ternaryMatch(3, []byte{0x06}, []byte{0xFF}),
+					rangeMatch(4, []byte{0x00, 0x00}, []byte{0xFF, 0xFF}),
+				},
+			}),
+		},
+		{
+			name: "partial ternary mask and range",
+			older: tableEntryEntity(&p4v1.TableEntry{
+				TableId: servicesId,
+				Match: []*p4v1.FieldMatch{
+					ternaryMatch(3, []byte{0x06}, []byte{0x0E}),
+					rangeMatch(4, []byte{0x00}, []byte{0x7F}),
+				},
+			}),
+			current: tableEntryEntity(&p4v1.TableEntry{
+				TableId: servicesId,
+				Match: []*p4v1.FieldMatch{
+					ternaryMatch(3, []byte{0x06}, []byte{0x0E}),
+					rangeMatch(4, []byte{0x00, 0x00}, []byte{0x00, 0x7F}),
+				},
+			}),
+		},
+		{
+			name:    "wildcard",
+			older:   tableEntryEntity(&p4v1.TableEntry{}),
+			current: tableEntryEntity(&p4v1.TableEntry{}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := u.UpgradeEntity(tt.older)
+			require.NoError(t, err)
+			assert.True(t, proto.Equal(tt.current, got), "got %v", got)
+			got, err = u.DowngradeEntity(tt.current)
+			require.NoError(t, err)
+			assert.Equal(t, tt.older.GetTableEntry().GetTableId(), got.GetTableEntry().GetTableId())
+			// Upgrading again must give the same entity.
+			got, err = u.UpgradeEntity(got)
+			require.NoError(t, err)
+			assert.True(t, proto.Equal(tt.current, got), "got %v", got)
+		})
+	}
+	// Values that do not fit the older version.
+	_, err = u.DowngradeEntity(tableEntryEntity(&p4v1.TableEntry{
+		TableId: Table_IngressPipeUpstreamLines,
+		Match:   []*p4v1.FieldMatch{exactMatch(Hdr_IngressPipeUpstreamLines_STag, 0x01, 0x00)},
+	}))
+	assert.EqualError(t, err, "table ID 33956689: match field ID 3: value 0100 exceeds 8 bits")
+	_, err = u.UpgradeEntity(tableEntryEntity(&p4v1.TableEntry{TableId: Table_IngressPipeUpstreamLines}))
+	assert.EqualError(t, err, "unknown or untranslatable table ID 33956689")
+}
+
+func Test_VersionUpgrader_PacketIo(t *testing.T) {
+	current := logicalP4Info(t)
+	u, err := NewVersionUpgrader(olderP4Info(current), current)
+	require.NoError(t, err)
+	out, err := u.UpgradePacketOut(&p4v1.PacketOut{Payload: []byte{0xAB}, Metadata: []*p4v1.PacketMetadata{
+		meta(5, 0x01, 0x02), meta(2, 0x00)}})
+	assert.NoError(t, err)
+	assert.Equal(t, &p4v1.PacketOut{Payload: []byte{0xAB}, Metadata: []*p4v1.PacketMetadata{
+		meta(1, 0x01, 0x02), meta(2, 0x00)}}, out)
+	in, err := u.DowngradePacketIn(&p4v1.PacketIn{Payload: []byte{0xAB}, Metadata: []*p4v1.PacketMetadata{
+		meta(1, 0x01)}})
+	assert.NoError(t, err)
+	assert.Equal(t, &p4v1.PacketIn{Payload: []byte{0xAB}, Metadata: []*p4v1.PacketMetadata{
+		meta(1, 0x00, 0x01)}}, in)
+}
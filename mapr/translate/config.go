@@ -0,0 +1,51 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package translate
+
+import "fmt"
+
+// Config of the translator.
+type Config struct {
+	// Kinds of objects whose deletion also deletes the objects referring to them, e.g., "next_hop_group" to delete the
+	// routes using a group with it. Deletes of objects of other kinds are rejected while referred to. Cascades are
+	// transitive, e.g., deleting a MyStation entry cascades to the next hop groups using its next hops only if both
+	// "my_station" and "next_hop" are given.
+	CascadeDeletes []string `json:"cascade_deletes"`
+}
+
+// Kinds of objects that can be referred to by others, see LogicalStore.References.
+var referredKinds = map[string]bool{
+	ObjectMyStation:    true,
+	ObjectNextHop:      true,
+	ObjectNextHopGroup: true,
+}
+
+// Returns the default config.
+func DefaultConfig() *Config {
+	return &Config{}
+}
+
+// Returns an error if the config is invalid.
+func (c *Config) Validate() error {
+	for _, k := range c.CascadeDeletes {
+		if !referredKinds[k] {
+			return fmt.Errorf("cascade_deletes: %q is not one of %s, %s or %s", k, ObjectMyStation, ObjectNextHop,
+				ObjectNextHopGroup)
+		}
+	}
+	return nil
+}
+
+// Returns true if deletes of objects of the given kind cascade to the objects referring to them.
+func (c *Config) cascades(kind string) bool {
+	for _, k := range c.CascadeDeletes {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
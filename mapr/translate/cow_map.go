@@ -0,0 +1,152 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package translate
+
+import (
+	"sync/atomic"
+)
+
+// Copy-on-write maps, holding the entities of the P4RtStore and the objects of the LogicalStore.
+//
+// A cowMap is split into shards by key hash. Snapshots share the shards with the store, which copies each shard the
+// first time it modifies it after a snapshot. Hence, taking a snapshot is O(shards), and the first write to a shard
+// after a snapshot is O(n/shards), rather than O(n) for copying whole maps. Maps start with one shard, and the number
+// of shards doubles as they grow, up to cowMaxShards.
+//
+// Like the stores, maps are modified by a single goroutine, which can also read them without locking. Snapshots are
+// taken from other goroutines, hence they only read the map, except for its generation, which lookups never read:
+// snapshots and modifications are serialized by the stores, lookups may run concurrently with snapshots.
+
+const (
+	// Number of keys per shard above which the number of shards of a map doubles.
+	cowShardSize = 64
+	// Maximum number of shards of a map.
+	cowMaxShards = 1024
+)
+
+// Last generation given to a map, see cowMap.gen.
+var cowGeneration uint64
+
+type cowShard struct {
+	// Generation of the map that created the shard, the only one that may modify it in place.
+	gen     uint64
+	entries map[string]interface{}
+}
+
+// A map with string keys, sharing its shards with its copies. The zero value is an empty map.
+type cowMap struct {
+	// Identifies the shards created by this map, which it may modify in place. Other shards are shared with copies.
+	gen    uint64
+	len    int
+	shards []*cowShard
+}
+
+// Returns a read-only copy of the map, sharing its shards, and gives the map a new generation, so that it copies the
+// shared shards before modifying them.
+func (m *cowMap) snapshot() cowMap {
+	shards := make([]*cowShard, len(m.shards))
+	copy(shards, m.shards)
+	m.gen = atomic.AddUint64(&cowGeneration, 1)
+	return cowMap{len: m.len, shards: shards}
+}
+
+// Returns a copy of the map, sharing its shards, which the copy copies before modifying them. The map must not be
+// modified anymore, e.g., a bucket of a table entry index shared with a snapshot.
+func (m *cowMap) clone() cowMap {
+	shards := make([]*cowShard, len(m.shards))
+	copy(shards, m.shards)
+	return cowMap{
+		gen:    atomic.AddUint64(&cowGeneration, 1),
+		len:    m.len,
+		shards: shards,
+	}
+}
+
+// FNV-1a.
+func cowHash(k string) uint32 {
+	h := uint32(2166136261)
+	for i := 0; i < len(k); i++ {
+		h ^= uint32(k[i])
+		h *= 16777619
+	}
+	return h
+}
+
+func (m *cowMap) shard(k string) int {
+	return int(cowHash(k) & uint32(len(m.shards)-1))
+}
+
+// Returns the shard with the given index, copying it first if shared.
+func (m *cowMap) own(i int) *cowShard {
+	s := m.shards[i]
+	if s.gen == m.gen {
+		return s
+	}
+	c := &cowShard{gen: m.gen, entries: make(map[string]interface{}, len(s.entries))}
+	for k, v := range s.entries {
+		c.entries[k] = v
+	}
+	m.shards[i] = c
+	return c
+}
+
+func (m *cowMap) get(k string) (interface{}, bool) {
+	if m.len == 0 {
+		return nil, false
+	}
+	v, ok := m.shards[m.shard(k)].entries[k]
+	return v, ok
+}
+
+func (m *cowMap) put(k string, v interface{}) {
+	if len(m.shards) == 0 {
+		m.shards = []*cowShard{{gen: m.gen, entries: make(map[string]interface{})}}
+	}
+	s := m.own(m.shard(k))
+	if _, ok := s.entries[k]; !ok {
+		m.len++
+	}
+	s.entries[k] = v
+	if m.len > cowShardSize*len(m.shards) && len(m.shards) < cowMaxShards {
+		m.reshard(2 * len(m.shards))
+	}
+}
+
+func (m *cowMap) delete(k string) {
+	if m.len == 0 {
+		return
+	}
+	i := m.shard(k)
+	if _, ok := m.shards[i].entries[k]; !ok {
+		return
+	}
+	delete(m.own(i).entries, k)
+	m.len--
+}
+
+// Calls f for each entry, in no particular order.
+func (m *cowMap) each(f func(k string, v interface{})) {
+	for _, s := range m.shards {
+		for k, v := range s.entries {
+			f(k, v)
+		}
+	}
+}
+
+// Redistributes the entries in n new shards, n being a power of 2.
+func (m *cowMap) reshard(n int) {
+	shards := make([]*cowShard, n)
+	for i := range shards {
+		shards[i] = &cowShard{gen: m.gen, entries: make(map[string]interface{}, m.len/n)}
+	}
+	for _, s := range m.shards {
+		for k, v := range s.entries {
+			shards[cowHash(k)&uint32(n-1)].entries[k] = v
+		}
+	}
+	m.shards = shards
+}
@@ -0,0 +1,63 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package translate
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_cowMap(t *testing.T) {
+	var m cowMap
+	for i := 0; i < 1000; i++ {
+		m.put(fmt.Sprint(i), i)
+	}
+	m.put("1", -1)
+	m.delete("2")
+	m.delete("missing")
+
+	assert.Equal(t, 999, m.len)
+	v, ok := m.get("1")
+	assert.True(t, ok)
+	assert.Equal(t, -1, v)
+	_, ok = m.get("2")
+	assert.False(t, ok)
+	n := 0
+	m.each(func(string, interface{}) { n++ })
+	assert.Equal(t, 999, n)
+	assert.Equal(t, 16, len(m.shards), "shards should grow with the map")
+}
+
+func Test_cowMap_clone(t *testing.T) {
+	var m cowMap
+	for i := 0; i < 1000; i++ {
+		m.put(fmt.Sprint(i), i)
+	}
+	c := m.clone()
+	c.put("1", -1)
+	c.delete("2")
+	c.put("new", 0)
+
+	v, _ := m.get("1")
+	assert.Equal(t, 1, v, "original should not see modified entries")
+	_, ok := m.get("2")
+	assert.True(t, ok, "original should not see removed entries")
+	_, ok = m.get("new")
+	assert.False(t, ok, "original should not see new entries")
+	assert.Equal(t, 1000, m.len)
+	assert.Equal(t, 1000, c.len)
+
+	// Only the shards modified by the copy are copied.
+	copied := 0
+	for i := range m.shards {
+		if m.shards[i] != c.shards[i] {
+			copied++
+		}
+	}
+	assert.LessOrEqual(t, copied, 3)
+}
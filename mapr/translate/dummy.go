@@ -26,3 +26,21 @@ func (d dummyTranslator) Translate(u *p4v1.Update) ([]*p4v1.Update, error) {
 func (d dummyTranslator) ApplyUpdate(*p4v1.Update, []*p4v1.Update) error {
 	return nil
 }
+
+func (d dummyTranslator) Reset() {
+}
+
+// Returns no updates, as the target state is the same as the logical one.
+func (d dummyTranslator) Reconcile(P4RtStore) ([]*p4v1.Update, error) {
+	return nil, nil
+}
+
+// Returns no updates, as references between logical objects are not tracked.
+func (d dummyTranslator) Cascade(*p4v1.Update) ([]*p4v1.Update, error) {
+	return nil, nil
+}
+
+// Returns no updates, as objects are never pending.
+func (d dummyTranslator) Ready() []*p4v1.Update {
+	return nil
+}
@@ -0,0 +1,498 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package translate
+
+import (
+	"encoding/binary"
+	"strings"
+)
+
+// The maps of the LogicalStore: typed views of cowMaps, shared with snapshots of the store. Absent keys map to the zero
+// value of the value type, e.g., nil.
+
+// Separates the kind and key of an ObjectId in map keys. Kinds are constants that do not contain it.
+const objectIdSeparator = "\x00"
+
+func (id ObjectId) mapKey() string {
+	return id.Kind + objectIdSeparator + id.Key
+}
+
+func objectIdOf(s string) ObjectId {
+	i := strings.Index(s, objectIdSeparator)
+	return ObjectId{Kind: s[:i], Key: s[i+len(objectIdSeparator):]}
+}
+
+func portKeyOf(s string) PortKey {
+	var k PortKey
+	copy(k[:], s)
+	return k
+}
+
+func lineIdKeyOf(s string) LineIdKey {
+	var k LineIdKey
+	copy(k[:], s)
+	return k
+}
+
+func uint32MapKey(k uint32) string {
+	var buf [4]byte
+	return string(appendUint32(buf[:0], k))
+}
+
+func uint32Of(s string) uint32 {
+	return binary.BigEndian.Uint32([]byte(s))
+}
+
+// A map of the interface types of ports.
+type IfTypeMap struct {
+	m cowMap
+}
+
+func (x *IfTypeMap) Get(k PortKey) *IfTypeEntry {
+	v, _ := x.m.get(string(k[:]))
+	e, _ := v.(*IfTypeEntry)
+	return e
+}
+
+func (x *IfTypeMap) Put(k PortKey, v *IfTypeEntry) {
+	x.m.put(string(k[:]), v)
+}
+
+func (x *IfTypeMap) Delete(k PortKey) {
+	x.m.delete(string(k[:]))
+}
+
+func (x *IfTypeMap) Len() int {
+	return x.m.len
+}
+
+// Calls f for each entry, in no particular order.
+func (x *IfTypeMap) Range(f func(k PortKey, v *IfTypeEntry)) {
+	x.m.each(func(s string, v interface{}) {
+		f(portKeyOf(s), v.(*IfTypeEntry))
+	})
+}
+
+// A map of the MyStation entries of ports.
+type MyStationMap struct {
+	m cowMap
+}
+
+func (x *MyStationMap) Get(k PortKey) *MyStationEntry {
+	v, _ := x.m.get(string(k[:]))
+	e, _ := v.(*MyStationEntry)
+	return e
+}
+
+func (x *MyStationMap) Put(k PortKey, v *MyStationEntry) {
+	x.m.put(string(k[:]), v)
+}
+
+func (x *MyStationMap) Delete(k PortKey) {
+	x.m.delete(string(k[:]))
+}
+
+func (x *MyStationMap) Len() int {
+	return x.m.len
+}
+
+// Calls f for each entry, in no particular order.
+func (x *MyStationMap) Range(f func(k PortKey, v *MyStationEntry)) {
+	x.m.each(func(s string, v interface{}) {
+		f(portKeyOf(s), v.(*MyStationEntry))
+	})
+}
+
+// A map of ACL entries.
+type AclMap struct {
+	m cowMap
+}
+
+func (x *AclMap) Get(k AclKey) *AclEntry {
+	v, _ := x.m.get(string(k))
+	e, _ := v.(*AclEntry)
+	return e
+}
+
+func (x *AclMap) Put(k AclKey, v *AclEntry) {
+	x.m.put(string(k), v)
+}
+
+func (x *AclMap) Delete(k AclKey) {
+	x.m.delete(string(k))
+}
+
+func (x *AclMap) Len() int {
+	return x.m.len
+}
+
+// Calls f for each entry, in no particular order.
+func (x *AclMap) Range(f func(k AclKey, v *AclEntry)) {
+	x.m.each(func(s string, v interface{}) {
+		f(AclKey(s), v.(*AclEntry))
+	})
+}
+
+// A map of the punted PPPoE control packets.
+type PppoePuntedMap struct {
+	m cowMap
+}
+
+func (x *PppoePuntedMap) Get(k CtrlPuntedKey) *PppoePuntedEntry {
+	v, _ := x.m.get(string(k))
+	e, _ := v.(*PppoePuntedEntry)
+	return e
+}
+
+func (x *PppoePuntedMap) Put(k CtrlPuntedKey, v *PppoePuntedEntry) {
+	x.m.put(string(k), v)
+}
+
+func (x *PppoePuntedMap) Delete(k CtrlPuntedKey) {
+	x.m.delete(string(k))
+}
+
+func (x *PppoePuntedMap) Len() int {
+	return x.m.len
+}
+
+// Calls f for each entry, in no particular order.
+func (x *PppoePuntedMap) Range(f func(k CtrlPuntedKey, v *PppoePuntedEntry)) {
+	x.m.each(func(s string, v interface{}) {
+		f(CtrlPuntedKey(s), v.(*PppoePuntedEntry))
+	})
+}
+
+// A map of the attachments of one direction, by line ID.
+type AttachmentMap struct {
+	m cowMap
+}
+
+func (x *AttachmentMap) Get(k LineIdKey) *AttachmentEntry {
+	v, _ := x.m.get(string(k[:]))
+	e, _ := v.(*AttachmentEntry)
+	return e
+}
+
+func (x *AttachmentMap) Put(k LineIdKey, v *AttachmentEntry) {
+	x.m.put(string(k[:]), v)
+}
+
+func (x *AttachmentMap) Delete(k LineIdKey) {
+	x.m.delete(string(k[:]))
+}
+
+func (x *AttachmentMap) Len() int {
+	return x.m.len
+}
+
+// Calls f for each entry, in no particular order.
+func (x *AttachmentMap) Range(f func(k LineIdKey, v *AttachmentEntry)) {
+	x.m.each(func(s string, v interface{}) {
+		f(lineIdKeyOf(s), v.(*AttachmentEntry))
+	})
+}
+
+// A map of IPv4 routes, by prefix.
+type RouteV4Map struct {
+	m cowMap
+}
+
+func (x *RouteV4Map) Get(k Ipv4LpmKey) *RouteV4Entry {
+	v, _ := x.m.get(string(k))
+	e, _ := v.(*RouteV4Entry)
+	return e
+}
+
+func (x *RouteV4Map) Put(k Ipv4LpmKey, v *RouteV4Entry) {
+	x.m.put(string(k), v)
+}
+
+func (x *RouteV4Map) Delete(k Ipv4LpmKey) {
+	x.m.delete(string(k))
+}
+
+func (x *RouteV4Map) Len() int {
+	return x.m.len
+}
+
+// Calls f for each entry, in no particular order.
+func (x *RouteV4Map) Range(f func(k Ipv4LpmKey, v *RouteV4Entry)) {
+	x.m.each(func(s string, v interface{}) {
+		f(Ipv4LpmKey(s), v.(*RouteV4Entry))
+	})
+}
+
+// A map of next hop groups, by group ID.
+type NextHopGroupMap struct {
+	m cowMap
+}
+
+func (x *NextHopGroupMap) Get(k uint32) *NextHopGroup {
+	v, _ := x.m.get(uint32MapKey(k))
+	e, _ := v.(*NextHopGroup)
+	return e
+}
+
+func (x *NextHopGroupMap) Put(k uint32, v *NextHopGroup) {
+	x.m.put(uint32MapKey(k), v)
+}
+
+func (x *NextHopGroupMap) Delete(k uint32) {
+	x.m.delete(uint32MapKey(k))
+}
+
+func (x *NextHopGroupMap) Len() int {
+	return x.m.len
+}
+
+// Calls f for each entry, in no particular order.
+func (x *NextHopGroupMap) Range(f func(k uint32, v *NextHopGroup)) {
+	x.m.each(func(s string, v interface{}) {
+		f(uint32Of(s), v.(*NextHopGroup))
+	})
+}
+
+// A map of next hops, by ID.
+type NextHopEntryMap struct {
+	m cowMap
+}
+
+func (x *NextHopEntryMap) Get(k uint32) *NextHopEntry {
+	v, _ := x.m.get(uint32MapKey(k))
+	e, _ := v.(*NextHopEntry)
+	return e
+}
+
+func (x *NextHopEntryMap) Put(k uint32, v *NextHopEntry) {
+	x.m.put(uint32MapKey(k), v)
+}
+
+func (x *NextHopEntryMap) Delete(k uint32) {
+	x.m.delete(uint32MapKey(k))
+}
+
+func (x *NextHopEntryMap) Len() int {
+	return x.m.len
+}
+
+// Calls f for each entry, in no particular order.
+func (x *NextHopEntryMap) Range(f func(k uint32, v *NextHopEntry)) {
+	x.m.each(func(s string, v interface{}) {
+		f(uint32Of(s), v.(*NextHopEntry))
+	})
+}
+
+// A map of the refs of the attachments by index key, see AttachmentsByIndex.
+type AttachmentIndexMap struct {
+	m cowMap
+}
+
+func (x *AttachmentIndexMap) Get(k AttachmentIndexKey) []AttachmentRef {
+	v, _ := x.m.get(string(k))
+	e, _ := v.([]AttachmentRef)
+	return e
+}
+
+func (x *AttachmentIndexMap) Put(k AttachmentIndexKey, v []AttachmentRef) {
+	x.m.put(string(k), v)
+}
+
+func (x *AttachmentIndexMap) Delete(k AttachmentIndexKey) {
+	x.m.delete(string(k))
+}
+
+func (x *AttachmentIndexMap) Len() int {
+	return x.m.len
+}
+
+// Calls f for each entry, in no particular order.
+func (x *AttachmentIndexMap) Range(f func(k AttachmentIndexKey, v []AttachmentRef)) {
+	x.m.each(func(s string, v interface{}) {
+		f(AttachmentIndexKey(s), v.([]AttachmentRef))
+	})
+}
+
+// A map of the keys of the target entities produced by each object.
+type ProducedMap struct {
+	m cowMap
+}
+
+func (x *ProducedMap) Get(k ObjectId) []EntityKey {
+	v, _ := x.m.get(k.mapKey())
+	e, _ := v.([]EntityKey)
+	return e
+}
+
+func (x *ProducedMap) Put(k ObjectId, v []EntityKey) {
+	x.m.put(k.mapKey(), v)
+}
+
+func (x *ProducedMap) Delete(k ObjectId) {
+	x.m.delete(k.mapKey())
+}
+
+func (x *ProducedMap) Len() int {
+	return x.m.len
+}
+
+// Calls f for each entry, in no particular order.
+func (x *ProducedMap) Range(f func(k ObjectId, v []EntityKey)) {
+	x.m.each(func(s string, v interface{}) {
+		f(objectIdOf(s), v.([]EntityKey))
+	})
+}
+
+// A map of the objects owning each target entity.
+type OwnersMap struct {
+	m cowMap
+}
+
+func (x *OwnersMap) Get(k EntityKey) []ObjectId {
+	v, _ := x.m.get(string(k))
+	e, _ := v.([]ObjectId)
+	return e
+}
+
+func (x *OwnersMap) Put(k EntityKey, v []ObjectId) {
+	x.m.put(string(k), v)
+}
+
+func (x *OwnersMap) Delete(k EntityKey) {
+	x.m.delete(string(k))
+}
+
+func (x *OwnersMap) Len() int {
+	return x.m.len
+}
+
+// Calls f for each entry, in no particular order.
+func (x *OwnersMap) Range(f func(k EntityKey, v []ObjectId)) {
+	x.m.each(func(s string, v interface{}) {
+		f(EntityKey(s), v.([]ObjectId))
+	})
+}
+
+// A map of the references of each object referring to others.
+type ReferencesMap struct {
+	m cowMap
+}
+
+func (x *ReferencesMap) Get(k ObjectId) *References {
+	v, _ := x.m.get(k.mapKey())
+	e, _ := v.(*References)
+	return e
+}
+
+func (x *ReferencesMap) Put(k ObjectId, v *References) {
+	x.m.put(k.mapKey(), v)
+}
+
+func (x *ReferencesMap) Delete(k ObjectId) {
+	x.m.delete(k.mapKey())
+}
+
+func (x *ReferencesMap) Len() int {
+	return x.m.len
+}
+
+// Calls f for each entry, in no particular order.
+func (x *ReferencesMap) Range(f func(k ObjectId, v *References)) {
+	x.m.each(func(s string, v interface{}) {
+		f(objectIdOf(s), v.(*References))
+	})
+}
+
+// A map of the objects referring to each object.
+type ReferrersMap struct {
+	m cowMap
+}
+
+func (x *ReferrersMap) Get(k ObjectId) []ObjectId {
+	v, _ := x.m.get(k.mapKey())
+	e, _ := v.([]ObjectId)
+	return e
+}
+
+func (x *ReferrersMap) Put(k ObjectId, v []ObjectId) {
+	x.m.put(k.mapKey(), v)
+}
+
+func (x *ReferrersMap) Delete(k ObjectId) {
+	x.m.delete(k.mapKey())
+}
+
+func (x *ReferrersMap) Len() int {
+	return x.m.len
+}
+
+// Calls f for each entry, in no particular order.
+func (x *ReferrersMap) Range(f func(k ObjectId, v []ObjectId)) {
+	x.m.each(func(s string, v interface{}) {
+		f(objectIdOf(s), v.([]ObjectId))
+	})
+}
+
+// A map of the objects that are not translated yet.
+type PendingMap struct {
+	m cowMap
+}
+
+func (x *PendingMap) Get(k ObjectId) *PendingObject {
+	v, _ := x.m.get(k.mapKey())
+	e, _ := v.(*PendingObject)
+	return e
+}
+
+func (x *PendingMap) Put(k ObjectId, v *PendingObject) {
+	x.m.put(k.mapKey(), v)
+}
+
+func (x *PendingMap) Delete(k ObjectId) {
+	x.m.delete(k.mapKey())
+}
+
+func (x *PendingMap) Len() int {
+	return x.m.len
+}
+
+// Calls f for each entry, in no particular order.
+func (x *PendingMap) Range(f func(k ObjectId, v *PendingObject)) {
+	x.m.each(func(s string, v interface{}) {
+		f(objectIdOf(s), v.(*PendingObject))
+	})
+}
+
+// A set of objects, mapped to true.
+type ObjectSet struct {
+	m cowMap
+}
+
+func (x *ObjectSet) Get(k ObjectId) bool {
+	v, _ := x.m.get(k.mapKey())
+	e, _ := v.(bool)
+	return e
+}
+
+func (x *ObjectSet) Put(k ObjectId, v bool) {
+	x.m.put(k.mapKey(), v)
+}
+
+func (x *ObjectSet) Delete(k ObjectId) {
+	x.m.delete(k.mapKey())
+}
+
+func (x *ObjectSet) Len() int {
+	return x.m.len
+}
+
+// Calls f for each entry, in no particular order.
+func (x *ObjectSet) Range(f func(k ObjectId, v bool)) {
+	x.m.each(func(s string, v interface{}) {
+		f(objectIdOf(s), v.(bool))
+	})
+}
@@ -0,0 +1,1288 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Code generated by mapr/codec/gen from ../../p4src/build/p4info.txt. DO NOT EDIT.
+
+package translate
+
+import (
+	"fmt"
+	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
+	"mapr/codec"
+)
+
+// Action nop
+type NopAction struct {
+}
+
+func (a *NopAction) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_Nop}
+	return act
+}
+
+func (a *NopAction) FromAction(act *p4v1.Action) error {
+	*a = NopAction{}
+	if act == nil || act.ActionId != Action_Nop {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	if len(act.Params) > 0 {
+		return fmt.Errorf("invalid %T ID %d", act.Params[0], act.Params[0].ParamId)
+	}
+	return nil
+}
+
+// Action drop_now
+type DropNowAction struct {
+}
+
+func (a *DropNowAction) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_DropNow}
+	return act
+}
+
+func (a *DropNowAction) FromAction(act *p4v1.Action) error {
+	*a = DropNowAction{}
+	if act == nil || act.ActionId != Action_DropNow {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	if len(act.Params) > 0 {
+		return fmt.Errorf("invalid %T ID %d", act.Params[0], act.Params[0].ParamId)
+	}
+	return nil
+}
+
+// Action IngressPipe.set_if_type
+type IngressPipeSetIfTypeAction struct {
+	IfType []byte // bitwidth 3
+}
+
+func (a *IngressPipeSetIfTypeAction) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_IngressPipeSetIfType}
+	if a.IfType != nil {
+		act.Params = append(act.Params, &p4v1.Action_Param{ParamId: ActionParam_IngressPipeSetIfType_IfType, Value: a.IfType})
+	}
+	return act
+}
+
+func (a *IngressPipeSetIfTypeAction) FromAction(act *p4v1.Action) error {
+	*a = IngressPipeSetIfTypeAction{}
+	if act == nil || act.ActionId != Action_IngressPipeSetIfType {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	for _, p := range act.Params {
+		switch p.ParamId {
+		case ActionParam_IngressPipeSetIfType_IfType:
+			a.IfType = p.Value
+		default:
+			return fmt.Errorf("invalid %T ID %d", p, p.ParamId)
+		}
+	}
+	return nil
+}
+
+// Action IngressPipe.set_my_station
+type IngressPipeSetMyStationAction struct {
+}
+
+func (a *IngressPipeSetMyStationAction) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_IngressPipeSetMyStation}
+	return act
+}
+
+func (a *IngressPipeSetMyStationAction) FromAction(act *p4v1.Action) error {
+	*a = IngressPipeSetMyStationAction{}
+	if act == nil || act.ActionId != Action_IngressPipeSetMyStation {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	if len(act.Params) > 0 {
+		return fmt.Errorf("invalid %T ID %d", act.Params[0], act.Params[0].ParamId)
+	}
+	return nil
+}
+
+// Action IngressPipe.set_accounting_id
+type IngressPipeSetAccountingIdAction struct {
+	AccountingId []byte // bitwidth 32
+}
+
+func (a *IngressPipeSetAccountingIdAction) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_IngressPipeSetAccountingId}
+	if a.AccountingId != nil {
+		act.Params = append(act.Params, &p4v1.Action_Param{ParamId: ActionParam_IngressPipeSetAccountingId_AccountingId, Value: a.AccountingId})
+	}
+	return act
+}
+
+func (a *IngressPipeSetAccountingIdAction) FromAction(act *p4v1.Action) error {
+	*a = IngressPipeSetAccountingIdAction{}
+	if act == nil || act.ActionId != Action_IngressPipeSetAccountingId {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	for _, p := range act.Params {
+		switch p.ParamId {
+		case ActionParam_IngressPipeSetAccountingId_AccountingId:
+			a.AccountingId = p.Value
+		default:
+			return fmt.Errorf("invalid %T ID %d", p, p.ParamId)
+		}
+	}
+	return nil
+}
+
+// Action IngressPipe.upstream.set_line
+type IngressPipeUpstreamSetLineAction struct {
+	LineId []byte // bitwidth 32
+}
+
+func (a *IngressPipeUpstreamSetLineAction) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_IngressPipeUpstreamSetLine}
+	if a.LineId != nil {
+		act.Params = append(act.Params, &p4v1.Action_Param{ParamId: ActionParam_IngressPipeUpstreamSetLine_LineId, Value: a.LineId})
+	}
+	return act
+}
+
+func (a *IngressPipeUpstreamSetLineAction) FromAction(act *p4v1.Action) error {
+	*a = IngressPipeUpstreamSetLineAction{}
+	if act == nil || act.ActionId != Action_IngressPipeUpstreamSetLine {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	for _, p := range act.Params {
+		switch p.ParamId {
+		case ActionParam_IngressPipeUpstreamSetLine_LineId:
+			a.LineId = p.Value
+		default:
+			return fmt.Errorf("invalid %T ID %d", p, p.ParamId)
+		}
+	}
+	return nil
+}
+
+// Action IngressPipe.upstream.punt
+type IngressPipeUpstreamPuntAction struct {
+}
+
+func (a *IngressPipeUpstreamPuntAction) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_IngressPipeUpstreamPunt}
+	return act
+}
+
+func (a *IngressPipeUpstreamPuntAction) FromAction(act *p4v1.Action) error {
+	*a = IngressPipeUpstreamPuntAction{}
+	if act == nil || act.ActionId != Action_IngressPipeUpstreamPunt {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	if len(act.Params) > 0 {
+		return fmt.Errorf("invalid %T ID %d", act.Params[0], act.Params[0].ParamId)
+	}
+	return nil
+}
+
+// Action IngressPipe.upstream.reject
+type IngressPipeUpstreamRejectAction struct {
+}
+
+func (a *IngressPipeUpstreamRejectAction) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_IngressPipeUpstreamReject}
+	return act
+}
+
+func (a *IngressPipeUpstreamRejectAction) FromAction(act *p4v1.Action) error {
+	*a = IngressPipeUpstreamRejectAction{}
+	if act == nil || act.ActionId != Action_IngressPipeUpstreamReject {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	if len(act.Params) > 0 {
+		return fmt.Errorf("invalid %T ID %d", act.Params[0], act.Params[0].ParamId)
+	}
+	return nil
+}
+
+// Action IngressPipe.upstream.route_v4
+type IngressPipeUpstreamRouteV4Action struct {
+	Port []byte // bitwidth 9
+	Dmac []byte // bitwidth 48
+}
+
+func (a *IngressPipeUpstreamRouteV4Action) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_IngressPipeUpstreamRouteV4}
+	if a.Port != nil {
+		act.Params = append(act.Params, &p4v1.Action_Param{ParamId: ActionParam_IngressPipeUpstreamRouteV4_Port, Value: a.Port})
+	}
+	if a.Dmac != nil {
+		act.Params = append(act.Params, &p4v1.Action_Param{ParamId: ActionParam_IngressPipeUpstreamRouteV4_Dmac, Value: a.Dmac})
+	}
+	return act
+}
+
+func (a *IngressPipeUpstreamRouteV4Action) FromAction(act *p4v1.Action) error {
+	*a = IngressPipeUpstreamRouteV4Action{}
+	if act == nil || act.ActionId != Action_IngressPipeUpstreamRouteV4 {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	for _, p := range act.Params {
+		switch p.ParamId {
+		case ActionParam_IngressPipeUpstreamRouteV4_Port:
+			a.Port = p.Value
+		case ActionParam_IngressPipeUpstreamRouteV4_Dmac:
+			a.Dmac = p.Value
+		default:
+			return fmt.Errorf("invalid %T ID %d", p, p.ParamId)
+		}
+	}
+	return nil
+}
+
+// Action IngressPipe.upstream.cos.set_cos_id
+type IngressPipeUpstreamCosSetCosIdAction struct {
+	CosId []byte // bitwidth 32
+}
+
+func (a *IngressPipeUpstreamCosSetCosIdAction) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_IngressPipeUpstreamCosSetCosId}
+	if a.CosId != nil {
+		act.Params = append(act.Params, &p4v1.Action_Param{ParamId: ActionParam_IngressPipeUpstreamCosSetCosId_CosId, Value: a.CosId})
+	}
+	return act
+}
+
+func (a *IngressPipeUpstreamCosSetCosIdAction) FromAction(act *p4v1.Action) error {
+	*a = IngressPipeUpstreamCosSetCosIdAction{}
+	if act == nil || act.ActionId != Action_IngressPipeUpstreamCosSetCosId {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	for _, p := range act.Params {
+		switch p.ParamId {
+		case ActionParam_IngressPipeUpstreamCosSetCosId_CosId:
+			a.CosId = p.Value
+		default:
+			return fmt.Errorf("invalid %T ID %d", p, p.ParamId)
+		}
+	}
+	return nil
+}
+
+// Action IngressPipe.downstream.miss
+type IngressPipeDownstreamMissAction struct {
+}
+
+func (a *IngressPipeDownstreamMissAction) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_IngressPipeDownstreamMiss}
+	return act
+}
+
+func (a *IngressPipeDownstreamMissAction) FromAction(act *p4v1.Action) error {
+	*a = IngressPipeDownstreamMissAction{}
+	if act == nil || act.ActionId != Action_IngressPipeDownstreamMiss {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	if len(act.Params) > 0 {
+		return fmt.Errorf("invalid %T ID %d", act.Params[0], act.Params[0].ParamId)
+	}
+	return nil
+}
+
+// Action IngressPipe.downstream.set_line
+type IngressPipeDownstreamSetLineAction struct {
+	LineId []byte // bitwidth 32
+}
+
+func (a *IngressPipeDownstreamSetLineAction) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_IngressPipeDownstreamSetLine}
+	if a.LineId != nil {
+		act.Params = append(act.Params, &p4v1.Action_Param{ParamId: ActionParam_IngressPipeDownstreamSetLine_LineId, Value: a.LineId})
+	}
+	return act
+}
+
+func (a *IngressPipeDownstreamSetLineAction) FromAction(act *p4v1.Action) error {
+	*a = IngressPipeDownstreamSetLineAction{}
+	if act == nil || act.ActionId != Action_IngressPipeDownstreamSetLine {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	for _, p := range act.Params {
+		switch p.ParamId {
+		case ActionParam_IngressPipeDownstreamSetLine_LineId:
+			a.LineId = p.Value
+		default:
+			return fmt.Errorf("invalid %T ID %d", p, p.ParamId)
+		}
+	}
+	return nil
+}
+
+// Action IngressPipe.downstream.set_pppoe_attachment_v4
+type IngressPipeDownstreamSetPppoeAttachmentV4Action struct {
+	Port        []byte // bitwidth 9
+	Dmac        []byte // bitwidth 48
+	STag        []byte // bitwidth 12
+	CTag        []byte // bitwidth 12
+	PppoeSessId []byte // bitwidth 16
+}
+
+func (a *IngressPipeDownstreamSetPppoeAttachmentV4Action) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_IngressPipeDownstreamSetPppoeAttachmentV4}
+	if a.Port != nil {
+		act.Params = append(act.Params, &p4v1.Action_Param{ParamId: ActionParam_IngressPipeDownstreamSetPppoeAttachmentV4_Port, Value: a.Port})
+	}
+	if a.Dmac != nil {
+		act.Params = append(act.Params, &p4v1.Action_Param{ParamId: ActionParam_IngressPipeDownstreamSetPppoeAttachmentV4_Dmac, Value: a.Dmac})
+	}
+	if a.STag != nil {
+		act.Params = append(act.Params, &p4v1.Action_Param{ParamId: ActionParam_IngressPipeDownstreamSetPppoeAttachmentV4_STag, Value: a.STag})
+	}
+	if a.CTag != nil {
+		act.Params = append(act.Params, &p4v1.Action_Param{ParamId: ActionParam_IngressPipeDownstreamSetPppoeAttachmentV4_CTag, Value: a.CTag})
+	}
+	if a.PppoeSessId != nil {
+		act.Params = append(act.Params, &p4v1.Action_Param{ParamId: ActionParam_IngressPipeDownstreamSetPppoeAttachmentV4_PppoeSessId, Value: a.PppoeSessId})
+	}
+	return act
+}
+
+func (a *IngressPipeDownstreamSetPppoeAttachmentV4Action) FromAction(act *p4v1.Action) error {
+	*a = IngressPipeDownstreamSetPppoeAttachmentV4Action{}
+	if act == nil || act.ActionId != Action_IngressPipeDownstreamSetPppoeAttachmentV4 {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	for _, p := range act.Params {
+		switch p.ParamId {
+		case ActionParam_IngressPipeDownstreamSetPppoeAttachmentV4_Port:
+			a.Port = p.Value
+		case ActionParam_IngressPipeDownstreamSetPppoeAttachmentV4_Dmac:
+			a.Dmac = p.Value
+		case ActionParam_IngressPipeDownstreamSetPppoeAttachmentV4_STag:
+			a.STag = p.Value
+		case ActionParam_IngressPipeDownstreamSetPppoeAttachmentV4_CTag:
+			a.CTag = p.Value
+		case ActionParam_IngressPipeDownstreamSetPppoeAttachmentV4_PppoeSessId:
+			a.PppoeSessId = p.Value
+		default:
+			return fmt.Errorf("invalid %T ID %d", p, p.ParamId)
+		}
+	}
+	return nil
+}
+
+// Action IngressPipe.downstream.cos.set_cos_id
+type IngressPipeDownstreamCosSetCosIdAction struct {
+	CosId []byte // bitwidth 32
+}
+
+func (a *IngressPipeDownstreamCosSetCosIdAction) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_IngressPipeDownstreamCosSetCosId}
+	if a.CosId != nil {
+		act.Params = append(act.Params, &p4v1.Action_Param{ParamId: ActionParam_IngressPipeDownstreamCosSetCosId_CosId, Value: a.CosId})
+	}
+	return act
+}
+
+func (a *IngressPipeDownstreamCosSetCosIdAction) FromAction(act *p4v1.Action) error {
+	*a = IngressPipeDownstreamCosSetCosIdAction{}
+	if act == nil || act.ActionId != Action_IngressPipeDownstreamCosSetCosId {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	for _, p := range act.Params {
+		switch p.ParamId {
+		case ActionParam_IngressPipeDownstreamCosSetCosId_CosId:
+			a.CosId = p.Value
+		default:
+			return fmt.Errorf("invalid %T ID %d", p, p.ParamId)
+		}
+	}
+	return nil
+}
+
+// Action IngressPipe.acl.set_port
+type IngressPipeAclSetPortAction struct {
+	Port []byte // bitwidth 9
+}
+
+func (a *IngressPipeAclSetPortAction) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_IngressPipeAclSetPort}
+	if a.Port != nil {
+		act.Params = append(act.Params, &p4v1.Action_Param{ParamId: ActionParam_IngressPipeAclSetPort_Port, Value: a.Port})
+	}
+	return act
+}
+
+func (a *IngressPipeAclSetPortAction) FromAction(act *p4v1.Action) error {
+	*a = IngressPipeAclSetPortAction{}
+	if act == nil || act.ActionId != Action_IngressPipeAclSetPort {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	for _, p := range act.Params {
+		switch p.ParamId {
+		case ActionParam_IngressPipeAclSetPort_Port:
+			a.Port = p.Value
+		default:
+			return fmt.Errorf("invalid %T ID %d", p, p.ParamId)
+		}
+	}
+	return nil
+}
+
+// Action IngressPipe.acl.punt
+type IngressPipeAclPuntAction struct {
+}
+
+func (a *IngressPipeAclPuntAction) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_IngressPipeAclPunt}
+	return act
+}
+
+func (a *IngressPipeAclPuntAction) FromAction(act *p4v1.Action) error {
+	*a = IngressPipeAclPuntAction{}
+	if act == nil || act.ActionId != Action_IngressPipeAclPunt {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	if len(act.Params) > 0 {
+		return fmt.Errorf("invalid %T ID %d", act.Params[0], act.Params[0].ParamId)
+	}
+	return nil
+}
+
+// Action IngressPipe.acl.drop
+type IngressPipeAclDropAction struct {
+}
+
+func (a *IngressPipeAclDropAction) ToAction() *p4v1.Action {
+	act := &p4v1.Action{ActionId: Action_IngressPipeAclDrop}
+	return act
+}
+
+func (a *IngressPipeAclDropAction) FromAction(act *p4v1.Action) error {
+	*a = IngressPipeAclDropAction{}
+	if act == nil || act.ActionId != Action_IngressPipeAclDrop {
+		return fmt.Errorf("invalid Action %s", act)
+	}
+	if len(act.Params) > 0 {
+		return fmt.Errorf("invalid %T ID %d", act.Params[0], act.Params[0].ParamId)
+	}
+	return nil
+}
+
+// Table IngressPipe.if_types
+type IngressPipeIfTypesEntry struct {
+	Port []byte // exact, bitwidth 9
+	// One of: IngressPipeSetIfTypeAction
+	Action codec.Action
+}
+
+func (e *IngressPipeIfTypesEntry) ToTableEntry() *p4v1.TableEntry {
+	t := &p4v1.TableEntry{TableId: Table_IngressPipeIfTypes}
+	if e.Port != nil {
+		t.Match = append(t.Match, codec.ExactMatch(Hdr_IngressPipeIfTypes_Port, e.Port))
+	}
+	t.Action = codec.DirectTableAction(e.Action)
+	return t
+}
+
+func (e *IngressPipeIfTypesEntry) FromTableEntry(t *p4v1.TableEntry) error {
+	*e = IngressPipeIfTypesEntry{}
+	if t.TableId != Table_IngressPipeIfTypes {
+		return fmt.Errorf("invalid table ID %d", t.TableId)
+	}
+	var err error
+	for _, m := range t.Match {
+		switch m.FieldId {
+		case Hdr_IngressPipeIfTypes_Port:
+			e.Port, err = codec.GetExact(m)
+		default:
+			err = fmt.Errorf("invalid %T ID %d", m, m.FieldId)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	act := t.GetAction().GetAction()
+	if act == nil {
+		if t.GetAction() != nil {
+			return fmt.Errorf("invalid Action %s", t.GetAction())
+		}
+		return nil
+	}
+	switch act.ActionId {
+	case Action_IngressPipeSetIfType:
+		e.Action = &IngressPipeSetIfTypeAction{}
+	default:
+		return fmt.Errorf("invalid Action %s", t.GetAction())
+	}
+	return e.Action.FromAction(act)
+}
+
+// Table IngressPipe.my_stations
+type IngressPipeMyStationsEntry struct {
+	Port   []byte // exact, bitwidth 9
+	EthDst []byte // exact, bitwidth 48
+	// One of: IngressPipeSetMyStationAction, NopAction
+	Action codec.Action
+}
+
+func (e *IngressPipeMyStationsEntry) ToTableEntry() *p4v1.TableEntry {
+	t := &p4v1.TableEntry{TableId: Table_IngressPipeMyStations}
+	if e.Port != nil {
+		t.Match = append(t.Match, codec.ExactMatch(Hdr_IngressPipeMyStations_Port, e.Port))
+	}
+	if e.EthDst != nil {
+		t.Match = append(t.Match, codec.ExactMatch(Hdr_IngressPipeMyStations_EthDst, e.EthDst))
+	}
+	t.Action = codec.DirectTableAction(e.Action)
+	return t
+}
+
+func (e *IngressPipeMyStationsEntry) FromTableEntry(t *p4v1.TableEntry) error {
+	*e = IngressPipeMyStationsEntry{}
+	if t.TableId != Table_IngressPipeMyStations {
+		return fmt.Errorf("invalid table ID %d", t.TableId)
+	}
+	var err error
+	for _, m := range t.Match {
+		switch m.FieldId {
+		case Hdr_IngressPipeMyStations_Port:
+			e.Port, err = codec.GetExact(m)
+		case Hdr_IngressPipeMyStations_EthDst:
+			e.EthDst, err = codec.GetExact(m)
+		default:
+			err = fmt.Errorf("invalid %T ID %d", m, m.FieldId)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	act := t.GetAction().GetAction()
+	if act == nil {
+		if t.GetAction() != nil {
+			return fmt.Errorf("invalid Action %s", t.GetAction())
+		}
+		return nil
+	}
+	switch act.ActionId {
+	case Action_IngressPipeSetMyStation:
+		e.Action = &IngressPipeSetMyStationAction{}
+	case Action_Nop:
+		e.Action = &NopAction{}
+	default:
+		return fmt.Errorf("invalid Action %s", t.GetAction())
+	}
+	return e.Action.FromAction(act)
+}
+
+// Table IngressPipe.accounting_ids
+type IngressPipeAccountingIdsEntry struct {
+	LineId []byte // exact, bitwidth 32
+	CosId  []byte // exact, bitwidth 32
+	// One of: IngressPipeSetAccountingIdAction
+	Action codec.Action
+}
+
+func (e *IngressPipeAccountingIdsEntry) ToTableEntry() *p4v1.TableEntry {
+	t := &p4v1.TableEntry{TableId: Table_IngressPipeAccountingIds}
+	if e.LineId != nil {
+		t.Match = append(t.Match, codec.ExactMatch(Hdr_IngressPipeAccountingIds_LineId, e.LineId))
+	}
+	if e.CosId != nil {
+		t.Match = append(t.Match, codec.ExactMatch(Hdr_IngressPipeAccountingIds_CosId, e.CosId))
+	}
+	t.Action = codec.DirectTableAction(e.Action)
+	return t
+}
+
+func (e *IngressPipeAccountingIdsEntry) FromTableEntry(t *p4v1.TableEntry) error {
+	*e = IngressPipeAccountingIdsEntry{}
+	if t.TableId != Table_IngressPipeAccountingIds {
+		return fmt.Errorf("invalid table ID %d", t.TableId)
+	}
+	var err error
+	for _, m := range t.Match {
+		switch m.FieldId {
+		case Hdr_IngressPipeAccountingIds_LineId:
+			e.LineId, err = codec.GetExact(m)
+		case Hdr_IngressPipeAccountingIds_CosId:
+			e.CosId, err = codec.GetExact(m)
+		default:
+			err = fmt.Errorf("invalid %T ID %d", m, m.FieldId)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	act := t.GetAction().GetAction()
+	if act == nil {
+		if t.GetAction() != nil {
+			return fmt.Errorf("invalid Action %s", t.GetAction())
+		}
+		return nil
+	}
+	switch act.ActionId {
+	case Action_IngressPipeSetAccountingId:
+		e.Action = &IngressPipeSetAccountingIdAction{}
+	default:
+		return fmt.Errorf("invalid Action %s", t.GetAction())
+	}
+	return e.Action.FromAction(act)
+}
+
+// Table IngressPipe.upstream.lines
+type IngressPipeUpstreamLinesEntry struct {
+	Port []byte // exact, bitwidth 9
+	CTag []byte // exact, bitwidth 12
+	STag []byte // exact, bitwidth 12
+	// One of: IngressPipeUpstreamSetLineAction
+	Action codec.Action
+}
+
+func (e *IngressPipeUpstreamLinesEntry) ToTableEntry() *p4v1.TableEntry {
+	t := &p4v1.TableEntry{TableId: Table_IngressPipeUpstreamLines}
+	if e.Port != nil {
+		t.Match = append(t.Match, codec.ExactMatch(Hdr_IngressPipeUpstreamLines_Port, e.Port))
+	}
+	if e.CTag != nil {
+		t.Match = append(t.Match, codec.ExactMatch(Hdr_IngressPipeUpstreamLines_CTag, e.CTag))
+	}
+	if e.STag != nil {
+		t.Match = append(t.Match, codec.ExactMatch(Hdr_IngressPipeUpstreamLines_STag, e.STag))
+	}
+	t.Action = codec.DirectTableAction(e.Action)
+	return t
+}
+
+func (e *IngressPipeUpstreamLinesEntry) FromTableEntry(t *p4v1.TableEntry) error {
+	*e = IngressPipeUpstreamLinesEntry{}
+	if t.TableId != Table_IngressPipeUpstreamLines {
+		return fmt.Errorf("invalid table ID %d", t.TableId)
+	}
+	var err error
+	for _, m := range t.Match {
+		switch m.FieldId {
+		case Hdr_IngressPipeUpstreamLines_Port:
+			e.Port, err = codec.GetExact(m)
+		case Hdr_IngressPipeUpstreamLines_CTag:
+			e.CTag, err = codec.GetExact(m)
+		case Hdr_IngressPipeUpstreamLines_STag:
+			e.STag, err = codec.GetExact(m)
+		default:
+			err = fmt.Errorf("invalid %T ID %d", m, m.FieldId)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	act := t.GetAction().GetAction()
+	if act == nil {
+		if t.GetAction() != nil {
+			return fmt.Errorf("invalid Action %s", t.GetAction())
+		}
+		return nil
+	}
+	switch act.ActionId {
+	case Action_IngressPipeUpstreamSetLine:
+		e.Action = &IngressPipeUpstreamSetLineAction{}
+	default:
+		return fmt.Errorf("invalid Action %s", t.GetAction())
+	}
+	return e.Action.FromAction(act)
+}
+
+// Table IngressPipe.upstream.pppoe_punts
+type IngressPipeUpstreamPppoePuntsEntry struct {
+	PppoeCode  []byte         // exact, bitwidth 8
+	PppoeProto *codec.Ternary // ternary, bitwidth 16
+	Priority   int32
+	// One of: IngressPipeUpstreamPuntAction, NopAction
+	Action codec.Action
+}
+
+func (e *IngressPipeUpstreamPppoePuntsEntry) ToTableEntry() *p4v1.TableEntry {
+	t := &p4v1.TableEntry{TableId: Table_IngressPipeUpstreamPppoePunts}
+	if e.PppoeCode != nil {
+		t.Match = append(t.Match, codec.ExactMatch(Hdr_IngressPipeUpstreamPppoePunts_PppoeCode, e.PppoeCode))
+	}
+	if e.PppoeProto != nil {
+		t.Match = append(t.Match, codec.TernaryMatch(Hdr_IngressPipeUpstreamPppoePunts_PppoeProto, e.PppoeProto))
+	}
+	t.Priority = e.Priority
+	t.Action = codec.DirectTableAction(e.Action)
+	return t
+}
+
+func (e *IngressPipeUpstreamPppoePuntsEntry) FromTableEntry(t *p4v1.TableEntry) error {
+	*e = IngressPipeUpstreamPppoePuntsEntry{}
+	if t.TableId != Table_IngressPipeUpstreamPppoePunts {
+		return fmt.Errorf("invalid table ID %d", t.TableId)
+	}
+	var err error
+	for _, m := range t.Match {
+		switch m.FieldId {
+		case Hdr_IngressPipeUpstreamPppoePunts_PppoeCode:
+			e.PppoeCode, err = codec.GetExact(m)
+		case Hdr_IngressPipeUpstreamPppoePunts_PppoeProto:
+			e.PppoeProto, err = codec.GetTernary(m)
+		default:
+			err = fmt.Errorf("invalid %T ID %d", m, m.FieldId)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	e.Priority = t.Priority
+	act := t.GetAction().GetAction()
+	if act == nil {
+		if t.GetAction() != nil {
+			return fmt.Errorf("invalid Action %s", t.GetAction())
+		}
+		return nil
+	}
+	switch act.ActionId {
+	case Action_IngressPipeUpstreamPunt:
+		e.Action = &IngressPipeUpstreamPuntAction{}
+	case Action_Nop:
+		e.Action = &NopAction{}
+	default:
+		return fmt.Errorf("invalid Action %s", t.GetAction())
+	}
+	return e.Action.FromAction(act)
+}
+
+// Table IngressPipe.upstream.attachments_v4
+type IngressPipeUpstreamAttachmentsV4Entry struct {
+	LineId      []byte // exact, bitwidth 32
+	EthSrc      []byte // exact, bitwidth 48
+	Ipv4Src     []byte // exact, bitwidth 32
+	PppoeSessId []byte // exact, bitwidth 16
+	// One of: NopAction, IngressPipeUpstreamRejectAction
+	Action codec.Action
+}
+
+func (e *IngressPipeUpstreamAttachmentsV4Entry) ToTableEntry() *p4v1.TableEntry {
+	t := &p4v1.TableEntry{TableId: Table_IngressPipeUpstreamAttachmentsV4}
+	if e.LineId != nil {
+		t.Match = append(t.Match, codec.ExactMatch(Hdr_IngressPipeUpstreamAttachmentsV4_LineId, e.LineId))
+	}
+	if e.EthSrc != nil {
+		t.Match = append(t.Match, codec.ExactMatch(Hdr_IngressPipeUpstreamAttachmentsV4_EthSrc, e.EthSrc))
+	}
+	if e.Ipv4Src != nil {
+		t.Match = append(t.Match, codec.ExactMatch(Hdr_IngressPipeUpstreamAttachmentsV4_Ipv4Src, e.Ipv4Src))
+	}
+	if e.PppoeSessId != nil {
+		t.Match = append(t.Match, codec.ExactMatch(Hdr_IngressPipeUpstreamAttachmentsV4_PppoeSessId, e.PppoeSessId))
+	}
+	t.Action = codec.DirectTableAction(e.Action)
+	return t
+}
+
+func (e *IngressPipeUpstreamAttachmentsV4Entry) FromTableEntry(t *p4v1.TableEntry) error {
+	*e = IngressPipeUpstreamAttachmentsV4Entry{}
+	if t.TableId != Table_IngressPipeUpstreamAttachmentsV4 {
+		return fmt.Errorf("invalid table ID %d", t.TableId)
+	}
+	var err error
+	for _, m := range t.Match {
+		switch m.FieldId {
+		case Hdr_IngressPipeUpstreamAttachmentsV4_LineId:
+			e.LineId, err = codec.GetExact(m)
+		case Hdr_IngressPipeUpstreamAttachmentsV4_EthSrc:
+			e.EthSrc, err = codec.GetExact(m)
+		case Hdr_IngressPipeUpstreamAttachmentsV4_Ipv4Src:
+			e.Ipv4Src, err = codec.GetExact(m)
+		case Hdr_IngressPipeUpstreamAttachmentsV4_PppoeSessId:
+			e.PppoeSessId, err = codec.GetExact(m)
+		default:
+			err = fmt.Errorf("invalid %T ID %d", m, m.FieldId)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	act := t.GetAction().GetAction()
+	if act == nil {
+		if t.GetAction() != nil {
+			return fmt.Errorf("invalid Action %s", t.GetAction())
+		}
+		return nil
+	}
+	switch act.ActionId {
+	case Action_Nop:
+		e.Action = &NopAction{}
+	case Action_IngressPipeUpstreamReject:
+		e.Action = &IngressPipeUpstreamRejectAction{}
+	default:
+		return fmt.Errorf("invalid Action %s", t.GetAction())
+	}
+	return e.Action.FromAction(act)
+}
+
+// Table IngressPipe.upstream.routes_v4
+type IngressPipeUpstreamRoutesV4Entry struct {
+	Ipv4Dst               *codec.Lpm // lpm, bitwidth 32
+	ActionProfileGroupId  uint32
+	ActionProfileMemberId uint32
+}
+
+func (e *IngressPipeUpstreamRoutesV4Entry) ToTableEntry() *p4v1.TableEntry {
+	t := &p4v1.TableEntry{TableId: Table_IngressPipeUpstreamRoutesV4}
+	if e.Ipv4Dst != nil {
+		t.Match = append(t.Match, codec.LpmMatch(Hdr_IngressPipeUpstreamRoutesV4_Ipv4Dst, e.Ipv4Dst))
+	}
+	t.Action = codec.IndirectTableAction(e.ActionProfileGroupId, e.ActionProfileMemberId)
+	return t
+}
+
+func (e *IngressPipeUpstreamRoutesV4Entry) FromTableEntry(t *p4v1.TableEntry) error {
+	*e = IngressPipeUpstreamRoutesV4Entry{}
+	if t.TableId != Table_IngressPipeUpstreamRoutesV4 {
+		return fmt.Errorf("invalid table ID %d", t.TableId)
+	}
+	var err error
+	for _, m := range t.Match {
+		switch m.FieldId {
+		case Hdr_IngressPipeUpstreamRoutesV4_Ipv4Dst:
+			e.Ipv4Dst, err = codec.GetLpm(m)
+		default:
+			err = fmt.Errorf("invalid %T ID %d", m, m.FieldId)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	switch a := t.GetAction().GetType().(type) {
+	case nil:
+	case *p4v1.TableAction_ActionProfileGroupId:
+		e.ActionProfileGroupId = a.ActionProfileGroupId
+	case *p4v1.TableAction_ActionProfileMemberId:
+		e.ActionProfileMemberId = a.ActionProfileMemberId
+	default:
+		return fmt.Errorf("invalid Action %s", t.GetAction())
+	}
+	return nil
+}
+
+// Table IngressPipe.upstream.cos.services_v4
+type IngressPipeUpstreamCosServicesV4Entry struct {
+	Ipv4Src   *codec.Ternary // ternary, bitwidth 32
+	Ipv4Dst   *codec.Ternary // ternary, bitwidth 32
+	Ipv4Proto *codec.Ternary // ternary, bitwidth 8
+	L4Sport   *codec.Range   // range, bitwidth 16
+	L4Dport   *codec.Range   // range, bitwidth 16
+	Priority  int32
+	// One of: IngressPipeUpstreamCosSetCosIdAction
+	Action codec.Action
+}
+
+func (e *IngressPipeUpstreamCosServicesV4Entry) ToTableEntry() *p4v1.TableEntry {
+	t := &p4v1.TableEntry{TableId: Table_IngressPipeUpstreamCosServicesV4}
+	if e.Ipv4Src != nil {
+		t.Match = append(t.Match, codec.TernaryMatch(Hdr_IngressPipeUpstreamCosServicesV4_Ipv4Src, e.Ipv4Src))
+	}
+	if e.Ipv4Dst != nil {
+		t.Match = append(t.Match, codec.TernaryMatch(Hdr_IngressPipeUpstreamCosServicesV4_Ipv4Dst, e.Ipv4Dst))
+	}
+	if e.Ipv4Proto != nil {
+		t.Match = append(t.Match, codec.TernaryMatch(Hdr_IngressPipeUpstreamCosServicesV4_Ipv4Proto, e.Ipv4Proto))
+	}
+	if e.L4Sport != nil {
+		t.Match = append(t.Match, codec.RangeMatch(Hdr_IngressPipeUpstreamCosServicesV4_L4Sport, e.L4Sport))
+	}
+	if e.L4Dport != nil {
+		t.Match = append(t.Match, codec.RangeMatch(Hdr_IngressPipeUpstreamCosServicesV4_L4Dport, e.L4Dport))
+	}
+	t.Priority = e.Priority
+	t.Action = codec.DirectTableAction(e.Action)
+	return t
+}
+
+func (e *IngressPipeUpstreamCosServicesV4Entry) FromTableEntry(t *p4v1.TableEntry) error {
+	*e = IngressPipeUpstreamCosServicesV4Entry{}
+	if t.TableId != Table_IngressPipeUpstreamCosServicesV4 {
+		return fmt.Errorf("invalid table ID %d", t.TableId)
+	}
+	var err error
+	for _, m := range t.Match {
+		switch m.FieldId {
+		case Hdr_IngressPipeUpstreamCosServicesV4_Ipv4Src:
+			e.Ipv4Src, err = codec.GetTernary(m)
+		case Hdr_IngressPipeUpstreamCosServicesV4_Ipv4Dst:
+			e.Ipv4Dst, err = codec.GetTernary(m)
+		case Hdr_IngressPipeUpstreamCosServicesV4_Ipv4Proto:
+			e.Ipv4Proto, err = codec.GetTernary(m)
+		case Hdr_IngressPipeUpstreamCosServicesV4_L4Sport:
+			e.L4Sport, err = codec.GetRange(m)
+		case Hdr_IngressPipeUpstreamCosServicesV4_L4Dport:
+			e.L4Dport, err = codec.GetRange(m)
+		default:
+			err = fmt.Errorf("invalid %T ID %d", m, m.FieldId)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	e.Priority = t.Priority
+	act := t.GetAction().GetAction()
+	if act == nil {
+		if t.GetAction() != nil {
+			return fmt.Errorf("invalid Action %s", t.GetAction())
+		}
+		return nil
+	}
+	switch act.ActionId {
+	case Action_IngressPipeUpstreamCosSetCosId:
+		e.Action = &IngressPipeUpstreamCosSetCosIdAction{}
+	default:
+		return fmt.Errorf("invalid Action %s", t.GetAction())
+	}
+	return e.Action.FromAction(act)
+}
+
+// Table IngressPipe.downstream.lines_v4
+type IngressPipeDownstreamLinesV4Entry struct {
+	Ipv4Dst []byte // exact, bitwidth 32
+	// One of: IngressPipeDownstreamSetLineAction, IngressPipeDownstreamMissAction
+	Action codec.Action
+}
+
+func (e *IngressPipeDownstreamLinesV4Entry) ToTableEntry() *p4v1.TableEntry {
+	t := &p4v1.TableEntry{TableId: Table_IngressPipeDownstreamLinesV4}
+	if e.Ipv4Dst != nil {
+		t.Match = append(t.Match, codec.ExactMatch(Hdr_IngressPipeDownstreamLinesV4_Ipv4Dst, e.Ipv4Dst))
+	}
+	t.Action = codec.DirectTableAction(e.Action)
+	return t
+}
+
+func (e *IngressPipeDownstreamLinesV4Entry) FromTableEntry(t *p4v1.TableEntry) error {
+	*e = IngressPipeDownstreamLinesV4Entry{}
+	if t.TableId != Table_IngressPipeDownstreamLinesV4 {
+		return fmt.Errorf("invalid table ID %d", t.TableId)
+	}
+	var err error
+	for _, m := range t.Match {
+		switch m.FieldId {
+		case Hdr_IngressPipeDownstreamLinesV4_Ipv4Dst:
+			e.Ipv4Dst, err = codec.GetExact(m)
+		default:
+			err = fmt.Errorf("invalid %T ID %d", m, m.FieldId)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	act := t.GetAction().GetAction()
+	if act == nil {
+		if t.GetAction() != nil {
+			return fmt.Errorf("invalid Action %s", t.GetAction())
+		}
+		return nil
+	}
+	switch act.ActionId {
+	case Action_IngressPipeDownstreamSetLine:
+		e.Action = &IngressPipeDownstreamSetLineAction{}
+	case Action_IngressPipeDownstreamMiss:
+		e.Action = &IngressPipeDownstreamMissAction{}
+	default:
+		return fmt.Errorf("invalid Action %s", t.GetAction())
+	}
+	return e.Action.FromAction(act)
+}
+
+// Table IngressPipe.downstream.attachments_v4
+type IngressPipeDownstreamAttachmentsV4Entry struct {
+	LineId []byte // exact, bitwidth 32
+	// One of: IngressPipeDownstreamSetPppoeAttachmentV4Action, IngressPipeDownstreamMissAction
+	Action codec.Action
+}
+
+func (e *IngressPipeDownstreamAttachmentsV4Entry) ToTableEntry() *p4v1.TableEntry {
+	t := &p4v1.TableEntry{TableId: Table_IngressPipeDownstreamAttachmentsV4}
+	if e.LineId != nil {
+		t.Match = append(t.Match, codec.ExactMatch(Hdr_IngressPipeDownstreamAttachmentsV4_LineId, e.LineId))
+	}
+	t.Action = codec.DirectTableAction(e.Action)
+	return t
+}
+
+func (e *IngressPipeDownstreamAttachmentsV4Entry) FromTableEntry(t *p4v1.TableEntry) error {
+	*e = IngressPipeDownstreamAttachmentsV4Entry{}
+	if t.TableId != Table_IngressPipeDownstreamAttachmentsV4 {
+		return fmt.Errorf("invalid table ID %d", t.TableId)
+	}
+	var err error
+	for _, m := range t.Match {
+		switch m.FieldId {
+		case Hdr_IngressPipeDownstreamAttachmentsV4_LineId:
+			e.LineId, err = codec.GetExact(m)
+		default:
+			err = fmt.Errorf("invalid %T ID %d", m, m.FieldId)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	act := t.GetAction().GetAction()
+	if act == nil {
+		if t.GetAction() != nil {
+			return fmt.Errorf("invalid Action %s", t.GetAction())
+		}
+		return nil
+	}
+	switch act.ActionId {
+	case Action_IngressPipeDownstreamSetPppoeAttachmentV4:
+		e.Action = &IngressPipeDownstreamSetPppoeAttachmentV4Action{}
+	case Action_IngressPipeDownstreamMiss:
+		e.Action = &IngressPipeDownstreamMissAction{}
+	default:
+		return fmt.Errorf("invalid Action %s", t.GetAction())
+	}
+	return e.Action.FromAction(act)
+}
+
+// Table IngressPipe.downstream.cos.services_v4
+type IngressPipeDownstreamCosServicesV4Entry struct {
+	Ipv4Src   *codec.Ternary // ternary, bitwidth 32
+	Ipv4Dst   *codec.Ternary // ternary, bitwidth 32
+	Ipv4Proto *codec.Ternary // ternary, bitwidth 8
+	L4Sport   *codec.Range   // range, bitwidth 16
+	L4Dport   *codec.Range   // range, bitwidth 16
+	Priority  int32
+	// One of: IngressPipeDownstreamCosSetCosIdAction
+	Action codec.Action
+}
+
+func (e *IngressPipeDownstreamCosServicesV4Entry) ToTableEntry() *p4v1.TableEntry {
+	t := &p4v1.TableEntry{TableId: Table_IngressPipeDownstreamCosServicesV4}
+	if e.Ipv4Src != nil {
+		t.Match = append(t.Match, codec.TernaryMatch(Hdr_IngressPipeDownstreamCosServicesV4_Ipv4Src, e.Ipv4Src))
+	}
+	if e.Ipv4Dst != nil {
+		t.Match = append(t.Match, codec.TernaryMatch(Hdr_IngressPipeDownstreamCosServicesV4_Ipv4Dst, e.Ipv4Dst))
+	}
+	if e.Ipv4Proto != nil {
+		t.Match = append(t.Match, codec.TernaryMatch(Hdr_IngressPipeDownstreamCosServicesV4_Ipv4Proto, e.Ipv4Proto))
+	}
+	if e.L4Sport != nil {
+		t.Match = append(t.Match, codec.RangeMatch(Hdr_IngressPipeDownstreamCosServicesV4_L4Sport, e.L4Sport))
+	}
+	if e.L4Dport != nil {
+		t.Match = append(t.Match, codec.RangeMatch(Hdr_IngressPipeDownstreamCosServicesV4_L4Dport, e.L4Dport))
+	}
+	t.Priority = e.Priority
+	t.Action = codec.DirectTableAction(e.Action)
+	return t
+}
+
+func (e *IngressPipeDownstreamCosServicesV4Entry) FromTableEntry(t *p4v1.TableEntry) error {
+	*e = IngressPipeDownstreamCosServicesV4Entry{}
+	if t.TableId != Table_IngressPipeDownstreamCosServicesV4 {
+		return fmt.Errorf("invalid table ID %d", t.TableId)
+	}
+	var err error
+	for _, m := range t.Match {
+		switch m.FieldId {
+		case Hdr_IngressPipeDownstreamCosServicesV4_Ipv4Src:
+			e.Ipv4Src, err = codec.GetTernary(m)
+		case Hdr_IngressPipeDownstreamCosServicesV4_Ipv4Dst:
+			e.Ipv4Dst, err = codec.GetTernary(m)
+		case Hdr_IngressPipeDownstreamCosServicesV4_Ipv4Proto:
+			e.Ipv4Proto, err = codec.GetTernary(m)
+		case Hdr_IngressPipeDownstreamCosServicesV4_L4Sport:
+			e.L4Sport, err = codec.GetRange(m)
+		case Hdr_IngressPipeDownstreamCosServicesV4_L4Dport:
+			e.L4Dport, err = codec.GetRange(m)
+		default:
+			err = fmt.Errorf("invalid %T ID %d", m, m.FieldId)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	e.Priority = t.Priority
+	act := t.GetAction().GetAction()
+	if act == nil {
+		if t.GetAction() != nil {
+			return fmt.Errorf("invalid Action %s", t.GetAction())
+		}
+		return nil
+	}
+	switch act.ActionId {
+	case Action_IngressPipeDownstreamCosSetCosId:
+		e.Action = &IngressPipeDownstreamCosSetCosIdAction{}
+	default:
+		return fmt.Errorf("invalid Action %s", t.GetAction())
+	}
+	return e.Action.FromAction(act)
+}
+
+// Table IngressPipe.acl.acls
+type IngressPipeAclAclsEntry struct {
+	Port      *codec.Ternary // ternary, bitwidth 9
+	IfType    *codec.Ternary // ternary, bitwidth 3
+	EthSrc    *codec.Ternary // ternary, bitwidth 48
+	EthDst    *codec.Ternary // ternary, bitwidth 48
+	EthType   *codec.Ternary // ternary, bitwidth 16
+	Ipv4Src   *codec.Ternary // ternary, bitwidth 32
+	Ipv4Dst   *codec.Ternary // ternary, bitwidth 32
+	Ipv4Proto *codec.Ternary // ternary, bitwidth 8
+	L4Sport   *codec.Ternary // ternary, bitwidth 16
+	L4Dport   *codec.Ternary // ternary, bitwidth 16
+	Priority  int32
+	// One of: IngressPipeAclSetPortAction, IngressPipeAclPuntAction, IngressPipeAclDropAction, NopAction
+	Action codec.Action
+}
+
+func (e *IngressPipeAclAclsEntry) ToTableEntry() *p4v1.TableEntry {
+	t := &p4v1.TableEntry{TableId: Table_IngressPipeAclAcls}
+	if e.Port != nil {
+		t.Match = append(t.Match, codec.TernaryMatch(Hdr_IngressPipeAclAcls_Port, e.Port))
+	}
+	if e.IfType != nil {
+		t.Match = append(t.Match, codec.TernaryMatch(Hdr_IngressPipeAclAcls_IfType, e.IfType))
+	}
+	if e.EthSrc != nil {
+		t.Match = append(t.Match, codec.TernaryMatch(Hdr_IngressPipeAclAcls_EthSrc, e.EthSrc))
+	}
+	if e.EthDst != nil {
+		t.Match = append(t.Match, codec.TernaryMatch(Hdr_IngressPipeAclAcls_EthDst, e.EthDst))
+	}
+	if e.EthType != nil {
+		t.Match = append(t.Match, codec.TernaryMatch(Hdr_IngressPipeAclAcls_EthType, e.EthType))
+	}
+	if e.Ipv4Src != nil {
+		t.Match = append(t.Match, codec.TernaryMatch(Hdr_IngressPipeAclAcls_Ipv4Src, e.Ipv4Src))
+	}
+	if e.Ipv4Dst != nil {
+		t.Match = append(t.Match, codec.TernaryMatch(Hdr_IngressPipeAclAcls_Ipv4Dst, e.Ipv4Dst))
+	}
+	if e.Ipv4Proto != nil {
+		t.Match = append(t.Match, codec.TernaryMatch(Hdr_IngressPipeAclAcls_Ipv4Proto, e.Ipv4Proto))
+	}
+	if e.L4Sport != nil {
+		t.Match = append(t.Match, codec.TernaryMatch(Hdr_IngressPipeAclAcls_L4Sport, e.L4Sport))
+	}
+	if e.L4Dport != nil {
+		t.Match = append(t.Match, codec.TernaryMatch(Hdr_IngressPipeAclAcls_L4Dport, e.L4Dport))
+	}
+	t.Priority = e.Priority
+	t.Action = codec.DirectTableAction(e.Action)
+	return t
+}
+
+func (e *IngressPipeAclAclsEntry) FromTableEntry(t *p4v1.TableEntry) error {
+	*e = IngressPipeAclAclsEntry{}
+	if t.TableId != Table_IngressPipeAclAcls {
+		return fmt.Errorf("invalid table ID %d", t.TableId)
+	}
+	var err error
+	for _, m := range t.Match {
+		switch m.FieldId {
+		case Hdr_IngressPipeAclAcls_Port:
+			e.Port, err = codec.GetTernary(m)
+		case Hdr_IngressPipeAclAcls_IfType:
+			e.IfType, err = codec.GetTernary(m)
+		case Hdr_IngressPipeAclAcls_EthSrc:
+			e.EthSrc, err = codec.GetTernary(m)
+		case Hdr_IngressPipeAclAcls_EthDst:
+			e.EthDst, err = codec.GetTernary(m)
+		case Hdr_IngressPipeAclAcls_EthType:
+			e.EthType, err = codec.GetTernary(m)
+		case Hdr_IngressPipeAclAcls_Ipv4Src:
+			e.Ipv4Src, err = codec.GetTernary(m)
+		case Hdr_IngressPipeAclAcls_Ipv4Dst:
+			e.Ipv4Dst, err = codec.GetTernary(m)
+		case Hdr_IngressPipeAclAcls_Ipv4Proto:
+			e.Ipv4Proto, err = codec.GetTernary(m)
+		case Hdr_IngressPipeAclAcls_L4Sport:
+			e.L4Sport, err = codec.GetTernary(m)
+		case Hdr_IngressPipeAclAcls_L4Dport:
+			e.L4Dport, err = codec.GetTernary(m)
+		default:
+			err = fmt.Errorf("invalid %T ID %d", m, m.FieldId)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	e.Priority = t.Priority
+	act := t.GetAction().GetAction()
+	if act == nil {
+		if t.GetAction() != nil {
+			return fmt.Errorf("invalid Action %s", t.GetAction())
+		}
+		return nil
+	}
+	switch act.ActionId {
+	case Action_IngressPipeAclSetPort:
+		e.Action = &IngressPipeAclSetPortAction{}
+	case Action_IngressPipeAclPunt:
+		e.Action = &IngressPipeAclPuntAction{}
+	case Action_IngressPipeAclDrop:
+		e.Action = &IngressPipeAclDropAction{}
+	case Action_Nop:
+		e.Action = &NopAction{}
+	default:
+		return fmt.Errorf("invalid Action %s", t.GetAction())
+	}
+	return e.Action.FromAction(act)
+}
+
+// Member of action profile IngressPipe.upstream.ecmp
+type IngressPipeUpstreamEcmpMember struct {
+	MemberId uint32
+	// One of: IngressPipeUpstreamRouteV4Action
+	Action codec.Action
+}
+
+func (m *IngressPipeUpstreamEcmpMember) ToActionProfileMember() *p4v1.ActionProfileMember {
+	member := &p4v1.ActionProfileMember{ActionProfileId: ActionProfile_IngressPipeUpstreamEcmp, MemberId: m.MemberId}
+	if m.Action != nil {
+		member.Action = m.Action.ToAction()
+	}
+	return member
+}
+
+func (m *IngressPipeUpstreamEcmpMember) FromActionProfileMember(member *p4v1.ActionProfileMember) error {
+	*m = IngressPipeUpstreamEcmpMember{}
+	if member.ActionProfileId != ActionProfile_IngressPipeUpstreamEcmp {
+		return fmt.Errorf("invalid action profile ID %d", member.ActionProfileId)
+	}
+	m.MemberId = member.MemberId
+	if member.Action == nil {
+		return nil
+	}
+	switch member.Action.ActionId {
+	case Action_IngressPipeUpstreamRouteV4:
+		m.Action = &IngressPipeUpstreamRouteV4Action{}
+	default:
+		return fmt.Errorf("invalid Action %s", member.Action)
+	}
+	return m.Action.FromAction(member.Action)
+}
+
+// Group of action profile IngressPipe.upstream.ecmp
+type IngressPipeUpstreamEcmpGroup struct {
+	GroupId uint32
+	Members []*p4v1.ActionProfileGroup_Member
+	MaxSize int32
+}
+
+func (g *IngressPipeUpstreamEcmpGroup) ToActionProfileGroup() *p4v1.ActionProfileGroup {
+	return &p4v1.ActionProfileGroup{
+		ActionProfileId: ActionProfile_IngressPipeUpstreamEcmp,
+		GroupId:         g.GroupId,
+		Members:         g.Members,
+		MaxSize:         g.MaxSize,
+	}
+}
+
+func (g *IngressPipeUpstreamEcmpGroup) FromActionProfileGroup(group *p4v1.ActionProfileGroup) error {
+	*g = IngressPipeUpstreamEcmpGroup{}
+	if group.ActionProfileId != ActionProfile_IngressPipeUpstreamEcmp {
+		return fmt.Errorf("invalid action profile ID %d", group.ActionProfileId)
+	}
+	g.GroupId = group.GroupId
+	g.Members = group.Members
+	g.MaxSize = group.MaxSize
+	return nil
+}
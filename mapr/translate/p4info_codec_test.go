@@ -0,0 +1,128 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package translate
+
+import (
+	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"mapr/codec"
+	"reflect"
+	"testing"
+)
+
+// The methods generated for each table.
+type tableEntryCodec interface {
+	ToTableEntry() *p4v1.TableEntry
+	FromTableEntry(*p4v1.TableEntry) error
+}
+
+func Test_tableEntryCodec(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry tableEntryCodec
+	}{
+		{"exact", &IngressPipeMyStationsEntry{
+			Port:   []byte{0, 1},
+			EthDst: []byte{0xaa, 0, 0, 0, 0, 1},
+			Action: &IngressPipeSetMyStationAction{},
+		}},
+		{"ternary", &IngressPipeAclAclsEntry{
+			Port:     &codec.Ternary{Value: []byte{0, 1}, Mask: []byte{0x01, 0xff}},
+			EthType:  &codec.Ternary{Value: []byte{0x08, 0}, Mask: []byte{0xff, 0xff}},
+			Priority: 10,
+			Action:   &IngressPipeAclSetPortAction{Port: []byte{0, 2}},
+		}},
+		{"ternary and range", &IngressPipeUpstreamCosServicesV4Entry{
+			Ipv4Dst:  &codec.Ternary{Value: []byte{10, 0, 0, 0}, Mask: []byte{0xff, 0, 0, 0}},
+			L4Dport:  &codec.Range{Low: []byte{0, 80}, High: []byte{0x1f, 0x90}},
+			Priority: 1,
+			Action:   &IngressPipeUpstreamCosSetCosIdAction{CosId: []byte{0, 0, 0, 2}},
+		}},
+		{"lpm with action profile", &IngressPipeUpstreamRoutesV4Entry{
+			Ipv4Dst:              &codec.Lpm{Value: []byte{10, 0, 0, 0}, PrefixLen: 8},
+			ActionProfileGroupId: 10,
+		}},
+		{"no action", &IngressPipeMyStationsEntry{Port: []byte{0, 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := reflect.New(reflect.TypeOf(tt.entry).Elem()).Interface().(tableEntryCodec)
+			require.NoError(t, got.FromTableEntry(tt.entry.ToTableEntry()))
+			assert.Equal(t, tt.entry, got)
+		})
+	}
+}
+
+func Test_tableEntryCodec_errors(t *testing.T) {
+	myStation := (&IngressPipeMyStationsEntry{Port: []byte{0, 1}, Action: &IngressPipeSetMyStationAction{}}).
+		ToTableEntry()
+	wrongField := (&IngressPipeMyStationsEntry{Port: []byte{0, 1}}).ToTableEntry()
+	wrongField.Match[0].FieldId = 42
+	wrongAction := (&IngressPipeMyStationsEntry{Port: []byte{0, 1}, Action: &IngressPipeAclDropAction{}}).
+		ToTableEntry()
+	wrongParam := (&IngressPipeAclAclsEntry{Action: &IngressPipeAclSetPortAction{Port: []byte{0, 2}}}).ToTableEntry()
+	wrongParam.GetAction().GetAction().Params[0].ParamId = 42
+	tests := []struct {
+		name  string
+		entry tableEntryCodec
+		from  *p4v1.TableEntry
+	}{
+		{"table ID", &IngressPipeAclAclsEntry{}, myStation},
+		{"match field ID", &IngressPipeMyStationsEntry{}, wrongField},
+		{"action ID", &IngressPipeMyStationsEntry{}, wrongAction},
+		{"param ID", &IngressPipeAclAclsEntry{}, wrongParam},
+		{"action of indirect table", &IngressPipeUpstreamRoutesV4Entry{},
+			&p4v1.TableEntry{TableId: Table_IngressPipeUpstreamRoutesV4, Action: myStation.Action}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Error(t, tt.entry.FromTableEntry(tt.from))
+		})
+	}
+}
+
+func Test_actionProfileCodec(t *testing.T) {
+	member := &IngressPipeUpstreamEcmpMember{
+		MemberId: 1,
+		Action:   &IngressPipeUpstreamRouteV4Action{Port: []byte{0, 1}, Dmac: []byte{0xcc, 0, 0, 0, 0, 1}},
+	}
+	gotMember := &IngressPipeUpstreamEcmpMember{}
+	require.NoError(t, gotMember.FromActionProfileMember(member.ToActionProfileMember()))
+	assert.Equal(t, member, gotMember)
+	wrongAction := (&IngressPipeUpstreamEcmpMember{MemberId: 1, Action: &IngressPipeAclDropAction{}}).
+		ToActionProfileMember()
+	assert.Error(t, gotMember.FromActionProfileMember(wrongAction))
+
+	group := &IngressPipeUpstreamEcmpGroup{
+		GroupId: 10,
+		Members: []*p4v1.ActionProfileGroup_Member{{MemberId: 1, Weight: 1}},
+		MaxSize: 8,
+	}
+	gotGroup := &IngressPipeUpstreamEcmpGroup{}
+	require.NoError(t, gotGroup.FromActionProfileGroup(group.ToActionProfileGroup()))
+	assert.Equal(t, group, gotGroup)
+	wrongProfile := group.ToActionProfileGroup()
+	wrongProfile.ActionProfileId = 42
+	assert.Error(t, gotGroup.FromActionProfileGroup(wrongProfile))
+}
+
+// Decoding into a reused value does not keep the fields of the previous one.
+func Test_codec_reset(t *testing.T) {
+	e := &IngressPipeMyStationsEntry{}
+	require.NoError(t, e.FromTableEntry((&IngressPipeMyStationsEntry{
+		Port:   []byte{0, 1},
+		EthDst: []byte{0xaa, 0, 0, 0, 0, 1},
+		Action: &IngressPipeSetMyStationAction{},
+	}).ToTableEntry()))
+	require.NoError(t, e.FromTableEntry((&IngressPipeMyStationsEntry{Port: []byte{0, 2}}).ToTableEntry()))
+	assert.Equal(t, &IngressPipeMyStationsEntry{Port: []byte{0, 2}}, e)
+
+	a := &IngressPipeUpstreamRouteV4Action{Port: []byte{0, 1}, Dmac: []byte{0xcc, 0, 0, 0, 0, 1}}
+	require.NoError(t, a.FromAction((&IngressPipeUpstreamRouteV4Action{Port: []byte{0, 2}}).ToAction()))
+	assert.Equal(t, &IngressPipeUpstreamRouteV4Action{Port: []byte{0, 2}}, a)
+}
@@ -0,0 +1,147 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package translate
+
+import (
+	"github.com/golang/protobuf/proto"
+	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
+)
+
+// Canonical binary encoding of P4Runtime entity keys.
+//
+// A table entry key is encoded as the table ID and priority (4 bytes each, big-endian), followed by each field match
+// in ascending field ID order. Each field match is encoded as the field ID (uvarint), a byte identifying the match
+// type, and the match values, each prefixed by its length (uvarint). Byte values are canonicalized by stripping
+// leading zeros as mandated by the P4Runtime spec, such that two semantically equal entries result in the same key,
+// regardless of the order of their match fields or the width of their byte values.
+
+// Match type tags.
+const (
+	keyMatchExact    byte = 1
+	keyMatchTernary  byte = 2
+	keyMatchLpm      byte = 3
+	keyMatchRange    byte = 4
+	keyMatchOptional byte = 5
+	keyMatchOther    byte = 6
+)
+
+// Max number of field matches that can be sorted without allocating.
+const keyMaxSortedMatches = 16
+
+// Returns the given byte value without leading zeros. A zero value is encoded as a single zero byte.
+func canonicalBytes(b []byte) []byte {
+	for len(b) > 1 && b[0] == 0 {
+		b = b[1:]
+	}
+	return b
+}
+
+func appendUvarint(dst []byte, v uint64) []byte {
+	for v >= 0x80 {
+		dst = append(dst, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(dst, byte(v))
+}
+
+func appendUint32(dst []byte, v uint32) []byte {
+	return append(dst, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+func appendKeyBytes(dst []byte, b []byte) []byte {
+	b = canonicalBytes(b)
+	dst = appendUvarint(dst, uint64(len(b)))
+	return append(dst, b...)
+}
+
+func appendFieldMatch(dst []byte, m *p4v1.FieldMatch) []byte {
+	dst = appendUvarint(dst, uint64(m.FieldId))
+	switch x := m.FieldMatchType.(type) {
+	case *p4v1.FieldMatch_Exact_:
+		dst = append(dst, keyMatchExact)
+		dst = appendKeyBytes(dst, x.Exact.GetValue())
+	case *p4v1.FieldMatch_Ternary_:
+		dst = append(dst, keyMatchTernary)
+		dst = appendKeyBytes(dst, x.Ternary.GetValue())
+		dst = appendKeyBytes(dst, x.Ternary.GetMask())
+	case *p4v1.FieldMatch_Lpm:
+		dst = append(dst, keyMatchLpm)
+		dst = appendKeyBytes(dst, x.Lpm.GetValue())
+		dst = appendUvarint(dst, uint64(x.Lpm.GetPrefixLen()))
+	case *p4v1.FieldMatch_Range_:
+		dst = append(dst, keyMatchRange)
+		dst = appendKeyBytes(dst, x.Range.GetLow())
+		dst = appendKeyBytes(dst, x.Range.GetHigh())
+	case *p4v1.FieldMatch_Optional_:
+		dst = append(dst, keyMatchOptional)
+		dst = appendKeyBytes(dst, x.Optional.GetValue())
+	default:
+		// Architecture-specific match, not much we can do other than using its serialized form.
+		dst = append(dst, keyMatchOther)
+		b, _ := proto.Marshal(m)
+		dst = appendUvarint(dst, uint64(len(b)))
+		dst = append(dst, b...)
+	}
+	return dst
+}
+
+func matchesSorted(match []*p4v1.FieldMatch) bool {
+	for i := 1; i < len(match); i++ {
+		if match[i-1].FieldId > match[i].FieldId {
+			return false
+		}
+	}
+	return true
+}
+
+// Appends to dst the canonical binary key of a table entry with the given table ID, match and priority.
+func AppendTableEntryKey(dst []byte, tableId uint32, match []*p4v1.FieldMatch, priority int32) []byte {
+	dst = appendUint32(dst, tableId)
+	dst = appendUint32(dst, uint32(priority))
+	if !matchesSorted(match) {
+		// Insertion sort on a copy, the number of match fields is small.
+		var buf [keyMaxSortedMatches]*p4v1.FieldMatch
+		sorted := buf[:0]
+		if len(match) > keyMaxSortedMatches {
+			sorted = make([]*p4v1.FieldMatch, 0, len(match))
+		}
+		for _, m := range match {
+			i := len(sorted)
+			sorted = append(sorted, m)
+			for ; i > 0 && sorted[i-1].FieldId > m.FieldId; i-- {
+				sorted[i] = sorted[i-1]
+			}
+			sorted[i] = m
+		}
+		match = sorted
+	}
+	for _, m := range match {
+		dst = appendFieldMatch(dst, m)
+	}
+	return dst
+}
+
+// Appends to dst the binary key of an action profile member or group with the given IDs.
+func appendActProfKey(dst []byte, actProfId uint32, id uint32) []byte {
+	dst = appendUint32(dst, actProfId)
+	return appendUint32(dst, id)
+}
+
+// Size of the stack buffer used to compute keys for lookups, larger keys are computed on the heap.
+const keyBufSize = 128
+
+// Appends to dst the binary key of an indexed extern entry (e.g., counter, meter, register) with the given ID and
+// index. A nil index, i.e., all indexes, is encoded differently than any actual index.
+func appendIndexedKey(dst []byte, id uint32, index *p4v1.Index) []byte {
+	dst = appendUint32(dst, id)
+	if index == nil {
+		return append(dst, 0)
+	}
+	dst = append(dst, 1)
+	dst = appendUint32(dst, uint32(index.Index>>32))
+	return appendUint32(dst, uint32(index.Index))
+}
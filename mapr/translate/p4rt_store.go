@@ -7,15 +7,27 @@
 package translate
 
 import (
+	"encoding/binary"
 	"fmt"
 	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
-	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sync"
 )
 
 // A store of P4Runtime entities with map semantics.
 type P4RtStore interface {
-	// Updates the store using the content of the given P4Runtime WriteRequest's Update.
+	// Updates the store using the content of the given P4Runtime WriteRequest's Update. With dryRun, the store is not
+	// modified, and an error is returned if the update could not be applied as per the P4Runtime spec, e.g.,
+	// ALREADY_EXISTS when inserting an existing entity. Wildcard writes to all the indexes of counters, meters and
+	// registers are rejected with INVALID_ARGUMENT in both cases.
 	ApplyUpdate(r *p4v1.Update, dryRun bool) error
+	// Returns an immutable point-in-time view of the store. The snapshot shares the shards of its maps with the store,
+	// which copies each shard the first time it modifies it after the snapshot (copy-on-write), see cowMap. Snapshots
+	// can be read from any goroutine while the store is being modified, but they cannot be modified themselves.
+	Snapshot() P4RtStore
+	// Removes all entities from the store. Secondary indexes are emptied but remain declared.
+	Clear()
 	// Stores the given table entry.
 	PutTableEntry(*p4v1.TableEntry)
 	// Returns the table entry associated with the given key, or nil.
@@ -28,6 +40,12 @@ type P4RtStore interface {
 	TableEntries() []*p4v1.TableEntry
 	// Returns the number of table entries in the store.
 	TableEntryCount() int
+	// Declares a secondary index on table entries with the given name, using f to extract the index values of each
+	// entry. Existing entries are indexed immediately, and the index is kept consistent across Put and Remove.
+	AddTableEntryIndex(name string, f TableEntryIndexFunc)
+	// Returns all table entries that have the given value in the index with the given name, none if the index is not
+	// declared. Runs in O(result).
+	TableEntriesByIndex(name string, value []byte) []*p4v1.TableEntry
 	// Stores the given action profile group.
 	PutActProfGroup(*p4v1.ActionProfileGroup)
 	// Returns the action profile group associated with the given key, or nil.
@@ -52,28 +70,263 @@ type P4RtStore interface {
 	ActProfMembers() []*p4v1.ActionProfileMember
 	// Returns the number of action profile members in the store.
 	ActProfMemberCount() int
+	// Stores the given counter entry.
+	PutCounterEntry(*p4v1.CounterEntry)
+	// Returns the counter entry associated with the given key, or nil.
+	GetCounterEntry(*string) *p4v1.CounterEntry
+	// Removes the given counter entry.
+	RemoveCounterEntry(*p4v1.CounterEntry)
+	// Returns a slice of counter entries that satisfy the predicate f.
+	FilterCounterEntries(f func(*p4v1.CounterEntry) bool) []*p4v1.CounterEntry
+	// Returns all counter entries.
+	CounterEntries() []*p4v1.CounterEntry
+	// Returns the number of counter entries in the store.
+	CounterEntryCount() int
+	// Stores the given direct counter entry.
+	PutDirectCounterEntry(*p4v1.DirectCounterEntry)
+	// Returns the direct counter entry associated with the given key, or nil.
+	GetDirectCounterEntry(*string) *p4v1.DirectCounterEntry
+	// Removes the given direct counter entry.
+	RemoveDirectCounterEntry(*p4v1.DirectCounterEntry)
+	// Returns a slice of direct counter entries that satisfy the predicate f.
+	FilterDirectCounterEntries(f func(*p4v1.DirectCounterEntry) bool) []*p4v1.DirectCounterEntry
+	// Returns all direct counter entries.
+	DirectCounterEntries() []*p4v1.DirectCounterEntry
+	// Returns the number of direct counter entries in the store.
+	DirectCounterEntryCount() int
+	// Stores the given meter entry.
+	PutMeterEntry(*p4v1.MeterEntry)
+	// Returns the meter entry associated with the given key, or nil.
+	GetMeterEntry(*string) *p4v1.MeterEntry
+	// Removes the given meter entry.
+	RemoveMeterEntry(*p4v1.MeterEntry)
+	// Returns a slice of meter entries that satisfy the predicate f.
+	FilterMeterEntries(f func(*p4v1.MeterEntry) bool) []*p4v1.MeterEntry
+	// Returns all meter entries.
+	MeterEntries() []*p4v1.MeterEntry
+	// Returns the number of meter entries in the store.
+	MeterEntryCount() int
+	// Stores the given direct meter entry.
+	PutDirectMeterEntry(*p4v1.DirectMeterEntry)
+	// Returns the direct meter entry associated with the given key, or nil.
+	GetDirectMeterEntry(*string) *p4v1.DirectMeterEntry
+	// Removes the given direct meter entry.
+	RemoveDirectMeterEntry(*p4v1.DirectMeterEntry)
+	// Returns a slice of direct meter entries that satisfy the predicate f.
+	FilterDirectMeterEntries(f func(*p4v1.DirectMeterEntry) bool) []*p4v1.DirectMeterEntry
+	// Returns all direct meter entries.
+	DirectMeterEntries() []*p4v1.DirectMeterEntry
+	// Returns the number of direct meter entries in the store.
+	DirectMeterEntryCount() int
+	// Stores the given register entry.
+	PutRegisterEntry(*p4v1.RegisterEntry)
+	// Returns the register entry associated with the given key, or nil.
+	GetRegisterEntry(*string) *p4v1.RegisterEntry
+	// Removes the given register entry.
+	RemoveRegisterEntry(*p4v1.RegisterEntry)
+	// Returns a slice of register entries that satisfy the predicate f.
+	FilterRegisterEntries(f func(*p4v1.RegisterEntry) bool) []*p4v1.RegisterEntry
+	// Returns all register entries.
+	RegisterEntries() []*p4v1.RegisterEntry
+	// Returns the number of register entries in the store.
+	RegisterEntryCount() int
+	// Stores the given PRE multicast group entry.
+	PutMulticastGroup(*p4v1.MulticastGroupEntry)
+	// Returns the PRE multicast group entry associated with the given key, or nil.
+	GetMulticastGroup(*string) *p4v1.MulticastGroupEntry
+	// Removes the given PRE multicast group entry.
+	RemoveMulticastGroup(*p4v1.MulticastGroupEntry)
+	// Returns a slice of PRE multicast group entries that satisfy the predicate f.
+	FilterMulticastGroups(f func(*p4v1.MulticastGroupEntry) bool) []*p4v1.MulticastGroupEntry
+	// Returns all PRE multicast group entries.
+	MulticastGroups() []*p4v1.MulticastGroupEntry
+	// Returns the number of PRE multicast group entries in the store.
+	MulticastGroupCount() int
+	// Stores the given PRE clone session entry.
+	PutCloneSession(*p4v1.CloneSessionEntry)
+	// Returns the PRE clone session entry associated with the given key, or nil.
+	GetCloneSession(*string) *p4v1.CloneSessionEntry
+	// Removes the given PRE clone session entry.
+	RemoveCloneSession(*p4v1.CloneSessionEntry)
+	// Returns a slice of PRE clone session entries that satisfy the predicate f.
+	FilterCloneSessions(f func(*p4v1.CloneSessionEntry) bool) []*p4v1.CloneSessionEntry
+	// Returns all PRE clone session entries.
+	CloneSessions() []*p4v1.CloneSessionEntry
+	// Returns the number of PRE clone session entries in the store.
+	CloneSessionCount() int
+	// Stores the given value set entry.
+	PutValueSetEntry(*p4v1.ValueSetEntry)
+	// Returns the value set entry associated with the given key, or nil.
+	GetValueSetEntry(*string) *p4v1.ValueSetEntry
+	// Removes the given value set entry.
+	RemoveValueSetEntry(*p4v1.ValueSetEntry)
+	// Returns a slice of value set entries that satisfy the predicate f.
+	FilterValueSetEntries(f func(*p4v1.ValueSetEntry) bool) []*p4v1.ValueSetEntry
+	// Returns all value set entries.
+	ValueSetEntries() []*p4v1.ValueSetEntry
+	// Returns the number of value set entries in the store.
+	ValueSetEntryCount() int
+}
+
+// Returns the values under which the given table entry should be indexed. An entry with no values is not indexed.
+// Values are indexed and looked up in canonical form, i.e., without leading zero bytes, as in the primary keys.
+type TableEntryIndexFunc func(*p4v1.TableEntry) [][]byte
+
+// A secondary index on table entries, mapping index values to buckets of the entries (by primary key) that have that
+// value. Both the index and its buckets are cowMaps, shared with snapshots.
+type tableEntryIndex struct {
+	f       TableEntryIndexFunc
+	buckets cowMap
+}
+
+type tableEntryIndexBucket struct {
+	// Generation of the index that created the bucket, the only one that may modify it in place.
+	gen     uint64
+	entries cowMap
+}
+
+// Returns a read-only copy of the index, sharing its buckets, see cowMap.snapshot. The buckets shared with the copy
+// are copied before being modified, see bucket.
+func (i *tableEntryIndex) snapshot() *tableEntryIndex {
+	return &tableEntryIndex{f: i.f, buckets: i.buckets.snapshot()}
+}
+
+// Returns the bucket of the given value, creating it if missing, or copying it if shared with a snapshot.
+func (i *tableEntryIndex) bucket(v string) *tableEntryIndexBucket {
+	x, ok := i.buckets.get(v)
+	if ok && x.(*tableEntryIndexBucket).gen == i.buckets.gen {
+		return x.(*tableEntryIndexBucket)
+	}
+	b := &tableEntryIndexBucket{gen: i.buckets.gen}
+	if ok {
+		b.entries = x.(*tableEntryIndexBucket).entries.clone()
+	}
+	i.buckets.put(v, b)
+	return b
+}
+
+func (i *tableEntryIndex) put(key string, entry *p4v1.TableEntry) {
+	for _, v := range i.f(entry) {
+		i.bucket(string(canonicalBytes(v))).entries.put(key, entry)
+	}
+}
+
+func (i *tableEntryIndex) remove(key string, entry *p4v1.TableEntry) {
+	for _, v := range i.f(entry) {
+		v = canonicalBytes(v)
+		x, ok := i.buckets.get(string(v))
+		if !ok {
+			continue
+		}
+		if b := x.(*tableEntryIndexBucket); b.entries.len == 1 {
+			if _, ok := b.entries.get(key); ok {
+				i.buckets.delete(string(v))
+			}
+			continue
+		}
+		i.bucket(string(v)).entries.delete(key)
+	}
 }
 
 type p4RtStore struct {
-	name           string
-	tableEntries   map[string]*p4v1.TableEntry
-	actProfGroups  map[string]*p4v1.ActionProfileGroup
-	actProfMembers map[string]*p4v1.ActionProfileMember
+	name string
+	// Serializes modifications and snapshots. Modifications are expected to come from a single goroutine, which reads
+	// the store without locking: snapshots only read the maps, see cowMap.
+	mu sync.Mutex
+	// Whether this store is a snapshot.
+	frozen bool
+
+	tableEntries         cowMap
+	tableEntryIndexes    map[string]*tableEntryIndex
+	actProfGroups        cowMap
+	actProfMembers       cowMap
+	counterEntries       cowMap
+	directCounterEntries cowMap
+	meterEntries         cowMap
+	directMeterEntries   cowMap
+	registerEntries      cowMap
+	multicastGroups      cowMap
+	cloneSessions        cowMap
+	valueSetEntries      cowMap
 }
 
 func NewP4RtStore(name string) *p4RtStore {
 	return &p4RtStore{
-		name:           name,
-		tableEntries:   make(map[string]*p4v1.TableEntry),
-		actProfGroups:  make(map[string]*p4v1.ActionProfileGroup),
-		actProfMembers: make(map[string]*p4v1.ActionProfileMember),
+		name:              name,
+		tableEntryIndexes: make(map[string]*tableEntryIndex),
+	}
+}
+
+// Returns the maps of entities of the store, except for the table entry indexes.
+func (s *p4RtStore) maps() []*cowMap {
+	return []*cowMap{
+		&s.tableEntries,
+		&s.actProfGroups,
+		&s.actProfMembers,
+		&s.counterEntries,
+		&s.directCounterEntries,
+		&s.meterEntries,
+		&s.directMeterEntries,
+		&s.registerEntries,
+		&s.multicastGroups,
+		&s.cloneSessions,
+		&s.valueSetEntries,
+	}
+}
+
+func (s *p4RtStore) Snapshot() P4RtStore {
+	if s.frozen {
+		return s
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snap := &p4RtStore{
+		name:              s.name,
+		frozen:            true,
+		tableEntryIndexes: make(map[string]*tableEntryIndex, len(s.tableEntryIndexes)),
+	}
+	// The store keeps its maps, which the goroutine modifying it reads without locking.
+	snapMaps := snap.maps()
+	for i, m := range s.maps() {
+		*snapMaps[i] = m.snapshot()
+	}
+	for name, i := range s.tableEntryIndexes {
+		snap.tableEntryIndexes[name] = i.snapshot()
+	}
+	return snap
+}
+
+func (s *p4RtStore) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkWritable()
+	// Snapshots keep the old maps.
+	for _, m := range s.maps() {
+		*m = cowMap{}
+	}
+	indexes := make(map[string]*tableEntryIndex, len(s.tableEntryIndexes))
+	for name, i := range s.tableEntryIndexes {
+		indexes[name] = &tableEntryIndex{f: i.f}
+	}
+	s.tableEntryIndexes = indexes
+}
+
+// Panics if the store is a snapshot. Must be called before modifying the maps, with mu held.
+func (s *p4RtStore) checkWritable() {
+	if s.frozen {
+		panic(fmt.Sprintf("P4RtStore(%s): cannot modify a snapshot", s.name))
 	}
 }
 
 func (s *p4RtStore) ApplyUpdate(u *p4v1.Update, dryRun bool) error {
+	if err := checkIndex(u.Entity); err != nil {
+		return err
+	}
 	if dryRun {
-		// TODO: implement validation logic
-		return nil
+		return s.validate(u)
+	}
+	if s.frozen {
+		return fmt.Errorf("P4RtStore(%s) is a read-only snapshot", s.name)
 	}
 	defer s.logStoreSummary()
 	switch x := u.Entity.Entity.(type) {
@@ -95,23 +348,138 @@ func (s *p4RtStore) ApplyUpdate(u *p4v1.Update, dryRun bool) error {
 		} else {
 			s.PutActProfMember(x.ActionProfileMember)
 		}
+	// Counters, meters, registers and value sets can only be modified. We still support DELETE to reset them.
+	case *p4v1.Entity_CounterEntry:
+		if u.Type == p4v1.Update_DELETE {
+			s.RemoveCounterEntry(x.CounterEntry)
+		} else {
+			s.PutCounterEntry(x.CounterEntry)
+		}
+	case *p4v1.Entity_DirectCounterEntry:
+		if u.Type == p4v1.Update_DELETE {
+			s.RemoveDirectCounterEntry(x.DirectCounterEntry)
+		} else {
+			s.PutDirectCounterEntry(x.DirectCounterEntry)
+		}
+	case *p4v1.Entity_MeterEntry:
+		if u.Type == p4v1.Update_DELETE {
+			s.RemoveMeterEntry(x.MeterEntry)
+		} else {
+			s.PutMeterEntry(x.MeterEntry)
+		}
+	case *p4v1.Entity_DirectMeterEntry:
+		if u.Type == p4v1.Update_DELETE {
+			s.RemoveDirectMeterEntry(x.DirectMeterEntry)
+		} else {
+			s.PutDirectMeterEntry(x.DirectMeterEntry)
+		}
+	case *p4v1.Entity_RegisterEntry:
+		if u.Type == p4v1.Update_DELETE {
+			s.RemoveRegisterEntry(x.RegisterEntry)
+		} else {
+			s.PutRegisterEntry(x.RegisterEntry)
+		}
+	case *p4v1.Entity_ValueSetEntry:
+		if u.Type == p4v1.Update_DELETE {
+			s.RemoveValueSetEntry(x.ValueSetEntry)
+		} else {
+			s.PutValueSetEntry(x.ValueSetEntry)
+		}
+	case *p4v1.Entity_PacketReplicationEngineEntry:
+		switch y := x.PacketReplicationEngineEntry.Type.(type) {
+		case *p4v1.PacketReplicationEngineEntry_MulticastGroupEntry:
+			if u.Type == p4v1.Update_DELETE {
+				s.RemoveMulticastGroup(y.MulticastGroupEntry)
+			} else {
+				s.PutMulticastGroup(y.MulticastGroupEntry)
+			}
+		case *p4v1.PacketReplicationEngineEntry_CloneSessionEntry:
+			if u.Type == p4v1.Update_DELETE {
+				s.RemoveCloneSession(y.CloneSessionEntry)
+			} else {
+				s.PutCloneSession(y.CloneSessionEntry)
+			}
+		default:
+			return fmt.Errorf("invalid PacketReplicationEngineEntry type %T", y)
+		}
 	default:
 		log.Warnf("P4RtStore(%s): storing %T not implemented, ignoring... [%v]", s.name, x, x)
 	}
 	return nil
 }
 
+// Returns an INVALID_ARGUMENT error if the given entity is a counter, meter or register entry without index, i.e.,
+// referring to all indexes. The store does not know the size of counters, meters and registers, hence it cannot apply
+// writes to all their indexes.
+func checkIndex(e *p4v1.Entity) error {
+	var index *p4v1.Index
+	switch x := e.GetEntity().(type) {
+	case *p4v1.Entity_CounterEntry:
+		index = x.CounterEntry.Index
+	case *p4v1.Entity_MeterEntry:
+		index = x.MeterEntry.Index
+	case *p4v1.Entity_RegisterEntry:
+		index = x.RegisterEntry.Index
+	default:
+		return nil
+	}
+	if index == nil {
+		return status.Errorf(codes.InvalidArgument, "writing all indexes of %T is not supported", e.Entity)
+	}
+	return nil
+}
+
+// Returns an error if the given update cannot be applied to the store: inserted entities must not exist, modified and
+// deleted ones must. Counters, meters, registers and value sets always exist, hence they are not checked.
+func (s *p4RtStore) validate(u *p4v1.Update) error {
+	var exists bool
+	switch x := u.Entity.GetEntity().(type) {
+	case *p4v1.Entity_TableEntry:
+		key := KeyFromTableEntry(x.TableEntry)
+		exists = s.GetTableEntry(&key) != nil
+	case *p4v1.Entity_ActionProfileGroup:
+		key := KeyFromActProfGroup(x.ActionProfileGroup)
+		exists = s.GetActProfGroup(&key) != nil
+	case *p4v1.Entity_ActionProfileMember:
+		key := KeyFromActProfMember(x.ActionProfileMember)
+		exists = s.GetActProfMember(&key) != nil
+	case *p4v1.Entity_PacketReplicationEngineEntry:
+		switch y := x.PacketReplicationEngineEntry.Type.(type) {
+		case *p4v1.PacketReplicationEngineEntry_MulticastGroupEntry:
+			key := KeyFromMulticastGroup(y.MulticastGroupEntry)
+			exists = s.GetMulticastGroup(&key) != nil
+		case *p4v1.PacketReplicationEngineEntry_CloneSessionEntry:
+			key := KeyFromCloneSession(y.CloneSessionEntry)
+			exists = s.GetCloneSession(&key) != nil
+		default:
+			return fmt.Errorf("invalid PacketReplicationEngineEntry type %T", y)
+		}
+	default:
+		return nil
+	}
+	switch {
+	case u.Type == p4v1.Update_INSERT && exists:
+		return status.Errorf(codes.AlreadyExists, "P4RtStore(%s): %T already exists", s.name, u.Entity.Entity)
+	case u.Type != p4v1.Update_INSERT && !exists:
+		return status.Errorf(codes.NotFound, "P4RtStore(%s): %T does not exist", s.name, u.Entity.Entity)
+	}
+	return nil
+}
+
 func (s *p4RtStore) logStoreSummary() {
-	log.Debugf("P4RtStore(%s) summary: TableEntryCount=%d, ActProfGroupCount=%d, ActProfMemberCount=%d",
-		s.name, s.TableEntryCount(), s.ActProfGroupCount(), s.ActProfMemberCount())
+	log.Debugf("P4RtStore(%s) summary: TableEntryCount=%d, ActProfGroupCount=%d, ActProfMemberCount=%d, "+
+		"CounterEntryCount=%d, DirectCounterEntryCount=%d, MeterEntryCount=%d, DirectMeterEntryCount=%d, "+
+		"RegisterEntryCount=%d, MulticastGroupCount=%d, CloneSessionCount=%d, ValueSetEntryCount=%d",
+		s.name, s.TableEntryCount(), s.ActProfGroupCount(), s.ActProfMemberCount(),
+		s.CounterEntryCount(), s.DirectCounterEntryCount(), s.MeterEntryCount(), s.DirectMeterEntryCount(),
+		s.RegisterEntryCount(), s.MulticastGroupCount(), s.CloneSessionCount(), s.ValueSetEntryCount())
 }
 
-// Returns a string that uniquely identifies a table entry.
+// Returns a string that uniquely identifies a table entry. The string is a compact binary encoding of the fields that
+// determine uniqueness as defined by the P4RT spec, and is independent of the order of the match fields.
 func TableEntryKey(tableId uint32, match []*p4v1.FieldMatch, priority int32) string {
-	// Fields that determine uniqueness are defined by the P4RT spec.
-	// We return a string as that's a comparable and can be used as a map key. Is there a more efficient way of getting
-	// a comparable key out of a protobuf TableEntry message?
-	return fmt.Sprintf("%v-%v-%v", tableId, match, priority)
+	var buf [keyBufSize]byte
+	return string(AppendTableEntryKey(buf[:0], tableId, match, priority))
 }
 
 // Returns a string that uniquely identifies the given table entry.
@@ -120,24 +488,54 @@ func KeyFromTableEntry(t *p4v1.TableEntry) string {
 }
 
 func (s *p4RtStore) PutTableEntry(entry *p4v1.TableEntry) {
-	s.tableEntries[KeyFromTableEntry(entry)] = entry
+	key := KeyFromTableEntry(entry)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkWritable()
+	if old, ok := s.tableEntries.get(key); ok {
+		for _, i := range s.tableEntryIndexes {
+			i.remove(key, old.(*p4v1.TableEntry))
+		}
+	}
+	s.tableEntries.put(key, entry)
+	for _, i := range s.tableEntryIndexes {
+		i.put(key, entry)
+	}
 }
 
 func (s *p4RtStore) GetTableEntry(key *string) *p4v1.TableEntry {
-	return s.tableEntries[*key]
+	v, _ := s.tableEntries.get(*key)
+	e, _ := v.(*p4v1.TableEntry)
+	return e
 }
 
 func (s *p4RtStore) RemoveTableEntry(entry *p4v1.TableEntry) {
-	delete(s.tableEntries, KeyFromTableEntry(entry))
+	var buf [keyBufSize]byte
+	key := AppendTableEntryKey(buf[:0], entry.TableId, entry.Match, entry.Priority)
+	// Use the stored entry to update indexes, the given one might not have an action (e.g., when deleting).
+	old, ok := s.tableEntries.get(string(key))
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkWritable()
+	for _, i := range s.tableEntryIndexes {
+		i.remove(string(key), old.(*p4v1.TableEntry))
+	}
+	s.tableEntries.delete(string(key))
+	// Direct resources share the lifetime of the table entry.
+	s.directCounterEntries.delete(string(key))
+	s.directMeterEntries.delete(string(key))
 }
 
 func (s *p4RtStore) FilterTableEntries(f func(*p4v1.TableEntry) bool) []*p4v1.TableEntry {
 	filtered := make([]*p4v1.TableEntry, 0)
-	for _, value := range s.tableEntries {
-		if f(value) {
-			filtered = append(filtered, value)
+	s.tableEntries.each(func(_ string, v interface{}) {
+		if e := v.(*p4v1.TableEntry); f(e) {
+			filtered = append(filtered, e)
 		}
-	}
+	})
 	return filtered
 }
 
@@ -148,11 +546,104 @@ func (s *p4RtStore) TableEntries() []*p4v1.TableEntry {
 }
 
 func (s *p4RtStore) TableEntryCount() int {
-	return len(s.tableEntries)
+	return s.tableEntries.len
+}
+
+func (s *p4RtStore) AddTableEntryIndex(name string, f TableEntryIndexFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkWritable()
+	if s.tableEntryIndexes == nil {
+		s.tableEntryIndexes = make(map[string]*tableEntryIndex)
+	}
+	i := &tableEntryIndex{f: f}
+	s.tableEntries.each(func(key string, entry interface{}) {
+		i.put(key, entry.(*p4v1.TableEntry))
+	})
+	s.tableEntryIndexes[name] = i
+}
+
+func (s *p4RtStore) TableEntriesByIndex(name string, value []byte) []*p4v1.TableEntry {
+	i, ok := s.tableEntryIndexes[name]
+	if !ok {
+		log.Errorf("P4RtStore(%s): undeclared table entry index %s", s.name, name)
+		return nil
+	}
+	x, ok := i.buckets.get(string(canonicalBytes(value)))
+	if !ok {
+		return []*p4v1.TableEntry{}
+	}
+	bucket := x.(*tableEntryIndexBucket)
+	result := make([]*p4v1.TableEntry, 0, bucket.entries.len)
+	bucket.entries.each(func(_ string, entry interface{}) {
+		result = append(result, entry.(*p4v1.TableEntry))
+	})
+	return result
+}
+
+// Returns the value used by TableIdIndex for the given table ID.
+func TableIdIndexValue(tableId uint32) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, tableId)
+	return b
+}
+
+// Returns an index function that indexes table entries by table ID. See TableIdIndexValue.
+func TableIdIndex() TableEntryIndexFunc {
+	return func(entry *p4v1.TableEntry) [][]byte {
+		return [][]byte{TableIdIndexValue(entry.TableId)}
+	}
+}
+
+// Returns an index function that indexes entries of the given table by the value of the given match field. For
+// ternary and LPM matches, the value is indexed regardless of the mask or prefix length.
+func MatchFieldIndex(tableId uint32, fieldId uint32) TableEntryIndexFunc {
+	return func(entry *p4v1.TableEntry) [][]byte {
+		if entry.TableId != tableId {
+			return nil
+		}
+		for _, m := range entry.Match {
+			if m.FieldId != fieldId {
+				continue
+			}
+			switch x := m.FieldMatchType.(type) {
+			case *p4v1.FieldMatch_Exact_:
+				return [][]byte{x.Exact.Value}
+			case *p4v1.FieldMatch_Ternary_:
+				return [][]byte{x.Ternary.Value}
+			case *p4v1.FieldMatch_Lpm:
+				return [][]byte{x.Lpm.Value}
+			case *p4v1.FieldMatch_Optional_:
+				return [][]byte{x.Optional.Value}
+			}
+		}
+		return nil
+	}
+}
+
+// Returns an index function that indexes entries of the given table by the value of the given action parameter, for
+// entries using the given action.
+func ActionParamIndex(tableId uint32, actionId uint32, paramId uint32) TableEntryIndexFunc {
+	return func(entry *p4v1.TableEntry) [][]byte {
+		if entry.TableId != tableId {
+			return nil
+		}
+		act := entry.GetAction().GetAction()
+		if act == nil || act.ActionId != actionId {
+			return nil
+		}
+		for _, p := range act.Params {
+			if p.ParamId == paramId {
+				return [][]byte{p.Value}
+			}
+		}
+		return nil
+	}
 }
 
 func ActProfGroupKey(actProfId uint32, groupIp uint32) string {
-	return fmt.Sprintf("%d-%d", actProfId, groupIp)
+	var buf [8]byte
+	return string(appendActProfKey(buf[:0], actProfId, groupIp))
 }
 
 // Returns a string that uniquely identifies the given table entry.
@@ -161,24 +652,33 @@ func KeyFromActProfGroup(g *p4v1.ActionProfileGroup) string {
 }
 
 func (s *p4RtStore) PutActProfGroup(g *p4v1.ActionProfileGroup) {
-	s.actProfGroups[KeyFromActProfGroup(g)] = g
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkWritable()
+	s.actProfGroups.put(KeyFromActProfGroup(g), g)
 }
 
 func (s *p4RtStore) GetActProfGroup(key *string) *p4v1.ActionProfileGroup {
-	return s.actProfGroups[*key]
+	v, _ := s.actProfGroups.get(*key)
+	e, _ := v.(*p4v1.ActionProfileGroup)
+	return e
 }
 
 func (s *p4RtStore) RemoveActProfGroup(g *p4v1.ActionProfileGroup) {
-	delete(s.actProfGroups, KeyFromActProfGroup(g))
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkWritable()
+	var buf [8]byte
+	s.actProfGroups.delete(string(appendActProfKey(buf[:0], g.ActionProfileId, g.GroupId)))
 }
 
 func (s *p4RtStore) FilterActProfGroups(f func(*p4v1.ActionProfileGroup) bool) []*p4v1.ActionProfileGroup {
 	filtered := make([]*p4v1.ActionProfileGroup, 0)
-	for _, value := range s.actProfGroups {
-		if f(value) {
-			filtered = append(filtered, value)
+	s.actProfGroups.each(func(_ string, v interface{}) {
+		if e := v.(*p4v1.ActionProfileGroup); f(e) {
+			filtered = append(filtered, e)
 		}
-	}
+	})
 	return filtered
 }
 
@@ -189,11 +689,12 @@ func (s *p4RtStore) ActProfGroups() []*p4v1.ActionProfileGroup {
 }
 
 func (s *p4RtStore) ActProfGroupCount() int {
-	return len(s.actProfGroups)
+	return s.actProfGroups.len
 }
 
 func ActProfMemberKey(actProfId uint32, memberId uint32) string {
-	return fmt.Sprintf("%d-%d", actProfId, memberId)
+	var buf [8]byte
+	return string(appendActProfKey(buf[:0], actProfId, memberId))
 }
 
 // Returns a string that uniquely identifies the given table entry.
@@ -202,24 +703,33 @@ func KeyFromActProfMember(g *p4v1.ActionProfileMember) string {
 }
 
 func (s *p4RtStore) PutActProfMember(g *p4v1.ActionProfileMember) {
-	s.actProfMembers[KeyFromActProfMember(g)] = g
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkWritable()
+	s.actProfMembers.put(KeyFromActProfMember(g), g)
 }
 
 func (s *p4RtStore) GetActProfMember(key *string) *p4v1.ActionProfileMember {
-	return s.actProfMembers[*key]
+	v, _ := s.actProfMembers.get(*key)
+	e, _ := v.(*p4v1.ActionProfileMember)
+	return e
 }
 
 func (s *p4RtStore) RemoveActProfMember(g *p4v1.ActionProfileMember) {
-	delete(s.actProfMembers, KeyFromActProfMember(g))
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkWritable()
+	var buf [8]byte
+	s.actProfMembers.delete(string(appendActProfKey(buf[:0], g.ActionProfileId, g.MemberId)))
 }
 
 func (s *p4RtStore) FilterActProfMembers(f func(*p4v1.ActionProfileMember) bool) []*p4v1.ActionProfileMember {
 	filtered := make([]*p4v1.ActionProfileMember, 0)
-	for _, value := range s.actProfMembers {
-		if f(value) {
-			filtered = append(filtered, value)
+	s.actProfMembers.each(func(_ string, v interface{}) {
+		if e := v.(*p4v1.ActionProfileMember); f(e) {
+			filtered = append(filtered, e)
 		}
-	}
+	})
 	return filtered
 }
 
@@ -230,5 +740,403 @@ func (s *p4RtStore) ActProfMembers() []*p4v1.ActionProfileMember {
 }
 
 func (s *p4RtStore) ActProfMemberCount() int {
-	return len(s.actProfMembers)
+	return s.actProfMembers.len
+}
+
+// Returns a string that uniquely identifies the counter entry with the given ID and index. A nil index (i.e., all
+// indexes) is identified by a different key than any actual index, it is only used by reads, as writes to all indexes
+// are rejected by ApplyUpdate.
+func CounterEntryKey(counterId uint32, index *p4v1.Index) string {
+	var buf [16]byte
+	return string(appendIndexedKey(buf[:0], counterId, index))
+}
+
+// Returns a string that uniquely identifies the given counter entry.
+func KeyFromCounterEntry(e *p4v1.CounterEntry) string {
+	return CounterEntryKey(e.CounterId, e.Index)
+}
+
+// Returns a string that uniquely identifies the given direct counter entry, i.e., the key of its table entry.
+func KeyFromDirectCounterEntry(e *p4v1.DirectCounterEntry) string {
+	return KeyFromTableEntry(e.TableEntry)
+}
+
+// Returns a string that uniquely identifies the meter entry with the given ID and index.
+func MeterEntryKey(meterId uint32, index *p4v1.Index) string {
+	var buf [16]byte
+	return string(appendIndexedKey(buf[:0], meterId, index))
+}
+
+// Returns a string that uniquely identifies the given meter entry.
+func KeyFromMeterEntry(e *p4v1.MeterEntry) string {
+	return MeterEntryKey(e.MeterId, e.Index)
+}
+
+// Returns a string that uniquely identifies the given direct meter entry, i.e., the key of its table entry.
+func KeyFromDirectMeterEntry(e *p4v1.DirectMeterEntry) string {
+	return KeyFromTableEntry(e.TableEntry)
+}
+
+// Returns a string that uniquely identifies the register entry with the given ID and index.
+func RegisterEntryKey(registerId uint32, index *p4v1.Index) string {
+	var buf [16]byte
+	return string(appendIndexedKey(buf[:0], registerId, index))
+}
+
+// Returns a string that uniquely identifies the given register entry.
+func KeyFromRegisterEntry(e *p4v1.RegisterEntry) string {
+	return RegisterEntryKey(e.RegisterId, e.Index)
+}
+
+// Returns a string that uniquely identifies the PRE multicast group with the given ID.
+func MulticastGroupKey(groupId uint32) string {
+	var buf [4]byte
+	return string(appendUint32(buf[:0], groupId))
+}
+
+// Returns a string that uniquely identifies the given PRE multicast group entry.
+func KeyFromMulticastGroup(e *p4v1.MulticastGroupEntry) string {
+	return MulticastGroupKey(e.MulticastGroupId)
+}
+
+// Returns a string that uniquely identifies the PRE clone session with the given ID.
+func CloneSessionKey(sessionId uint32) string {
+	var buf [4]byte
+	return string(appendUint32(buf[:0], sessionId))
+}
+
+// Returns a string that uniquely identifies the given PRE clone session entry.
+func KeyFromCloneSession(e *p4v1.CloneSessionEntry) string {
+	return CloneSessionKey(e.SessionId)
+}
+
+// Returns a string that uniquely identifies the value set with the given ID.
+func ValueSetEntryKey(valueSetId uint32) string {
+	var buf [4]byte
+	return string(appendUint32(buf[:0], valueSetId))
+}
+
+// Returns a string that uniquely identifies the given value set entry.
+func KeyFromValueSetEntry(e *p4v1.ValueSetEntry) string {
+	return ValueSetEntryKey(e.ValueSetId)
+}
+
+func (s *p4RtStore) PutCounterEntry(e *p4v1.CounterEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkWritable()
+	s.counterEntries.put(KeyFromCounterEntry(e), e)
+}
+
+func (s *p4RtStore) GetCounterEntry(key *string) *p4v1.CounterEntry {
+	v, _ := s.counterEntries.get(*key)
+	e, _ := v.(*p4v1.CounterEntry)
+	return e
+}
+
+func (s *p4RtStore) RemoveCounterEntry(e *p4v1.CounterEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkWritable()
+	s.counterEntries.delete(KeyFromCounterEntry(e))
+}
+
+func (s *p4RtStore) FilterCounterEntries(f func(*p4v1.CounterEntry) bool) []*p4v1.CounterEntry {
+	filtered := make([]*p4v1.CounterEntry, 0)
+	s.counterEntries.each(func(_ string, v interface{}) {
+		if e := v.(*p4v1.CounterEntry); f(e) {
+			filtered = append(filtered, e)
+		}
+	})
+	return filtered
+}
+
+func (s *p4RtStore) CounterEntries() []*p4v1.CounterEntry {
+	return s.FilterCounterEntries(func(*p4v1.CounterEntry) bool {
+		return true
+	})
+}
+
+func (s *p4RtStore) CounterEntryCount() int {
+	return s.counterEntries.len
+}
+
+func (s *p4RtStore) PutDirectCounterEntry(e *p4v1.DirectCounterEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkWritable()
+	s.directCounterEntries.put(KeyFromDirectCounterEntry(e), e)
+}
+
+func (s *p4RtStore) GetDirectCounterEntry(key *string) *p4v1.DirectCounterEntry {
+	v, _ := s.directCounterEntries.get(*key)
+	e, _ := v.(*p4v1.DirectCounterEntry)
+	return e
+}
+
+func (s *p4RtStore) RemoveDirectCounterEntry(e *p4v1.DirectCounterEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkWritable()
+	s.directCounterEntries.delete(KeyFromDirectCounterEntry(e))
+}
+
+func (s *p4RtStore) FilterDirectCounterEntries(f func(*p4v1.DirectCounterEntry) bool) []*p4v1.DirectCounterEntry {
+	filtered := make([]*p4v1.DirectCounterEntry, 0)
+	s.directCounterEntries.each(func(_ string, v interface{}) {
+		if e := v.(*p4v1.DirectCounterEntry); f(e) {
+			filtered = append(filtered, e)
+		}
+	})
+	return filtered
+}
+
+func (s *p4RtStore) DirectCounterEntries() []*p4v1.DirectCounterEntry {
+	return s.FilterDirectCounterEntries(func(*p4v1.DirectCounterEntry) bool {
+		return true
+	})
+}
+
+func (s *p4RtStore) DirectCounterEntryCount() int {
+	return s.directCounterEntries.len
+}
+
+func (s *p4RtStore) PutMeterEntry(e *p4v1.MeterEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkWritable()
+	s.meterEntries.put(KeyFromMeterEntry(e), e)
+}
+
+func (s *p4RtStore) GetMeterEntry(key *string) *p4v1.MeterEntry {
+	v, _ := s.meterEntries.get(*key)
+	e, _ := v.(*p4v1.MeterEntry)
+	return e
+}
+
+func (s *p4RtStore) RemoveMeterEntry(e *p4v1.MeterEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkWritable()
+	s.meterEntries.delete(KeyFromMeterEntry(e))
+}
+
+func (s *p4RtStore) FilterMeterEntries(f func(*p4v1.MeterEntry) bool) []*p4v1.MeterEntry {
+	filtered := make([]*p4v1.MeterEntry, 0)
+	s.meterEntries.each(func(_ string, v interface{}) {
+		if e := v.(*p4v1.MeterEntry); f(e) {
+			filtered = append(filtered, e)
+		}
+	})
+	return filtered
+}
+
+func (s *p4RtStore) MeterEntries() []*p4v1.MeterEntry {
+	return s.FilterMeterEntries(func(*p4v1.MeterEntry) bool {
+		return true
+	})
+}
+
+func (s *p4RtStore) MeterEntryCount() int {
+	return s.meterEntries.len
+}
+
+func (s *p4RtStore) PutDirectMeterEntry(e *p4v1.DirectMeterEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkWritable()
+	s.directMeterEntries.put(KeyFromDirectMeterEntry(e), e)
+}
+
+func (s *p4RtStore) GetDirectMeterEntry(key *string) *p4v1.DirectMeterEntry {
+	v, _ := s.directMeterEntries.get(*key)
+	e, _ := v.(*p4v1.DirectMeterEntry)
+	return e
+}
+
+func (s *p4RtStore) RemoveDirectMeterEntry(e *p4v1.DirectMeterEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkWritable()
+	s.directMeterEntries.delete(KeyFromDirectMeterEntry(e))
+}
+
+func (s *p4RtStore) FilterDirectMeterEntries(f func(*p4v1.DirectMeterEntry) bool) []*p4v1.DirectMeterEntry {
+	filtered := make([]*p4v1.DirectMeterEntry, 0)
+	s.directMeterEntries.each(func(_ string, v interface{}) {
+		if e := v.(*p4v1.DirectMeterEntry); f(e) {
+			filtered = append(filtered, e)
+		}
+	})
+	return filtered
+}
+
+func (s *p4RtStore) DirectMeterEntries() []*p4v1.DirectMeterEntry {
+	return s.FilterDirectMeterEntries(func(*p4v1.DirectMeterEntry) bool {
+		return true
+	})
+}
+
+func (s *p4RtStore) DirectMeterEntryCount() int {
+	return s.directMeterEntries.len
+}
+
+func (s *p4RtStore) PutRegisterEntry(e *p4v1.RegisterEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkWritable()
+	s.registerEntries.put(KeyFromRegisterEntry(e), e)
+}
+
+func (s *p4RtStore) GetRegisterEntry(key *string) *p4v1.RegisterEntry {
+	v, _ := s.registerEntries.get(*key)
+	e, _ := v.(*p4v1.RegisterEntry)
+	return e
+}
+
+func (s *p4RtStore) RemoveRegisterEntry(e *p4v1.RegisterEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkWritable()
+	s.registerEntries.delete(KeyFromRegisterEntry(e))
+}
+
+func (s *p4RtStore) FilterRegisterEntries(f func(*p4v1.RegisterEntry) bool) []*p4v1.RegisterEntry {
+	filtered := make([]*p4v1.RegisterEntry, 0)
+	s.registerEntries.each(func(_ string, v interface{}) {
+		if e := v.(*p4v1.RegisterEntry); f(e) {
+			filtered = append(filtered, e)
+		}
+	})
+	return filtered
+}
+
+func (s *p4RtStore) RegisterEntries() []*p4v1.RegisterEntry {
+	return s.FilterRegisterEntries(func(*p4v1.RegisterEntry) bool {
+		return true
+	})
+}
+
+func (s *p4RtStore) RegisterEntryCount() int {
+	return s.registerEntries.len
+}
+
+func (s *p4RtStore) PutMulticastGroup(e *p4v1.MulticastGroupEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkWritable()
+	s.multicastGroups.put(KeyFromMulticastGroup(e), e)
+}
+
+func (s *p4RtStore) GetMulticastGroup(key *string) *p4v1.MulticastGroupEntry {
+	v, _ := s.multicastGroups.get(*key)
+	e, _ := v.(*p4v1.MulticastGroupEntry)
+	return e
+}
+
+func (s *p4RtStore) RemoveMulticastGroup(e *p4v1.MulticastGroupEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkWritable()
+	s.multicastGroups.delete(KeyFromMulticastGroup(e))
+}
+
+func (s *p4RtStore) FilterMulticastGroups(f func(*p4v1.MulticastGroupEntry) bool) []*p4v1.MulticastGroupEntry {
+	filtered := make([]*p4v1.MulticastGroupEntry, 0)
+	s.multicastGroups.each(func(_ string, v interface{}) {
+		if e := v.(*p4v1.MulticastGroupEntry); f(e) {
+			filtered = append(filtered, e)
+		}
+	})
+	return filtered
+}
+
+func (s *p4RtStore) MulticastGroups() []*p4v1.MulticastGroupEntry {
+	return s.FilterMulticastGroups(func(*p4v1.MulticastGroupEntry) bool {
+		return true
+	})
+}
+
+func (s *p4RtStore) MulticastGroupCount() int {
+	return s.multicastGroups.len
+}
+
+func (s *p4RtStore) PutCloneSession(e *p4v1.CloneSessionEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkWritable()
+	s.cloneSessions.put(KeyFromCloneSession(e), e)
+}
+
+func (s *p4RtStore) GetCloneSession(key *string) *p4v1.CloneSessionEntry {
+	v, _ := s.cloneSessions.get(*key)
+	e, _ := v.(*p4v1.CloneSessionEntry)
+	return e
+}
+
+func (s *p4RtStore) RemoveCloneSession(e *p4v1.CloneSessionEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkWritable()
+	s.cloneSessions.delete(KeyFromCloneSession(e))
+}
+
+func (s *p4RtStore) FilterCloneSessions(f func(*p4v1.CloneSessionEntry) bool) []*p4v1.CloneSessionEntry {
+	filtered := make([]*p4v1.CloneSessionEntry, 0)
+	s.cloneSessions.each(func(_ string, v interface{}) {
+		if e := v.(*p4v1.CloneSessionEntry); f(e) {
+			filtered = append(filtered, e)
+		}
+	})
+	return filtered
+}
+
+func (s *p4RtStore) CloneSessions() []*p4v1.CloneSessionEntry {
+	return s.FilterCloneSessions(func(*p4v1.CloneSessionEntry) bool {
+		return true
+	})
+}
+
+func (s *p4RtStore) CloneSessionCount() int {
+	return s.cloneSessions.len
+}
+
+func (s *p4RtStore) PutValueSetEntry(e *p4v1.ValueSetEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkWritable()
+	s.valueSetEntries.put(KeyFromValueSetEntry(e), e)
+}
+
+func (s *p4RtStore) GetValueSetEntry(key *string) *p4v1.ValueSetEntry {
+	v, _ := s.valueSetEntries.get(*key)
+	e, _ := v.(*p4v1.ValueSetEntry)
+	return e
+}
+
+func (s *p4RtStore) RemoveValueSetEntry(e *p4v1.ValueSetEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkWritable()
+	s.valueSetEntries.delete(KeyFromValueSetEntry(e))
+}
+
+func (s *p4RtStore) FilterValueSetEntries(f func(*p4v1.ValueSetEntry) bool) []*p4v1.ValueSetEntry {
+	filtered := make([]*p4v1.ValueSetEntry, 0)
+	s.valueSetEntries.each(func(_ string, v interface{}) {
+		if e := v.(*p4v1.ValueSetEntry); f(e) {
+			filtered = append(filtered, e)
+		}
+	})
+	return filtered
+}
+
+func (s *p4RtStore) ValueSetEntries() []*p4v1.ValueSetEntry {
+	return s.FilterValueSetEntries(func(*p4v1.ValueSetEntry) bool {
+		return true
+	})
+}
+
+func (s *p4RtStore) ValueSetEntryCount() int {
+	return s.valueSetEntries.len
 }
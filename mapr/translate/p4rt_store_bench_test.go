@@ -0,0 +1,139 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package translate
+
+import (
+	"encoding/binary"
+	"fmt"
+	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
+	"testing"
+)
+
+const benchEntryCount = 100000
+
+// Returns n table entries similar to the upstream attachments ones, i.e., with 4 exact match fields.
+func benchTableEntries(n int) []*p4v1.TableEntry {
+	entries := make([]*p4v1.TableEntry, n)
+	for i := 0; i < n; i++ {
+		lineId := make([]byte, 4)
+		binary.BigEndian.PutUint32(lineId, uint32(i))
+		entries[i] = &p4v1.TableEntry{
+			TableId: Table_IngressPipeUpstreamAttachmentsV4,
+			Match: []*p4v1.FieldMatch{
+				{FieldId: 1, FieldMatchType: &p4v1.FieldMatch_Exact_{Exact: &p4v1.FieldMatch_Exact{Value: lineId}}},
+				{FieldId: 2, FieldMatchType: &p4v1.FieldMatch_Exact_{Exact: &p4v1.FieldMatch_Exact{
+					Value: []byte{0x00, 0xAA, 0x00, lineId[1], lineId[2], lineId[3]}}}},
+				{FieldId: 3, FieldMatchType: &p4v1.FieldMatch_Exact_{Exact: &p4v1.FieldMatch_Exact{
+					Value: []byte{0x0A, lineId[1], lineId[2], lineId[3]}}}},
+				{FieldId: 4, FieldMatchType: &p4v1.FieldMatch_Exact_{Exact: &p4v1.FieldMatch_Exact{
+					Value: lineId[2:]}}},
+			},
+		}
+	}
+	return entries
+}
+
+// The key format used before the binary encoding, for comparison.
+func legacyTableEntryKey(t *p4v1.TableEntry) string {
+	return fmt.Sprintf("%v-%v-%v", t.TableId, t.Match, t.Priority)
+}
+
+func BenchmarkKeyFromTableEntry(b *testing.B) {
+	entries := benchTableEntries(benchEntryCount)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = KeyFromTableEntry(entries[i%benchEntryCount])
+	}
+}
+
+func BenchmarkLegacyTableEntryKey(b *testing.B) {
+	entries := benchTableEntries(benchEntryCount)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = legacyTableEntryKey(entries[i%benchEntryCount])
+	}
+}
+
+func BenchmarkPutTableEntry(b *testing.B) {
+	entries := benchTableEntries(benchEntryCount)
+	var s P4RtStore
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if i%benchEntryCount == 0 {
+			// Start over with an empty store every benchEntryCount entries.
+			b.StopTimer()
+			s = NewP4RtStore("bench")
+			b.StartTimer()
+		}
+		s.PutTableEntry(entries[i%benchEntryCount])
+	}
+}
+
+func BenchmarkPutTableEntryLegacy(b *testing.B) {
+	entries := benchTableEntries(benchEntryCount)
+	var m map[string]*p4v1.TableEntry
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if i%benchEntryCount == 0 {
+			b.StopTimer()
+			m = make(map[string]*p4v1.TableEntry)
+			b.StartTimer()
+		}
+		e := entries[i%benchEntryCount]
+		m[legacyTableEntryKey(e)] = e
+	}
+}
+
+func BenchmarkGetTableEntry(b *testing.B) {
+	entries := benchTableEntries(benchEntryCount)
+	s := NewP4RtStore("bench")
+	keys := make([]string, benchEntryCount)
+	for i, e := range entries {
+		s.PutTableEntry(e)
+		keys[i] = KeyFromTableEntry(e)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if s.GetTableEntry(&keys[i%benchEntryCount]) == nil {
+			b.Fatal("missing entry")
+		}
+	}
+}
+
+func BenchmarkRemoveTableEntry(b *testing.B) {
+	entries := benchTableEntries(benchEntryCount)
+	s := NewP4RtStore("bench")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		e := entries[i%benchEntryCount]
+		b.StopTimer()
+		s.PutTableEntry(e)
+		b.StartTimer()
+		s.RemoveTableEntry(e)
+	}
+}
+
+// Writes after each snapshot, as when the store is scraped for metrics between writes.
+func BenchmarkPutTableEntryAfterSnapshot(b *testing.B) {
+	entries := benchTableEntries(benchEntryCount)
+	s := newIndexedStore()
+	for _, e := range entries {
+		s.PutTableEntry(e)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = s.Snapshot()
+		s.PutTableEntry(entries[i%benchEntryCount])
+	}
+}
@@ -0,0 +1,137 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package translate
+
+import (
+	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func update(t p4v1.Update_Type, e *p4v1.Entity) *p4v1.Update {
+	return &p4v1.Update{Type: t, Entity: e}
+}
+
+func Test_store_ApplyUpdate_Entities(t *testing.T) {
+	counter := &p4v1.CounterEntry{CounterId: 1, Index: &p4v1.Index{Index: 10}, Data: &p4v1.CounterData{PacketCount: 1}}
+	meter := &p4v1.MeterEntry{MeterId: 2, Index: &p4v1.Index{Index: 10}, Config: &p4v1.MeterConfig{Cir: 100}}
+	register := &p4v1.RegisterEntry{RegisterId: 3, Index: &p4v1.Index{Index: 10}}
+	mcGroup := &p4v1.MulticastGroupEntry{MulticastGroupId: 4, Replicas: []*p4v1.Replica{{EgressPort: 1}}}
+	cloneSession := &p4v1.CloneSessionEntry{SessionId: 5, Replicas: []*p4v1.Replica{{EgressPort: 2}}}
+	valueSet := &p4v1.ValueSetEntry{ValueSetId: 6}
+	entities := []*p4v1.Entity{
+		{Entity: &p4v1.Entity_CounterEntry{CounterEntry: counter}},
+		{Entity: &p4v1.Entity_MeterEntry{MeterEntry: meter}},
+		{Entity: &p4v1.Entity_RegisterEntry{RegisterEntry: register}},
+		{Entity: &p4v1.Entity_PacketReplicationEngineEntry{PacketReplicationEngineEntry: &p4v1.PacketReplicationEngineEntry{
+			Type: &p4v1.PacketReplicationEngineEntry_MulticastGroupEntry{MulticastGroupEntry: mcGroup}}}},
+		{Entity: &p4v1.Entity_PacketReplicationEngineEntry{PacketReplicationEngineEntry: &p4v1.PacketReplicationEngineEntry{
+			Type: &p4v1.PacketReplicationEngineEntry_CloneSessionEntry{CloneSessionEntry: cloneSession}}}},
+		{Entity: &p4v1.Entity_ValueSetEntry{ValueSetEntry: valueSet}},
+	}
+
+	s := NewP4RtStore("test")
+	for _, e := range entities {
+		assert.NoError(t, s.ApplyUpdate(update(p4v1.Update_INSERT, e), false))
+	}
+	assert.Equal(t, []*p4v1.CounterEntry{counter}, s.CounterEntries())
+	assert.Equal(t, []*p4v1.MeterEntry{meter}, s.MeterEntries())
+	assert.Equal(t, []*p4v1.RegisterEntry{register}, s.RegisterEntries())
+	assert.Equal(t, []*p4v1.MulticastGroupEntry{mcGroup}, s.MulticastGroups())
+	assert.Equal(t, []*p4v1.CloneSessionEntry{cloneSession}, s.CloneSessions())
+	assert.Equal(t, []*p4v1.ValueSetEntry{valueSet}, s.ValueSetEntries())
+
+	key := MulticastGroupKey(4)
+	assert.Equal(t, mcGroup, s.GetMulticastGroup(&key))
+	key = CounterEntryKey(1, &p4v1.Index{Index: 10})
+	assert.Equal(t, counter, s.GetCounterEntry(&key))
+	// Wildcard writes to all indexes are rejected, and do not modify any index.
+	wildcard := &p4v1.CounterEntry{CounterId: 1, Data: &p4v1.CounterData{PacketCount: 2}}
+	err := s.ApplyUpdate(update(p4v1.Update_MODIFY, &p4v1.Entity{Entity: &p4v1.Entity_CounterEntry{
+		CounterEntry: wildcard}}), false)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, []*p4v1.CounterEntry{counter}, s.CounterEntries())
+
+	for _, e := range entities {
+		assert.NoError(t, s.ApplyUpdate(update(p4v1.Update_DELETE, e), false))
+	}
+	assert.Zero(t, s.CounterEntryCount())
+	assert.Zero(t, s.MeterEntryCount())
+	assert.Zero(t, s.RegisterEntryCount())
+	assert.Zero(t, s.MulticastGroupCount())
+	assert.Zero(t, s.CloneSessionCount())
+	assert.Zero(t, s.ValueSetEntryCount())
+}
+
+func Test_store_DirectEntries_RemovedWithTableEntry(t *testing.T) {
+	s := NewP4RtStore("test")
+	s.PutTableEntry(&mockTableEntry1)
+	s.PutTableEntry(&mockTableEntry2)
+	// Direct entries might refer to the table entry with match fields in a different order.
+	s.PutDirectCounterEntry(&p4v1.DirectCounterEntry{TableEntry: &reorderedMockTableEntry1})
+	s.PutDirectMeterEntry(&p4v1.DirectMeterEntry{TableEntry: &mockTableEntry1})
+	s.PutDirectCounterEntry(&p4v1.DirectCounterEntry{TableEntry: &mockTableEntry2})
+
+	key := KeyFromTableEntry(&mockTableEntry1)
+	assert.NotNil(t, s.GetDirectCounterEntry(&key))
+	assert.NotNil(t, s.GetDirectMeterEntry(&key))
+
+	s.RemoveTableEntry(&mockTableEntry1)
+	assert.Nil(t, s.GetDirectCounterEntry(&key))
+	assert.Nil(t, s.GetDirectMeterEntry(&key))
+	assert.Equal(t, 1, s.DirectCounterEntryCount())
+	assert.Zero(t, s.DirectMeterEntryCount())
+}
+
+func Test_store_ApplyUpdate_InvalidPreEntry(t *testing.T) {
+	s := NewP4RtStore("test")
+	e := &p4v1.Entity{Entity: &p4v1.Entity_PacketReplicationEngineEntry{
+		PacketReplicationEngineEntry: &p4v1.PacketReplicationEngineEntry{}}}
+	assert.Error(t, s.ApplyUpdate(update(p4v1.Update_INSERT, e), false))
+}
+
+func Test_store_ApplyUpdate_DryRun(t *testing.T) {
+	s := NewP4RtStore("test")
+	s.PutTableEntry(&mockTableEntry1)
+	s.PutActProfMember(&p4v1.ActionProfileMember{ActionProfileId: 1, MemberId: 1})
+	entry := func(e *p4v1.TableEntry) *p4v1.Entity {
+		return &p4v1.Entity{Entity: &p4v1.Entity_TableEntry{TableEntry: e}}
+	}
+	member := func(id uint32) *p4v1.Entity {
+		return &p4v1.Entity{Entity: &p4v1.Entity_ActionProfileMember{
+			ActionProfileMember: &p4v1.ActionProfileMember{ActionProfileId: 1, MemberId: id}}}
+	}
+	tests := []struct {
+		name   string
+		update *p4v1.Update
+		want   codes.Code
+	}{
+		{"insert", update(p4v1.Update_INSERT, entry(&mockTableEntry2)), codes.OK},
+		{"insert existing", update(p4v1.Update_INSERT, entry(&mockTableEntry1)), codes.AlreadyExists},
+		{"modify", update(p4v1.Update_MODIFY, entry(&mockTableEntry1NewAction)), codes.OK},
+		{"modify missing", update(p4v1.Update_MODIFY, entry(&mockTableEntry2)), codes.NotFound},
+		{"delete", update(p4v1.Update_DELETE, entry(&mockTableEntry1NoAction)), codes.OK},
+		{"delete missing", update(p4v1.Update_DELETE, entry(&mockTableEntry2)), codes.NotFound},
+		{"insert existing member", update(p4v1.Update_INSERT, member(1)), codes.AlreadyExists},
+		{"delete missing member", update(p4v1.Update_DELETE, member(2)), codes.NotFound},
+		{"counter", update(p4v1.Update_MODIFY, &p4v1.Entity{Entity: &p4v1.Entity_CounterEntry{
+			CounterEntry: &p4v1.CounterEntry{CounterId: 1, Index: &p4v1.Index{Index: 1}}}}), codes.OK},
+		{"all counter indexes", update(p4v1.Update_MODIFY, &p4v1.Entity{Entity: &p4v1.Entity_CounterEntry{
+			CounterEntry: &p4v1.CounterEntry{CounterId: 1}}}), codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, status.Code(s.ApplyUpdate(tt.update, true)))
+			// The store is not modified.
+			assert.Equal(t, 1, s.TableEntryCount())
+			assert.Equal(t, 1, s.ActProfMemberCount())
+			assert.Zero(t, s.CounterEntryCount())
+		})
+	}
+}
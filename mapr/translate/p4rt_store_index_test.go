@@ -0,0 +1,104 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package translate
+
+import (
+	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var mockTableEntry1NewAction = p4v1.TableEntry{
+	TableId:  1,
+	Match:    []*p4v1.FieldMatch{&mockFieldMatch1, &mockFieldMatch2},
+	Action:   &mockTableAction2,
+	Priority: 1,
+}
+
+var mockTableEntry1NoAction = p4v1.TableEntry{
+	TableId:  1,
+	Match:    []*p4v1.FieldMatch{&mockFieldMatch1, &mockFieldMatch2},
+	Priority: 1,
+}
+
+func newIndexedStore() *p4RtStore {
+	s := NewP4RtStore("test")
+	s.AddTableEntryIndex("table", TableIdIndex())
+	s.AddTableEntryIndex("match", MatchFieldIndex(1, 2))
+	s.AddTableEntryIndex("param", ActionParamIndex(1, 1, 1))
+	return s
+}
+
+func Test_store_TableEntriesByIndex(t *testing.T) {
+	s := newIndexedStore()
+	s.PutTableEntry(&mockTableEntry1)
+	s.PutTableEntry(&mockTableEntry2)
+
+	assert.ElementsMatch(t, []*p4v1.TableEntry{&mockTableEntry1}, s.TableEntriesByIndex("table", TableIdIndexValue(1)))
+	assert.ElementsMatch(t, []*p4v1.TableEntry{&mockTableEntry2}, s.TableEntriesByIndex("table", TableIdIndexValue(2)))
+	assert.ElementsMatch(t, []*p4v1.TableEntry{&mockTableEntry1}, s.TableEntriesByIndex("match", []byte{0x02}))
+	assert.Empty(t, s.TableEntriesByIndex("match", []byte{0x03}), "match index should ignore other tables")
+	assert.ElementsMatch(t, []*p4v1.TableEntry{&mockTableEntry1}, s.TableEntriesByIndex("param", []byte{0x0A}))
+}
+
+func Test_store_TableEntriesByIndex_Modify(t *testing.T) {
+	s := newIndexedStore()
+	s.PutTableEntry(&mockTableEntry1)
+	s.PutTableEntry(&mockTableEntry1NewAction)
+
+	assert.Empty(t, s.TableEntriesByIndex("param", []byte{0x0A}), "old param value should not be indexed")
+	assert.ElementsMatch(t, []*p4v1.TableEntry{&mockTableEntry1NewAction}, s.TableEntriesByIndex("param", []byte{0x0C}))
+	assert.ElementsMatch(t, []*p4v1.TableEntry{&mockTableEntry1NewAction}, s.TableEntriesByIndex("match", []byte{0x02}))
+}
+
+func Test_store_TableEntriesByIndex_Remove(t *testing.T) {
+	s := newIndexedStore()
+	s.PutTableEntry(&mockTableEntry1)
+	s.PutTableEntry(&mockTableEntry2)
+	// Deletes might come without action, index should be updated using the stored entry.
+	s.RemoveTableEntry(&mockTableEntry1NoAction)
+
+	assert.Empty(t, s.TableEntriesByIndex("table", TableIdIndexValue(1)))
+	assert.Empty(t, s.TableEntriesByIndex("match", []byte{0x02}))
+	assert.Empty(t, s.TableEntriesByIndex("param", []byte{0x0A}))
+	assert.ElementsMatch(t, []*p4v1.TableEntry{&mockTableEntry2}, s.TableEntriesByIndex("table", TableIdIndexValue(2)))
+}
+
+func Test_store_AddTableEntryIndex_Existing(t *testing.T) {
+	s := NewP4RtStore("test")
+	s.PutTableEntry(&mockTableEntry1)
+	s.PutTableEntry(&mockTableEntry2)
+	s.AddTableEntryIndex("table", TableIdIndex())
+
+	assert.ElementsMatch(t, []*p4v1.TableEntry{&mockTableEntry2}, s.TableEntriesByIndex("table", TableIdIndexValue(2)))
+}
+
+func Test_store_TableEntriesByIndex_Canonical(t *testing.T) {
+	s := NewP4RtStore("test")
+	s.AddTableEntryIndex("param", ActionParamIndex(1, 1, 1))
+	padded := &p4v1.TableEntry{
+		TableId: 1,
+		Match:   []*p4v1.FieldMatch{&mockFieldMatch1},
+		Action: &p4v1.TableAction{Type: &p4v1.TableAction_Action{Action: &p4v1.Action{
+			ActionId: 1,
+			Params:   []*p4v1.Action_Param{{ParamId: 1, Value: []byte{0, 0, 0, 0x0A}}},
+		}}},
+	}
+	s.PutTableEntry(padded)
+
+	assert.ElementsMatch(t, []*p4v1.TableEntry{padded}, s.TableEntriesByIndex("param", []byte{0x0A}))
+	assert.ElementsMatch(t, []*p4v1.TableEntry{padded}, s.TableEntriesByIndex("param", []byte{0, 0x0A}))
+	s.RemoveTableEntry(&p4v1.TableEntry{TableId: 1, Match: []*p4v1.FieldMatch{&mockFieldMatch1}})
+	assert.Empty(t, s.TableEntriesByIndex("param", []byte{0x0A}))
+}
+
+func Test_store_TableEntriesByIndex_Undeclared(t *testing.T) {
+	s := newIndexedStore()
+	s.PutTableEntry(&mockTableEntry1)
+
+	assert.Empty(t, s.TableEntriesByIndex("undeclared", []byte{0x02}))
+}
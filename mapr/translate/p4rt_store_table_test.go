@@ -75,7 +75,7 @@ var mockTableEntry1 = p4v1.TableEntry{
 	Priority: 1,
 }
 
-var mockTableEntryKey1 = "1-[field_id:1 exact:<value:\"\\001\" >  field_id:2 exact:<value:\"\\002\" > ]-1"
+var mockTableEntryKey1 = KeyFromTableEntry(&mockTableEntry1)
 
 var sameAsMockTableEntry1 = p4v1.TableEntry{
 	TableId:  1,
@@ -84,6 +84,24 @@ var sameAsMockTableEntry1 = p4v1.TableEntry{
 	Priority: 1,
 }
 
+// Same as mockTableEntry1, but with match fields in reverse order and padded values.
+var reorderedMockTableEntry1 = p4v1.TableEntry{
+	TableId: 1,
+	Match: []*p4v1.FieldMatch{
+		{
+			FieldId: 2,
+			FieldMatchType: &p4v1.FieldMatch_Exact_{
+				Exact: &p4v1.FieldMatch_Exact{
+					Value: []byte{0x00, 0x02},
+				},
+			},
+		},
+		&mockFieldMatch1,
+	},
+	Action:   &mockTableAction2,
+	Priority: 1,
+}
+
 var mockTableEntry2 = p4v1.TableEntry{
 	TableId:  2,
 	Match:    []*p4v1.FieldMatch{&mockFieldMatch3, &mockFieldMatch4},
@@ -91,7 +109,7 @@ var mockTableEntry2 = p4v1.TableEntry{
 	Priority: 1,
 }
 
-var mockTableEntryKey2 = "2-[field_id:3 exact:<value:\"\\003\" >  field_id:4 exact:<value:\"\\004\" > ]-1"
+var mockTableEntryKey2 = KeyFromTableEntry(&mockTableEntry2)
 
 func Test_store_FilterTableEntries(t *testing.T) {
 	type fields struct {
@@ -144,9 +162,7 @@ func Test_store_FilterTableEntries(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := p4RtStore{
-				tableEntries: tt.fields.tableEntries,
-			}
+			s := newStoreWithTableEntries(tt.fields.tableEntries)
 			got := s.FilterTableEntries(tt.args.f)
 			assert.ElementsMatch(t, tt.want, got, "FilterTableEntries(): elements don't match the expected ones")
 		})
@@ -178,9 +194,7 @@ func Test_store_PutTableEntry(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := p4RtStore{
-				tableEntries: tt.fields.tableEntries,
-			}
+			s := newStoreWithTableEntries(tt.fields.tableEntries)
 			s.PutTableEntry(tt.args.entry)
 			assert.Equal(t, tt.wantCount, s.TableEntryCount(), "TableEntryCount() should return expected count")
 		})
@@ -213,9 +227,7 @@ func Test_store_GetTableEntry(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := p4RtStore{
-				tableEntries: tt.fields.tableEntries,
-			}
+			s := newStoreWithTableEntries(tt.fields.tableEntries)
 			assert.Equal(t, tt.want, s.GetTableEntry(tt.args.key), "GetTableEntry() should return expected value")
 		})
 	}
@@ -246,9 +258,7 @@ func Test_store_RemoveTableEntry(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := p4RtStore{
-				tableEntries: tt.fields.tableEntries,
-			}
+			s := newStoreWithTableEntries(tt.fields.tableEntries)
 			s.RemoveTableEntry(tt.args.entry)
 			assert.Equal(t, tt.wantCount, s.TableEntryCount(), "TableEntryCount() should return expected count")
 		})
@@ -284,9 +294,7 @@ func Test_store_TableEntries(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := p4RtStore{
-				tableEntries: tt.fields.tableEntries,
-			}
+			s := newStoreWithTableEntries(tt.fields.tableEntries)
 			got := s.TableEntries()
 			assert.ElementsMatch(t, tt.want, got, "TableEntries(): elements don't match the expected ones")
 		})
@@ -302,9 +310,10 @@ func Test_tableEntryKey(t *testing.T) {
 		args args
 		want string
 	}{
-		{"1", args{&mockTableEntry1}, mockTableEntryKey1},
+		{"1", args{&mockTableEntry1}, "\x00\x00\x00\x01\x00\x00\x00\x01\x01\x01\x01\x01\x02\x01\x01\x02"},
 		{"sameAs1", args{&sameAsMockTableEntry1}, mockTableEntryKey1},
-		{"2", args{&mockTableEntry2}, mockTableEntryKey2},
+		{"reordered1", args{&reorderedMockTableEntry1}, mockTableEntryKey1},
+		{"2", args{&mockTableEntry2}, "\x00\x00\x00\x02\x00\x00\x00\x01\x03\x01\x01\x03\x04\x01\x01\x04"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -332,9 +341,7 @@ func Test_store_TableEntryCount(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := p4RtStore{
-				tableEntries: tt.fields.tableEntries,
-			}
+			s := newStoreWithTableEntries(tt.fields.tableEntries)
 			assert.Equal(t, tt.want, s.TableEntryCount(), "TableEntryCount() should return expected count")
 		})
 	}
@@ -13,6 +13,15 @@ import (
 
 var emptyTableEntries = map[string]*p4v1.TableEntry{}
 
+// Returns a store holding the given table entries, by key.
+func newStoreWithTableEntries(entries map[string]*p4v1.TableEntry) *p4RtStore {
+	s := NewP4RtStore("test")
+	for k, e := range entries {
+		s.tableEntries.put(k, e)
+	}
+	return s
+}
+
 var mockUpdateInsertTableEntry1 = p4v1.Update{
 	Type:   p4v1.Update_INSERT,
 	Entity: &p4v1.Entity{Entity: &p4v1.Entity_TableEntry{TableEntry: &mockTableEntry1}},
@@ -44,9 +53,7 @@ func Test_store_Update(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := &p4RtStore{
-				tableEntries: tt.fields.tableEntries,
-			}
+			s := newStoreWithTableEntries(tt.fields.tableEntries)
 			s.ApplyUpdate(tt.args.update, tt.args.dryRun)
 			if gotTableEntryCount := s.TableEntryCount(); gotTableEntryCount != tt.wantTableEntryCount {
 				t.Errorf("TableEntryCount() = %v, want %v", gotTableEntryCount, tt.wantTableEntryCount)
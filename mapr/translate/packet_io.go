@@ -0,0 +1,221 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package translate
+
+import (
+	"fmt"
+	p4confv1 "github.com/p4lang/p4runtime/go/p4/config/v1"
+	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
+	"math/bits"
+	"sort"
+	"strings"
+)
+
+// Names of the controller packet metadata headers, as per the @controller_header annotation.
+const (
+	ControllerHeaderPacketIn  = "packet_in"
+	ControllerHeaderPacketOut = "packet_out"
+)
+
+// Names of the packet metadata carrying port numbers, translated using the PortMap.
+var portPacketMetadata = map[string]bool{
+	"ingress_port": true,
+	"egress_port":  true,
+}
+
+// Translates the metadata of PacketIn and PacketOut messages between the logical and target pipelines.
+//
+// Metadata fields are matched by name, and a field can be translated only if the destination one is at least as wide
+// as the source one. Fields whose name starts with an underscore (e.g., "_pad") are considered padding: they are
+// stripped from the source message and set to zero in the translated one. Moreover:
+// - PacketOut: target fields that do not exist in the logical pipeline are set to zero, while logical fields that do
+//   not exist in the target cannot be translated;
+// - PacketIn: target fields that do not exist in the logical pipeline are stripped, while logical fields that do not
+//   exist in the target cannot be translated.
+type PacketIoTranslator struct {
+	// Target to logical.
+	packetIn *packetMetaMapping
+	// Logical to target.
+	packetOut *packetMetaMapping
+}
+
+// A mapping of packet metadata from a source controller header to a destination one.
+type packetMetaMapping struct {
+	// Source metadata ID to destination one. Missing IDs are not mapped, i.e., they are stripped.
+	ids map[uint32]uint32
+	// Source metadata ID to its bit width.
+	srcWidths map[uint32]int32
+	// Source metadata IDs carrying port numbers.
+	srcPorts map[uint32]bool
+	// Translates port numbers from source to destination.
+	mapPort func([]byte) ([]byte, error)
+	// Destination metadata, sorted by ID. Those not mapped from a source one are set to zero.
+	dst []*p4confv1.ControllerPacketMetadata_Metadata
+}
+
+func findControllerHeader(p4info *p4confv1.P4Info, name string) *p4confv1.ControllerPacketMetadata {
+	for _, h := range p4info.GetControllerPacketMetadata() {
+		if h.GetPreamble().GetName() == name {
+			return h
+		}
+	}
+	return nil
+}
+
+func isPaddingMeta(m *p4confv1.ControllerPacketMetadata_Metadata) bool {
+	return strings.HasPrefix(m.Name, "_")
+}
+
+// Returns a mapping from the src controller header to the dst one. If synthesize is true, dst metadata that do not
+// exist in src are set to zero, otherwise they result in an error. If strip is true, src metadata that do not exist in
+// dst are stripped, otherwise they result in an error. Port numbers are translated using mapPort.
+func newPacketMetaMapping(src, dst *p4confv1.ControllerPacketMetadata, synthesize, strip bool,
+	mapPort func([]byte) ([]byte, error)) (*packetMetaMapping, error) {
+	m := &packetMetaMapping{
+		ids:       make(map[uint32]uint32),
+		srcWidths: make(map[uint32]int32),
+		srcPorts:  make(map[uint32]bool),
+		mapPort:   mapPort,
+	}
+	dstByName := make(map[string]*p4confv1.ControllerPacketMetadata_Metadata)
+	for _, d := range dst.GetMetadata() {
+		dstByName[d.Name] = d
+		m.dst = append(m.dst, d)
+	}
+	sort.Slice(m.dst, func(i, j int) bool {
+		return m.dst[i].Id < m.dst[j].Id
+	})
+	srcNames := make(map[string]bool)
+	for _, s := range src.GetMetadata() {
+		m.srcWidths[s.Id] = s.Bitwidth
+		srcNames[s.Name] = true
+		if isPaddingMeta(s) {
+			continue
+		}
+		d, ok := dstByName[s.Name]
+		if !ok {
+			if strip {
+				continue
+			}
+			return nil, fmt.Errorf("%s metadata %s has no counterpart", src.GetPreamble().GetName(), s.Name)
+		}
+		if d.Bitwidth < s.Bitwidth {
+			return nil, fmt.Errorf("%s metadata %s is %d bits wide, cannot translate to %d bits",
+				src.GetPreamble().GetName(), s.Name, s.Bitwidth, d.Bitwidth)
+		}
+		m.ids[s.Id] = d.Id
+		m.srcPorts[s.Id] = portPacketMetadata[s.Name]
+	}
+	if !synthesize {
+		for _, d := range m.dst {
+			if !isPaddingMeta(d) && !srcNames[d.Name] {
+				return nil, fmt.Errorf("%s metadata %s cannot be synthesized", dst.GetPreamble().GetName(), d.Name)
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m *packetMetaMapping) translate(src []*p4v1.PacketMetadata) ([]*p4v1.PacketMetadata, error) {
+	values := make(map[uint32][]byte)
+	for _, s := range src {
+		width, ok := m.srcWidths[s.MetadataId]
+		if !ok {
+			return nil, fmt.Errorf("invalid metadata ID %d", s.MetadataId)
+		}
+		value := canonicalBytes(s.Value)
+		if len(value) == 0 {
+			value = []byte{0}
+		}
+		if bitLen(value) > int(width) {
+			return nil, fmt.Errorf("value %x of metadata ID %d exceeds %d bits", s.Value, s.MetadataId, width)
+		}
+		dstId, ok := m.ids[s.MetadataId]
+		if !ok {
+			continue
+		}
+		if m.srcPorts[s.MetadataId] {
+			mapped, err := m.mapPort(value)
+			if err != nil {
+				return nil, err
+			}
+			value = mapped
+		}
+		values[dstId] = value
+	}
+	dst := make([]*p4v1.PacketMetadata, len(m.dst))
+	for i, d := range m.dst {
+		value, ok := values[d.Id]
+		if !ok {
+			value = []byte{0}
+		} else if bitLen(value) > int(d.Bitwidth) {
+			// Might happen when mapping ports.
+			return nil, fmt.Errorf("value %x of metadata %s exceeds %d bits", value, d.Name, d.Bitwidth)
+		}
+		dst[i] = &p4v1.PacketMetadata{MetadataId: d.Id, Value: value}
+	}
+	return dst, nil
+}
+
+// Returns the number of bits required to represent the given big-endian value.
+func bitLen(b []byte) int {
+	b = canonicalBytes(b)
+	if len(b) == 0 {
+		return 0
+	}
+	return (len(b)-1)*8 + bits.Len8(b[0])
+}
+
+// Creates a new PacketIoTranslator, returning an error if the controller headers of the given P4Infos are not
+// compatible. Packets can only be translated in the directions for which both P4Infos define a controller header.
+// Port metadata are translated using the given PortMap, nil for the identity.
+func NewPacketIoTranslator(logical, target *p4confv1.P4Info, ports *PortMap) (*PacketIoTranslator, error) {
+	t := &PacketIoTranslator{}
+	logicalIn := findControllerHeader(logical, ControllerHeaderPacketIn)
+	targetIn := findControllerHeader(target, ControllerHeaderPacketIn)
+	if logicalIn != nil && targetIn != nil {
+		m, err := newPacketMetaMapping(targetIn, logicalIn, false, true, ports.ToLogical)
+		if err != nil {
+			return nil, err
+		}
+		t.packetIn = m
+	}
+	logicalOut := findControllerHeader(logical, ControllerHeaderPacketOut)
+	targetOut := findControllerHeader(target, ControllerHeaderPacketOut)
+	if logicalOut != nil && targetOut != nil {
+		m, err := newPacketMetaMapping(logicalOut, targetOut, true, false, ports.ToTarget)
+		if err != nil {
+			return nil, err
+		}
+		t.packetOut = m
+	}
+	return t, nil
+}
+
+// Translates the given PacketIn from the target to the logical pipeline.
+func (t *PacketIoTranslator) TranslatePacketIn(p *p4v1.PacketIn) (*p4v1.PacketIn, error) {
+	if t.packetIn == nil {
+		return nil, fmt.Errorf("%s not supported by both logical and target pipelines", ControllerHeaderPacketIn)
+	}
+	metadata, err := t.packetIn.translate(p.Metadata)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", ControllerHeaderPacketIn, err)
+	}
+	return &p4v1.PacketIn{Payload: p.Payload, Metadata: metadata}, nil
+}
+
+// Translates the given PacketOut from the logical to the target pipeline.
+func (t *PacketIoTranslator) TranslatePacketOut(p *p4v1.PacketOut) (*p4v1.PacketOut, error) {
+	if t.packetOut == nil {
+		return nil, fmt.Errorf("%s not supported by both logical and target pipelines", ControllerHeaderPacketOut)
+	}
+	metadata, err := t.packetOut.translate(p.Metadata)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", ControllerHeaderPacketOut, err)
+	}
+	return &p4v1.PacketOut{Payload: p.Payload, Metadata: metadata}, nil
+}
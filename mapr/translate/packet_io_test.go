@@ -0,0 +1,123 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package translate
+
+import (
+	p4confv1 "github.com/p4lang/p4runtime/go/p4/config/v1"
+	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type mockMeta struct {
+	id    uint32
+	name  string
+	width int32
+}
+
+func mockP4Info(packetIn []mockMeta, packetOut []mockMeta) *p4confv1.P4Info {
+	p4info := &p4confv1.P4Info{}
+	for name, metas := range map[string][]mockMeta{ControllerHeaderPacketIn: packetIn, ControllerHeaderPacketOut: packetOut} {
+		if metas == nil {
+			continue
+		}
+		h := &p4confv1.ControllerPacketMetadata{Preamble: &p4confv1.Preamble{Name: name}}
+		for _, m := range metas {
+			h.Metadata = append(h.Metadata, &p4confv1.ControllerPacketMetadata_Metadata{
+				Id: m.id, Name: m.name, Bitwidth: m.width})
+		}
+		p4info.ControllerPacketMetadata = append(p4info.ControllerPacketMetadata, h)
+	}
+	return p4info
+}
+
+var mockLogicalPacketIoP4Info = mockP4Info(
+	[]mockMeta{{1, "ingress_port", 9}, {2, "_pad", 7}},
+	[]mockMeta{{1, "egress_port", 9}, {2, "_pad", 7}})
+
+// Same fields with different IDs, plus target-specific ones and a wider egress port.
+var mockTargetPacketIoP4Info = mockP4Info(
+	[]mockMeta{{1, "_pad0", 7}, {2, "ingress_port", 9}, {3, "punt_reason", 8}},
+	[]mockMeta{{1, "do_forwarding", 1}, {2, "egress_port", 16}, {3, "_pad0", 7}})
+
+func meta(id uint32, value ...byte) *p4v1.PacketMetadata {
+	return &p4v1.PacketMetadata{MetadataId: id, Value: value}
+}
+
+func Test_PacketIoTranslator_PacketIn(t *testing.T) {
+	trn, err := NewPacketIoTranslator(mockLogicalPacketIoP4Info, mockTargetPacketIoP4Info, nil)
+	require.NoError(t, err)
+	got, err := trn.TranslatePacketIn(&p4v1.PacketIn{
+		Payload:  []byte{0xAB},
+		Metadata: []*p4v1.PacketMetadata{meta(1, 0x00), meta(2, 0x00, 0x01, 0x05), meta(3, 0x02)},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &p4v1.PacketIn{
+		Payload:  []byte{0xAB},
+		Metadata: []*p4v1.PacketMetadata{meta(1, 0x01, 0x05), meta(2, 0x00)},
+	}, got, "should strip target-only metadata and set padding to zero")
+
+	_, err = trn.TranslatePacketIn(&p4v1.PacketIn{Metadata: []*p4v1.PacketMetadata{meta(4, 0x00)}})
+	assert.Error(t, err, "unknown metadata ID")
+	_, err = trn.TranslatePacketIn(&p4v1.PacketIn{Metadata: []*p4v1.PacketMetadata{meta(3, 0x01, 0x00)}})
+	assert.Error(t, err, "value exceeding width")
+}
+
+func Test_PacketIoTranslator_PacketOut(t *testing.T) {
+	trn, err := NewPacketIoTranslator(mockLogicalPacketIoP4Info, mockTargetPacketIoP4Info, nil)
+	require.NoError(t, err)
+	got, err := trn.TranslatePacketOut(&p4v1.PacketOut{
+		Payload:  []byte{0xAB},
+		Metadata: []*p4v1.PacketMetadata{meta(1, 0x01, 0x05), meta(2, 0x00)},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &p4v1.PacketOut{
+		Payload:  []byte{0xAB},
+		Metadata: []*p4v1.PacketMetadata{meta(1, 0x00), meta(2, 0x01, 0x05), meta(3, 0x00)},
+	}, got, "should synthesize target-only metadata")
+
+	_, err = trn.TranslatePacketOut(&p4v1.PacketOut{Metadata: []*p4v1.PacketMetadata{meta(1, 0x02, 0x00)}})
+	assert.Error(t, err, "value exceeding width")
+}
+
+func Test_NewPacketIoTranslator_Untranslatable(t *testing.T) {
+	tests := []struct {
+		name    string
+		logical *p4confv1.P4Info
+		target  *p4confv1.P4Info
+	}{
+		{
+			name:    "packet-out field narrower in target",
+			logical: mockP4Info(nil, []mockMeta{{1, "egress_port", 16}}),
+			target:  mockP4Info(nil, []mockMeta{{1, "egress_port", 9}}),
+		},
+		{
+			name:    "packet-out field missing in target",
+			logical: mockP4Info(nil, []mockMeta{{1, "egress_port", 9}, {2, "queue_id", 5}}),
+			target:  mockP4Info(nil, []mockMeta{{1, "egress_port", 9}}),
+		},
+		{
+			name:    "packet-in field missing in target",
+			logical: mockP4Info([]mockMeta{{1, "ingress_port", 9}, {2, "punt_reason", 8}}, nil),
+			target:  mockP4Info([]mockMeta{{1, "ingress_port", 9}}, nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewPacketIoTranslator(tt.logical, tt.target, nil)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func Test_PacketIoTranslator_MissingHeader(t *testing.T) {
+	trn, err := NewPacketIoTranslator(mockP4Info([]mockMeta{{1, "ingress_port", 9}}, nil), mockTargetPacketIoP4Info, nil)
+	require.NoError(t, err)
+	_, err = trn.TranslatePacketOut(&p4v1.PacketOut{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,229 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package translate
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// A bidirectional mapping between logical port numbers, as used by the control plane (e.g., front-panel numbering), and
+// target ones (e.g., SDK port IDs). A nil *PortMap is the identity mapping.
+type PortMap struct {
+	toTarget  map[uint32]uint32
+	toLogical map[uint32]uint32
+}
+
+// An entry of a port map file.
+type PortMapEntry struct {
+	LogicalPort uint32 `json:"logical_port"`
+	TargetPort  uint32 `json:"target_port"`
+}
+
+// Creates a new PortMap from the given entries. Returns an error if a logical or target port is mapped more than once.
+func NewPortMap(entries []PortMapEntry) (*PortMap, error) {
+	m := &PortMap{
+		toTarget:  make(map[uint32]uint32),
+		toLogical: make(map[uint32]uint32),
+	}
+	for _, e := range entries {
+		if _, ok := m.toTarget[e.LogicalPort]; ok {
+			return nil, fmt.Errorf("logical port %d is mapped more than once", e.LogicalPort)
+		}
+		if _, ok := m.toLogical[e.TargetPort]; ok {
+			return nil, fmt.Errorf("target port %d is mapped more than once", e.TargetPort)
+		}
+		m.toTarget[e.LogicalPort] = e.TargetPort
+		m.toLogical[e.TargetPort] = e.LogicalPort
+	}
+	return m, nil
+}
+
+// Loads a PortMap from a JSON file containing a list of PortMapEntry, e.g.:
+// [{"logical_port": 1, "target_port": 260}, {"logical_port": 2, "target_port": 268}]
+func LoadPortMapFile(path string) (*PortMap, error) {
+	bytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var entries []PortMapEntry
+	if err := json.Unmarshal(bytes, &entries); err != nil {
+		return nil, fmt.Errorf("invalid port map file %s: %v", path, err)
+	}
+	return NewPortMap(entries)
+}
+
+// Loads a PortMap from a Stratum chassis config file in protobuf text format (e.g., chassis_config.pb.txt). Logical
+// ports are the front-panel port numbers (singleton_ports.port), target ones are the port IDs used by P4Runtime
+// (singleton_ports.id). Channelized ports are not supported, as they cannot be identified by a front-panel number.
+func LoadPortMapChassisConfig(path string) (*PortMap, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var entries []PortMapEntry
+	// A minimal parser of the text format, we only care about the top-level singleton_ports blocks and their scalar
+	// fields, while nested blocks (e.g., config_params) are skipped.
+	depth := 0
+	var port map[string]string
+	scanner := bufio.NewScanner(f)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		switch {
+		case line == "":
+		case strings.HasSuffix(line, "{"):
+			if depth == 0 && strings.TrimSpace(strings.TrimSuffix(line, "{")) == "singleton_ports" {
+				port = make(map[string]string)
+			}
+			depth++
+		case line == "}":
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("%s:%d: unbalanced braces", path, lineNum)
+			}
+			if depth == 0 && port != nil {
+				e, err := parseChassisConfigPort(port)
+				if err != nil {
+					return nil, fmt.Errorf("%s:%d: %v", path, lineNum, err)
+				}
+				entries = append(entries, e)
+				port = nil
+			}
+		default:
+			if depth != 1 || port == nil {
+				continue
+			}
+			kv := strings.SplitN(line, ":", 2)
+			if len(kv) != 2 {
+				return nil, fmt.Errorf("%s:%d: cannot parse %q", path, lineNum, line)
+			}
+			port[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("%s: unbalanced braces", path)
+	}
+	return NewPortMap(entries)
+}
+
+// Returns the PortMapEntry for the given singleton port fields.
+func parseChassisConfigPort(fields map[string]string) (PortMapEntry, error) {
+	id, err := strconv.ParseUint(fields["id"], 10, 32)
+	if err != nil {
+		return PortMapEntry{}, fmt.Errorf("invalid singleton port id %q", fields["id"])
+	}
+	port, err := strconv.ParseUint(fields["port"], 10, 32)
+	if err != nil {
+		return PortMapEntry{}, fmt.Errorf("invalid port of singleton port %d: %q", id, fields["port"])
+	}
+	if channel, ok := fields["channel"]; ok && channel != "0" && channel != "1" {
+		return PortMapEntry{}, fmt.Errorf("singleton port %d is channelized, not supported", id)
+	}
+	return PortMapEntry{LogicalPort: uint32(port), TargetPort: uint32(id)}, nil
+}
+
+// Returns the target port for the given logical one, encoded with at least as many bytes as the given value.
+func (m *PortMap) ToTarget(port []byte) ([]byte, error) {
+	if m == nil {
+		return port, nil
+	}
+	return mapPort(m.toTarget, port, "logical")
+}
+
+// Returns the logical port for the given target one, encoded with at least as many bytes as the given value.
+func (m *PortMap) ToLogical(port []byte) ([]byte, error) {
+	if m == nil {
+		return port, nil
+	}
+	return mapPort(m.toLogical, port, "target")
+}
+
+func mapPort(m map[uint32]uint32, port []byte, kind string) ([]byte, error) {
+	if port == nil {
+		return nil, nil
+	}
+	b := canonicalBytes(port)
+	if len(b) > 4 {
+		return nil, fmt.Errorf("invalid %s port %x", kind, port)
+	}
+	var p uint32
+	for _, x := range b {
+		p = p<<8 | uint32(x)
+	}
+	mapped, ok := m[p]
+	if !ok {
+		return nil, fmt.Errorf("%s port %d is not mapped", kind, p)
+	}
+	n := len(port)
+	for ; n < 4 && mapped>>(8*uint(n)) != 0; n++ {
+	}
+	value := make([]byte, n)
+	for i := n - 1; i >= 0; i-- {
+		value[i] = byte(mapped)
+		mapped >>= 8
+	}
+	return value, nil
+}
+
+// Returns the target value and mask of the given ternary match on a logical port field of the given width. Only exact
+// (all ones mask) and wildcard (zero mask) matches can be mapped, since the target numbering does not preserve the bits
+// of logical ports. The target mask of exact matches covers targetWidth bits.
+func (m *PortMap) TernaryToTarget(value []byte, mask []byte, width int, targetWidth int) ([]byte, []byte, error) {
+	if m == nil || bitLen(mask) == 0 {
+		return value, mask, nil
+	}
+	if !isFullMask(mask, width) {
+		return nil, nil, fmt.Errorf("cannot map port %x with mask %x", value, mask)
+	}
+	mapped, err := m.ToTarget(value)
+	if err != nil {
+		return nil, nil, err
+	}
+	return mapped, fullMask(targetWidth), nil
+}
+
+// Returns true if the given mask has all ones in the lowest width bits, and zeros elsewhere.
+func isFullMask(mask []byte, width int) bool {
+	b := canonicalBytes(mask)
+	if bitLen(b) != width {
+		return false
+	}
+	for i, x := range b {
+		if i == 0 {
+			if x&(x+1) != 0 {
+				return false
+			}
+		} else if x != 0xFF {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns a mask with all ones in the lowest width bits.
+func fullMask(width int) []byte {
+	mask := make([]byte, (width+7)/8)
+	for i := range mask {
+		mask[i] = 0xFF
+	}
+	if r := width % 8; r != 0 {
+		mask[0] = byte(1<<uint(r) - 1)
+	}
+	return mask
+}
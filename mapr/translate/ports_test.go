@@ -0,0 +1,104 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package translate
+
+import (
+	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func mockPortMap(t *testing.T) *PortMap {
+	m, err := NewPortMap([]PortMapEntry{{1, 260}, {2, 268}})
+	require.NoError(t, err)
+	return m
+}
+
+func Test_PortMap(t *testing.T) {
+	m := mockPortMap(t)
+	tests := []struct {
+		name    string
+		f       func([]byte) ([]byte, error)
+		port    []byte
+		want    []byte
+		wantErr bool
+	}{
+		{"to target", m.ToTarget, []byte{0x00, 0x01}, []byte{0x01, 0x04}, false},
+		{"to target widens value", m.ToTarget, []byte{0x02}, []byte{0x01, 0x0C}, false},
+		{"to logical keeps width", m.ToLogical, []byte{0x01, 0x04}, []byte{0x00, 0x01}, false},
+		{"unmapped logical port", m.ToTarget, []byte{0x00, 0x03}, nil, true},
+		{"unmapped target port", m.ToLogical, []byte{0x00, 0x01}, nil, true},
+		{"nil port", m.ToTarget, nil, nil, false},
+		{"nil map is identity", (*PortMap)(nil).ToTarget, []byte{0x00, 0x03}, []byte{0x00, 0x03}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.f(tt.port)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_NewPortMap_Duplicates(t *testing.T) {
+	_, err := NewPortMap([]PortMapEntry{{1, 260}, {1, 268}})
+	assert.Error(t, err)
+	_, err = NewPortMap([]PortMapEntry{{1, 260}, {2, 260}})
+	assert.Error(t, err)
+}
+
+func Test_PortMap_TernaryToTarget(t *testing.T) {
+	m := mockPortMap(t)
+	value, mask, err := m.TernaryToTarget([]byte{0x00, 0x01}, []byte{0x01, 0xFF}, 9, 9)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x01, 0x04}, value)
+	assert.Equal(t, []byte{0x01, 0xFF}, mask)
+	_, _, err = m.TernaryToTarget([]byte{0x00, 0x01}, []byte{0x00, 0xFF}, 9, 9)
+	assert.Error(t, err, "partial masks cannot be mapped")
+}
+
+func Test_LoadPortMapFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mapr")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "port_map.json")
+	require.NoError(t, ioutil.WriteFile(path, []byte(`[{"logical_port": 1, "target_port": 260}]`), 0644))
+	m, err := LoadPortMapFile(path)
+	require.NoError(t, err)
+	got, err := m.ToTarget([]byte{0x01})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x01, 0x04}, got)
+}
+
+func Test_LoadPortMapChassisConfig(t *testing.T) {
+	m, err := LoadPortMapChassisConfig("../../ptf/lib/chassis_config.pb.txt")
+	require.NoError(t, err)
+	assert.Len(t, m.toTarget, 8)
+	got, err := m.ToTarget([]byte{0x00, 0x08})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x00, 0x08}, got)
+}
+
+func Test_PacketIoTranslator_Ports(t *testing.T) {
+	trn, err := NewPacketIoTranslator(mockLogicalPacketIoP4Info, mockTargetPacketIoP4Info, mockPortMap(t))
+	require.NoError(t, err)
+	in, err := trn.TranslatePacketIn(&p4v1.PacketIn{Metadata: []*p4v1.PacketMetadata{meta(2, 0x01, 0x0C)}})
+	assert.NoError(t, err)
+	assert.Equal(t, []*p4v1.PacketMetadata{meta(1, 0x00, 0x02), meta(2, 0x00)}, in.Metadata)
+	out, err := trn.TranslatePacketOut(&p4v1.PacketOut{Metadata: []*p4v1.PacketMetadata{meta(1, 0x01)}})
+	assert.NoError(t, err)
+	assert.Equal(t, []*p4v1.PacketMetadata{meta(1, 0x00), meta(2, 0x01, 0x04), meta(3, 0x00)}, out.Metadata)
+	_, err = trn.TranslatePacketOut(&p4v1.PacketOut{Metadata: []*p4v1.PacketMetadata{meta(1, 0x05)}})
+	assert.Error(t, err, "unmapped port")
+}
@@ -0,0 +1,273 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package translate
+
+import (
+	"encoding/hex"
+	"fmt"
+	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
+	"math/big"
+	"net"
+	"strings"
+)
+
+// Kinds of logical objects.
+const (
+	ObjectIfType       = "if_type"
+	ObjectMyStation    = "my_station"
+	ObjectAttachment   = "attachment"
+	ObjectRouteV4      = "route_v4"
+	ObjectNextHopGroup = "next_hop_group"
+	ObjectNextHop      = "next_hop"
+	ObjectAcl          = "acl"
+	ObjectPppoePunt    = "pppoe_punt"
+)
+
+// Identifies an object of the LogicalStore.
+type ObjectId struct {
+	// One of the Object constants, e.g., ObjectAttachment.
+	Kind string
+	// Unique among the objects of the same kind, e.g., "up/42" for the upstream attachment of line 42.
+	Key string
+}
+
+func (o ObjectId) String() string {
+	return o.Kind + "/" + o.Key
+}
+
+// Returns the given byte value as a decimal number.
+func decimal(b []byte) string {
+	return new(big.Int).SetBytes(b).String()
+}
+
+// Returns the given byte value as a dotted IPv4 address, or as hex if too wide.
+func ipv4String(b []byte) string {
+	b = canonicalBytes(b)
+	if len(b) > net.IPv4len {
+		return hex.EncodeToString(b)
+	}
+	addr := make(net.IP, net.IPv4len)
+	copy(addr[net.IPv4len-len(b):], b)
+	return addr.String()
+}
+
+func directionKey(d Direction) string {
+	return strings.ToLower(string(d))
+}
+
+func (i IfTypeEntry) ObjectId() ObjectId {
+	return ObjectId{Kind: ObjectIfType, Key: decimal(i.Port)}
+}
+
+func (m MyStationEntry) ObjectId() ObjectId {
+	return ObjectId{Kind: ObjectMyStation, Key: decimal(m.Port)}
+}
+
+func (a AttachmentEntry) ObjectId() ObjectId {
+	return ObjectId{Kind: ObjectAttachment, Key: directionKey(a.Direction) + "/" + decimal(a.LineId)}
+}
+
+func (r RouteV4Entry) ObjectId() ObjectId {
+	return ObjectId{Kind: ObjectRouteV4,
+		Key: fmt.Sprintf("%s/%s/%d", directionKey(r.Direction), ipv4String(r.Ipv4Addr), r.PrefixLen)}
+}
+
+func (n NextHopGroup) ObjectId() ObjectId {
+	return ObjectId{Kind: ObjectNextHopGroup, Key: fmt.Sprint(n.GroupId)}
+}
+
+func (n NextHopEntry) ObjectId() ObjectId {
+	return ObjectId{Kind: ObjectNextHop, Key: fmt.Sprint(n.Id)}
+}
+
+func (a AclEntry) ObjectId() ObjectId {
+	return ObjectId{Kind: ObjectAcl, Key: hex.EncodeToString([]byte(ToAclKey(&a)))}
+}
+
+func (c PppoePuntedEntry) ObjectId() ObjectId {
+	return ObjectId{Kind: ObjectPppoePunt, Key: string(ToCtrlPuntedKey(c.PppoeCode, c.PppoeProto))}
+}
+
+// Returns the ID of the logical object modified by writing the given logical entity.
+func ObjectIdOf(e *p4v1.Entity) (ObjectId, error) {
+	switch x := e.Entity.(type) {
+	case *p4v1.Entity_TableEntry:
+		t := x.TableEntry
+		switch t.TableId {
+		case Table_IngressPipeIfTypes:
+			y, err := parseIfTypeEntry(t)
+			return y.ObjectId(), err
+		case Table_IngressPipeMyStations:
+			y, err := parseMyStationEntry(t)
+			return y.ObjectId(), err
+		case Table_IngressPipeUpstreamLines:
+			a := AttachmentEntry{}
+			err := parseUpstreamLineEntry(t, &a)
+			return a.ObjectId(), err
+		case Table_IngressPipeUpstreamAttachmentsV4:
+			a := AttachmentEntry{}
+			err := parseUpstreamAttachmentV4Entry(t, &a)
+			return a.ObjectId(), err
+		case Table_IngressPipeDownstreamLinesV4:
+			a := AttachmentEntry{}
+			err := parseDownstreamLinesV4Entry(t, &a)
+			return a.ObjectId(), err
+		case Table_IngressPipeDownstreamAttachmentsV4:
+			a := AttachmentEntry{}
+			err := parseDownstreamAttachmentsV4(t, &a)
+			return a.ObjectId(), err
+		case Table_IngressPipeUpstreamRoutesV4:
+			y, err := parseUpstreamRouteV4Entry(t)
+			return y.ObjectId(), err
+		case Table_IngressPipeAclAcls:
+			y, err := parseAclEntry(t)
+			return y.ObjectId(), err
+		case Table_IngressPipeUpstreamPppoePunts:
+			y, err := parsePppoePunts(t)
+			return y.ObjectId(), err
+		}
+	case *p4v1.Entity_ActionProfileGroup:
+		if x.ActionProfileGroup.ActionProfileId == ActionProfile_IngressPipeUpstreamEcmp {
+			y, err := parseUpstreamRoutesV4ActProfGroup(x.ActionProfileGroup)
+			return y.ObjectId(), err
+		}
+	case *p4v1.Entity_ActionProfileMember:
+		if x.ActionProfileMember.ActionProfileId == ActionProfile_IngressPipeUpstreamEcmp {
+			y, err := parseUpstreamRoutesV4ActProfMember(x.ActionProfileMember)
+			return y.ObjectId(), err
+		}
+	}
+	return ObjectId{}, fmt.Errorf("%v is not a logical object", e)
+}
+
+// Identifies a target entity among the ones of all kinds, see EntityKeyOf.
+type EntityKey string
+
+// Prefixes telling apart the keys of different kinds, e.g., action profile members and groups.
+const (
+	entityKeyTableEntry    = "t"
+	entityKeyActProfMember = "m"
+	entityKeyActProfGroup  = "g"
+)
+
+// Returns the key of the given target entity. Only table entries and action profile members and groups, i.e., the
+// entities produced by processors, are supported, ok is false for other kinds.
+func EntityKeyOf(e *p4v1.Entity) (k EntityKey, ok bool) {
+	switch x := e.GetEntity().(type) {
+	case *p4v1.Entity_TableEntry:
+		return EntityKey(entityKeyTableEntry + KeyFromTableEntry(x.TableEntry)), true
+	case *p4v1.Entity_ActionProfileMember:
+		return EntityKey(entityKeyActProfMember + KeyFromActProfMember(x.ActionProfileMember)), true
+	case *p4v1.Entity_ActionProfileGroup:
+		return EntityKey(entityKeyActProfGroup + KeyFromActProfGroup(x.ActionProfileGroup)), true
+	default:
+		return "", false
+	}
+}
+
+// Returns the entity with the given key in the given store, nil if not found.
+func LookupEntity(s P4RtStore, k EntityKey) *p4v1.Entity {
+	if len(k) == 0 {
+		return nil
+	}
+	key := string(k[1:])
+	switch string(k[:1]) {
+	case entityKeyTableEntry:
+		if x := s.GetTableEntry(&key); x != nil {
+			return tableEntryEntity(x)
+		}
+	case entityKeyActProfMember:
+		if x := s.GetActProfMember(&key); x != nil {
+			return actProfMemberEntity(x)
+		}
+	case entityKeyActProfGroup:
+		if x := s.GetActProfGroup(&key); x != nil {
+			return actProfGroupEntity(x)
+		}
+	}
+	return nil
+}
+
+// Slices of keys and IDs in the provenance maps are shared with snapshots, hence they are replaced rather than
+// modified.
+
+func addEntityKey(keys []EntityKey, k EntityKey) []EntityKey {
+	for _, x := range keys {
+		if x == k {
+			return keys
+		}
+	}
+	return append(keys[:len(keys):len(keys)], k)
+}
+
+func removeEntityKey(keys []EntityKey, k EntityKey) []EntityKey {
+	result := make([]EntityKey, 0, len(keys))
+	for _, x := range keys {
+		if x != k {
+			result = append(result, x)
+		}
+	}
+	return result
+}
+
+func addObjectId(ids []ObjectId, id ObjectId) []ObjectId {
+	for _, x := range ids {
+		if x == id {
+			return ids
+		}
+	}
+	return append(ids[:len(ids):len(ids)], id)
+}
+
+func removeObjectId(ids []ObjectId, id ObjectId) []ObjectId {
+	result := make([]ObjectId, 0, len(ids))
+	for _, x := range ids {
+		if x != id {
+			result = append(result, x)
+		}
+	}
+	return result
+}
+
+// Records that the given target updates were produced by writing the logical object with the given ID. Target
+// entities are owned by all the objects that inserted or modified them, until deleted. Deleting the object releases
+// the entities it still owns, e.g., the ones shared with other objects.
+func (s *LogicalStore) recordProvenance(id ObjectId, uType p4v1.Update_Type, target []*p4v1.Update) {
+	for _, u := range target {
+		k, ok := EntityKeyOf(u.Entity)
+		if !ok {
+			continue
+		}
+		if u.Type == p4v1.Update_DELETE {
+			for _, owner := range s.Owners.Get(k) {
+				s.setProduced(owner, removeEntityKey(s.Produced.Get(owner), k))
+			}
+			s.Owners.Delete(k)
+		} else {
+			s.Produced.Put(id, addEntityKey(s.Produced.Get(id), k))
+			s.Owners.Put(k, addObjectId(s.Owners.Get(k), id))
+		}
+	}
+	if uType == p4v1.Update_DELETE {
+		for _, k := range s.Produced.Get(id) {
+			if owners := removeObjectId(s.Owners.Get(k), id); len(owners) > 0 {
+				s.Owners.Put(k, owners)
+			} else {
+				s.Owners.Delete(k)
+			}
+		}
+		s.Produced.Delete(id)
+	}
+}
+
+func (s *LogicalStore) setProduced(id ObjectId, keys []EntityKey) {
+	if len(keys) > 0 {
+		s.Produced.Put(id, keys)
+	} else {
+		s.Produced.Delete(id)
+	}
+}
@@ -0,0 +1,140 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package translate
+
+import (
+	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"mapr/codec"
+	"testing"
+)
+
+func Test_ObjectIdOf(t *testing.T) {
+	tests := []struct {
+		name   string
+		entity *p4v1.Entity
+		want   ObjectId
+	}{
+		{
+			name:   "if type",
+			entity: tableEntryEntity(&mockTableEntryIfTypesPort2Access),
+			want:   ObjectId{Kind: ObjectIfType, Key: "2"},
+		},
+		{
+			name: "upstream line",
+			entity: tableEntryEntity((&IngressPipeUpstreamLinesEntry{
+				Port:   []byte{0x01, 0x04},
+				CTag:   []byte{0x0f, 0xa0},
+				STag:   []byte{0x00, 0x0a},
+				Action: &IngressPipeUpstreamSetLineAction{LineId: []byte{0, 0, 0, 42}},
+			}).ToTableEntry()),
+			want: ObjectId{Kind: ObjectAttachment, Key: "up/42"},
+		},
+		{
+			name: "downstream line",
+			entity: tableEntryEntity((&IngressPipeDownstreamLinesV4Entry{
+				Ipv4Dst: []byte{10, 0, 0, 1},
+				Action:  &IngressPipeDownstreamSetLineAction{LineId: []byte{42}},
+			}).ToTableEntry()),
+			want: ObjectId{Kind: ObjectAttachment, Key: "down/42"},
+		},
+		{
+			name: "route",
+			entity: tableEntryEntity((&IngressPipeUpstreamRoutesV4Entry{
+				Ipv4Dst:              &codec.Lpm{Value: []byte{10, 0, 0, 0}, PrefixLen: 8},
+				ActionProfileGroupId: 7,
+			}).ToTableEntry()),
+			want: ObjectId{Kind: ObjectRouteV4, Key: "up/10.0.0.0/8"},
+		},
+		{
+			name: "next hop",
+			entity: actProfMemberEntity((&IngressPipeUpstreamEcmpMember{
+				MemberId: 3,
+				Action:   &IngressPipeUpstreamRouteV4Action{Port: []byte{1}, Dmac: []byte{0xaa, 0, 0, 0, 0, 1}},
+			}).ToActionProfileMember()),
+			want: ObjectId{Kind: ObjectNextHop, Key: "3"},
+		},
+		{
+			name:   "next hop group",
+			entity: actProfGroupEntity(&p4v1.ActionProfileGroup{ActionProfileId: ActionProfile_IngressPipeUpstreamEcmp, GroupId: 7}),
+			want:   ObjectId{Kind: ObjectNextHopGroup, Key: "7"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ObjectIdOf(tt.entity)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := ObjectIdOf(tableEntryEntity(&p4v1.TableEntry{TableId: 1}))
+	assert.Error(t, err)
+}
+
+func Test_EntityKeyOf(t *testing.T) {
+	store := NewP4RtStore("test")
+	store.PutActProfMember(reconcileMember(1))
+	store.PutActProfGroup(reconcileGroup(1))
+	store.PutTableEntry(reconcileTableEntry(1, 1, 1))
+	for _, e := range []*p4v1.Entity{
+		actProfMemberEntity(reconcileMember(1)),
+		actProfGroupEntity(reconcileGroup(1)),
+		tableEntryEntity(reconcileTableEntry(1, 1, 1)),
+	} {
+		k, ok := EntityKeyOf(e)
+		require.True(t, ok)
+		assert.Equal(t, e, LookupEntity(store, k))
+	}
+	// Members and groups with the same IDs have different keys.
+	memberKey, _ := EntityKeyOf(actProfMemberEntity(reconcileMember(1)))
+	groupKey, _ := EntityKeyOf(actProfGroupEntity(reconcileGroup(1)))
+	assert.NotEqual(t, memberKey, groupKey)
+
+	_, ok := EntityKeyOf(meterEntity(&p4v1.MeterEntry{MeterId: 1}))
+	assert.False(t, ok)
+	assert.Nil(t, LookupEntity(store, ""))
+}
+
+func Test_LogicalStore_recordProvenance(t *testing.T) {
+	up := ObjectId{Kind: ObjectAttachment, Key: "up/1"}
+	down := ObjectId{Kind: ObjectAttachment, Key: "down/1"}
+	shared := tableEntryEntity(reconcileTableEntry(1, 1, 0))
+	upOnly := tableEntryEntity(reconcileTableEntry(1, 2, 0))
+	downOnly := tableEntryEntity(reconcileTableEntry(1, 3, 0))
+	sharedKey, _ := EntityKeyOf(shared)
+	upKey, _ := EntityKeyOf(upOnly)
+	downKey, _ := EntityKeyOf(downOnly)
+
+	s := newLogicalStore()
+	record := func(id ObjectId, uType p4v1.Update_Type, target ...*p4v1.Update) {
+		s.BeginUpdate()
+		defer s.EndUpdate()
+		s.recordProvenance(id, uType, target)
+	}
+	record(up, p4v1.Update_INSERT, newUpdate(p4v1.Update_INSERT, shared), newUpdate(p4v1.Update_INSERT, upOnly))
+	record(down, p4v1.Update_INSERT, newUpdate(p4v1.Update_MODIFY, shared), newUpdate(p4v1.Update_INSERT, downOnly))
+	assert.Equal(t, []EntityKey{sharedKey, upKey}, s.Produced.Get(up))
+	assert.Equal(t, []EntityKey{sharedKey, downKey}, s.Produced.Get(down))
+	assert.Equal(t, []ObjectId{up, down}, s.Owners.Get(sharedKey))
+	assert.Equal(t, []ObjectId{down}, s.Owners.Get(downKey))
+
+	// The upstream attachment is removed, the shared entity is left on the target.
+	snapshot := s.Snapshot()
+	record(up, p4v1.Update_DELETE, newUpdate(p4v1.Update_DELETE, upOnly))
+	assert.Nil(t, s.Produced.Get(up))
+	assert.Nil(t, s.Owners.Get(upKey))
+	assert.Equal(t, []ObjectId{down}, s.Owners.Get(sharedKey))
+	// Snapshots are not affected.
+	assert.Equal(t, []ObjectId{up, down}, snapshot.Owners.Get(sharedKey))
+	assert.Equal(t, []EntityKey{sharedKey, upKey}, snapshot.Produced.Get(up))
+
+	record(down, p4v1.Update_DELETE, newUpdate(p4v1.Update_DELETE, shared), newUpdate(p4v1.Update_DELETE, downOnly))
+	assert.Zero(t, s.Produced.Len())
+	assert.Zero(t, s.Owners.Len())
+}
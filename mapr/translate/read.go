@@ -0,0 +1,121 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package translate
+
+import (
+	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Serving of P4Runtime reads from a store, i.e., with the entities as written by controllers.
+//
+// Counters and registers hold runtime data of the target rather than the state written by controllers, hence they
+// cannot be read from a store.
+
+// Returns the entities of the given store matching the given entity of a ReadRequest. As per the P4Runtime spec, zero
+// IDs are wildcards, e.g., a table entry with table ID 0 reads all table entries, and a table entry without match
+// fields reads all the entries of its table. Returns an UNIMPLEMENTED error for counters and registers, see above.
+// The returned entities are shared with the store, and must not be modified.
+func ReadStore(s P4RtStore, e *p4v1.Entity) ([]*p4v1.Entity, error) {
+	var entities []*p4v1.Entity
+	switch x := e.GetEntity().(type) {
+	case *p4v1.Entity_TableEntry:
+		for _, t := range readTableEntries(s, x.TableEntry) {
+			entities = append(entities, tableEntryEntity(t))
+		}
+	case *p4v1.Entity_ActionProfileMember:
+		m := x.ActionProfileMember
+		for _, y := range s.FilterActProfMembers(func(y *p4v1.ActionProfileMember) bool {
+			return matchId(m.ActionProfileId, y.ActionProfileId) && matchId(m.MemberId, y.MemberId)
+		}) {
+			entities = append(entities, actProfMemberEntity(y))
+		}
+	case *p4v1.Entity_ActionProfileGroup:
+		g := x.ActionProfileGroup
+		for _, y := range s.FilterActProfGroups(func(y *p4v1.ActionProfileGroup) bool {
+			return matchId(g.ActionProfileId, y.ActionProfileId) && matchId(g.GroupId, y.GroupId)
+		}) {
+			entities = append(entities, actProfGroupEntity(y))
+		}
+	case *p4v1.Entity_MeterEntry:
+		m := x.MeterEntry
+		for _, y := range s.FilterMeterEntries(func(y *p4v1.MeterEntry) bool {
+			return matchId(m.MeterId, y.MeterId) && (m.Index == nil || m.Index.Index == y.Index.GetIndex())
+		}) {
+			entities = append(entities, meterEntity(y))
+		}
+	case *p4v1.Entity_DirectMeterEntry:
+		t := x.DirectMeterEntry.TableEntry
+		for _, y := range s.FilterDirectMeterEntries(func(y *p4v1.DirectMeterEntry) bool {
+			return t == nil || matchTableEntry(t, y.TableEntry)
+		}) {
+			entities = append(entities, directMeterEntity(y))
+		}
+	case *p4v1.Entity_PacketReplicationEngineEntry:
+		switch y := x.PacketReplicationEngineEntry.Type.(type) {
+		case *p4v1.PacketReplicationEngineEntry_MulticastGroupEntry:
+			for _, z := range s.FilterMulticastGroups(func(z *p4v1.MulticastGroupEntry) bool {
+				return matchId(y.MulticastGroupEntry.MulticastGroupId, z.MulticastGroupId)
+			}) {
+				entities = append(entities, multicastGroupEntity(z))
+			}
+		case *p4v1.PacketReplicationEngineEntry_CloneSessionEntry:
+			for _, z := range s.FilterCloneSessions(func(z *p4v1.CloneSessionEntry) bool {
+				return matchId(y.CloneSessionEntry.SessionId, z.SessionId)
+			}) {
+				entities = append(entities, cloneSessionEntity(z))
+			}
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "invalid PacketReplicationEngineEntry type %T", y)
+		}
+	case *p4v1.Entity_ValueSetEntry:
+		for _, y := range s.FilterValueSetEntries(func(y *p4v1.ValueSetEntry) bool {
+			return matchId(x.ValueSetEntry.ValueSetId, y.ValueSetId)
+		}) {
+			entities = append(entities, valueSetEntity(y))
+		}
+	case *p4v1.Entity_CounterEntry, *p4v1.Entity_DirectCounterEntry, *p4v1.Entity_RegisterEntry:
+		return nil, status.Errorf(codes.Unimplemented, "reading %T is not supported", x)
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid entity type %T", x)
+	}
+	return entities, nil
+}
+
+// Returns true if the given ID of a read entity, 0 for a wildcard, matches the ID of a stored one.
+func matchId(read, stored uint32) bool {
+	return read == 0 || read == stored
+}
+
+// Returns true if the given table entry of a ReadRequest matches the stored one: table ID 0 matches all entries, no
+// match fields all the entries of the table, otherwise the match fields and priority must be the same.
+func matchTableEntry(read, stored *p4v1.TableEntry) bool {
+	switch {
+	case read.TableId == 0:
+		return true
+	case read.TableId != stored.TableId:
+		return false
+	case len(read.Match) == 0 && !read.IsDefaultAction:
+		return true
+	}
+	return KeyFromTableEntry(read) == KeyFromTableEntry(stored)
+}
+
+// Returns the table entries of the given store matching the given table entry of a ReadRequest, see matchTableEntry.
+func readTableEntries(s P4RtStore, t *p4v1.TableEntry) []*p4v1.TableEntry {
+	if t.TableId == 0 || len(t.Match) == 0 && !t.IsDefaultAction {
+		return s.FilterTableEntries(func(y *p4v1.TableEntry) bool {
+			return matchTableEntry(t, y)
+		})
+	}
+	key := KeyFromTableEntry(t)
+	if y := s.GetTableEntry(&key); y != nil {
+		return []*p4v1.TableEntry{y}
+	}
+	return nil
+}
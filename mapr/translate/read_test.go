@@ -0,0 +1,79 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package translate
+
+import (
+	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func Test_ReadStore(t *testing.T) {
+	s := NewP4RtStore("test")
+	s.PutTableEntry(&mockTableEntry1)
+	s.PutTableEntry(&mockTableEntry2)
+	member1 := &p4v1.ActionProfileMember{ActionProfileId: 1, MemberId: 1}
+	member2 := &p4v1.ActionProfileMember{ActionProfileId: 2, MemberId: 1}
+	s.PutActProfMember(member1)
+	s.PutActProfMember(member2)
+	group := &p4v1.ActionProfileGroup{ActionProfileId: 1, GroupId: 10}
+	s.PutActProfGroup(group)
+	meter := &p4v1.MeterEntry{MeterId: 1, Index: &p4v1.Index{Index: 3}}
+	s.PutMeterEntry(meter)
+	directMeter := &p4v1.DirectMeterEntry{TableEntry: &mockTableEntry1}
+	s.PutDirectMeterEntry(directMeter)
+	mcGroup := &p4v1.MulticastGroupEntry{MulticastGroupId: 1}
+	s.PutMulticastGroup(mcGroup)
+	tests := []struct {
+		name string
+		read *p4v1.Entity
+		want []*p4v1.Entity
+	}{
+		{"all table entries", tableEntryEntity(&p4v1.TableEntry{}),
+			[]*p4v1.Entity{tableEntryEntity(&mockTableEntry1), tableEntryEntity(&mockTableEntry2)}},
+		{"table entries of a table", tableEntryEntity(&p4v1.TableEntry{TableId: 2}),
+			[]*p4v1.Entity{tableEntryEntity(&mockTableEntry2)}},
+		{"table entry", tableEntryEntity(&reorderedMockTableEntry1),
+			[]*p4v1.Entity{tableEntryEntity(&mockTableEntry1)}},
+		{"missing table entry", tableEntryEntity(&p4v1.TableEntry{TableId: 1, Match: mockTableEntry1.Match}), nil},
+		{"all members", actProfMemberEntity(&p4v1.ActionProfileMember{}),
+			[]*p4v1.Entity{actProfMemberEntity(member1), actProfMemberEntity(member2)}},
+		{"members of a profile", actProfMemberEntity(&p4v1.ActionProfileMember{ActionProfileId: 2}),
+			[]*p4v1.Entity{actProfMemberEntity(member2)}},
+		{"group", actProfGroupEntity(&p4v1.ActionProfileGroup{ActionProfileId: 1, GroupId: 10}),
+			[]*p4v1.Entity{actProfGroupEntity(group)}},
+		{"all indexes of a meter", meterEntity(&p4v1.MeterEntry{MeterId: 1}), []*p4v1.Entity{meterEntity(meter)}},
+		{"other index of a meter", meterEntity(&p4v1.MeterEntry{MeterId: 1, Index: &p4v1.Index{Index: 4}}), nil},
+		{"direct meters of a table", directMeterEntity(&p4v1.DirectMeterEntry{TableEntry: &p4v1.TableEntry{TableId: 1}}),
+			[]*p4v1.Entity{directMeterEntity(directMeter)}},
+		{"direct meters of another table",
+			directMeterEntity(&p4v1.DirectMeterEntry{TableEntry: &p4v1.TableEntry{TableId: 2}}), nil},
+		{"all multicast groups", multicastGroupEntity(&p4v1.MulticastGroupEntry{}),
+			[]*p4v1.Entity{multicastGroupEntity(mcGroup)}},
+		{"no clone sessions", cloneSessionEntity(&p4v1.CloneSessionEntry{}), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadStore(s, tt.read)
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func Test_ReadStore_RuntimeData(t *testing.T) {
+	for _, e := range []*p4v1.Entity{
+		{Entity: &p4v1.Entity_CounterEntry{CounterEntry: &p4v1.CounterEntry{}}},
+		{Entity: &p4v1.Entity_DirectCounterEntry{DirectCounterEntry: &p4v1.DirectCounterEntry{}}},
+		{Entity: &p4v1.Entity_RegisterEntry{RegisterEntry: &p4v1.RegisterEntry{}}},
+	} {
+		_, err := ReadStore(NewP4RtStore("test"), e)
+		assert.Equal(t, codes.Unimplemented, status.Code(err), "%T", e.Entity)
+	}
+}
@@ -0,0 +1,164 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package translate
+
+import (
+	"github.com/golang/protobuf/proto"
+	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
+	"sort"
+)
+
+// Counters and registers hold runtime data rather than forwarding state, hence they are neither replayed nor
+// reconciled.
+
+func newUpdate(uType p4v1.Update_Type, e *p4v1.Entity) *p4v1.Update {
+	return &p4v1.Update{Type: uType, Entity: e}
+}
+
+func tableEntryEntity(e *p4v1.TableEntry) *p4v1.Entity {
+	return &p4v1.Entity{Entity: &p4v1.Entity_TableEntry{TableEntry: e}}
+}
+
+func actProfMemberEntity(m *p4v1.ActionProfileMember) *p4v1.Entity {
+	return &p4v1.Entity{Entity: &p4v1.Entity_ActionProfileMember{ActionProfileMember: m}}
+}
+
+func actProfGroupEntity(g *p4v1.ActionProfileGroup) *p4v1.Entity {
+	return &p4v1.Entity{Entity: &p4v1.Entity_ActionProfileGroup{ActionProfileGroup: g}}
+}
+
+func multicastGroupEntity(g *p4v1.MulticastGroupEntry) *p4v1.Entity {
+	return &p4v1.Entity{Entity: &p4v1.Entity_PacketReplicationEngineEntry{
+		PacketReplicationEngineEntry: &p4v1.PacketReplicationEngineEntry{
+			Type: &p4v1.PacketReplicationEngineEntry_MulticastGroupEntry{MulticastGroupEntry: g}}}}
+}
+
+func cloneSessionEntity(c *p4v1.CloneSessionEntry) *p4v1.Entity {
+	return &p4v1.Entity{Entity: &p4v1.Entity_PacketReplicationEngineEntry{
+		PacketReplicationEngineEntry: &p4v1.PacketReplicationEngineEntry{
+			Type: &p4v1.PacketReplicationEngineEntry_CloneSessionEntry{CloneSessionEntry: c}}}}
+}
+
+func meterEntity(m *p4v1.MeterEntry) *p4v1.Entity {
+	return &p4v1.Entity{Entity: &p4v1.Entity_MeterEntry{MeterEntry: m}}
+}
+
+func directMeterEntity(m *p4v1.DirectMeterEntry) *p4v1.Entity {
+	return &p4v1.Entity{Entity: &p4v1.Entity_DirectMeterEntry{DirectMeterEntry: m}}
+}
+
+func valueSetEntity(v *p4v1.ValueSetEntry) *p4v1.Entity {
+	return &p4v1.Entity{Entity: &p4v1.Entity_ValueSetEntry{ValueSetEntry: v}}
+}
+
+// The entities of a store of one kind, keyed as in the store.
+type entitySet map[string]*p4v1.Entity
+
+// Returns the keys of the set in ascending order, such that updates are generated deterministically.
+func (s entitySet) sortedKeys() []string {
+	keys := make([]string, 0, len(s))
+	for k := range s {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Returns the entities of the given store grouped by kind, in dependency order: action profile members before the
+// groups referring to them, PRE entries and value sets before the table entries that might refer to them, and table
+// entries before their direct meters.
+func storeEntities(s P4RtStore) []entitySet {
+	members := make(entitySet)
+	for _, x := range s.ActProfMembers() {
+		members[KeyFromActProfMember(x)] = actProfMemberEntity(x)
+	}
+	groups := make(entitySet)
+	for _, x := range s.ActProfGroups() {
+		groups[KeyFromActProfGroup(x)] = actProfGroupEntity(x)
+	}
+	pre := make(entitySet)
+	for _, x := range s.MulticastGroups() {
+		pre[KeyFromMulticastGroup(x)] = multicastGroupEntity(x)
+	}
+	for _, x := range s.CloneSessions() {
+		// Keys of multicast groups and clone sessions are both 4 bytes, tell them apart.
+		pre["c"+KeyFromCloneSession(x)] = cloneSessionEntity(x)
+	}
+	valueSets := make(entitySet)
+	for _, x := range s.ValueSetEntries() {
+		valueSets[KeyFromValueSetEntry(x)] = valueSetEntity(x)
+	}
+	meters := make(entitySet)
+	for _, x := range s.MeterEntries() {
+		meters[KeyFromMeterEntry(x)] = meterEntity(x)
+	}
+	tables := make(entitySet)
+	for _, x := range s.TableEntries() {
+		tables[KeyFromTableEntry(x)] = tableEntryEntity(x)
+	}
+	directMeters := make(entitySet)
+	for _, x := range s.DirectMeterEntries() {
+		directMeters[KeyFromDirectMeterEntry(x)] = directMeterEntity(x)
+	}
+	return []entitySet{members, groups, pre, valueSets, meters, tables, directMeters}
+}
+
+// Returns true for entities that always exist on the target, and can only be modified.
+func modifyOnly(e *p4v1.Entity) bool {
+	switch e.Entity.(type) {
+	case *p4v1.Entity_MeterEntry, *p4v1.Entity_DirectMeterEntry, *p4v1.Entity_ValueSetEntry:
+		return true
+	default:
+		return false
+	}
+}
+
+// Returns the updates that re-create the content of the given store from scratch, in dependency order.
+func StoreUpdates(s P4RtStore) []*p4v1.Update {
+	var updates []*p4v1.Update
+	for _, set := range storeEntities(s) {
+		for _, key := range set.sortedKeys() {
+			e := set[key]
+			uType := p4v1.Update_INSERT
+			if modifyOnly(e) {
+				uType = p4v1.Update_MODIFY
+			}
+			updates = append(updates, newUpdate(uType, e))
+		}
+	}
+	return updates
+}
+
+// Returns the minimal updates that bring a device from the from state to the to state, in an order that never leaves
+// dangling references: inserts and modifies follow the dependency order, while deletes follow the reverse one, after
+// all inserts and modifies.
+func DiffStores(from, to P4RtStore) []*p4v1.Update {
+	fromSets := storeEntities(from)
+	toSets := storeEntities(to)
+	var updates []*p4v1.Update
+	for i, toSet := range toSets {
+		for _, key := range toSet.sortedKeys() {
+			e := toSet[key]
+			old, ok := fromSets[i][key]
+			switch {
+			case !ok && modifyOnly(e), ok && !proto.Equal(old, e):
+				updates = append(updates, newUpdate(p4v1.Update_MODIFY, e))
+			case !ok:
+				updates = append(updates, newUpdate(p4v1.Update_INSERT, e))
+			}
+		}
+	}
+	for i := len(fromSets) - 1; i >= 0; i-- {
+		for _, key := range fromSets[i].sortedKeys() {
+			e := fromSets[i][key]
+			if _, ok := toSets[i][key]; !ok && !modifyOnly(e) {
+				updates = append(updates, newUpdate(p4v1.Update_DELETE, e))
+			}
+		}
+	}
+	return updates
+}
@@ -0,0 +1,138 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package translate
+
+import (
+	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func reconcileTableEntry(tableId uint32, value byte, groupId uint32) *p4v1.TableEntry {
+	return &p4v1.TableEntry{
+		TableId: tableId,
+		Match: []*p4v1.FieldMatch{{FieldId: 1, FieldMatchType: &p4v1.FieldMatch_Exact_{
+			Exact: &p4v1.FieldMatch_Exact{Value: []byte{value}}}}},
+		Action: &p4v1.TableAction{Type: &p4v1.TableAction_ActionProfileGroupId{ActionProfileGroupId: groupId}},
+	}
+}
+
+func reconcileGroup(memberIds ...uint32) *p4v1.ActionProfileGroup {
+	g := &p4v1.ActionProfileGroup{ActionProfileId: 1, GroupId: 1}
+	for _, id := range memberIds {
+		g.Members = append(g.Members, &p4v1.ActionProfileGroup_Member{MemberId: id, Weight: 1})
+	}
+	return g
+}
+
+func reconcileMember(id uint32) *p4v1.ActionProfileMember {
+	return &p4v1.ActionProfileMember{ActionProfileId: 1, MemberId: id, Action: &p4v1.Action{ActionId: 1}}
+}
+
+func Test_DiffStores(t *testing.T) {
+	from := NewP4RtStore("from")
+	from.PutActProfMember(reconcileMember(1))
+	from.PutActProfMember(reconcileMember(3))
+	from.PutActProfGroup(reconcileGroup(1, 3))
+	from.PutTableEntry(reconcileTableEntry(1, 1, 1))
+	from.PutTableEntry(reconcileTableEntry(1, 2, 1))
+	from.PutMeterEntry(&p4v1.MeterEntry{MeterId: 1, Config: &p4v1.MeterConfig{Cir: 10}})
+
+	to := NewP4RtStore("to")
+	to.PutActProfMember(reconcileMember(1))
+	to.PutActProfMember(reconcileMember(2))
+	to.PutActProfGroup(reconcileGroup(1, 2))
+	to.PutTableEntry(reconcileTableEntry(1, 1, 1))
+	to.PutTableEntry(reconcileTableEntry(1, 3, 1))
+	to.PutMeterEntry(&p4v1.MeterEntry{MeterId: 1, Config: &p4v1.MeterConfig{Cir: 20}})
+
+	want := []*p4v1.Update{
+		newUpdate(p4v1.Update_INSERT, actProfMemberEntity(reconcileMember(2))),
+		newUpdate(p4v1.Update_MODIFY, actProfGroupEntity(reconcileGroup(1, 2))),
+		newUpdate(p4v1.Update_MODIFY, meterEntity(&p4v1.MeterEntry{MeterId: 1, Config: &p4v1.MeterConfig{Cir: 20}})),
+		newUpdate(p4v1.Update_INSERT, tableEntryEntity(reconcileTableEntry(1, 3, 1))),
+		// Deletes last, in reverse dependency order.
+		newUpdate(p4v1.Update_DELETE, tableEntryEntity(reconcileTableEntry(1, 2, 1))),
+		newUpdate(p4v1.Update_DELETE, actProfMemberEntity(reconcileMember(3))),
+	}
+	assert.Equal(t, want, DiffStores(from, to))
+	assert.Empty(t, DiffStores(to, to))
+}
+
+func Test_StoreUpdates(t *testing.T) {
+	s := NewP4RtStore("test")
+	s.PutTableEntry(reconcileTableEntry(1, 1, 1))
+	s.PutActProfGroup(reconcileGroup(1))
+	s.PutActProfMember(reconcileMember(1))
+	s.PutValueSetEntry(&p4v1.ValueSetEntry{ValueSetId: 1})
+	s.PutCounterEntry(&p4v1.CounterEntry{CounterId: 1})
+
+	want := []*p4v1.Update{
+		newUpdate(p4v1.Update_INSERT, actProfMemberEntity(reconcileMember(1))),
+		newUpdate(p4v1.Update_INSERT, actProfGroupEntity(reconcileGroup(1))),
+		newUpdate(p4v1.Update_MODIFY, valueSetEntity(&p4v1.ValueSetEntry{ValueSetId: 1})),
+		newUpdate(p4v1.Update_INSERT, tableEntryEntity(reconcileTableEntry(1, 1, 1))),
+	}
+	assert.Equal(t, want, StoreUpdates(s))
+}
+
+func Test_store_Clear(t *testing.T) {
+	s := NewP4RtStore("test")
+	s.AddTableEntryIndex("byTable", TableIdIndex())
+	s.PutTableEntry(reconcileTableEntry(1, 1, 1))
+	s.PutActProfMember(reconcileMember(1))
+	snap := s.Snapshot()
+
+	s.Clear()
+	assert.Zero(t, s.TableEntryCount())
+	assert.Zero(t, s.ActProfMemberCount())
+	assert.Empty(t, s.TableEntriesByIndex("byTable", TableIdIndexValue(1)))
+	assert.Equal(t, 1, snap.TableEntryCount(), "snapshots are not affected")
+
+	// Indexes are still maintained.
+	s.PutTableEntry(reconcileTableEntry(1, 2, 1))
+	assert.Len(t, s.TableEntriesByIndex("byTable", TableIdIndexValue(1)), 1)
+	assert.Panics(t, func() { snap.Clear() })
+}
+
+// A processor translating each IfTypeEntry to a target table entry with the if_type as the table ID.
+type reconcileProcessor struct {
+	Processor
+}
+
+func (p reconcileProcessor) HandleIfTypeEntry(e *IfTypeEntry, uType p4v1.Update_Type) ([]*p4v1.Update, error) {
+	return []*p4v1.Update{
+		newUpdate(uType, tableEntryEntity(reconcileTableEntry(uint32(e.IfType[0]), e.Port[len(e.Port)-1], 0))),
+	}, nil
+}
+
+func Test_translator_Reconcile(t *testing.T) {
+	ctx := NewContext(nil)
+	trn := NewTranslator(reconcileProcessor{}, ctx, nil)
+	logical := NewP4RtStore("logical")
+	for _, e := range []*p4v1.TableEntry{&mockTableEntryIfTypesPort1Core, &mockTableEntryIfTypesPort2Access} {
+		u := newUpdate(p4v1.Update_INSERT, tableEntryEntity(e))
+		target, err := trn.Translate(u)
+		require.NoError(t, err)
+		require.NoError(t, trn.ApplyUpdate(u, target))
+		require.NoError(t, logical.ApplyUpdate(u, false))
+	}
+	// A stale target entry not derived from the logical state, e.g., left over before a restart.
+	stale := reconcileTableEntry(99, 1, 0)
+	ctx.Target().PutTableEntry(stale)
+
+	updates, err := trn.Reconcile(logical)
+	require.NoError(t, err)
+	assert.Equal(t, []*p4v1.Update{newUpdate(p4v1.Update_DELETE, tableEntryEntity(stale))}, updates)
+	assert.Equal(t, 2, ctx.Target().TableEntryCount())
+	assert.Equal(t, 2, ctx.Logical().IfTypes.Len())
+
+	trn.Reset()
+	assert.Zero(t, ctx.Target().TableEntryCount())
+	assert.Zero(t, ctx.Logical().IfTypes.Len())
+}
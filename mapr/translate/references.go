@@ -0,0 +1,253 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package translate
+
+import (
+	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"mapr/codec"
+	"sort"
+)
+
+// References between logical objects: routes refer to their next hop group, next hop groups to their members, and next
+// hops and complete downstream attachments to the MyStation entry of their port, whose MAC address is used as source
+// MAC. Objects cannot be deleted while referred to, unless deletes of their kind are cascaded, see Config.
+//
+// As the P4Runtime spec requires for action profiles, routes and groups cannot be inserted before the objects they refer
+// to. Instead, MyStation entries are prerequisites that controllers might write in any order, e.g., after a restart:
+// objects written before them are accepted but not translated, i.e., pending, until all the objects they refer to are
+// written and translated.
+
+// The references of an object to others.
+type References struct {
+	// The objects referred to.
+	To []ObjectId
+	// The logical entities of the object, to delete it when cascading the delete of an object it refers to.
+	Entities []*p4v1.Entity
+}
+
+// A reference to an object, whether the object exists and is pending, and whether the object is a prerequisite that
+// can be written after the referrer.
+type reference struct {
+	to           ObjectId
+	exists       bool
+	pending      bool
+	prerequisite bool
+}
+
+// A logical object that is not translated yet, as it waits for other objects to be written or translated.
+type PendingObject struct {
+	// The logical entity of the object, translated once ready.
+	Entity *p4v1.Entity
+	// The objects waiting for, either missing prerequisites or pending objects. Empty if the object is ready, but
+	// translating it failed.
+	Waiting []ObjectId
+}
+
+// Returns the references of the given object, one of the types stored in the LogicalStore, to others.
+func (s *LogicalStore) referencesOf(o interface{}) []reference {
+	var refs []reference
+	myStation := func(port []byte) {
+		refs = append(refs, reference{to: MyStationEntry{Port: port}.ObjectId(),
+			exists: s.MyStations.Get(ToPortKey(port)) != nil, prerequisite: true})
+	}
+	add := func(to ObjectId, exists bool) {
+		refs = append(refs, reference{to: to, exists: exists, pending: s.Pending.Get(to) != nil})
+	}
+	switch x := o.(type) {
+	case *RouteV4Entry:
+		add(NextHopGroup{GroupId: x.NextHopGroupId}.ObjectId(), s.UpstreamNextHopGroups.Get(x.NextHopGroupId) != nil)
+	case *NextHopGroup:
+		for _, m := range x.Members {
+			add(NextHopEntry{Id: m.MemberId}.ObjectId(), s.UpstreamNextHopEntries.Get(m.MemberId) != nil)
+		}
+	case *NextHopEntry:
+		myStation(x.Port)
+	case *AttachmentEntry:
+		// Incomplete attachments are not translated, hence they do not use the MAC address yet.
+		if x.Direction == DirectionDownstream && len(x.MissingFields()) == 0 {
+			myStation(x.Port)
+		}
+	}
+	return refs
+}
+
+// Returns the logical entities of the given object, which must be one referring to others.
+func referrerEntities(o interface{}) []*p4v1.Entity {
+	switch x := o.(type) {
+	case *RouteV4Entry:
+		return []*p4v1.Entity{tableEntryEntity((&IngressPipeUpstreamRoutesV4Entry{
+			Ipv4Dst:              &codec.Lpm{Value: x.Ipv4Addr, PrefixLen: x.PrefixLen},
+			ActionProfileGroupId: x.NextHopGroupId,
+		}).ToTableEntry())}
+	case *NextHopGroup:
+		g := p4v1.ActionProfileGroup(*x)
+		return []*p4v1.Entity{actProfGroupEntity(&g)}
+	case *NextHopEntry:
+		return []*p4v1.Entity{actProfMemberEntity((&IngressPipeUpstreamEcmpMember{
+			MemberId: x.Id,
+			Action:   &IngressPipeUpstreamRouteV4Action{Port: x.Port, Dmac: x.MacAddr},
+		}).ToActionProfileMember())}
+	case *AttachmentEntry:
+		return []*p4v1.Entity{
+			tableEntryEntity((&IngressPipeDownstreamAttachmentsV4Entry{
+				LineId: x.LineId,
+				Action: &IngressPipeDownstreamSetPppoeAttachmentV4Action{
+					Port:        x.Port,
+					Dmac:        x.MacAddr,
+					STag:        x.STag,
+					CTag:        x.CTag,
+					PppoeSessId: x.PppoeSessId,
+				},
+			}).ToTableEntry()),
+			tableEntryEntity((&IngressPipeDownstreamLinesV4Entry{
+				Ipv4Dst: x.Ipv4Addr,
+				Action:  &IngressPipeDownstreamSetLineAction{LineId: x.LineId},
+			}).ToTableEntry()),
+		}
+	}
+	panic("not an object referring to others")
+}
+
+// Returns the objects the given object, about to be inserted or modified, waits for: missing prerequisites and pending
+// objects. Returns a NOT_FOUND error if the object refers to other missing objects.
+func (s *LogicalStore) waitingFor(id ObjectId, o interface{}) ([]ObjectId, error) {
+	var waiting []ObjectId
+	for _, r := range s.referencesOf(o) {
+		switch {
+		case !r.exists && !r.prerequisite:
+			return nil, status.Errorf(codes.NotFound, "%s refers to %s, which does not exist", id, r.to)
+		case !r.exists, r.pending:
+			waiting = addObjectId(waiting, r.to)
+		}
+	}
+	return waiting, nil
+}
+
+// Returns a FAILED_PRECONDITION error if the object with the given ID, about to be deleted, is referred to by others.
+func (s *LogicalStore) checkNotReferred(id ObjectId) error {
+	if referrers := s.Referrers.Get(id); len(referrers) > 0 {
+		return status.Errorf(codes.FailedPrecondition, "%s is referred to by %d objects, e.g., %s", id,
+			len(referrers), referrers[0])
+	}
+	return nil
+}
+
+// Records the references of the given object, replacing the previous ones of the object with the given ID. o is nil if
+// the object was deleted.
+func (s *LogicalStore) setReferences(id ObjectId, o interface{}) {
+	if old := s.References.Get(id); old != nil {
+		for _, to := range old.To {
+			if referrers := removeObjectId(s.Referrers.Get(to), id); len(referrers) > 0 {
+				s.Referrers.Put(to, referrers)
+			} else {
+				s.Referrers.Delete(to)
+			}
+		}
+		s.References.Delete(id)
+	}
+	if o == nil {
+		return
+	}
+	refs := s.referencesOf(o)
+	if len(refs) == 0 {
+		return
+	}
+	r := &References{Entities: referrerEntities(o)}
+	for _, ref := range refs {
+		r.To = addObjectId(r.To, ref.to)
+		s.Referrers.Put(ref.to, addObjectId(s.Referrers.Get(ref.to), id))
+	}
+	s.References.Put(id, r)
+}
+
+// Records the references of the object with the given ID, just written with the given entity, and whether it is
+// pending. The object is no longer stale, see invalidateReferrers. o and entity are nil if the object was deleted.
+func (s *LogicalStore) updateReferences(id ObjectId, o interface{}, entity *p4v1.Entity) {
+	s.Stale.Delete(id)
+	s.setReferences(id, o)
+	var waiting []ObjectId
+	if o != nil {
+		// Validated when translating the object.
+		waiting, _ = s.waitingFor(id, o)
+	}
+	s.setPending(id, entity, waiting)
+}
+
+// Records whether the object with the given ID, just written with the given entity, is pending, i.e., it waits for
+// other objects. If not, the objects waiting for it stop doing so. entity is nil if the object was deleted.
+func (s *LogicalStore) setPending(id ObjectId, entity *p4v1.Entity, waiting []ObjectId) {
+	if entity != nil && len(waiting) > 0 {
+		s.Pending.Put(id, &PendingObject{Entity: entity, Waiting: waiting})
+		return
+	}
+	s.Pending.Delete(id)
+	if entity == nil {
+		return
+	}
+	for _, r := range s.Referrers.Get(id) {
+		if p := s.Pending.Get(r); p != nil {
+			s.Pending.Put(r, &PendingObject{Entity: p.Entity, Waiting: removeObjectId(p.Waiting, id)})
+		}
+	}
+}
+
+// Returns the inserts of the pending objects that no longer wait for others, in ascending ID order.
+func (s *LogicalStore) ready() []*p4v1.Update {
+	var ids []ObjectId
+	s.Pending.Range(func(id ObjectId, p *PendingObject) {
+		if len(p.Waiting) == 0 {
+			ids = append(ids, id)
+		}
+	})
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i].String() < ids[j].String()
+	})
+	updates := make([]*p4v1.Update, 0, len(ids))
+	for _, id := range ids {
+		updates = append(updates, newUpdate(p4v1.Update_INSERT, s.Pending.Get(id).Entity))
+	}
+	return updates
+}
+
+// Returns the deletes of the objects referring, directly or not, to the object deleted by the given update, referrers
+// first. Returns a FAILED_PRECONDITION error if the delete does not cascade to some referrer, see Config.
+func (s *LogicalStore) cascade(u *p4v1.Update, config *Config) ([]*p4v1.Update, error) {
+	if u.Type != p4v1.Update_DELETE {
+		return nil, nil
+	}
+	id, err := ObjectIdOf(u.Entity)
+	if err != nil {
+		return nil, err
+	}
+	var deletes []*p4v1.Update
+	visited := map[ObjectId]bool{id: true}
+	var visit func(id ObjectId) error
+	visit = func(id ObjectId) error {
+		for _, r := range s.Referrers.Get(id) {
+			if visited[r] {
+				continue
+			}
+			if !config.cascades(id.Kind) {
+				return s.checkNotReferred(id)
+			}
+			visited[r] = true
+			if err := visit(r); err != nil {
+				return err
+			}
+			for _, e := range s.References.Get(r).Entities {
+				deletes = append(deletes, newUpdate(p4v1.Update_DELETE, e))
+			}
+		}
+		return nil
+	}
+	if err := visit(id); err != nil {
+		return nil, err
+	}
+	return deletes, nil
+}
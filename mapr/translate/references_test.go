@@ -0,0 +1,256 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package translate
+
+import (
+	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"mapr/codec"
+	"testing"
+)
+
+func myStationEntity(port byte) *p4v1.Entity {
+	return tableEntryEntity((&IngressPipeMyStationsEntry{
+		Port:   []byte{0, port},
+		EthDst: []byte{0xee, 0, 0, 0, 0, port},
+		Action: &IngressPipeSetMyStationAction{},
+	}).ToTableEntry())
+}
+
+func nextHopEntity(id uint32, port byte) *p4v1.Entity {
+	return actProfMemberEntity((&IngressPipeUpstreamEcmpMember{
+		MemberId: id,
+		Action:   &IngressPipeUpstreamRouteV4Action{Port: []byte{0, port}, Dmac: []byte{0xcc, 0, 0, 0, 0, byte(id)}},
+	}).ToActionProfileMember())
+}
+
+func nextHopGroupEntity(id uint32, members ...uint32) *p4v1.Entity {
+	g := &p4v1.ActionProfileGroup{ActionProfileId: ActionProfile_IngressPipeUpstreamEcmp, GroupId: id, MaxSize: 8}
+	for _, m := range members {
+		g.Members = append(g.Members, &p4v1.ActionProfileGroup_Member{MemberId: m, Weight: 1})
+	}
+	return actProfGroupEntity(g)
+}
+
+func routeV4Entity(prefix byte, groupId uint32) *p4v1.Entity {
+	return tableEntryEntity((&IngressPipeUpstreamRoutesV4Entry{
+		Ipv4Dst:              &codec.Lpm{Value: []byte{prefix, 0, 0, 0}, PrefixLen: 8},
+		ActionProfileGroupId: groupId,
+	}).ToTableEntry())
+}
+
+// Writes a MyStation entry on port 1, next hops 1 and 2 on it, a group with both, a route using the group, and a
+// complete downstream attachment on port 1, and an incomplete one on port 2.
+func writeReferences(t *testing.T, write func(p4v1.Update_Type, *p4v1.Entity) error) {
+	require.NoError(t, write(p4v1.Update_INSERT, myStationEntity(1)))
+	require.NoError(t, write(p4v1.Update_INSERT, nextHopEntity(1, 1)))
+	require.NoError(t, write(p4v1.Update_INSERT, nextHopEntity(2, 1)))
+	require.NoError(t, write(p4v1.Update_INSERT, nextHopGroupEntity(10, 1, 2)))
+	require.NoError(t, write(p4v1.Update_INSERT, routeV4Entity(10, 10)))
+	require.NoError(t, write(p4v1.Update_INSERT, downstreamLineEntity(1, 1)))
+	require.NoError(t, write(p4v1.Update_INSERT, downstreamAttachmentEntity(1, 1, 100, 200, 1)))
+	require.NoError(t, write(p4v1.Update_INSERT, downstreamLineEntity(2, 2)))
+}
+
+func Test_translator_checkReferences(t *testing.T) {
+	tests := []struct {
+		name   string
+		uType  p4v1.Update_Type
+		entity *p4v1.Entity
+		want   codes.Code
+		// Expected substring of the error message.
+		wantErr string
+	}{
+		{"route", p4v1.Update_INSERT, routeV4Entity(11, 11), codes.NotFound,
+			"route_v4/up/11.0.0.0/8 refers to next_hop_group/11, which does not exist"},
+		{"group", p4v1.Update_INSERT, nextHopGroupEntity(11, 1, 3), codes.NotFound,
+			"next_hop_group/11 refers to next_hop/3, which does not exist"},
+		{"modified group", p4v1.Update_MODIFY, nextHopGroupEntity(10, 3), codes.NotFound,
+			"next_hop_group/10 refers to next_hop/3, which does not exist"},
+		// MyStation entries are prerequisites, objects written before them are pending.
+		{"next hop", p4v1.Update_INSERT, nextHopEntity(3, 2), codes.OK, ""},
+		{"downstream attachment", p4v1.Update_INSERT, downstreamAttachmentEntity(2, 2, 100, 201, 2), codes.OK, ""},
+		{"modified next hop", p4v1.Update_MODIFY, nextHopEntity(1, 2), codes.NotFound,
+			"next_hop/1 is translated, it cannot refer to my_station/2, which is missing or pending"},
+		{"incomplete downstream attachment", p4v1.Update_INSERT, downstreamLineEntity(3, 3), codes.OK, ""},
+		{"referred group", p4v1.Update_DELETE, nextHopGroupEntity(10, 1, 2), codes.FailedPrecondition,
+			"next_hop_group/10 is referred to by 1 objects, e.g., route_v4/up/10.0.0.0/8"},
+		{"referred next hop", p4v1.Update_DELETE, nextHopEntity(2, 1), codes.FailedPrecondition,
+			"next_hop/2 is referred to by 1 objects, e.g., next_hop_group/10"},
+		{"referred my station", p4v1.Update_DELETE, myStationEntity(1), codes.FailedPrecondition,
+			"my_station/1 is referred to by 3 objects"},
+		{"route delete", p4v1.Update_DELETE, routeV4Entity(10, 10), codes.OK, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, write := newNopTranslator(t)
+			writeReferences(t, write)
+			err := write(tt.uType, tt.entity)
+			assert.Equal(t, tt.want, status.Code(err))
+			if tt.wantErr != "" {
+				assert.Contains(t, err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_LogicalStore_setReferences(t *testing.T) {
+	ctx, write := newNopTranslator(t)
+	writeReferences(t, write)
+	s := ctx.Logical()
+	assert.Equal(t, []ObjectId{{ObjectNextHop, "1"}, {ObjectNextHop, "2"}},
+		s.References.Get(ObjectId{ObjectNextHopGroup, "10"}).To)
+	assert.Equal(t, []ObjectId{{ObjectNextHop, "1"}, {ObjectNextHop, "2"}, {ObjectAttachment, "down/1"}},
+		s.Referrers.Get(ObjectId{ObjectMyStation, "1"}))
+
+	// Removing a member from the group releases it, snapshots are not affected.
+	snapshot := ctx.Snapshot().Logical()
+	require.NoError(t, write(p4v1.Update_MODIFY, nextHopGroupEntity(10, 1)))
+	assert.Nil(t, s.Referrers.Get(ObjectId{ObjectNextHop, "2"}))
+	require.NoError(t, write(p4v1.Update_DELETE, nextHopEntity(2, 1)))
+	assert.Equal(t, []ObjectId{{ObjectNextHop, "1"}, {ObjectAttachment, "down/1"}},
+		s.Referrers.Get(ObjectId{ObjectMyStation, "1"}))
+	assert.Equal(t, []ObjectId{{ObjectNextHopGroup, "10"}}, snapshot.Referrers.Get(ObjectId{ObjectNextHop, "2"}))
+
+	// Deleting the attachment releases the MyStation entry.
+	require.NoError(t, write(p4v1.Update_DELETE, downstreamAttachmentEntity(1, 1, 100, 200, 1)))
+	assert.Nil(t, s.References.Get(ObjectId{ObjectAttachment, "down/1"}))
+	assert.Equal(t, []ObjectId{{ObjectNextHop, "1"}}, s.Referrers.Get(ObjectId{ObjectMyStation, "1"}))
+}
+
+func Test_translator_Cascade(t *testing.T) {
+	tests := []struct {
+		name    string
+		cascade []string
+		delete  *p4v1.Entity
+		want    []*p4v1.Entity
+		wantErr codes.Code
+	}{
+		{"not referred", nil, routeV4Entity(10, 10), nil, codes.OK},
+		{"no cascade", nil, nextHopGroupEntity(10, 1, 2), nil, codes.FailedPrecondition},
+		{"group", []string{ObjectNextHopGroup}, nextHopGroupEntity(10, 1, 2), []*p4v1.Entity{routeV4Entity(10, 10)},
+			codes.OK},
+		{"next hop", []string{ObjectNextHop}, nextHopEntity(1, 1), nil, codes.FailedPrecondition},
+		{"transitive", []string{ObjectNextHop, ObjectNextHopGroup}, nextHopEntity(1, 1),
+			[]*p4v1.Entity{routeV4Entity(10, 10), nextHopGroupEntity(10, 1, 2)}, codes.OK},
+		// Referrers come before the objects they refer to, each once.
+		{"my station", []string{ObjectMyStation, ObjectNextHop, ObjectNextHopGroup}, myStationEntity(1),
+			[]*p4v1.Entity{routeV4Entity(10, 10), nextHopGroupEntity(10, 1, 2), nextHopEntity(1, 1),
+				nextHopEntity(2, 1), downstreamAttachmentEntity(1, 1, 100, 200, 1), downstreamLineEntity(1, 1)},
+			codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := NewContext(nil)
+			trn := NewTranslator(nopProcessor{}, ctx, &Config{CascadeDeletes: tt.cascade})
+			write := func(uType p4v1.Update_Type, e *p4v1.Entity) error {
+				u := newUpdate(uType, e)
+				target, err := trn.Translate(u)
+				if err != nil {
+					return err
+				}
+				return trn.ApplyUpdate(u, target)
+			}
+			writeReferences(t, write)
+			cascaded, err := trn.Cascade(newUpdate(p4v1.Update_DELETE, tt.delete))
+			require.Equal(t, tt.wantErr, status.Code(err))
+			var want []*p4v1.Update
+			for _, e := range tt.want {
+				want = append(want, newUpdate(p4v1.Update_DELETE, e))
+			}
+			assert.Equal(t, want, cascaded)
+			if err != nil {
+				return
+			}
+			// Once the cascaded deletes are written, the object can be deleted.
+			for _, u := range cascaded {
+				require.NoError(t, write(u.Type, u.Entity))
+			}
+			require.NoError(t, write(p4v1.Update_DELETE, tt.delete))
+			id, err := ObjectIdOf(tt.delete)
+			require.NoError(t, err)
+			assert.Nil(t, ctx.Logical().Referrers.Get(id))
+		})
+	}
+}
+
+// A processor recording the objects it translates.
+type recordingProcessor struct {
+	nopProcessor
+	translated *[]string
+}
+
+func (p recordingProcessor) HandleAttachmentEntry(a *AttachmentEntry, ok bool) ([]*p4v1.Update, error) {
+	if ok {
+		*p.translated = append(*p.translated, a.ObjectId().String())
+	}
+	return nil, nil
+}
+
+func (p recordingProcessor) HandleRouteV4NextHopEntry(n *NextHopEntry, uType p4v1.Update_Type) ([]*p4v1.Update,
+	error) {
+	*p.translated = append(*p.translated, uType.String()+" "+n.ObjectId().String())
+	return nil, nil
+}
+
+func (p recordingProcessor) HandleRouteV4NextHopGroup(g *NextHopGroup, uType p4v1.Update_Type) ([]*p4v1.Update,
+	error) {
+	*p.translated = append(*p.translated, uType.String()+" "+g.ObjectId().String())
+	return nil, nil
+}
+
+func Test_translator_Ready(t *testing.T) {
+	var translated []string
+	ctx := NewContext(nil)
+	trn := NewTranslator(recordingProcessor{translated: &translated}, ctx, nil)
+	write := func(uType p4v1.Update_Type, e *p4v1.Entity) {
+		u := newUpdate(uType, e)
+		target, err := trn.Translate(u)
+		require.NoError(t, err)
+		require.NoError(t, trn.ApplyUpdate(u, target))
+	}
+	writeReady := func() {
+		for ready := trn.Ready(); len(ready) > 0; ready = trn.Ready() {
+			for _, u := range ready {
+				write(u.Type, u.Entity)
+			}
+		}
+	}
+	s := ctx.Logical()
+
+	// Objects waiting for the MyStation entry, directly or not, are accepted but not translated.
+	write(p4v1.Update_INSERT, nextHopEntity(1, 1))
+	write(p4v1.Update_INSERT, nextHopEntity(2, 1))
+	write(p4v1.Update_INSERT, nextHopGroupEntity(10, 1, 2))
+	write(p4v1.Update_MODIFY, nextHopGroupEntity(10, 1))
+	write(p4v1.Update_INSERT, downstreamLineEntity(1, 1))
+	write(p4v1.Update_INSERT, downstreamAttachmentEntity(1, 1, 100, 200, 1))
+	assert.Empty(t, translated)
+	assert.Empty(t, trn.Ready())
+	assert.Equal(t, []ObjectId{{ObjectMyStation, "1"}}, s.Pending.Get(ObjectId{ObjectNextHop, "1"}).Waiting)
+	assert.Equal(t, []ObjectId{{ObjectNextHop, "1"}}, s.Pending.Get(ObjectId{ObjectNextHopGroup, "10"}).Waiting)
+	assert.Equal(t, []ObjectId{{ObjectMyStation, "1"}}, s.Pending.Get(ObjectId{ObjectAttachment, "down/1"}).Waiting)
+
+	// Deleting a pending object translates nothing.
+	write(p4v1.Update_DELETE, nextHopEntity(2, 1))
+	assert.Empty(t, translated)
+	snapshot := ctx.Snapshot().Logical()
+
+	// Once the MyStation entry is written, the objects waiting for it are translated, then the ones waiting for them.
+	write(p4v1.Update_INSERT, myStationEntity(1))
+	writeReady()
+	assert.Equal(t, []string{"attachment/down/1", "INSERT next_hop/1", "INSERT next_hop_group/10"}, translated)
+	assert.Zero(t, s.Pending.Len())
+	assert.Equal(t, 3, snapshot.Pending.Len())
+
+	// Further updates are translated as usual.
+	write(p4v1.Update_MODIFY, nextHopGroupEntity(10, 1))
+	assert.Equal(t, "MODIFY next_hop_group/10", translated[len(translated)-1])
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package translate
+
+import (
+	"github.com/golang/protobuf/proto"
+	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
+	"reflect"
+	"sort"
+)
+
+// Re-rendering of the target entities derived from modified logical objects.
+//
+// Modifying an object re-translates it, and only the target entities that differ from the ones it produced before are
+// written, e.g., a MODIFY of the action profile group for a change of the members of a next hop group, while the
+// entities it no longer produces are deleted, e.g., for a change of interface type. Objects referring to the modified
+// one, e.g., the next hops and downstream attachments using the MAC address of a MyStation entry as source MAC, are
+// stale: they are re-translated in turn, see Translator.Ready.
+
+// Returns the given target updates, produced by modifying the translated object with the given ID, without the ones
+// modifying entities to their current value, and with the deletes of the entities the object produced before, but no
+// longer, and does not share with other objects. Modifies of missing entities become inserts, e.g., when match fields
+// change.
+func (t translator) rerender(id ObjectId, target []*p4v1.Update) []*p4v1.Update {
+	rendered := make(map[EntityKey]bool, len(target))
+	var updates []*p4v1.Update
+	for _, u := range target {
+		k, ok := EntityKeyOf(u.Entity)
+		if !ok {
+			updates = append(updates, u)
+			continue
+		}
+		rendered[k] = true
+		if u.Type != p4v1.Update_MODIFY {
+			updates = append(updates, u)
+			continue
+		}
+		switch old := LookupEntity(t.ctx.Target(), k); {
+		case old == nil:
+			updates = append(updates, newUpdate(p4v1.Update_INSERT, u.Entity))
+		case !proto.Equal(old, u.Entity):
+			updates = append(updates, u)
+		}
+	}
+	// In reverse order of creation, e.g., table entries before the groups they point to.
+	produced := t.ctx.Logical().Produced.Get(id)
+	for i := len(produced) - 1; i >= 0; i-- {
+		k := produced[i]
+		if rendered[k] || len(t.ctx.Logical().Owners.Get(k)) > 1 {
+			continue
+		}
+		if old := LookupEntity(t.ctx.Target(), k); old != nil {
+			updates = append(updates, newUpdate(p4v1.Update_DELETE, old))
+		}
+	}
+	return updates
+}
+
+// Returns true if the given objects, old and new values of the same object, differ.
+func changed(old, o interface{}) bool {
+	if g, ok := old.(*NextHopGroup); ok {
+		return !proto.Equal((*p4v1.ActionProfileGroup)(g), (*p4v1.ActionProfileGroup)(o.(*NextHopGroup)))
+	}
+	return !reflect.DeepEqual(old, o)
+}
+
+// Marks as stale the translated objects referring to the object with the given ID, just modified.
+func (s *LogicalStore) invalidateReferrers(id ObjectId) {
+	for _, r := range s.Referrers.Get(id) {
+		if s.Pending.Get(r) == nil {
+			s.Stale.Put(r, true)
+		}
+	}
+}
+
+// Returns the modifies re-translating the stale objects, in ascending ID order.
+func (s *LogicalStore) stale() []*p4v1.Update {
+	ids := make([]ObjectId, 0, s.Stale.Len())
+	s.Stale.Range(func(id ObjectId, _ bool) {
+		ids = append(ids, id)
+	})
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i].String() < ids[j].String()
+	})
+	updates := make([]*p4v1.Update, 0, len(ids))
+	for _, id := range ids {
+		// Referrers always have references, e.g., the attachments_v4 entry of a downstream attachment, re-evaluating
+		// the whole attachment.
+		updates = append(updates, newUpdate(p4v1.Update_MODIFY, s.References.Get(id).Entities[0]))
+	}
+	return updates
+}
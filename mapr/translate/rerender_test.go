@@ -0,0 +1,115 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package translate
+
+import (
+	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// A processor rendering logical objects as target entities of the logical pipeline, using the MAC address of the
+// MyStation entry of their port in next hops, as fabric does.
+type renderingProcessor struct {
+	nopProcessor
+	ctx Context
+}
+
+func (p renderingProcessor) HandleIfTypeEntry(e *IfTypeEntry, uType p4v1.Update_Type) ([]*p4v1.Update, error) {
+	if e.IfType[0] != IfTypeCore {
+		return nil, nil
+	}
+	return []*p4v1.Update{newUpdate(uType, ifTypeEntity(e.Port[1], e.IfType[0]))}, nil
+}
+
+func (p renderingProcessor) HandleMyStationEntry(e *MyStationEntry, uType p4v1.Update_Type) ([]*p4v1.Update, error) {
+	return []*p4v1.Update{newUpdate(uType, tableEntryEntity((&IngressPipeMyStationsEntry{
+		Port:   e.Port,
+		EthDst: e.EthDst,
+		Action: &IngressPipeSetMyStationAction{},
+	}).ToTableEntry()))}, nil
+}
+
+func (p renderingProcessor) HandleRouteV4NextHopEntry(n *NextHopEntry, uType p4v1.Update_Type) ([]*p4v1.Update,
+	error) {
+	myStation := p.ctx.Logical().MyStations.Get(ToPortKey(n.Port))
+	return []*p4v1.Update{newUpdate(uType, actProfMemberEntity((&IngressPipeUpstreamEcmpMember{
+		MemberId: n.Id,
+		Action:   &IngressPipeUpstreamRouteV4Action{Port: n.Port, Dmac: myStation.EthDst},
+	}).ToActionProfileMember()))}, nil
+}
+
+func (p renderingProcessor) HandleRouteV4NextHopGroup(g *NextHopGroup, uType p4v1.Update_Type) ([]*p4v1.Update,
+	error) {
+	x := p4v1.ActionProfileGroup(*g)
+	return []*p4v1.Update{newUpdate(uType, actProfGroupEntity(&x)), newUpdate(uType, routeV4Entity(1, g.GroupId))}, nil
+}
+
+func ifTypeEntity(port byte, ifType byte) *p4v1.Entity {
+	return tableEntryEntity((&IngressPipeIfTypesEntry{
+		Port:   []byte{0, port},
+		Action: &IngressPipeSetIfTypeAction{IfType: []byte{ifType}},
+	}).ToTableEntry())
+}
+
+func Test_translator_rerender(t *testing.T) {
+	ctx := NewContext(nil)
+	trn := NewTranslator(renderingProcessor{ctx: ctx}, ctx, nil)
+	translate := func(uType p4v1.Update_Type, e *p4v1.Entity) []*p4v1.Update {
+		u := newUpdate(uType, e)
+		target, err := trn.Translate(u)
+		require.NoError(t, err)
+		require.NoError(t, trn.ApplyUpdate(u, target))
+		return target
+	}
+	translate(p4v1.Update_INSERT, ifTypeEntity(1, IfTypeCore))
+	translate(p4v1.Update_INSERT, myStationEntity(1))
+	translate(p4v1.Update_INSERT, nextHopEntity(1, 1))
+	translate(p4v1.Update_INSERT, nextHopEntity(2, 1))
+	translate(p4v1.Update_INSERT, nextHopGroupEntity(10, 1, 2))
+	assert.Empty(t, trn.Ready())
+
+	// The MAC address is a match field: the entry with the new one is inserted, and the previous one deleted.
+	myStation := &IngressPipeMyStationsEntry{
+		Port:   []byte{0, 1},
+		EthDst: []byte{0xee, 0, 0, 0, 0, 0xff},
+		Action: &IngressPipeSetMyStationAction{},
+	}
+	target := translate(p4v1.Update_MODIFY, tableEntryEntity(myStation.ToTableEntry()))
+	assert.Equal(t, []*p4v1.Update{
+		newUpdate(p4v1.Update_INSERT, tableEntryEntity(myStation.ToTableEntry())),
+		newUpdate(p4v1.Update_DELETE, myStationEntity(1)),
+	}, target)
+
+	// The next hops using the MAC address are stale, and re-translated with a modify of their member only.
+	ready := trn.Ready()
+	assert.Equal(t, []*p4v1.Update{
+		newUpdate(p4v1.Update_MODIFY, nextHopEntity(1, 1)),
+		newUpdate(p4v1.Update_MODIFY, nextHopEntity(2, 1)),
+	}, ready)
+	for _, u := range ready {
+		target := translate(u.Type, u.Entity)
+		require.Len(t, target, 1)
+		assert.Equal(t, p4v1.Update_MODIFY, target[0].Type)
+		assert.Equal(t, myStation.EthDst, target[0].Entity.GetActionProfileMember().Action.Params[1].Value)
+	}
+	// The next hops did not change, hence neither did the groups using them.
+	assert.Empty(t, trn.Ready())
+	assert.Zero(t, ctx.Logical().Stale.Len())
+
+	// Only the changed entities of modified objects are written.
+	target = translate(p4v1.Update_MODIFY, nextHopGroupEntity(10, 1))
+	require.Len(t, target, 1)
+	assert.Equal(t, nextHopGroupEntity(10, 1), target[0].Entity)
+	assert.Empty(t, translate(p4v1.Update_MODIFY, nextHopGroupEntity(10, 1)))
+
+	// Entities no longer produced are deleted.
+	target = translate(p4v1.Update_MODIFY, ifTypeEntity(1, IfTypeAccess))
+	assert.Equal(t, []*p4v1.Update{newUpdate(p4v1.Update_DELETE, ifTypeEntity(1, IfTypeCore))}, target)
+	assert.Nil(t, ctx.Logical().Produced.Get(ObjectId{ObjectIfType, "1"}))
+}
@@ -0,0 +1,158 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package translate
+
+import (
+	"encoding/binary"
+	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+func Test_store_Snapshot(t *testing.T) {
+	s := newIndexedStore()
+	s.PutTableEntry(&mockTableEntry1)
+	s.PutActProfMember(&p4v1.ActionProfileMember{ActionProfileId: 1, MemberId: 1})
+	snap := s.Snapshot()
+
+	s.PutTableEntry(&mockTableEntry1NewAction)
+	s.PutTableEntry(&mockTableEntry2)
+	s.RemoveActProfMember(&p4v1.ActionProfileMember{ActionProfileId: 1, MemberId: 1})
+
+	key := KeyFromTableEntry(&mockTableEntry1)
+	assert.Equal(t, &mockTableEntry1, snap.GetTableEntry(&key), "snapshot should not see modified entries")
+	assert.Equal(t, 1, snap.TableEntryCount(), "snapshot should not see new entries")
+	assert.Equal(t, 1, snap.ActProfMemberCount(), "snapshot should not see removed entries")
+	assert.ElementsMatch(t, []*p4v1.TableEntry{&mockTableEntry1}, snap.TableEntriesByIndex("param", []byte{0x0A}))
+	assert.Empty(t, snap.TableEntriesByIndex("param", []byte{0x0C}))
+
+	assert.Equal(t, &mockTableEntry1NewAction, s.GetTableEntry(&key))
+	assert.Equal(t, 2, s.TableEntryCount())
+	assert.Zero(t, s.ActProfMemberCount())
+	assert.ElementsMatch(t, []*p4v1.TableEntry{&mockTableEntry1NewAction}, s.TableEntriesByIndex("param", []byte{0x0C}))
+}
+
+func Test_store_Snapshot_ReadOnly(t *testing.T) {
+	snap := NewP4RtStore("test").Snapshot()
+	assert.Same(t, snap, snap.Snapshot(), "snapshot of a snapshot should be itself")
+	assert.NoError(t, snap.ApplyUpdate(&mockUpdateInsertTableEntry1, true))
+	assert.Error(t, snap.ApplyUpdate(&mockUpdateInsertTableEntry1, false))
+	assert.Panics(t, func() { snap.PutTableEntry(&mockTableEntry1) })
+}
+
+func Test_store_Snapshot_Concurrent(t *testing.T) {
+	entries := benchTableEntries(1000)
+	s := NewP4RtStore("test")
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for _, e := range entries {
+			s.PutTableEntry(e)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		snap := s.Snapshot()
+		// Entries are inserted in order, a consistent snapshot holds a prefix of them.
+		n := snap.TableEntryCount()
+		for _, e := range entries[:n] {
+			key := KeyFromTableEntry(e)
+			assert.NotNil(t, snap.GetTableEntry(&key))
+		}
+	}
+	wg.Wait()
+	assert.Equal(t, len(entries), s.TableEntryCount())
+}
+
+// Snapshots do not modify what the goroutine modifying the store reads without locking.
+func Test_store_Snapshot_ConcurrentReads(t *testing.T) {
+	entries := benchTableEntries(1000)
+	s := NewP4RtStore("test")
+	s.AddTableEntryIndex("table", TableIdIndex())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i, e := range entries {
+			s.PutTableEntry(e)
+			key := KeyFromTableEntry(e)
+			assert.Same(t, e, s.GetTableEntry(&key))
+			assert.Len(t, s.TableEntriesByIndex("table", TableIdIndexValue(e.TableId)), s.TableEntryCount())
+			if i%2 == 1 {
+				s.RemoveTableEntry(e)
+			}
+		}
+	}()
+	for {
+		select {
+		case <-done:
+			assert.Equal(t, len(entries)/2, s.TableEntryCount())
+			return
+		default:
+		}
+		snap := s.Snapshot()
+		assert.Len(t, snap.TableEntriesByIndex("table", TableIdIndexValue(entries[0].TableId)),
+			snap.TableEntryCount(), "index and entries of a snapshot should be consistent")
+	}
+}
+
+func Test_context_Snapshot(t *testing.T) {
+	ctx := NewContext(nil)
+	trn := NewTranslator(nil, ctx, nil)
+	logical := &p4v1.Update{
+		Type:   p4v1.Update_INSERT,
+		Entity: &p4v1.Entity{Entity: &p4v1.Entity_TableEntry{TableEntry: &mockTableEntryIfTypesPort1Core}},
+	}
+	target := []*p4v1.Update{&mockUpdateInsertTableEntry1}
+	snap := ctx.Snapshot()
+	assert.NoError(t, trn.ApplyUpdate(logical, target))
+
+	assert.Zero(t, snap.Logical().IfTypes.Len())
+	assert.Zero(t, snap.Target().TableEntryCount())
+	assert.Equal(t, 1, ctx.Logical().IfTypes.Len())
+	assert.Equal(t, 1, ctx.Target().TableEntryCount())
+
+	snap = ctx.Snapshot()
+	logical.Type = p4v1.Update_DELETE
+	assert.NoError(t, trn.ApplyUpdate(logical, nil))
+	assert.Equal(t, 1, snap.Logical().IfTypes.Len())
+	assert.Zero(t, ctx.Logical().IfTypes.Len())
+	assert.Panics(t, func() { snap.Logical().BeginUpdate() })
+}
+
+func Test_context_Snapshot_Concurrent(t *testing.T) {
+	ctx := NewContext(nil)
+	trn := NewTranslator(nil, ctx, nil)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000; i++ {
+			// Each logical update comes with exactly one target update.
+			port := make([]byte, 2)
+			binary.BigEndian.PutUint16(port, uint16(i))
+			e := mockTableEntryIfTypesPort1Core
+			e.Match = []*p4v1.FieldMatch{{
+				FieldId:        Hdr_IngressPipeIfTypes_Port,
+				FieldMatchType: &p4v1.FieldMatch_Exact_{Exact: &p4v1.FieldMatch_Exact{Value: port}},
+			}}
+			u := &p4v1.Update{Type: p4v1.Update_INSERT, Entity: &p4v1.Entity{Entity: &p4v1.Entity_TableEntry{TableEntry: &e}}}
+			assert.NoError(t, trn.ApplyUpdate(u, []*p4v1.Update{u}))
+			// Translation reads the logical store without locking.
+			assert.NotNil(t, ctx.Logical().IfTypes.Get(ToPortKey(port)))
+		}
+	}()
+	for {
+		select {
+		case <-done:
+			return
+		default:
+		}
+		snap := ctx.Snapshot()
+		assert.Equal(t, snap.Logical().IfTypes.Len(), snap.Target().TableEntryCount(),
+			"logical and target stores should be consistent")
+	}
+}
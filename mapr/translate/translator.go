@@ -10,9 +10,14 @@ import (
 	"bytes"
 	"fmt"
 	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
-	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"mapr/logging"
+	"sync"
 )
 
+var log = logging.Subsystem("translate")
+
 // Produces updates for the target pipeline state by handling changes to the logical one.
 //
 // The operations of a Translator are supported by a Processor, which represent the target-specific logic.
@@ -30,6 +35,23 @@ type Translator interface {
 	Translate(logical *p4v1.Update) (target []*p4v1.Update, err error)
 	// Modifies the pipeline context by applying the given logical and target updates.
 	ApplyUpdate(logical *p4v1.Update, target []*p4v1.Update) error
+	// Clears the pipeline context, e.g., after the forwarding state of the target has been wiped.
+	Reset()
+	// Re-translates from scratch the logical state in the given store, replacing the pipeline context. Returns the
+	// updates that bring the target from the state in the previous context to the re-translated one. If translation
+	// fails, the context is left partially populated, and should be reset.
+	Reconcile(logical P4RtStore) ([]*p4v1.Update, error)
+	// Given a P4RT Update for the logical pipeline, Cascade() returns the logical updates deleting the objects that
+	// refer to the one deleted by the update, if any, as per the cascade policy. These should be written before the
+	// given update, in order. Returns an error if the update deletes an object referred to by others, and the delete
+	// does not cascade. Calling Cascade() does NOT alter the pipeline context.
+	Cascade(logical *p4v1.Update) ([]*p4v1.Update, error)
+	// Returns the logical updates translating the pending objects that are now ready, e.g., after the MyStation entry
+	// they were waiting for has been written, and re-translating the stale ones, i.e., referring to modified objects,
+	// e.g., the next hops using the MAC address of a modified MyStation entry. Each should be translated and applied
+	// like the updates of controllers, in order, then Ready() called again until no more updates are returned, or
+	// translating them fails. Objects failing to translate stay pending or stale.
+	Ready() []*p4v1.Update
 }
 
 // A processor of changes in the logical pipeline state. Provides methods that generate updates for the target.
@@ -61,71 +83,188 @@ type Context interface {
 	// A mirror of the target device's state (P4Runtime). Should be treated as read-only.
 	// Updates to this store are performed by the Write RPC handler in main.go.
 	Target() P4RtStore
+	// The mapping between logical and target port numbers. Processors should use it to translate ports in both
+	// directions. Might be nil, i.e., the identity mapping.
+	Ports() *PortMap
+	// Returns an immutable point-in-time view of the context. The logical and target stores of the snapshot are
+	// consistent with each other, i.e., they never reflect a logical update only partially applied.
+	Snapshot() Context
 }
 
 type context struct {
-	logical LogicalStore
+	logical *LogicalStore
 	target  P4RtStore
+	ports   *PortMap
 }
 
-func (p context) Logical() *LogicalStore {
-	return &p.logical
+func (p *context) Logical() *LogicalStore {
+	return p.logical
 }
 
-func (p context) Target() P4RtStore {
+func (p *context) Target() P4RtStore {
 	return p.target
 }
 
+func (p *context) Ports() *PortMap {
+	return p.ports
+}
+
+func (p *context) Snapshot() Context {
+	if p.logical.frozen {
+		return p
+	}
+	// Block updates while snapshotting both stores.
+	p.logical.mu.Lock()
+	defer p.logical.mu.Unlock()
+	return &context{
+		logical: p.logical.snapshot(),
+		target:  p.target.Snapshot(),
+		ports:   p.ports,
+	}
+}
+
 // A collection of maps holding the logical state.
+//
+// Maps are shared with snapshots of the store, and copied shard by shard as they are modified (copy-on-write, see
+// cowMap). As such, maps should only be modified between calls to BeginUpdate and EndUpdate.
 type LogicalStore struct {
-	IfTypes                map[PortKey]*IfTypeEntry
-	MyStations             map[PortKey]*MyStationEntry
-	Acl                    map[AclKey]*AclEntry
-	CtrlPunted             map[CtrlPuntedKey]*PppoePuntedEntry
-	UpstreamAttachments    map[LineIdKey]*AttachmentEntry
-	DownstreamAttachments  map[LineIdKey]*AttachmentEntry
-	UpstreamRoutesV4       map[Ipv4LpmKey]*RouteV4Entry
-	UpstreamNextHopGroups  map[uint32]*NextHopGroup
-	UpstreamNextHopEntries map[uint32]*NextHopEntry
-}
-
-// Creates a new Context.
-func NewContext() Context {
+	IfTypes                IfTypeMap
+	MyStations             MyStationMap
+	Acl                    AclMap
+	CtrlPunted             PppoePuntedMap
+	UpstreamAttachments    AttachmentMap
+	DownstreamAttachments  AttachmentMap
+	UpstreamRoutesV4       RouteV4Map
+	UpstreamNextHopGroups  NextHopGroupMap
+	UpstreamNextHopEntries NextHopEntryMap
+	// Secondary index of the attachments of both directions, see AttachmentsByIndex. Attachments should be modified
+	// with putAttachment and removeAttachment to keep it up to date.
+	AttachmentIndex AttachmentIndexMap
+	// Provenance of the target entities: the keys of the entities produced by each object, and the objects owning each
+	// entity. Entities might be owned by multiple objects, e.g., the upstream and downstream attachments of a line.
+	Produced ProducedMap
+	Owners   OwnersMap
+	// References between objects, see References: the references of each object referring to others, and the objects
+	// referring to each object.
+	References ReferencesMap
+	Referrers  ReferrersMap
+	// The objects that are not translated yet, as they wait for others, see PendingObject.
+	Pending PendingMap
+	// The translated objects to re-translate, as objects they refer to were modified, see invalidateReferrers.
+	Stale ObjectSet
+
+	// Held for the duration of an update, guards against concurrent snapshots.
+	mu sync.Mutex
+	// Whether this store is a snapshot.
+	frozen bool
+}
+
+func newLogicalStore() *LogicalStore {
+	return &LogicalStore{}
+}
+
+// Returns the maps of the store.
+func (s *LogicalStore) maps() []*cowMap {
+	return []*cowMap{
+		&s.IfTypes.m,
+		&s.MyStations.m,
+		&s.Acl.m,
+		&s.CtrlPunted.m,
+		&s.UpstreamAttachments.m,
+		&s.DownstreamAttachments.m,
+		&s.UpstreamRoutesV4.m,
+		&s.UpstreamNextHopGroups.m,
+		&s.UpstreamNextHopEntries.m,
+		&s.AttachmentIndex.m,
+		&s.Produced.m,
+		&s.Owners.m,
+		&s.References.m,
+		&s.Referrers.m,
+		&s.Pending.m,
+		&s.Stale.m,
+	}
+}
+
+// Returns an immutable point-in-time view of the store, sharing maps with it. Taking a snapshot is O(shards), and
+// waits for an ongoing update, if any, to end. Snapshots can be read from any goroutine.
+func (s *LogicalStore) Snapshot() *LogicalStore {
+	if s.frozen {
+		return s
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.snapshot()
+}
+
+// Must be called with mu held.
+func (s *LogicalStore) snapshot() *LogicalStore {
+	snap := &LogicalStore{frozen: true}
+	// The store keeps its maps, which translation reads without locking.
+	snapMaps := snap.maps()
+	for i, m := range s.maps() {
+		*snapMaps[i] = m.snapshot()
+	}
+	return snap
+}
+
+// Removes all objects from the store. Snapshots are not affected.
+func (s *LogicalStore) Clear() {
+	if s.frozen {
+		panic("LogicalStore: cannot modify a snapshot")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, m := range s.maps() {
+		*m = cowMap{}
+	}
+}
+
+// Prepares the store to be modified. Snapshots are blocked until EndUpdate is called.
+func (s *LogicalStore) BeginUpdate() {
+	if s.frozen {
+		panic("LogicalStore: cannot modify a snapshot")
+	}
+	s.mu.Lock()
+}
+
+// Ends an update started with BeginUpdate.
+func (s *LogicalStore) EndUpdate() {
+	s.mu.Unlock()
+}
+
+// Creates a new Context using the given port mapping, nil for the identity.
+func NewContext(ports *PortMap) Context {
 	return &context{
-		logical: LogicalStore{
-			IfTypes:                make(map[PortKey]*IfTypeEntry),
-			MyStations:             make(map[PortKey]*MyStationEntry),
-			Acl:                    make(map[AclKey]*AclEntry),
-			CtrlPunted:             make(map[CtrlPuntedKey]*PppoePuntedEntry),
-			UpstreamAttachments:    make(map[LineIdKey]*AttachmentEntry),
-			DownstreamAttachments:  make(map[LineIdKey]*AttachmentEntry),
-			UpstreamRoutesV4:       make(map[Ipv4LpmKey]*RouteV4Entry),
-			UpstreamNextHopGroups:  make(map[uint32]*NextHopGroup),
-			UpstreamNextHopEntries: make(map[uint32]*NextHopEntry),
-		},
-		target: NewP4RtStore("target"),
+		logical: newLogicalStore(),
+		target:  NewP4RtStore("target"),
+		ports:   ports,
 	}
 }
 
 type translator struct {
-	proc Processor
-	ctx  Context
+	proc   Processor
+	ctx    Context
+	config *Config
 }
 
-// Creates a new Translator
-func NewTranslator(proc Processor, ctx Context) Translator {
+// Creates a new Translator with the given config, nil for the default one.
+func NewTranslator(proc Processor, ctx Context, config *Config) Translator {
+	if config == nil {
+		config = DefaultConfig()
+	}
 	return &translator{
-		proc: proc,
-		ctx:  ctx,
+		proc:   proc,
+		ctx:    ctx,
+		config: config,
 	}
 }
 
 func (t *translator) logLogicalSummary() {
 	log.Debugf("Context summary: ifTypes=%d, myStations=%d, upAttachs=%d, downAttachs=%d, "+
 		"upRoutesV4=%d, upNextHopGroups=%d, upNextHopEntries=%d",
-		len(t.ctx.Logical().IfTypes), len(t.ctx.Logical().MyStations), len(t.ctx.Logical().UpstreamAttachments),
-		len(t.ctx.Logical().DownstreamAttachments), len(t.ctx.Logical().UpstreamRoutesV4),
-		len(t.ctx.Logical().UpstreamNextHopGroups), len(t.ctx.Logical().UpstreamNextHopEntries))
+		t.ctx.Logical().IfTypes.Len(), t.ctx.Logical().MyStations.Len(), t.ctx.Logical().UpstreamAttachments.Len(),
+		t.ctx.Logical().DownstreamAttachments.Len(), t.ctx.Logical().UpstreamRoutesV4.Len(),
+		t.ctx.Logical().UpstreamNextHopGroups.Len(), t.ctx.Logical().UpstreamNextHopEntries.Len())
 }
 
 func (t *translator) Translate(u *p4v1.Update) ([]*p4v1.Update, error) {
@@ -133,6 +272,11 @@ func (t *translator) Translate(u *p4v1.Update) ([]*p4v1.Update, error) {
 	if err != nil {
 		return nil, err
 	}
+	if u.Type == p4v1.Update_MODIFY {
+		// Parsed successfully above.
+		id, _ := ObjectIdOf(u.Entity)
+		target = t.rerender(id, target)
+	}
 	// Validate updates to target pipeline by performing a dry run.
 	for _, u := range target {
 		if err := t.ctx.Target().ApplyUpdate(u, true); err != nil {
@@ -144,13 +288,64 @@ func (t *translator) Translate(u *p4v1.Update) ([]*p4v1.Update, error) {
 
 func (t translator) ApplyUpdate(logical *p4v1.Update, target []*p4v1.Update) error {
 	defer t.logLogicalSummary()
+	// Keep snapshots of the context from seeing the target updates without the logical one.
+	t.ctx.Logical().BeginUpdate()
+	defer t.ctx.Logical().EndUpdate()
 	for _, u := range target {
 		if err := t.ctx.Target().ApplyUpdate(u, false); err != nil {
 			panic("ApplyUpdate(): error when applying update to target store (BUG?)")
 		}
 	}
-	_, err := t.translateOrStore(logical, false)
-	return err
+	if _, err := t.translateOrStore(logical, false); err != nil {
+		return err
+	}
+	// Parsed successfully above.
+	id, _ := ObjectIdOf(logical.Entity)
+	t.ctx.Logical().recordProvenance(id, logical.Type, target)
+	return nil
+}
+
+func (t *translator) Reset() {
+	t.ctx.Logical().Clear()
+	t.ctx.Target().Clear()
+	t.logLogicalSummary()
+}
+
+func (t *translator) Reconcile(logical P4RtStore) ([]*p4v1.Update, error) {
+	old := t.ctx.Target().Snapshot()
+	t.Reset()
+	for _, u := range logicalStoreUpdates(logical) {
+		target, err := t.Translate(u)
+		if err != nil {
+			return nil, fmt.Errorf("cannot re-translate %v: %v", u.Entity, err)
+		}
+		if err := t.ApplyUpdate(u, target); err != nil {
+			return nil, fmt.Errorf("cannot re-translate %v: %v", u.Entity, err)
+		}
+	}
+	return DiffStores(old, t.ctx.Target()), nil
+}
+
+// Returns the updates that re-create the content of the given logical store, in the order of StoreUpdates except for
+// MyStation entries, which come first as next hops and attachments refer to them.
+func logicalStoreUpdates(s P4RtStore) []*p4v1.Update {
+	var myStations, others []*p4v1.Update
+	for _, u := range StoreUpdates(s) {
+		if u.Entity.GetTableEntry().GetTableId() == Table_IngressPipeMyStations {
+			myStations = append(myStations, u)
+		} else {
+			others = append(others, u)
+		}
+	}
+	return append(myStations, others...)
+}
+
+func (t *translator) Cascade(u *p4v1.Update) ([]*p4v1.Update, error) {
+	return t.ctx.Logical().cascade(u, t.config)
+}
+
+func (t *translator) Ready() []*p4v1.Update {
+	return append(t.ctx.Logical().stale(), t.ctx.Logical().ready()...)
 }
 
 func (t translator) translateOrStore(u *p4v1.Update, translate bool) ([]*p4v1.Update, error) {
@@ -168,9 +363,9 @@ func (t translator) translateOrStore(u *p4v1.Update, translate bool) ([]*p4v1.Up
 			} else {
 				key := ToPortKey(x.Port)
 				if u.Type == p4v1.Update_DELETE {
-					delete(t.ctx.Logical().IfTypes, key)
+					t.ctx.Logical().IfTypes.Delete(key)
 				} else {
-					t.ctx.Logical().IfTypes[ToPortKey(x.Port)] = &x
+					t.ctx.Logical().IfTypes.Put(ToPortKey(x.Port), &x)
 				}
 				return nil, nil
 			}
@@ -180,14 +375,23 @@ func (t translator) translateOrStore(u *p4v1.Update, translate bool) ([]*p4v1.Up
 				return nil, err
 			}
 			if translate {
-				// TODO: implement validation
+				// MyStation entries do not refer to others, hence they are never pending.
+				if _, _, err := t.checkReferences(x.ObjectId(), &x, u.Type); err != nil {
+					return nil, err
+				}
 				return t.proc.HandleMyStationEntry(&x, u.Type)
 			} else {
 				key := ToPortKey(x.Port)
 				if u.Type == p4v1.Update_DELETE {
-					delete(t.ctx.Logical().MyStations, key)
+					t.ctx.Logical().MyStations.Delete(key)
+					t.ctx.Logical().updateReferences(x.ObjectId(), nil, nil)
 				} else {
-					t.ctx.Logical().MyStations[ToPortKey(x.Port)] = &x
+					old := t.ctx.Logical().MyStations.Get(key)
+					t.ctx.Logical().MyStations.Put(ToPortKey(x.Port), &x)
+					t.ctx.Logical().updateReferences(x.ObjectId(), &x, u.Entity)
+					if old != nil && changed(old, &x) {
+						t.ctx.Logical().invalidateReferrers(x.ObjectId())
+					}
 				}
 				return nil, nil
 			}
@@ -198,22 +402,24 @@ func (t translator) translateOrStore(u *p4v1.Update, translate bool) ([]*p4v1.Up
 				return nil, err
 			}
 			if translate {
-				// TODO: implement validation
-				return t.proc.HandleAttachmentEntry(&x, ok)
+				if u.Type != p4v1.Update_DELETE {
+					if err := t.ctx.Logical().checkAttachmentConflicts(&x); err != nil {
+						return nil, err
+					}
+				}
+				// Pending attachments are handled as incomplete ones.
+				_, translate, err := t.checkReferences(x.ObjectId(), &x, u.Type)
+				if err != nil {
+					return nil, err
+				}
+				return t.proc.HandleAttachmentEntry(&x, ok && translate)
 			} else {
-				key := ToLineIdKey(x.LineId)
 				if u.Type == p4v1.Update_DELETE {
-					if x.Direction == DirectionUpstream {
-						delete(t.ctx.Logical().UpstreamAttachments, key)
-					} else {
-						delete(t.ctx.Logical().DownstreamAttachments, key)
-					}
+					t.ctx.Logical().removeAttachment(x.Ref())
+					t.ctx.Logical().updateReferences(x.ObjectId(), nil, nil)
 				} else {
-					if x.Direction == DirectionUpstream {
-						t.ctx.Logical().UpstreamAttachments[key] = &x
-					} else {
-						t.ctx.Logical().DownstreamAttachments[key] = &x
-					}
+					t.ctx.Logical().putAttachment(&x)
+					t.ctx.Logical().updateReferences(x.ObjectId(), &x, u.Entity)
 				}
 				return nil, nil
 			}
@@ -223,14 +429,19 @@ func (t translator) translateOrStore(u *p4v1.Update, translate bool) ([]*p4v1.Up
 				return nil, err
 			}
 			if translate {
-				// TODO: implement validation
-				return t.proc.HandleRouteV4Entry(&x, u.Type)
+				uType, translate, err := t.checkReferences(x.ObjectId(), &x, u.Type)
+				if err != nil || !translate {
+					return nil, err
+				}
+				return t.proc.HandleRouteV4Entry(&x, uType)
 			} else {
 				key := ToIpv4LpmKey(x.Ipv4Addr, x.PrefixLen)
 				if u.Type == p4v1.Update_DELETE {
-					delete(t.ctx.Logical().UpstreamRoutesV4, key)
+					t.ctx.Logical().UpstreamRoutesV4.Delete(key)
+					t.ctx.Logical().updateReferences(x.ObjectId(), nil, nil)
 				} else {
-					t.ctx.Logical().UpstreamRoutesV4[key] = &x
+					t.ctx.Logical().UpstreamRoutesV4.Put(key, &x)
+					t.ctx.Logical().updateReferences(x.ObjectId(), &x, u.Entity)
 				}
 				return nil, nil
 			}
@@ -245,9 +456,9 @@ func (t translator) translateOrStore(u *p4v1.Update, translate bool) ([]*p4v1.Up
 			} else {
 				key := ToAclKey(&x)
 				if u.Type == p4v1.Update_DELETE {
-					delete(t.ctx.Logical().Acl, key)
+					t.ctx.Logical().Acl.Delete(key)
 				} else {
-					t.ctx.Logical().Acl[key] = &x
+					t.ctx.Logical().Acl.Put(key, &x)
 				}
 				return nil, nil
 			}
@@ -262,9 +473,9 @@ func (t translator) translateOrStore(u *p4v1.Update, translate bool) ([]*p4v1.Up
 			} else {
 				key := ToCtrlPuntedKey(x.PppoeCode, x.PppoeProto)
 				if u.Type == p4v1.Update_DELETE {
-					delete(t.ctx.Logical().CtrlPunted, key)
+					t.ctx.Logical().CtrlPunted.Delete(key)
 				} else {
-					t.ctx.Logical().CtrlPunted[key] = &x
+					t.ctx.Logical().CtrlPunted.Put(key, &x)
 				}
 				return nil, nil
 			}
@@ -279,14 +490,23 @@ func (t translator) translateOrStore(u *p4v1.Update, translate bool) ([]*p4v1.Up
 				return nil, err
 			}
 			if translate {
-				// TODO: implement validation
-				return t.proc.HandleRouteV4NextHopGroup(&x, u.Type)
+				uType, translate, err := t.checkReferences(x.ObjectId(), &x, u.Type)
+				if err != nil || !translate {
+					return nil, err
+				}
+				return t.proc.HandleRouteV4NextHopGroup(&x, uType)
 			} else {
 				key := x.GroupId
 				if u.Type == p4v1.Update_DELETE {
-					delete(t.ctx.Logical().UpstreamNextHopGroups, key)
+					t.ctx.Logical().UpstreamNextHopGroups.Delete(key)
+					t.ctx.Logical().updateReferences(x.ObjectId(), nil, nil)
 				} else {
-					t.ctx.Logical().UpstreamNextHopGroups[key] = &x
+					old := t.ctx.Logical().UpstreamNextHopGroups.Get(key)
+					t.ctx.Logical().UpstreamNextHopGroups.Put(key, &x)
+					t.ctx.Logical().updateReferences(x.ObjectId(), &x, u.Entity)
+					if old != nil && changed(old, &x) {
+						t.ctx.Logical().invalidateReferrers(x.ObjectId())
+					}
 				}
 				return nil, nil
 			}
@@ -301,14 +521,23 @@ func (t translator) translateOrStore(u *p4v1.Update, translate bool) ([]*p4v1.Up
 				return nil, err
 			}
 			if translate {
-				// TODO: implement validation
-				return t.proc.HandleRouteV4NextHopEntry(&x, u.Type)
+				uType, translate, err := t.checkReferences(x.ObjectId(), &x, u.Type)
+				if err != nil || !translate {
+					return nil, err
+				}
+				return t.proc.HandleRouteV4NextHopEntry(&x, uType)
 			} else {
 				key := x.Id
 				if u.Type == p4v1.Update_DELETE {
-					delete(t.ctx.Logical().UpstreamNextHopEntries, key)
+					t.ctx.Logical().UpstreamNextHopEntries.Delete(key)
+					t.ctx.Logical().updateReferences(x.ObjectId(), nil, nil)
 				} else {
-					t.ctx.Logical().UpstreamNextHopEntries[key] = &x
+					old := t.ctx.Logical().UpstreamNextHopEntries.Get(key)
+					t.ctx.Logical().UpstreamNextHopEntries.Put(key, &x)
+					t.ctx.Logical().updateReferences(x.ObjectId(), &x, u.Entity)
+					if old != nil && changed(old, &x) {
+						t.ctx.Logical().invalidateReferrers(x.ObjectId())
+					}
 				}
 				return nil, nil
 			}
@@ -321,6 +550,37 @@ func (t translator) translateOrStore(u *p4v1.Update, translate bool) ([]*p4v1.Up
 	// Should never be here.
 }
 
+// Checks the references of the given object, about to be written with the given update type, and returns the update
+// type to translate it with, or false if it should not be translated. Deleted objects must not be referred to by
+// others. Inserted or modified objects must not refer to missing objects, except for prerequisites: objects waiting for
+// them are pending, and translated with an insert once ready, see Ready. Objects already translated cannot become
+// pending.
+func (t translator) checkReferences(id ObjectId, o interface{}, uType p4v1.Update_Type) (p4v1.Update_Type, bool,
+	error) {
+	s := t.ctx.Logical()
+	pending := s.Pending.Get(id) != nil
+	if uType == p4v1.Update_DELETE {
+		if err := s.checkNotReferred(id); err != nil {
+			return uType, false, err
+		}
+		return uType, !pending, nil
+	}
+	waiting, err := s.waitingFor(id, o)
+	switch {
+	case err != nil:
+		return uType, false, err
+	case len(waiting) == 0 && pending:
+		return p4v1.Update_INSERT, true, nil
+	case len(waiting) == 0:
+		return uType, true, nil
+	case pending || uType == p4v1.Update_INSERT:
+		return uType, false, nil
+	default:
+		return uType, false, status.Errorf(codes.NotFound, "%s is translated, it cannot refer to %s, which is "+
+			"missing or pending", id, waiting[0])
+	}
+}
+
 // evalAttachment() evaluates a snapshot of the attachment that includes information from the given table entry, as well
 // as the context. ok is true if the snapshot is complete (all fields are known), false otherwise.
 func (t translator) evalAttachment(e *p4v1.TableEntry) (a AttachmentEntry, ok bool, err error) {
@@ -344,9 +604,9 @@ func (t translator) evalAttachment(e *p4v1.TableEntry) (a AttachmentEntry, ok bo
 	}
 	var stored *AttachmentEntry
 	if a.Direction == DirectionUpstream {
-		stored = t.ctx.Logical().UpstreamAttachments[ToLineIdKey(a.LineId)]
+		stored = t.ctx.Logical().UpstreamAttachments.Get(ToLineIdKey(a.LineId))
 	} else if a.Direction == DirectionDownstream {
-		stored = t.ctx.Logical().DownstreamAttachments[ToLineIdKey(a.LineId)]
+		stored = t.ctx.Logical().DownstreamAttachments.Get(ToLineIdKey(a.LineId))
 	} else {
 		panic("direction unknown")
 	}
@@ -372,171 +632,97 @@ func (t translator) evalAttachment(e *p4v1.TableEntry) (a AttachmentEntry, ok bo
 	if a.PppoeSessId == nil {
 		a.PppoeSessId = stored.PppoeSessId
 	}
-	ok = a.Port != nil && a.STag != nil && a.CTag != nil && a.MacAddr != nil && a.Ipv4Addr != nil && a.PppoeSessId != nil
+	ok = len(a.MissingFields()) == 0
 	return
 }
 
 func parseIfTypeEntry(t *p4v1.TableEntry) (IfTypeEntry, error) {
-	entry := IfTypeEntry{}
-	// Parse match
-	for _, m := range t.Match {
-		switch m.FieldId {
-		case Hdr_IngressPipeIfTypes_Port:
-			entry.Port = m.GetExact().Value
-		default:
-			return IfTypeEntry{}, fmt.Errorf("invalid %T ID %d", m, m.FieldId)
-		}
+	x := IngressPipeIfTypesEntry{}
+	if err := x.FromTableEntry(t); err != nil {
+		return IfTypeEntry{}, err
 	}
-	// Parse action
-	act := t.GetAction().GetAction()
-	if act == nil || act.ActionId != Action_IngressPipeSetIfType {
+	act, ok := x.Action.(*IngressPipeSetIfTypeAction)
+	if !ok {
 		return IfTypeEntry{}, fmt.Errorf("invalid Action %s", t.GetAction().String())
 	}
-	for _, p := range act.Params {
-		switch p.ParamId {
-		case ActionParam_IngressPipeSetIfType_IfType:
-			entry.IfType = p.Value
-		default:
-			return IfTypeEntry{}, fmt.Errorf("invalid %T ID %d", p, p.ParamId)
-		}
-	}
-	return entry, nil
+	return IfTypeEntry{Port: x.Port, IfType: act.IfType}, nil
 }
 
 func parseMyStationEntry(t *p4v1.TableEntry) (MyStationEntry, error) {
-	entry := MyStationEntry{}
-	// Parse match
-	for _, m := range t.Match {
-		switch m.FieldId {
-		case Hdr_IngressPipeMyStations_Port:
-			entry.Port = m.GetExact().Value
-		case Hdr_IngressPipeMyStations_EthDst:
-			entry.EthDst = m.GetExact().Value
-		default:
-			return MyStationEntry{}, fmt.Errorf("invalid %T ID %d", m, m.FieldId)
-		}
+	x := IngressPipeMyStationsEntry{}
+	if err := x.FromTableEntry(t); err != nil {
+		return MyStationEntry{}, err
 	}
-	// Parse action
-	act := t.GetAction().GetAction()
-	if act == nil || act.ActionId != Action_IngressPipeSetMyStation {
+	if _, ok := x.Action.(*IngressPipeSetMyStationAction); !ok {
 		return MyStationEntry{}, fmt.Errorf("invalid Action %s", t.GetAction())
 	}
-	return entry, nil
+	return MyStationEntry{Port: x.Port, EthDst: x.EthDst}, nil
 }
 
 func parseDownstreamLinesV4Entry(t *p4v1.TableEntry, a *AttachmentEntry) error {
 	a.Direction = DirectionDownstream
-	for _, m := range t.Match {
-		switch m.FieldId {
-		case Hdr_IngressPipeDownstreamLinesV4_Ipv4Dst:
-			a.Ipv4Addr = m.GetExact().Value
-		default:
-			return fmt.Errorf("invalid %T ID %d", m, m.FieldId)
-		}
+	x := IngressPipeDownstreamLinesV4Entry{}
+	if err := x.FromTableEntry(t); err != nil {
+		return err
 	}
-	// Parse action
-	act := t.GetAction().GetAction()
-	if act == nil || act.ActionId != Action_IngressPipeDownstreamSetLine {
+	act, ok := x.Action.(*IngressPipeDownstreamSetLineAction)
+	if !ok {
 		return fmt.Errorf("invalid Action %s", t.GetAction())
 	}
-	for _, p := range act.Params {
-		switch p.ParamId {
-		case ActionParam_IngressPipeDownstreamSetLine_LineId:
-			a.LineId = p.Value
-		default:
-			return fmt.Errorf("invalid %T ID %d", p, p.ParamId)
-		}
-	}
+	a.Ipv4Addr = x.Ipv4Dst
+	a.LineId = act.LineId
 	return nil
 }
 
 func parseDownstreamAttachmentsV4(t *p4v1.TableEntry, a *AttachmentEntry) error {
 	a.Direction = DirectionDownstream
-	for _, m := range t.Match {
-		switch m.FieldId {
-		case Hdr_IngressPipeDownstreamAttachmentsV4_LineId:
-			a.LineId = m.GetExact().Value
-		default:
-			return fmt.Errorf("invalid %T ID %d", m, m.FieldId)
-		}
+	x := IngressPipeDownstreamAttachmentsV4Entry{}
+	if err := x.FromTableEntry(t); err != nil {
+		return err
 	}
-	// Parse action
-	act := t.GetAction().GetAction()
-	if act == nil || act.ActionId != Action_IngressPipeDownstreamSetPppoeAttachmentV4 {
+	act, ok := x.Action.(*IngressPipeDownstreamSetPppoeAttachmentV4Action)
+	if !ok {
 		return fmt.Errorf("invalid Action %s", t.GetAction())
 	}
-	for _, p := range act.Params {
-		switch p.ParamId {
-		case ActionParam_IngressPipeDownstreamSetPppoeAttachmentV4_Port:
-			a.Port = p.Value
-		case ActionParam_IngressPipeDownstreamSetPppoeAttachmentV4_Dmac:
-			a.MacAddr = p.Value
-		case ActionParam_IngressPipeDownstreamSetPppoeAttachmentV4_STag:
-			a.STag = p.Value
-		case ActionParam_IngressPipeDownstreamSetPppoeAttachmentV4_CTag:
-			a.CTag = p.Value
-		case ActionParam_IngressPipeDownstreamSetPppoeAttachmentV4_PppoeSessId:
-			a.PppoeSessId = p.Value
-		default:
-			return fmt.Errorf("invalid %T ID %d", p, p.ParamId)
-		}
-	}
+	a.LineId = x.LineId
+	a.Port = act.Port
+	a.MacAddr = act.Dmac
+	a.STag = act.STag
+	a.CTag = act.CTag
+	a.PppoeSessId = act.PppoeSessId
 	return nil
 }
 
 func parseUpstreamLineEntry(t *p4v1.TableEntry, a *AttachmentEntry) error {
-	// Parse match
 	a.Direction = DirectionUpstream
-	for _, m := range t.Match {
-		switch m.FieldId {
-		case Hdr_IngressPipeUpstreamLines_Port:
-			a.Port = m.GetExact().Value
-		case Hdr_IngressPipeUpstreamLines_STag:
-			a.STag = m.GetExact().Value
-		case Hdr_IngressPipeUpstreamLines_CTag:
-			a.CTag = m.GetExact().Value
-		default:
-			return fmt.Errorf("invalid %T ID %d", m, m.FieldId)
-		}
+	x := IngressPipeUpstreamLinesEntry{}
+	if err := x.FromTableEntry(t); err != nil {
+		return err
 	}
-	// Parse action
-	act := t.GetAction().GetAction()
-	if act == nil || act.ActionId != Action_IngressPipeUpstreamSetLine {
+	act, ok := x.Action.(*IngressPipeUpstreamSetLineAction)
+	if !ok {
 		return fmt.Errorf("invalid Action %s", t.GetAction())
 	}
-	for _, p := range act.Params {
-		switch p.ParamId {
-		case ActionParam_IngressPipeUpstreamSetLine_LineId:
-			a.LineId = p.Value
-		default:
-			return fmt.Errorf("invalid %T ID %d", p, p.ParamId)
-		}
-	}
+	a.Port = x.Port
+	a.STag = x.STag
+	a.CTag = x.CTag
+	a.LineId = act.LineId
 	return nil
 }
 
 func parseUpstreamAttachmentV4Entry(t *p4v1.TableEntry, a *AttachmentEntry) error {
-	// Parse match
 	a.Direction = DirectionUpstream
-	for _, m := range t.Match {
-		switch m.FieldId {
-		case Hdr_IngressPipeUpstreamAttachmentsV4_LineId:
-			a.LineId = m.GetExact().Value
-		case Hdr_IngressPipeUpstreamAttachmentsV4_EthSrc:
-			a.MacAddr = m.GetExact().Value
-		case Hdr_IngressPipeUpstreamAttachmentsV4_Ipv4Src:
-			a.Ipv4Addr = m.GetExact().Value
-		case Hdr_IngressPipeUpstreamAttachmentsV4_PppoeSessId:
-			a.PppoeSessId = m.GetExact().Value
-		default:
-			return fmt.Errorf("invalid %T ID %d", m, m.FieldId)
-		}
+	x := IngressPipeUpstreamAttachmentsV4Entry{}
+	if err := x.FromTableEntry(t); err != nil {
+		return err
 	}
-	// Parse action
-	act := t.GetAction().GetAction()
-	if act == nil || act.ActionId != Action_Nop {
+	if _, ok := x.Action.(*NopAction); !ok {
 		return fmt.Errorf("invalid Action %s", t.GetAction())
 	}
+	a.LineId = x.LineId
+	a.MacAddr = x.EthSrc
+	a.Ipv4Addr = x.Ipv4Src
+	a.PppoeSessId = x.PppoeSessId
 	return nil
 }
 
@@ -544,21 +730,16 @@ func parseUpstreamRoutesV4ActProfMember(m *p4v1.ActionProfileMember) (NextHopEnt
 	n := NextHopEntry{
 		Id: m.MemberId,
 	}
-	// Parse action
-	act := m.Action
-	if act == nil || act.ActionId != Action_IngressPipeUpstreamRouteV4 {
-		return n, fmt.Errorf("invalid Action %s", act)
-	}
-	for _, p := range act.Params {
-		switch p.ParamId {
-		case ActionParam_IngressPipeUpstreamRouteV4_Dmac:
-			n.MacAddr = p.Value
-		case ActionParam_IngressPipeUpstreamRouteV4_Port:
-			n.Port = p.Value
-		default:
-			return n, fmt.Errorf("invalid %T ID %d", p, p.ParamId)
-		}
+	x := IngressPipeUpstreamEcmpMember{}
+	if err := x.FromActionProfileMember(m); err != nil {
+		return n, err
+	}
+	act, ok := x.Action.(*IngressPipeUpstreamRouteV4Action)
+	if !ok {
+		return n, fmt.Errorf("invalid Action %s", m.Action)
 	}
+	n.MacAddr = act.Dmac
+	n.Port = act.Port
 	return n, nil
 }
 
@@ -568,24 +749,20 @@ func parseUpstreamRoutesV4ActProfGroup(g *p4v1.ActionProfileGroup) (NextHopGroup
 }
 
 func parseUpstreamRouteV4Entry(t *p4v1.TableEntry) (RouteV4Entry, error) {
-	// Parse match
 	r := RouteV4Entry{}
 	r.Direction = DirectionUpstream
-	for _, m := range t.Match {
-		switch m.FieldId {
-		case Hdr_IngressPipeUpstreamRoutesV4_Ipv4Dst:
-			r.Ipv4Addr = m.GetLpm().Value
-			r.PrefixLen = m.GetLpm().PrefixLen
-		default:
-			return r, fmt.Errorf("invalid %T ID %d", m, m.FieldId)
-		}
+	x := IngressPipeUpstreamRoutesV4Entry{}
+	if err := x.FromTableEntry(t); err != nil {
+		return r, err
+	}
+	if x.Ipv4Dst != nil {
+		r.Ipv4Addr = x.Ipv4Dst.Value
+		r.PrefixLen = x.Ipv4Dst.PrefixLen
 	}
-	// Parse action
-	gid := t.GetAction().GetActionProfileGroupId()
-	if gid == 0 {
+	if x.ActionProfileGroupId == 0 {
 		return r, fmt.Errorf("was expecting non-zero ActionProfileGroupId but found %v", t.GetAction())
 	}
-	r.NextHopGroupId = gid
+	r.NextHopGroupId = x.ActionProfileGroupId
 	return r, nil
 }
 
@@ -596,17 +773,17 @@ func parseAclEntry(t *p4v1.TableEntry) (AclEntry, error) {
 
 func parsePppoePunts(t *p4v1.TableEntry) (PppoePuntedEntry, error) {
 	c := PppoePuntedEntry{}
-	for _, m := range t.Match {
-		switch m.FieldId {
-		case Hdr_IngressPipeUpstreamPppoePunts_PppoeCode:
-			c.PppoeCode = m.GetExact().Value
-		case Hdr_IngressPipeUpstreamPppoePunts_PppoeProto:
-			// FIXME: what if the mask if not 0xFFFF?
-			if !bytes.Equal(m.GetTernary().Mask, []byte{0xFF, 0xFF}) {
-				return c, fmt.Errorf("xxpected 0xFFFF as PPPoE Proto mask but found %x", m.GetTernary().Mask)
-			}
-			c.PppoeProto = m.GetTernary().Value
+	x := IngressPipeUpstreamPppoePuntsEntry{}
+	if err := x.FromTableEntry(t); err != nil {
+		return c, err
+	}
+	c.PppoeCode = x.PppoeCode
+	if x.PppoeProto != nil {
+		// FIXME: what if the mask if not 0xFFFF?
+		if !bytes.Equal(x.PppoeProto.Mask, []byte{0xFF, 0xFF}) {
+			return c, fmt.Errorf("expected 0xFFFF as PPPoE Proto mask but found %x", x.PppoeProto.Mask)
 		}
+		c.PppoeProto = x.PppoeProto.Value
 	}
 	return c, nil
 }
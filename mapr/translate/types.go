@@ -52,6 +52,28 @@ func (a AttachmentEntry) String() string {
 		a.Direction, a.Port, a.LineId, a.STag, a.CTag, a.MacAddr, a.Ipv4Addr, a.PppoeSessId)
 }
 
+// Returns the names of the fields of the attachment that are still unknown, e.g., "mac_addr", empty if the attachment
+// is complete.
+func (a AttachmentEntry) MissingFields() []string {
+	var missing []string
+	for _, f := range []struct {
+		name  string
+		value []byte
+	}{
+		{"port", a.Port},
+		{"s_tag", a.STag},
+		{"c_tag", a.CTag},
+		{"mac_addr", a.MacAddr},
+		{"ipv4_addr", a.Ipv4Addr},
+		{"pppoe_sess_id", a.PppoeSessId},
+	} {
+		if f.value == nil {
+			missing = append(missing, f.name)
+		}
+	}
+	return missing
+}
+
 // Abstraction of an action profile member for ECMP-capable routing tables
 type NextHopEntry struct {
 	Id      uint32
@@ -0,0 +1,413 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package translate
+
+import (
+	"fmt"
+	"github.com/golang/protobuf/proto"
+	p4confv1 "github.com/p4lang/p4runtime/go/p4/config/v1"
+	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
+	"sort"
+)
+
+// Translates entities and packet metadata between an older version of the logical P4Info, used by some controllers,
+// and the current one, understood by the translator.
+//
+// Entities are matched by name, hence IDs can change across versions. Match fields, action params and packet metadata
+// can be widened in the current version but not narrowed, except for LPM fields, whose width cannot change. Ternary
+// masks and ranges covering the whole field in the older version are extended to cover the whole field in the current
+// one. When translating back to the older version, values must fit the older width, and entities that do not exist in
+// the older P4Info cannot be translated.
+type VersionUpgrader struct {
+	// Older to current.
+	up *versionMapping
+	// Current to older.
+	down *versionMapping
+}
+
+// Maps a match field, action param or packet metadata from a source P4Info to a destination one.
+type valueMapping struct {
+	id       uint32
+	srcWidth int
+	dstWidth int
+}
+
+type tableMapping struct {
+	id     uint32
+	fields map[uint32]*valueMapping
+}
+
+type actionMapping struct {
+	id     uint32
+	params map[uint32]*valueMapping
+}
+
+// Maps the IDs of a source P4Info to the ones of a destination P4Info.
+type versionMapping struct {
+	tables  map[uint32]*tableMapping
+	actions map[uint32]*actionMapping
+	// Action profiles, counters and meters, direct ones included.
+	resources map[uint32]uint32
+	// Controller header name to metadata.
+	packetMeta map[string]map[uint32]*valueMapping
+}
+
+// Returns a mapping from the src P4Info to the dst one. If c is not nil, src entities that cannot be translated to
+// dst, and dst entities that cannot be synthesized from src ones, are reported as incompatibilities. Otherwise, they
+// are left unmapped.
+func newVersionMapping(src, dst *p4confv1.P4Info, c *incompatibilities) *versionMapping {
+	m := &versionMapping{
+		tables:     make(map[uint32]*tableMapping),
+		actions:    make(map[uint32]*actionMapping),
+		resources:  make(map[uint32]uint32),
+		packetMeta: make(map[string]map[uint32]*valueMapping),
+	}
+	report := func(format string, args ...interface{}) {
+		if c != nil {
+			c.add(format, args...)
+		}
+	}
+	dstTables := tablesByName(dst)
+	for _, s := range src.GetTables() {
+		name := s.Preamble.GetName()
+		d, ok := dstTables[name]
+		if !ok {
+			report("table %s: missing", name)
+			continue
+		}
+		t := &tableMapping{id: d.Preamble.GetId(), fields: make(map[uint32]*valueMapping)}
+		dstFields := make(map[string]*p4confv1.MatchField)
+		for _, f := range d.MatchFields {
+			dstFields[f.Name] = f
+		}
+		for _, sf := range s.MatchFields {
+			df, ok := dstFields[sf.Name]
+			if !ok {
+				report("table %s: match field %s: missing", name, sf.Name)
+				continue
+			}
+			delete(dstFields, sf.Name)
+			switch {
+			case matchTypeString(df) != matchTypeString(sf):
+				report("table %s: match field %s: match type is %s, expected %s", name, sf.Name,
+					matchTypeString(df), matchTypeString(sf))
+				continue
+			case df.GetMatchType() == p4confv1.MatchField_LPM && df.Bitwidth != sf.Bitwidth:
+				report("table %s: match field %s: LPM bitwidth is %d, expected %d", name, sf.Name, df.Bitwidth,
+					sf.Bitwidth)
+				continue
+			case c != nil && df.Bitwidth < sf.Bitwidth:
+				report("table %s: match field %s: bitwidth is %d, expected at least %d", name, sf.Name, df.Bitwidth,
+					sf.Bitwidth)
+				continue
+			}
+			t.fields[sf.Id] = &valueMapping{id: df.Id, srcWidth: int(sf.Bitwidth), dstWidth: int(df.Bitwidth)}
+		}
+		for _, df := range d.MatchFields {
+			// Exact fields cannot be omitted, while all others can.
+			if _, ok := dstFields[df.Name]; ok && df.GetMatchType() == p4confv1.MatchField_EXACT {
+				report("table %s: match field %s: unexpected", name, df.Name)
+			}
+		}
+		m.tables[s.Preamble.GetId()] = t
+	}
+	dstActions := actionsByName(dst)
+	for _, s := range src.GetActions() {
+		name := s.Preamble.GetName()
+		d, ok := dstActions[name]
+		if !ok {
+			report("action %s: missing", name)
+			continue
+		}
+		a := &actionMapping{id: d.Preamble.GetId(), params: make(map[uint32]*valueMapping)}
+		dstParams := make(map[string]*p4confv1.Action_Param)
+		for _, p := range d.Params {
+			dstParams[p.Name] = p
+		}
+		for _, sp := range s.Params {
+			dp, ok := dstParams[sp.Name]
+			if !ok {
+				report("action %s: param %s: missing", name, sp.Name)
+				continue
+			}
+			delete(dstParams, sp.Name)
+			if c != nil && dp.Bitwidth < sp.Bitwidth {
+				report("action %s: param %s: bitwidth is %d, expected at least %d", name, sp.Name, dp.Bitwidth,
+					sp.Bitwidth)
+				continue
+			}
+			a.params[sp.Id] = &valueMapping{id: dp.Id, srcWidth: int(sp.Bitwidth), dstWidth: int(dp.Bitwidth)}
+		}
+		for _, dp := range d.Params {
+			if _, ok := dstParams[dp.Name]; ok {
+				report("action %s: param %s: unexpected", name, dp.Name)
+			}
+		}
+		m.actions[s.Preamble.GetId()] = a
+	}
+	dstResources := resourcesByName(dst)
+	for kindName, s := range resourcesByName(src) {
+		d, ok := dstResources[kindName]
+		if !ok {
+			report("%s: missing", kindName)
+			continue
+		}
+		m.resources[s.GetId()] = d.GetId()
+	}
+	for _, s := range src.GetControllerPacketMetadata() {
+		name := s.Preamble.GetName()
+		d := findControllerHeader(dst, name)
+		if d == nil {
+			report("controller header %s: missing", name)
+			continue
+		}
+		metadata := make(map[uint32]*valueMapping)
+		dstMeta := make(map[string]*p4confv1.ControllerPacketMetadata_Metadata)
+		for _, x := range d.Metadata {
+			dstMeta[x.Name] = x
+		}
+		for _, sm := range s.Metadata {
+			dm, ok := dstMeta[sm.Name]
+			if !ok {
+				if !isPaddingMeta(sm) {
+					report("controller header %s: metadata %s: missing", name, sm.Name)
+				}
+				continue
+			}
+			delete(dstMeta, sm.Name)
+			if c != nil && dm.Bitwidth < sm.Bitwidth {
+				report("controller header %s: metadata %s: bitwidth is %d, expected at least %d", name, sm.Name,
+					dm.Bitwidth, sm.Bitwidth)
+				continue
+			}
+			metadata[sm.Id] = &valueMapping{id: dm.Id, srcWidth: int(sm.Bitwidth), dstWidth: int(dm.Bitwidth)}
+		}
+		// Metadata sent by controllers cannot be synthesized, except padding.
+		if name == ControllerHeaderPacketOut {
+			for _, dm := range d.Metadata {
+				if _, ok := dstMeta[dm.Name]; ok && !isPaddingMeta(dm) {
+					report("controller header %s: metadata %s: unexpected", name, dm.Name)
+				}
+			}
+		}
+		m.packetMeta[name] = metadata
+	}
+	return m
+}
+
+// Creates a new VersionUpgrader from the given older logical P4Info to the current one. Returns an error listing all
+// incompatibilities if entities of the older version cannot be translated to the current one.
+func NewVersionUpgrader(older, current *p4confv1.P4Info) (*VersionUpgrader, error) {
+	var c incompatibilities
+	up := newVersionMapping(older, current, &c)
+	if len(c) > 0 {
+		sort.Strings(c)
+		return nil, fmt.Errorf("cannot upgrade P4Info: %v", c)
+	}
+	return &VersionUpgrader{up: up, down: newVersionMapping(current, older, nil)}, nil
+}
+
+// Returns the given value encoded with the byte width of the destination, or an error if it does not fit.
+func (v *valueMapping) value(b []byte) ([]byte, error) {
+	if bitLen(b) > v.dstWidth {
+		return nil, fmt.Errorf("value %x exceeds %d bits", b, v.dstWidth)
+	}
+	b = canonicalBytes(b)
+	dst := make([]byte, (v.dstWidth+7)/8)
+	if len(b) > len(dst) {
+		// Only zeros, as per bitLen.
+		b = b[len(b)-len(dst):]
+	}
+	copy(dst[len(dst)-len(b):], b)
+	return dst, nil
+}
+
+// Returns the given mask, or range bound, translated to the destination. All ones in the source width are translated
+// to all ones in the destination width.
+func (v *valueMapping) mask(b []byte) ([]byte, error) {
+	if isFullMask(b, v.srcWidth) {
+		return fullMask(v.dstWidth), nil
+	}
+	return v.value(b)
+}
+
+func mapResourceId(ids map[uint32]uint32, id uint32) (uint32, error) {
+	if id == 0 {
+		// Wildcard.
+		return 0, nil
+	}
+	mapped, ok := ids[id]
+	if !ok {
+		return 0, fmt.Errorf("unknown or untranslatable ID %d", id)
+	}
+	return mapped, nil
+}
+
+func (m *versionMapping) action(a *p4v1.Action) error {
+	am, ok := m.actions[a.ActionId]
+	if !ok {
+		return fmt.Errorf("unknown or untranslatable action ID %d", a.ActionId)
+	}
+	actionId := a.ActionId
+	a.ActionId = am.id
+	for _, p := range a.Params {
+		pm, ok := am.params[p.ParamId]
+		if !ok {
+			return fmt.Errorf("action ID %d: unknown or untranslatable param ID %d", actionId, p.ParamId)
+		}
+		value, err := pm.value(p.Value)
+		if err != nil {
+			return fmt.Errorf("action ID %d: param ID %d: %v", actionId, p.ParamId, err)
+		}
+		p.ParamId = pm.id
+		p.Value = value
+	}
+	return nil
+}
+
+func fieldMatch(f *p4v1.FieldMatch, fm *valueMapping) error {
+	var err error
+	f.FieldId = fm.id
+	switch x := f.FieldMatchType.(type) {
+	case *p4v1.FieldMatch_Exact_:
+		x.Exact.Value, err = fm.value(x.Exact.Value)
+	case *p4v1.FieldMatch_Ternary_:
+		if x.Ternary.Value, err = fm.value(x.Ternary.Value); err == nil {
+			x.Ternary.Mask, err = fm.mask(x.Ternary.Mask)
+		}
+	case *p4v1.FieldMatch_Lpm:
+		x.Lpm.Value, err = fm.value(x.Lpm.Value)
+	case *p4v1.FieldMatch_Range_:
+		if x.Range.Low, err = fm.value(x.Range.Low); err == nil {
+			x.Range.High, err = fm.mask(x.Range.High)
+		}
+	case *p4v1.FieldMatch_Optional_:
+		x.Optional.Value, err = fm.value(x.Optional.Value)
+	default:
+		err = fmt.Errorf("unsupported match type %T", x)
+	}
+	return err
+}
+
+func (m *versionMapping) tableEntry(t *p4v1.TableEntry) error {
+	if t.TableId == 0 {
+		// Wildcard read.
+		return nil
+	}
+	tm, ok := m.tables[t.TableId]
+	if !ok {
+		return fmt.Errorf("unknown or untranslatable table ID %d", t.TableId)
+	}
+	tableId := t.TableId
+	t.TableId = tm.id
+	for _, f := range t.Match {
+		fm, ok := tm.fields[f.FieldId]
+		if !ok {
+			return fmt.Errorf("table ID %d: unknown or untranslatable match field ID %d", tableId, f.FieldId)
+		}
+		fieldId := f.FieldId
+		if err := fieldMatch(f, fm); err != nil {
+			return fmt.Errorf("table ID %d: match field ID %d: %v", tableId, fieldId, err)
+		}
+	}
+	switch x := t.GetAction().GetType().(type) {
+	case *p4v1.TableAction_Action:
+		return m.action(x.Action)
+	case *p4v1.TableAction_ActionProfileActionSet:
+		for _, a := range x.ActionProfileActionSet.ActionProfileActions {
+			if err := m.action(a.Action); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Translates the given entity in place.
+func (m *versionMapping) entity(e *p4v1.Entity) error {
+	var err error
+	switch x := e.Entity.(type) {
+	case *p4v1.Entity_TableEntry:
+		return m.tableEntry(x.TableEntry)
+	case *p4v1.Entity_ActionProfileMember:
+		x.ActionProfileMember.ActionProfileId, err = mapResourceId(m.resources,
+			x.ActionProfileMember.ActionProfileId)
+		if err == nil && x.ActionProfileMember.Action != nil {
+			err = m.action(x.ActionProfileMember.Action)
+		}
+	case *p4v1.Entity_ActionProfileGroup:
+		x.ActionProfileGroup.ActionProfileId, err = mapResourceId(m.resources, x.ActionProfileGroup.ActionProfileId)
+	case *p4v1.Entity_CounterEntry:
+		x.CounterEntry.CounterId, err = mapResourceId(m.resources, x.CounterEntry.CounterId)
+	case *p4v1.Entity_MeterEntry:
+		x.MeterEntry.MeterId, err = mapResourceId(m.resources, x.MeterEntry.MeterId)
+	case *p4v1.Entity_DirectCounterEntry:
+		if x.DirectCounterEntry.TableEntry != nil {
+			err = m.tableEntry(x.DirectCounterEntry.TableEntry)
+		}
+	case *p4v1.Entity_DirectMeterEntry:
+		if x.DirectMeterEntry.TableEntry != nil {
+			err = m.tableEntry(x.DirectMeterEntry.TableEntry)
+		}
+	case *p4v1.Entity_PacketReplicationEngineEntry:
+		// Not defined by the P4Info.
+	default:
+		err = fmt.Errorf("%T cannot be translated across P4Info versions", x)
+	}
+	return err
+}
+
+func (m *versionMapping) packetMetadata(header string, src []*p4v1.PacketMetadata) ([]*p4v1.PacketMetadata, error) {
+	mapping, ok := m.packetMeta[header]
+	if !ok {
+		return nil, fmt.Errorf("%s cannot be translated across P4Info versions", header)
+	}
+	var dst []*p4v1.PacketMetadata
+	for _, s := range src {
+		mm, ok := mapping[s.MetadataId]
+		if !ok {
+			// Padding, or metadata that does not exist in the destination.
+			continue
+		}
+		value, err := mm.value(s.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%s metadata ID %d: %v", header, s.MetadataId, err)
+		}
+		dst = append(dst, &p4v1.PacketMetadata{MetadataId: mm.id, Value: value})
+	}
+	return dst, nil
+}
+
+// Returns a copy of the given entity of the older version translated to the current one.
+func (u *VersionUpgrader) UpgradeEntity(e *p4v1.Entity) (*p4v1.Entity, error) {
+	e = proto.Clone(e).(*p4v1.Entity)
+	return e, u.up.entity(e)
+}
+
+// Returns a copy of the given entity of the current version translated to the older one.
+func (u *VersionUpgrader) DowngradeEntity(e *p4v1.Entity) (*p4v1.Entity, error) {
+	e = proto.Clone(e).(*p4v1.Entity)
+	return e, u.down.entity(e)
+}
+
+// Translates the given PacketOut from the older version to the current one.
+func (u *VersionUpgrader) UpgradePacketOut(p *p4v1.PacketOut) (*p4v1.PacketOut, error) {
+	metadata, err := u.up.packetMetadata(ControllerHeaderPacketOut, p.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	return &p4v1.PacketOut{Payload: p.Payload, Metadata: metadata}, nil
+}
+
+// Translates the given PacketIn from the current version to the older one.
+func (u *VersionUpgrader) DowngradePacketIn(p *p4v1.PacketIn) (*p4v1.PacketIn, error) {
+	metadata, err := u.down.packetMetadata(ControllerHeaderPacketIn, p.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	return &p4v1.PacketIn{Payload: p.Payload, Metadata: metadata}, nil
+}
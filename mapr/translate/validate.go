@@ -0,0 +1,52 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package translate
+
+import (
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Validation of logical updates against the LogicalStore, performed before translation.
+
+// Returns an ALREADY_EXISTS error if the given attachment claims the IPv4 address, the port and VLAN tags, or the port
+// and PPPoE session ID of an attachment of another line, in either direction. These would collide in the target
+// tables, e.g., overwriting the entries of another subscriber.
+func (s *LogicalStore) checkAttachmentConflicts(a *AttachmentEntry) error {
+	var checks []struct {
+		key  AttachmentIndexKey
+		what string
+	}
+	add := func(key AttachmentIndexKey, what string) {
+		checks = append(checks, struct {
+			key  AttachmentIndexKey
+			what string
+		}{key, what})
+	}
+	if a.Ipv4Addr != nil {
+		add(AttachmentIpv4Key(a.Ipv4Addr), fmt.Sprintf("IPv4 address %s", ipv4String(a.Ipv4Addr)))
+	}
+	if a.Port != nil && a.STag != nil && a.CTag != nil {
+		add(AttachmentVlansKey(a.Port, a.STag, a.CTag), fmt.Sprintf("S-tag %s and C-tag %s on port %s",
+			decimal(a.STag), decimal(a.CTag), decimal(a.Port)))
+	}
+	if a.Port != nil && a.PppoeSessId != nil {
+		add(AttachmentPppoeKey(a.Port, a.PppoeSessId), fmt.Sprintf("PPPoE session ID %s on port %s",
+			decimal(a.PppoeSessId), decimal(a.Port)))
+	}
+	lineId := ToLineIdKey(a.LineId)
+	for _, c := range checks {
+		for _, other := range s.AttachmentsByIndex(c.key) {
+			if ToLineIdKey(other.LineId) != lineId {
+				return status.Errorf(codes.AlreadyExists, "%s of line %s is already used by line %s", c.what,
+					decimal(a.LineId), decimal(other.LineId))
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+/*
+ * Copyright 2020-present Open Networking Foundation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package translate
+
+import (
+	p4v1 "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func downstreamAttachmentEntity(lineId byte, port byte, sTag byte, cTag byte, pppoeSessId byte) *p4v1.Entity {
+	return tableEntryEntity((&IngressPipeDownstreamAttachmentsV4Entry{
+		LineId: []byte{0, 0, 0, lineId},
+		Action: &IngressPipeDownstreamSetPppoeAttachmentV4Action{
+			Port:        []byte{0, port},
+			Dmac:        []byte{0xaa, 0, 0, 0, 0, lineId},
+			STag:        []byte{0, sTag},
+			CTag:        []byte{0, cTag},
+			PppoeSessId: []byte{0, pppoeSessId},
+		},
+	}).ToTableEntry())
+}
+
+func Test_LogicalStore_checkAttachmentConflicts(t *testing.T) {
+	tests := []struct {
+		name   string
+		uType  p4v1.Update_Type
+		entity *p4v1.Entity
+		// Expected substring of the error message, no error if empty.
+		want string
+	}{
+		{"same line upstream", p4v1.Update_MODIFY, upstreamAttachmentEntity(1, 1, 1, 1), ""},
+		{"same line downstream", p4v1.Update_INSERT, downstreamLineEntity(1, 1), ""},
+		{"same line downstream attachment", p4v1.Update_INSERT, downstreamAttachmentEntity(1, 3, 100, 200, 1), ""},
+		{"other values", p4v1.Update_INSERT, upstreamLineEntity(2, 3, 100, 201), ""},
+		{"vlans", p4v1.Update_INSERT, upstreamLineEntity(2, 3, 100, 200),
+			"S-tag 100 and C-tag 200 on port 3 of line 2 is already used by line 1"},
+		{"downstream vlans", p4v1.Update_INSERT, downstreamAttachmentEntity(2, 3, 100, 200, 2),
+			"S-tag 100 and C-tag 200 on port 3 of line 2 is already used by line 1"},
+		{"upstream ipv4", p4v1.Update_INSERT, upstreamAttachmentEntity(3, 3, 1, 3),
+			"IPv4 address 10.0.0.1 of line 3 is already used by line 1"},
+		{"downstream ipv4", p4v1.Update_INSERT, downstreamLineEntity(2, 1),
+			"IPv4 address 10.0.0.1 of line 2 is already used by line 1"},
+		{"pppoe session", p4v1.Update_INSERT, downstreamAttachmentEntity(2, 3, 100, 201, 1),
+			"PPPoE session ID 1 on port 3 of line 2 is already used by line 1"},
+		{"pppoe session on other port", p4v1.Update_INSERT, downstreamAttachmentEntity(2, 4, 100, 201, 1), ""},
+		// Conflicts are checked against the evaluated attachment, i.e., including the stored fields of the line.
+		{"evaluated", p4v1.Update_INSERT, upstreamAttachmentEntity(4, 4, 4, 1), ""},
+		{"evaluated conflict", p4v1.Update_INSERT, upstreamAttachmentEntity(5, 5, 5, 1),
+			"PPPoE session ID 1 on port 3 of line 5 is already used by line 1"},
+		{"delete", p4v1.Update_DELETE, upstreamLineEntity(1, 3, 100, 200), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, write := newNopTranslator(t)
+			require.NoError(t, write(p4v1.Update_INSERT, upstreamLineEntity(1, 3, 100, 200)))
+			require.NoError(t, write(p4v1.Update_INSERT, upstreamAttachmentEntity(1, 1, 1, 1)))
+			require.NoError(t, write(p4v1.Update_INSERT, upstreamLineEntity(4, 4, 100, 200)))
+			require.NoError(t, write(p4v1.Update_INSERT, upstreamLineEntity(5, 3, 100, 205)))
+			err := write(tt.uType, tt.entity)
+			if tt.want == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, codes.AlreadyExists, status.Code(err))
+				assert.Contains(t, err.Error(), tt.want)
+			}
+		})
+	}
+}